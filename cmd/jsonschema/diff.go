@@ -0,0 +1,141 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/altshiftab/jsonschema/pkg/schemadiff"
+)
+
+// ANSI escape sequences for colored output.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBold   = "\x1b[1m"
+)
+
+// runDiff implements the diff command.
+//
+// It prints the changes from the schema in the first file to the
+// schema in the second, as [schemadiff.JSON] finds them, one per line
+// with the JSON pointer of the change, marking the breaking ones.
+// The exit status is 0, or 1 if -exit-code-on-breaking is set and a
+// change is breaking, or 2 if the files can't be read, as for diff(1).
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	exitOnBreaking := fs.Bool("exit-code-on-breaking", false, "exit with status 1 if a change is breaking")
+	color := fs.String("color", "auto", "color the output: auto, always or never")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage of jsonschema diff:")
+		fmt.Fprintln(os.Stderr, "\tjsonschema diff [flags] old.json new.json")
+		fmt.Fprintln(os.Stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	var useColor bool
+	switch *color {
+	case "auto":
+		useColor = isTerminal(os.Stdout)
+	case "always":
+		useColor = true
+	case "never":
+	default:
+		fmt.Fprintf(os.Stderr, "jsonschema diff: -color must be auto, always or never, not %q\n", *color)
+		return 2
+	}
+
+	old, err := readJSON(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema diff: %v\n", err)
+		return 2
+	}
+	new, err := readJSON(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "jsonschema diff: %v\n", err)
+		return 2
+	}
+
+	changes := schemadiff.JSON(old, new)
+	printChanges(os.Stdout, changes, useColor)
+	if *exitOnBreaking && schemadiff.Breaking(changes) {
+		return 1
+	}
+	return 0
+}
+
+// printChanges prints changes to w, followed by a summary line.
+func printChanges(w io.Writer, changes []schemadiff.Change, useColor bool) {
+	paint := func(s, color string) string {
+		if !useColor {
+			return s
+		}
+		return color + s + colorReset
+	}
+
+	breaking := 0
+	for _, c := range changes {
+		var color string
+		switch c.Kind {
+		case schemadiff.Added:
+			color = colorGreen
+		case schemadiff.Removed:
+			color = colorRed
+		default:
+			color = colorYellow
+		}
+		line := paint(c.String(), color)
+		if c.Breaking {
+			breaking++
+			line += " " + paint("BREAKING", colorBold+colorRed)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	switch len(changes) {
+	case 0:
+		fmt.Fprintln(w, "no changes")
+	case 1:
+		fmt.Fprintf(w, "1 change, %d breaking\n", breaking)
+	default:
+		fmt.Fprintf(w, "%d changes, %d breaking\n", len(changes), breaking)
+	}
+}
+
+// readJSON returns the JSON value in the file name.
+func readJSON(name string) (any, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return v, nil
+}
+
+// isTerminal reports whether f is a terminal that shows colors,
+// which is not the case if the NO_COLOR environment variable is set.
+func isTerminal(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// jsonschema is a command for working with JSON schemas.
+//
+// Usage:
+//
+//	jsonschema <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	diff    report the changes between two versions of a schema
+//
+// Run "jsonschema <command> -h" for the flags of a command.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// A command is a subcommand of jsonschema.
+type command struct {
+	name  string
+	short string
+	// run runs the command with its arguments,
+	// and returns the exit status.
+	run func(args []string) int
+}
+
+// commands are the subcommands, in the order of the usage message.
+var commands = []*command{
+	{name: "diff", short: "report the changes between two versions of a schema", run: runDiff},
+}
+
+// usage prints usage information.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage of jsonschema:")
+	fmt.Fprintln(os.Stderr, "\tjsonschema <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-8s%s\n", c.name, c.short)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			os.Exit(c.run(os.Args[2:]))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "jsonschema: unknown command %q\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"text/template"
+
+	"github.com/altshiftab/jsonschema/pkg/keywordspec"
+)
+
+// generateDialect returns the source of the file for the
+// parts of the package described by d other than its keywords.
+func generateDialect(d *keywordspec.Dialect) []byte {
+	uri, err := url.Parse(d.SchemaID)
+	if err != nil || !uri.IsAbs() {
+		fmt.Fprintf(os.Stderr, "%s: schemaID %q is not an absolute URI\n", d.Package, d.SchemaID)
+		os.Exit(1)
+	}
+	data := struct {
+		*keywordspec.Dialect
+		Host, Path string
+	}{d, uri.Host, uri.Path}
+
+	buf := new(bytes.Buffer)
+	if err := dialectTemplate.Execute(buf, data); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return buf.Bytes()
+}
+
+// dialectTemplate is the template for the file generated for a dialect.
+var dialectTemplate = template.Must(template.New("dialect").Parse(`// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by keywordgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+{{- if not .Base}}
+	"reflect"
+{{- end}}
+	"sync"
+
+	"github.com/altshiftab/jsonschema/pkg/builder"
+{{- if not .Base}}
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+{{- end}}
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+{{- if .Base}}
+	basedialect {{printf "%q" .Base}}
+{{- end}}
+)
+
+const SchemaID = {{printf "%q" .SchemaID}}
+
+var Vocabulary = &schema.Vocabulary{
+	Name:     {{printf "%q" .Name}},
+	Schema:   SchemaID,
+	Keywords: keywordMap,
+	Cmp:      keywordCmp,
+	Resolve:  resolveSchema,
+	Docs:     keywordDocs,
+{{- if .IgnoreRefSiblings}}
+	// Keywords next to $ref are ignored.
+	IgnoreRefSiblings: true,
+{{- else}}
+	// Keywords next to $ref apply along with the referenced schema.
+	IgnoreRefSiblings: false,
+{{- end}}
+}
+
+func init() {
+	schema.RegisterVocabulary(Vocabulary, {{.Default}})
+}
+
+// Builder is a JSON schema builder for {{.Name}} schemas.
+{{- if .Base}}
+// It has methods for the keywords of this package. Use the
+// methods that take a [schema.Keyword] for the keywords
+// of the base dialect, which are in basedialect.Vocabulary.
+{{- else}}
+// It is like the draft 2020-12 Builder, with methods
+// for the {{.Name}} keywords.
+{{- end}}
+//
+// Programs should use [NewBuilder] or [NewSubBuilder] to get a Builder.
+type Builder struct {
+	b *builder.Builder
+}
+
+// NewBuilder returns a [Builder] to use to build a JSON schema.
+// Use this to build an entirely new schema.
+func NewBuilder() *Builder {
+	b := &Builder{builder.New(Vocabulary)}
+	return b.AddString(&schema.SchemaKeyword, SchemaID+"#")
+}
+
+// NewSubBuilder returns a [Builder] like [NewBuilder],
+// but is for a schema that will be part of some larger schema.
+func NewSubBuilder() *Builder {
+	return &Builder{builder.New(Vocabulary)}
+}
+
+// Build returns a newly built schema.
+func (b *Builder) Build() *schema.Schema {
+	return b.b.Build()
+}
+
+// NewSubBuilder returns a new [Builder] with the same vocabulary.
+// This is like the [NewSubBuilder] function in that it is for schemas
+// that will be part of some larger schema.
+// The new Builder is in strict mode if b is.
+func (b *Builder) NewSubBuilder() *Builder {
+	return &Builder{b.b.NewBuilder()}
+}
+
+// SetStrict sets whether b is in strict mode.
+// See [builder.Builder.SetStrict].
+func (b *Builder) SetStrict(strict bool) *Builder {
+	b.b.SetStrict(strict)
+	return b
+}
+
+// BoolSchema returns a newly built schema.
+// If acceptAll is true the schema accepts all instance values,
+// if false it accepts none.
+func (b *Builder) BoolSchema(acceptAll bool) *schema.Schema {
+	b2 := b.NewSubBuilder()
+	b2.b.AddBool(&schema.BoolKeyword, acceptAll)
+	return b2.Build()
+}
+
+// AddSchemaParts adds a list of parts.
+func (b *Builder) AddSchemaParts(parts []schema.Part) *Builder {
+	b.b = b.b.AddSchemaParts(parts)
+	return b
+}
+
+{{- if not .Base}}
+
+// Infer adds schema elements to b designed to validate JSON values
+// that unmarshal into values of the given type.
+// See [builder.Infer] for details.
+func Infer[T any](b *Builder, opts *builder.InferOpts) (*Builder, error) {
+	return builder.Infer[T](b, opts)
+}
+
+// InferType is like [Infer] buts takes a [reflect.Type] rather than
+// a type argument.
+func InferType(b *Builder, typ reflect.Type, opts *builder.InferOpts) (*Builder, error) {
+	return builder.InferType(b, typ, opts)
+}
+{{- end}}
+
+// resolveSchema resolves references in a schema with the
+{{- if .Base}}
+// resolver of the base dialect. We wrap the loader so that references
+{{- else}}
+// draft 2020-12 resolver. We wrap the loader so that references
+{{- end}}
+// to the meta-schema are served from the embedded copy, and so that
+// remote schemas that don't have a "$schema" keyword default to {{.Name}}.
+func resolveSchema(s *schema.Schema, ropts *schema.ResolveOpts) error {
+	var nopts schema.ResolveOpts
+	if ropts != nil {
+		nopts = *ropts
+	}
+	next := nopts.Loader
+	nopts.Loader = schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		ms, err := loadMetaSchema(uri)
+		if ms != nil || err != nil {
+			return ms, err
+		}
+		if next == nil {
+			return nil, errors.New("remote loading not permitted")
+		}
+		return next.Load(ctx, SchemaID, uri)
+	})
+{{- if .Base}}
+	return basedialect.Vocabulary.Resolve(s, &nopts)
+{{- else if .IDAnchors}}
+	return draft202012.ResolveWithIDAnchors(s, &nopts, {{printf "%q" .IDKeyword}})
+{{- else}}
+	return draft202012.Vocabulary.Resolve(s, &nopts)
+{{- end}}
+}
+
+//go:embed {{.MetaSchema}}
+var metaSchemaJSON []byte
+
+// loadMetaSchema checks whether uri refers to the meta-schema,
+// and loads the schema if it does. If uri is not the meta-schema,
+// this returns nil, nil.
+//
+// The result is not resolved, as it is returned by a loader;
+// see [schema.ResolveOpts].
+func loadMetaSchema(uri *url.URL) (*schema.Schema, error) {
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, nil
+	}
+	if uri.Host != {{printf "%q" .Host}} || uri.Path != {{printf "%q" .Path}} {
+		return nil, nil
+	}
+	return schema.SchemaFromJSONWithSource(SchemaID, uri, metaSchemaJSON)
+}
+
+// MetaSchema returns the {{.Name}} meta-schema, which describes
+// valid {{.Name}} schemas. It can be used to check a schema
+// before using it. The meta-schema is loaded from a copy embedded
+// in this package, so this does not access the network.
+//
+// The result is shared and must not be modified.
+func MetaSchema() *schema.Schema {
+	return metaSchema()
+}
+
+// metaSchema loads the meta-schema once.
+var metaSchema = sync.OnceValue(func() *schema.Schema {
+	uri, err := url.Parse(SchemaID)
+	if err != nil {
+		panic(err)
+	}
+	s, err := loadMetaSchema(uri)
+	if err == nil {
+		err = s.Resolve(&schema.ResolveOpts{
+			Vocabulary: Vocabulary,
+			URI:        uri,
+		})
+	}
+	if err != nil {
+		panic(fmt.Sprintf("loading embedded meta-schema: %v", err))
+	}
+	return s
+})
+`))
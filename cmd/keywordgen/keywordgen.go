@@ -0,0 +1,305 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// keywordgen generates repetitive code for JSON schema keywords.
+//
+// Given keyword files, it generates a file with the keywords,
+// their Builder methods and their sort order. Given a dialect file
+// with -d, it also generates the rest of a JSON schema version
+// package: its vocabulary, Builder type, reference resolver and
+// meta-schema. The file formats are those of package keywordspec.
+//
+// A dialect file with a "base" names a dialect package, such as
+// github.com/altshiftab/jsonschema/pkg/draft202012, whose keywords
+// are extended by those of the keyword files. Such a package may be
+// in any module; its keywords name their own validator functions:
+//
+//	//go:generate go run github.com/altshiftab/jsonschema/cmd/keywordgen -d dialect.json
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/keywordspec"
+)
+
+// packageName is the name of the package to use in the generated file.
+var packageName = flag.String("p", "", "package name in generated code")
+
+// output is the name of the output file to generate.
+var output = flag.String("o", "", "output file name")
+
+// dialectFile is the name of a dialect file, which describes
+// a whole package; see [keywordspec.Dialect].
+var dialectFile = flag.String("d", "", "dialect file name, instead of the other flags and input files")
+
+// usage prints usage information.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage of keywordgen:")
+	fmt.Fprintln(os.Stderr, "\tkeywordgen [flags] keyword1.json keyword2.json...")
+	fmt.Fprintln(os.Stderr, "\tkeywordgen -d dialect.json")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+}
+
+// header is the overall file header.
+const header = `// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by keywordgen; DO NOT EDIT.
+
+package %s
+
+import (
+%s)
+
+`
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if *dialectFile != "" {
+		if *packageName != "" || *output != "" || len(flag.Args()) > 0 {
+			fmt.Fprintln(os.Stderr, "-d may not be used with -p, -o or input files")
+			usage()
+			os.Exit(2)
+		}
+		d, groups, err := keywordspec.ReadDialect(*dialectFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		dir := filepath.Dir(*dialectFile)
+		writeOutput(filepath.Join(dir, d.KeywordsOutput), generateKeywords(d.Package, d.Base, groups))
+		writeOutput(filepath.Join(dir, d.Output), generateDialect(d))
+		return
+	}
+
+	if *packageName == "" {
+		fmt.Fprintln(os.Stderr, "missing required option -p PACKAGENAME")
+		usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		fmt.Fprintln(os.Stderr, "missing required option -o OUTPUT")
+		usage()
+		os.Exit(2)
+	}
+
+	if len(flag.Args()) < 1 {
+		fmt.Fprintln(os.Stderr, "no input files")
+		usage()
+		os.Exit(2)
+	}
+
+	var groups []*keywordspec.Group
+	for _, arg := range flag.Args() {
+		g, err := keywordspec.ReadGroup(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		groups = append(groups, g)
+	}
+	writeOutput(*output, generateKeywords(*packageName, "", groups))
+}
+
+// generateKeywords returns the source of the file for the keywords
+// of groups, in the package pkg. If base is not empty, it is the
+// import path of the base dialect; see [keywordspec.Dialect].
+func generateKeywords(pkg, base string, groups []*keywordspec.Group) []byte {
+	buf := new(bytes.Buffer)
+
+	fmt.Fprintf(buf, header, pkg, imports(groups, base))
+
+	mapBuf := new(bytes.Buffer)
+	fmt.Fprintln(mapBuf, "// keywordMap maps keyword names to [types.Keyword] values.")
+	docsBuf := new(bytes.Buffer)
+	fmt.Fprintln(docsBuf, "// keywordDocs maps keyword names to their documentation.")
+	if base == "" {
+		fmt.Fprintln(mapBuf, "var keywordMap = map[string]*schema.Keyword{")
+		fmt.Fprintln(docsBuf, "var keywordDocs = map[string]schema.KeywordDoc{")
+	} else {
+		fmt.Fprintln(mapBuf, "// It has the keywords of the base dialect, and those of this package.")
+		fmt.Fprintln(mapBuf, "var keywordMap = func() map[string]*schema.Keyword {")
+		fmt.Fprintln(mapBuf, "\tm := maps.Clone(basedialect.Vocabulary.Keywords)")
+		fmt.Fprintln(mapBuf, "\tmaps.Copy(m, map[string]*schema.Keyword{")
+		fmt.Fprintln(docsBuf, "var keywordDocs = func() map[string]schema.KeywordDoc {")
+		fmt.Fprintln(docsBuf, "\tm := maps.Clone(basedialect.Vocabulary.Docs)")
+		fmt.Fprintln(docsBuf, "\tmaps.Copy(m, map[string]schema.KeywordDoc{")
+	}
+
+	builderBuf := new(bytes.Buffer)
+	writeBuilderHeader(builderBuf)
+
+	var kd []keywordspec.Keyword
+
+	for i, keywords := range groups {
+		if i > 0 {
+			fmt.Fprintln(buf)
+		}
+		printKeywords(buf, keywords, base != "")
+		printKeywordsMap(mapBuf, keywords)
+		printKeywordsDocs(docsBuf, keywords)
+		printKeywordsBuilder(builderBuf, keywords)
+		kd = append(kd, keywords.Keywords...)
+	}
+
+	if base == "" {
+		fmt.Fprintln(mapBuf, "}")
+		fmt.Fprintln(docsBuf, "}")
+	} else {
+		fmt.Fprintln(mapBuf, "\t})\n\treturn m\n}()")
+		fmt.Fprintln(docsBuf, "\t})\n\treturn m\n}()")
+	}
+
+	fmt.Fprintln(buf)
+	buf.Write(mapBuf.Bytes())
+	fmt.Fprintln(buf)
+	buf.Write(docsBuf.Bytes())
+	fmt.Fprintln(buf)
+	buf.Write(builderBuf.Bytes())
+	fmt.Fprintln(buf)
+	if base == "" {
+		printKeywordsSort(buf, kd)
+	} else {
+		printKeywordsSortBase(buf, kd)
+	}
+
+	return buf.Bytes()
+}
+
+// imports returns the import lines for the keywords of groups,
+// with the base dialect base. The validator package is only
+// imported if it is used, so that a package outside this module,
+// which can't import it, can be generated.
+func imports(groups []*keywordspec.Group, base string) string {
+	sorted, useValidator := base != "", false
+	for _, g := range groups {
+		for _, k := range g.Keywords {
+			if len(k.After) > 0 {
+				sorted = true
+			}
+			if strings.HasPrefix(validateFunction(k, g.Prefix, base != ""), "validator.") {
+				useValidator = true
+			}
+		}
+	}
+	var sb strings.Builder
+	if sorted {
+		sb.WriteString("\t\"cmp\"\n")
+	} else {
+		sb.WriteString("\t\"strings\"\n")
+	}
+	if base != "" {
+		sb.WriteString("\t\"maps\"\n")
+	}
+	sb.WriteString("\n")
+	if useValidator {
+		sb.WriteString("\t\"github.com/altshiftab/jsonschema/internal/validator\"\n")
+	}
+	sb.WriteString("\t\"github.com/altshiftab/jsonschema/pkg/types/arg_type\"\n")
+	sb.WriteString("\t\"github.com/altshiftab/jsonschema/pkg/types/schema\"\n")
+	if base != "" {
+		fmt.Fprintf(&sb, "\tbasedialect %q\n", base)
+	}
+	return sb.String()
+}
+
+// printKeywords prints the definitions for a group of keywords.
+// The based argument reports whether the dialect has a base.
+func printKeywords(buf *bytes.Buffer, keywords *keywordspec.Group, based bool) {
+	fmt.Fprintln(buf, "var (")
+	for i, k := range keywords.Keywords {
+		if i > 0 {
+			fmt.Fprintln(buf)
+		}
+		fmt.Fprintf(buf, "\t%sKeyword = schema.Keyword{\n", k.Name[keywords.Prefix:])
+		fmt.Fprintf(buf, "\t\tName: %q,\n", k.Name)
+		fmt.Fprintf(buf, "\t\tArgType: arg_type.ArgType%s,\n", k.Type)
+		fmt.Fprintf(buf, "\t\tValidate: %s,\n", validateFunction(k, keywords.Prefix, based))
+		fmt.Fprintf(buf, "\t\tGenerated: false,\n")
+		fmt.Fprintf(buf, "\t\tLeaf: %t,\n", k.Leaf || k.AlwaysValid)
+		if k.UsesNotes {
+			fmt.Fprintf(buf, "\t\tUsesNotes: true,\n")
+		}
+		if k.Annotation {
+			fmt.Fprintf(buf, "\t\tAnnotation: true,\n")
+		}
+		fmt.Fprintln(buf, "\t}")
+	}
+	fmt.Fprintln(buf, ")")
+}
+
+// printKeywordsMap prints the entries for the keyword map.
+func printKeywordsMap(mapBuf *bytes.Buffer, keywords *keywordspec.Group) {
+	for _, k := range keywords.Keywords {
+		fmt.Fprintf(mapBuf, "\t%q: &%sKeyword,\n", k.Name, k.Name[keywords.Prefix:])
+	}
+}
+
+// printKeywordsDocs prints the entries for the keyword documentation map.
+func printKeywordsDocs(docsBuf *bytes.Buffer, keywords *keywordspec.Group) {
+	for _, k := range keywords.Keywords {
+		if k.Description == "" && k.Link == "" {
+			continue
+		}
+		fmt.Fprintf(docsBuf, "\t%q: {\n", k.Name)
+		fmt.Fprintf(docsBuf, "\t\tDescription: %q,\n", k.Description)
+		fmt.Fprintf(docsBuf, "\t\tLink: %q,\n", k.Link)
+		fmt.Fprintln(docsBuf, "\t},")
+	}
+}
+
+// validateFunction returns an expression for the validation function for k.
+// The expression uses a wrapper to parse the argument based on the type.
+// In a dialect with a base, which may not use the validator package,
+// the wrapper is [schema.ArgValidator], and a keyword that is always
+// valid has no validation function.
+func validateFunction(k keywordspec.Keyword, prefix int, based bool) string {
+	if k.RawValidator {
+		return k.Validator
+	}
+	if k.AlwaysValid {
+		if based {
+			return "nil"
+		}
+		return "validator.ValidateTrue"
+	}
+	name := k.Validator
+	if name == "" {
+		name = "validator.Validate" + oneup(k.Name[prefix:])
+	}
+	if based {
+		return fmt.Sprintf("schema.ArgValidator(%s)", name)
+	}
+	return fmt.Sprintf("validator.ArgType%s(%s)", k.Type, name)
+}
+
+// oneup returns the string with the first character converted to uppercase.
+func oneup(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// writeOutput formats the source src and writes it to the file name.
+func writeOutput(name string, src []byte) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: error formatting source: %v\n", os.Args[0], err)
+		fmt.Fprintf(os.Stderr, "%s", src)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(name, formatted, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/altshiftab/jsonschema/pkg/keywordspec"
+)
+
+// printKeywordsSort writes out the keyword sorting function.
+// Sorting keywords ensures that a keyword sees any required annotations.
+func printKeywordsSort(sortBuf *bytes.Buffer, keywords []keywordspec.Keyword) {
+	found := false
+	for _, k := range keywords {
+		if len(k.After) > 0 {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		fmt.Fprintln(sortBuf, "// keywordCmp is the keyword comparison routine.")
+		fmt.Fprintln(sortBuf, "func keywordCmp(a, b string) int {")
+		fmt.Fprintln(sortBuf, "\treturn strings.Compare(a, b)")
+		fmt.Fprintln(sortBuf, "}")
+		return
+	}
+
+	ranked := keywordspec.Rank(keywords)
+
+	fmt.Fprintln(sortBuf, "// sortRank is the ranking of each keyword when sorting")
+	fmt.Fprintln(sortBuf, "var sortRank = map[string]int{")
+	for i, name := range ranked {
+		fmt.Fprintf(sortBuf, "\t%q: %d,\n", name, i)
+	}
+	fmt.Fprintln(sortBuf, "}")
+	fmt.Fprintln(sortBuf)
+
+	fmt.Fprintln(sortBuf, "// keywordCmp is the keyword comparison routine.")
+	fmt.Fprintln(sortBuf, "func keywordCmp(a, b string) int {")
+	fmt.Fprintln(sortBuf, "\treturn cmp.Compare(sortRank[a], sortRank[b])")
+	fmt.Fprintln(sortBuf, "}")
+}
+
+// printKeywordsSortBase writes out the keyword sorting function for a
+// dialect with a base. The keywords of the dialect are sorted after
+// those of the base, which the base dialect sorts.
+func printKeywordsSortBase(sortBuf *bytes.Buffer, keywords []keywordspec.Keyword) {
+	fmt.Fprintln(sortBuf, "// sortRank is the ranking of each keyword of this package when sorting")
+	fmt.Fprintln(sortBuf, "var sortRank = map[string]int{")
+	for i, name := range keywordspec.Rank(keywords) {
+		fmt.Fprintf(sortBuf, "\t%q: %d,\n", name, i)
+	}
+	fmt.Fprintln(sortBuf, "}")
+	fmt.Fprintln(sortBuf)
+
+	fmt.Fprintln(sortBuf, "// keywordCmp is the keyword comparison routine.")
+	fmt.Fprintln(sortBuf, "// The keywords of this package come after those of the base dialect.")
+	fmt.Fprintln(sortBuf, "func keywordCmp(a, b string) int {")
+	fmt.Fprintln(sortBuf, "\tra, oka := sortRank[a]")
+	fmt.Fprintln(sortBuf, "\trb, okb := sortRank[b]")
+	fmt.Fprintln(sortBuf, "\tswitch {")
+	fmt.Fprintln(sortBuf, "\tcase oka && okb:")
+	fmt.Fprintln(sortBuf, "\t\treturn cmp.Compare(ra, rb)")
+	fmt.Fprintln(sortBuf, "\tcase oka:")
+	fmt.Fprintln(sortBuf, "\t\treturn 1")
+	fmt.Fprintln(sortBuf, "\tcase okb:")
+	fmt.Fprintln(sortBuf, "\t\treturn -1")
+	fmt.Fprintln(sortBuf, "\t}")
+	fmt.Fprintln(sortBuf, "\treturn basedialect.Vocabulary.Cmp(a, b)")
+	fmt.Fprintln(sortBuf, "}")
+}
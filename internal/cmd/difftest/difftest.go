@@ -0,0 +1,311 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// difftest runs a corpus of schemas and instances through this
+// module and through another validator, and reports the cases
+// where the verdicts differ, to find correctness gaps.
+//
+// The corpus is in the format of the JSON-Schema-Test-Suite:
+// each file holds a list of groups, each with a schema and a list
+// of tests, each with an instance and the expected verdict.
+// The arguments are files or directories of such files.
+//
+// The other validator, such as santhosh-tekuri/jsonschema or
+// gojsonschema, runs as a separate command given by the -cmd flag,
+// so that this module does not depend on it. The command reads
+// requests from its standard input, one JSON object per line:
+//
+//	{"schema": {...}, "instance": ...}
+//
+// and writes a response for each request to its standard output,
+// one JSON object per line:
+//
+//	{"valid": true}
+//	{"valid": false, "error": "..."}
+//	{"schemaError": "..."}
+//
+// A schemaError means that the validator rejects the schema itself.
+// An adapter for a Go validator is a short program in its own module
+// that decodes each request, compiles the schema, validates the
+// instance, and encodes the response.
+//
+// Schemas without a $schema keyword are for the draft given by the
+// -draft flag. A reference to http://localhost:1234/ is loaded from
+// the directory given by the -remotes flag, as the test suite expects.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft04"
+	_ "github.com/altshiftab/jsonschema/pkg/draft06"
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format"
+	"github.com/altshiftab/jsonschema/pkg/loader"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// otherCmd is the command that runs the other validator.
+var otherCmd = flag.String("cmd", "", "command that runs the other validator")
+
+// draft is the schema ID of schemas without a $schema keyword.
+var draft = flag.String("draft", draft202012.SchemaID, "default schema ID")
+
+// remotes is the directory of the schemas under http://localhost:1234/.
+var remotes = flag.String("remotes", "", "directory of remote schemas")
+
+// verbose reports every case, not only differences.
+var verbose = flag.Bool("v", false, "report every case")
+
+// usage prints usage information.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage of difftest:")
+	fmt.Fprintln(os.Stderr, "\tdifftest -cmd 'command args' [flags] file-or-dir...")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+}
+
+// group is a group of tests in the test suite format.
+type group struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
+	} `json:"tests"`
+}
+
+// request is a request to the other validator.
+type request struct {
+	Schema   json.RawMessage `json:"schema"`
+	Instance json.RawMessage `json:"instance"`
+}
+
+// response is a response from the other validator.
+type response struct {
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error"`
+	SchemaError string `json:"schemaError"`
+}
+
+// verdict is the result of one validator for one case:
+// "valid", "invalid", or "schema error".
+type verdict string
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *otherCmd == "" || len(flag.Args()) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	files, err := corpusFiles(flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	other, err := startOther(*otherCmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var ropts schema.ResolveOpts
+	if *remotes != "" {
+		ropts.Loader = loader.FS(os.DirFS(*remotes), map[string]string{
+			"http://localhost:1234/": ".",
+		})
+	}
+
+	cases, diffs := 0, 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var groups []group
+		if err := json.Unmarshal(data, &groups); err != nil {
+			log.Fatalf("%s: %v", file, err)
+		}
+
+		for _, g := range groups {
+			s, schemaErr := ours(g.Schema, &ropts)
+			for _, t := range g.Tests {
+				cases++
+				ov, odetail := oursVerdict(s, schemaErr, t.Data)
+				tv, tdetail, err := other.verdict(g.Schema, t.Data)
+				if err != nil {
+					log.Fatalf("%s: %s: %s: %v", file, g.Description, t.Description, err)
+				}
+				if ov == tv && !*verbose {
+					continue
+				}
+				if ov != tv {
+					diffs++
+				}
+				fmt.Printf("%s: %s: %s\n", file, g.Description, t.Description)
+				fmt.Printf("\texpected: %s\n", expected(t.Valid))
+				fmt.Printf("\tours:     %s%s\n", ov, detail(odetail))
+				fmt.Printf("\tother:    %s%s\n", tv, detail(tdetail))
+			}
+		}
+	}
+
+	if err := other.close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d cases, %d differences\n", cases, diffs)
+	if diffs > 0 {
+		os.Exit(1)
+	}
+}
+
+// corpusFiles returns the JSON files named by args,
+// looking in directories recursively.
+func corpusFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (path == arg || filepath.Ext(path) == ".json") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ours decodes and resolves the schema with this module.
+func ours(data []byte, ropts *schema.ResolveOpts) (*schema.Schema, error) {
+	s, err := schema.SchemaFromJSONWithSource(*draft, nil, data)
+	if err != nil {
+		return nil, err
+	}
+	opts := *ropts
+	if _, ok := s.LookupKeyword("$schema"); !ok {
+		opts.Vocabulary = schema.LookupVocabulary(*draft)
+	}
+	if err := s.Resolve(&opts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// oursVerdict returns the verdict of this module
+// for the instance, and the error message if any.
+func oursVerdict(s *schema.Schema, schemaErr error, data []byte) (verdict, string) {
+	if schemaErr != nil {
+		return "schema error", schemaErr.Error()
+	}
+	var instance any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return "schema error", err.Error()
+	}
+	err := s.ValidateWithOpts(instance, &schema.ValidateOpts{})
+	switch {
+	case err == nil:
+		return "valid", ""
+	case schema.IsValidationError(err):
+		return "invalid", err.Error()
+	default:
+		return "schema error", err.Error()
+	}
+}
+
+// expected returns the verdict that the test suite expects.
+func expected(valid bool) verdict {
+	if valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+// detail formats an error message for a report.
+func detail(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	return " (" + strings.ReplaceAll(msg, "\n", "; ") + ")"
+}
+
+// otherValidator is the running command of the other validator.
+type otherValidator struct {
+	cmd *exec.Cmd
+	in  io.WriteCloser
+	out *bufio.Scanner
+}
+
+// startOther starts the other validator. The command line
+// is split at spaces, without any shell quoting.
+func startOther(cmdline string) (*otherValidator, error) {
+	args := strings.Fields(cmdline)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(out)
+	sc.Buffer(nil, 64<<20)
+	return &otherValidator{cmd: cmd, in: in, out: sc}, nil
+}
+
+// verdict asks the other validator for its verdict.
+func (o *otherValidator) verdict(schemaData, instance []byte) (verdict, string, error) {
+	req, err := json.Marshal(request{Schema: schemaData, Instance: instance})
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := o.in.Write(append(req, '\n')); err != nil {
+		return "", "", err
+	}
+	if !o.out.Scan() {
+		if err := o.out.Err(); err != nil {
+			return "", "", err
+		}
+		return "", "", fmt.Errorf("%s exited without a response", o.cmd.Path)
+	}
+	var resp response
+	if err := json.Unmarshal(o.out.Bytes(), &resp); err != nil {
+		return "", "", fmt.Errorf("bad response %q: %v", o.out.Bytes(), err)
+	}
+	switch {
+	case resp.SchemaError != "":
+		return "schema error", resp.SchemaError, nil
+	case resp.Valid:
+		return "valid", "", nil
+	default:
+		return "invalid", resp.Error, nil
+	}
+}
+
+// close stops the other validator.
+func (o *otherValidator) close() error {
+	o.in.Close()
+	return o.cmd.Wait()
+}
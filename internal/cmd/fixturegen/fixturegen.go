@@ -0,0 +1,166 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// fixturegen generates a golden set of fixture files for a schema.
+// It writes instances that the schema accepts and instances that
+// the schema rejects, along with a Go test file that replays them.
+// Committing the output gives a schema automatic regression coverage:
+// if a change to the schema flips the verdict for any fixture,
+// the generated test fails.
+//
+// Valid instances are produced by walking the schema and building
+// values that satisfy its keywords. Invalid instances are produced by
+// applying mutations to the valid instances. Every candidate is checked
+// against the schema, so the fixtures are always classified correctly
+// even when the generator can't satisfy every keyword.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// count is the number of valid instances to try to generate.
+var count = flag.Int("n", 10, "number of valid instances to generate")
+
+// seed is the random seed, so that output is reproducible.
+var seed = flag.Uint64("seed", 1, "random seed")
+
+// outDir is the directory in which to write the fixtures.
+var outDir = flag.String("o", "testdata", "output directory for fixtures")
+
+// packageName is the package name of the generated test file.
+// If empty, no test file is written.
+var packageName = flag.String("p", "", "package name of generated test file")
+
+// usage prints usage information.
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage of fixturegen:")
+	fmt.Fprintln(os.Stderr, "\tfixturegen [flags] schema.json")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if len(flag.Args()) != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	schemaFile := flag.Arg(0)
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var s schema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Fatalf("%s: %v", schemaFile, err)
+	}
+
+	r := rand.New(rand.NewPCG(*seed, *seed))
+
+	valid := generateValid(&s, r, *count)
+	if len(valid) == 0 {
+		log.Fatalf("%s: could not generate any valid instances", schemaFile)
+	}
+	invalid := generateInvalid(&s, r, valid)
+
+	for _, dir := range []string{"valid", "invalid"} {
+		if err := os.MkdirAll(filepath.Join(*outDir, dir), 0o755); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	writeFile(filepath.Join(*outDir, "schema.json"), data)
+	writeInstances(filepath.Join(*outDir, "valid"), valid)
+	writeInstances(filepath.Join(*outDir, "invalid"), invalid)
+
+	if *packageName != "" {
+		writeHarness(*packageName, *outDir)
+	}
+
+	fmt.Printf("wrote %d valid and %d invalid fixtures to %s\n", len(valid), len(invalid), *outDir)
+}
+
+// generateValid returns up to n distinct instances accepted by s.
+func generateValid(s *schema.Schema, r *rand.Rand, n int) []any {
+	var ret []any
+	seen := make(map[string]bool)
+	for tries := 0; len(ret) < n && tries < n*20; tries++ {
+		g := &generator{r: r}
+		v, ok := g.generate(s)
+		if !ok {
+			continue
+		}
+		if s.Validate(v) != nil {
+			continue
+		}
+		key := string(encode(v))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// generateInvalid returns instances rejected by s,
+// built by mutating the valid instances.
+func generateInvalid(s *schema.Schema, r *rand.Rand, valid []any) []any {
+	var ret []any
+	seen := make(map[string]bool)
+	for _, v := range valid {
+		for _, m := range mutate(r, v) {
+			if err := s.Validate(m); err == nil || !schema.IsValidationError(err) {
+				continue
+			}
+			key := string(encode(m))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// writeInstances writes each instance to a numbered file in dir.
+func writeInstances(dir string, instances []any) {
+	for i, v := range instances {
+		writeFile(filepath.Join(dir, fmt.Sprintf("%03d.json", i)), encode(v))
+	}
+}
+
+// encode returns the indented JSON encoding of v.
+func encode(v any) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(v); err != nil {
+		log.Fatalf("encoding fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// writeFile writes a file, exiting on error.
+func writeFile(name string, data []byte) {
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// maxDepth limits recursion when generating instances
+// for recursive schemas.
+const maxDepth = 8
+
+// generator builds instances that are likely to satisfy a schema.
+// The result is not guaranteed to be valid; callers must check.
+type generator struct {
+	r     *rand.Rand
+	depth int
+}
+
+// generate returns an instance for s.
+// The bool result is false if the generator gave up.
+func (g *generator) generate(s *schema.Schema) (any, bool) {
+	if g.depth > maxDepth {
+		return nil, false
+	}
+	g.depth++
+	defer func() { g.depth-- }()
+
+	parts, ok := g.flatten(s)
+	if !ok {
+		return nil, false
+	}
+	kw := func(name string) (schema.PartValue, bool) {
+		for i := len(parts) - 1; i >= 0; i-- {
+			if parts[i].Keyword.Name == name {
+				return parts[i].Value, true
+			}
+		}
+		return nil, false
+	}
+
+	if v, ok := kw("const"); ok {
+		return v.(schema.PartAny).V, true
+	}
+	if v, ok := kw("enum"); ok {
+		if vals, ok := v.(schema.PartAny).V.([]any); ok && len(vals) > 0 {
+			return vals[g.r.IntN(len(vals))], true
+		}
+	}
+	if v, ok := kw("examples"); ok && g.r.IntN(4) == 0 {
+		if vals, ok := v.(schema.PartAny).V.([]any); ok && len(vals) > 0 {
+			return vals[g.r.IntN(len(vals))], true
+		}
+	}
+
+	switch g.pickType(kw) {
+	case "null":
+		return nil, true
+	case "boolean":
+		return g.r.IntN(2) == 0, true
+	case "integer":
+		return g.number(kw, true), true
+	case "number":
+		return g.number(kw, false), true
+	case "string":
+		return g.string(kw), true
+	case "array":
+		return g.array(kw)
+	case "object":
+		return g.object(kw)
+	}
+	return nil, false
+}
+
+// flatten returns the parts of s along with the parts of any
+// subschemas that apply to the same instance: references,
+// every allOf branch, and one randomly chosen anyOf and oneOf branch.
+// The bool result is false if s is the false schema.
+func (g *generator) flatten(s *schema.Schema) ([]schema.Part, bool) {
+	var parts []schema.Part
+	for _, part := range s.Parts {
+		switch {
+		case part.Keyword == &schema.BoolKeyword:
+			if !part.Value.(schema.PartBool) {
+				return nil, false
+			}
+
+		case part.Keyword.Generated:
+			switch part.Keyword.Name {
+			case schema.ResolvedRefKeywordName, schema.ResolvedDynamicRefKeywordName:
+				sub, ok := g.flatten(part.Value.(schema.PartSchema).S)
+				if !ok {
+					return nil, false
+				}
+				parts = append(parts, sub...)
+			}
+
+		case part.Keyword.Name == "allOf":
+			for _, branch := range part.Value.(schema.PartSchemas) {
+				sub, ok := g.flatten(branch)
+				if !ok {
+					return nil, false
+				}
+				parts = append(parts, sub...)
+			}
+
+		case part.Keyword.Name == "anyOf", part.Keyword.Name == "oneOf":
+			branches := part.Value.(schema.PartSchemas)
+			sub, ok := g.flatten(branches[g.r.IntN(len(branches))])
+			if !ok {
+				return nil, false
+			}
+			parts = append(parts, sub...)
+
+		default:
+			parts = append(parts, part)
+		}
+	}
+	return parts, true
+}
+
+// pickType chooses the JSON type of the instance to generate.
+func (g *generator) pickType(kw func(string) (schema.PartValue, bool)) string {
+	if v, ok := kw("type"); ok {
+		pv := v.(schema.PartStringOrStrings)
+		if pv.Strings == nil {
+			return pv.String
+		}
+		return pv.Strings[g.r.IntN(len(pv.Strings))]
+	}
+
+	// Guess the type from the keywords that are present.
+	guesses := []struct {
+		typ      string
+		keywords []string
+	}{
+		{"object", []string{"properties", "required", "additionalProperties", "patternProperties", "minProperties", "maxProperties"}},
+		{"array", []string{"items", "prefixItems", "minItems", "maxItems", "contains", "uniqueItems"}},
+		{"string", []string{"minLength", "maxLength", "pattern", "format"}},
+		{"number", []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}},
+	}
+	for _, guess := range guesses {
+		for _, k := range guess.keywords {
+			if _, ok := kw(k); ok {
+				return guess.typ
+			}
+		}
+	}
+
+	all := []string{"null", "boolean", "integer", "number", "string"}
+	return all[g.r.IntN(len(all))]
+}
+
+// number returns a number within the bounds set by the keywords.
+func (g *generator) number(kw func(string) (schema.PartValue, bool), integer bool) float64 {
+	lo, hi := -100.0, 100.0
+	if v, ok := kw("minimum"); ok {
+		lo = float64(v.(schema.PartFloat))
+		hi = max(hi, lo+100)
+	}
+	if v, ok := kw("exclusiveMinimum"); ok {
+		lo = math.Nextafter(float64(v.(schema.PartFloat)), math.Inf(1))
+		hi = max(hi, lo+100)
+	}
+	if v, ok := kw("maximum"); ok {
+		hi = float64(v.(schema.PartFloat))
+		lo = min(lo, hi-100)
+	}
+	if v, ok := kw("exclusiveMaximum"); ok {
+		hi = math.Nextafter(float64(v.(schema.PartFloat)), math.Inf(-1))
+		lo = min(lo, hi-100)
+	}
+
+	if v, ok := kw("multipleOf"); ok {
+		m := float64(v.(schema.PartFloat))
+		if m > 0 {
+			first, last := math.Ceil(lo/m), math.Floor(hi/m)
+			if first <= last {
+				return (first + math.Floor(g.r.Float64()*(last-first+1))) * m
+			}
+		}
+	}
+
+	if integer {
+		first, last := math.Ceil(lo), math.Floor(hi)
+		if first > last {
+			return first
+		}
+		return first + math.Floor(g.r.Float64()*(last-first+1))
+	}
+	return lo + g.r.Float64()*(hi-lo)
+}
+
+// formatSamples are strings that satisfy some common formats.
+var formatSamples = map[string][]string{
+	"date":          {"2024-02-29", "1999-12-31"},
+	"date-time":     {"2024-02-29T12:30:00Z", "1999-12-31T23:59:59+01:00"},
+	"time":          {"12:30:00Z", "23:59:59+01:00"},
+	"duration":      {"P1D", "PT1H30M"},
+	"email":         {"user@example.com", "first.last@example.org"},
+	"hostname":      {"example.com", "www.example.org"},
+	"ipv4":          {"192.0.2.1", "10.0.0.1"},
+	"ipv6":          {"2001:db8::1", "::1"},
+	"uri":           {"https://example.com/path", "urn:isbn:0451450523"},
+	"uri-reference": {"/relative/path", "https://example.com/"},
+	"uuid":          {"123e4567-e89b-12d3-a456-426614174000"},
+	"json-pointer":  {"/a/b", ""},
+	"regex":         {"^[a-z]+$"},
+}
+
+// string returns a string within the bounds set by the keywords.
+func (g *generator) string(kw func(string) (schema.PartValue, bool)) string {
+	if v, ok := kw("format"); ok {
+		if samples, ok := formatSamples[string(v.(schema.PartString))]; ok {
+			return samples[g.r.IntN(len(samples))]
+		}
+	}
+
+	lo, hi := 1, 8
+	if v, ok := kw("minLength"); ok {
+		lo = int(v.(schema.PartInt))
+		hi = max(hi, lo)
+	}
+	if v, ok := kw("maxLength"); ok {
+		hi = int(v.(schema.PartInt))
+		lo = min(lo, hi)
+	}
+	n := lo + g.r.IntN(hi-lo+1)
+
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.r.IntN(len(letters))]
+	}
+	return string(b)
+}
+
+// array returns an array satisfying the keywords.
+func (g *generator) array(kw func(string) (schema.PartValue, bool)) (any, bool) {
+	var prefix []*schema.Schema
+	if v, ok := kw("prefixItems"); ok {
+		prefix = v.(schema.PartSchemas)
+	}
+	var items *schema.Schema
+	if v, ok := kw("items"); ok {
+		items = v.(schema.PartSchema).S
+	}
+
+	lo, hi := len(prefix), len(prefix)+3
+	if v, ok := kw("minItems"); ok {
+		lo = max(lo, int(v.(schema.PartInt)))
+		hi = max(hi, lo)
+	}
+	if v, ok := kw("maxItems"); ok {
+		hi = int(v.(schema.PartInt))
+		lo = min(lo, hi)
+	}
+	n := lo + g.r.IntN(hi-lo+1)
+
+	ret := make([]any, 0, n)
+	for i := range n {
+		var (
+			e  any
+			ok bool
+		)
+		switch {
+		case i < len(prefix):
+			e, ok = g.generate(prefix[i])
+		case items != nil:
+			e, ok = g.generate(items)
+		default:
+			e, ok = g.generate(&schema.Schema{})
+		}
+		if !ok {
+			// Stop at the first element we can't build;
+			// the array may still satisfy minItems.
+			break
+		}
+		ret = append(ret, e)
+	}
+	return ret, true
+}
+
+// object returns an object satisfying the keywords.
+func (g *generator) object(kw func(string) (schema.PartValue, bool)) (any, bool) {
+	var props schema.PartMapSchema
+	if v, ok := kw("properties"); ok {
+		props = v.(schema.PartMapSchema)
+	}
+	var required []string
+	if v, ok := kw("required"); ok {
+		required = v.(schema.PartStrings)
+	}
+
+	// Iterate over property names in sorted order,
+	// so that the output is reproducible for a given seed.
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	for _, name := range required {
+		if _, ok := props[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	ret := make(map[string]any)
+	for _, name := range names {
+		isRequired := slices.Contains(required, name)
+		if !isRequired && g.r.IntN(2) == 0 {
+			continue
+		}
+		ps, ok := props[name]
+		if !ok {
+			ps = &schema.Schema{}
+		}
+		v, ok := g.generate(ps)
+		if !ok {
+			if isRequired {
+				return nil, false
+			}
+			continue
+		}
+		ret[name] = v
+	}
+	return ret, true
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"log"
+	"path/filepath"
+)
+
+// harness is the generated test file.
+// %[1]s is the package name.
+// %[2]q is the fixture directory, relative to the test file.
+const harness = `// Code generated by fixturegen; DO NOT EDIT.
+
+package %[1]s
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonschema"
+)
+
+// TestSchemaFixtures replays the fixtures generated by fixturegen.
+// Files in the valid directory must be accepted by the schema,
+// and files in the invalid directory must be rejected.
+func TestSchemaFixtures(t *testing.T) {
+	dir := %[2]q
+	data, err := os.ReadFile(filepath.Join(dir, "schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := jsonschema.New(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kind := range []string{"valid", "invalid"} {
+		files, err := filepath.Glob(filepath.Join(dir, kind, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			t.Run(kind+"/"+filepath.Base(file), func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var instance any
+				if err := json.Unmarshal(data, &instance); err != nil {
+					t.Fatal(err)
+				}
+				err = s.Validate(instance)
+				if kind == "valid" && err != nil {
+					t.Errorf("valid fixture rejected: %%v", err)
+				} else if kind == "invalid" && err == nil {
+					t.Error("invalid fixture accepted")
+				}
+			})
+		}
+	}
+}
+`
+
+// writeHarness writes the Go test file that replays the fixtures.
+// The file is written in the parent directory of fixtureDir.
+func writeHarness(pkg, fixtureDir string) {
+	parent := filepath.Dir(fixtureDir)
+	rel, err := filepath.Rel(parent, fixtureDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := fmt.Sprintf(harness, pkg, filepath.ToSlash(rel))
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		log.Fatalf("error formatting test harness: %v", err)
+	}
+	writeFile(filepath.Join(parent, "fixtures_test.go"), formatted)
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// maxMutants is the maximum number of mutants to return
+// for a single instance.
+const maxMutants = 5
+
+// mutation is a function that modifies a value.
+// It returns the new value, and false if the mutation
+// does not apply to this kind of value.
+type mutation func(r *rand.Rand, v any) (any, bool)
+
+// mutations is the list of mutations we apply.
+var mutations = []mutation{
+	changeType,
+	dropProperty,
+	addProperty,
+	dropElement,
+	duplicateElement,
+	stretchString,
+	emptyString,
+	pushNumber,
+	fractionalNumber,
+}
+
+// mutate returns values derived from v by applying a single mutation
+// at a single location. The results are not guaranteed to be invalid;
+// the caller must check.
+func mutate(r *rand.Rand, v any) []any {
+	paths := locations(v, nil)
+
+	var ret []any
+	for _, path := range paths {
+		for _, m := range mutations {
+			c := deepCopy(v)
+			if nv, ok := apply(r, c, path, m); ok {
+				ret = append(ret, nv)
+			}
+		}
+	}
+
+	r.Shuffle(len(ret), func(i, j int) {
+		ret[i], ret[j] = ret[j], ret[i]
+	})
+	if len(ret) > maxMutants*len(paths) {
+		ret = ret[:maxMutants*len(paths)]
+	}
+	return ret
+}
+
+// locations returns the paths to every value within v.
+// Each path is a list of object keys and array indexes.
+func locations(v any, prefix []any) [][]any {
+	ret := [][]any{slices.Clone(prefix)}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			ret = append(ret, locations(v[k], append(prefix, k))...)
+		}
+	case []any:
+		for i, e := range v {
+			ret = append(ret, locations(e, append(prefix, i))...)
+		}
+	}
+	return ret
+}
+
+// apply applies m to the value at path within v,
+// returning the new root value.
+func apply(r *rand.Rand, v any, path []any, m mutation) (any, bool) {
+	if len(path) == 0 {
+		return m(r, v)
+	}
+	switch key := path[0].(type) {
+	case string:
+		obj := v.(map[string]any)
+		nv, ok := apply(r, obj[key], path[1:], m)
+		if !ok {
+			return nil, false
+		}
+		obj[key] = nv
+	case int:
+		arr := v.([]any)
+		nv, ok := apply(r, arr[key], path[1:], m)
+		if !ok {
+			return nil, false
+		}
+		arr[key] = nv
+	}
+	return v, true
+}
+
+// deepCopy returns a copy of a JSON value.
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = deepCopy(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// changeType replaces a value with a value of a different JSON type.
+func changeType(r *rand.Rand, v any) (any, bool) {
+	var choices []any
+	if _, ok := v.(string); !ok {
+		choices = append(choices, "mutated")
+	}
+	if _, ok := v.(float64); !ok {
+		choices = append(choices, 42.0)
+	}
+	if _, ok := v.(bool); !ok {
+		choices = append(choices, true)
+	}
+	if v != nil {
+		choices = append(choices, nil)
+	}
+	if _, ok := v.(map[string]any); !ok {
+		choices = append(choices, map[string]any{})
+	}
+	if _, ok := v.([]any); !ok {
+		choices = append(choices, []any{})
+	}
+	return choices[r.IntN(len(choices))], true
+}
+
+// dropProperty removes a property from an object.
+func dropProperty(r *rand.Rand, v any) (any, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	delete(m, keys[r.IntN(len(keys))])
+	return m, true
+}
+
+// addProperty adds an unexpected property to an object.
+func addProperty(r *rand.Rand, v any) (any, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	m["unexpectedProperty"] = "mutated"
+	return m, true
+}
+
+// dropElement removes the last element of an array.
+func dropElement(r *rand.Rand, v any) (any, bool) {
+	a, ok := v.([]any)
+	if !ok || len(a) == 0 {
+		return nil, false
+	}
+	return a[:len(a)-1], true
+}
+
+// duplicateElement appends a copy of an element of an array.
+func duplicateElement(r *rand.Rand, v any) (any, bool) {
+	a, ok := v.([]any)
+	if !ok || len(a) == 0 {
+		return nil, false
+	}
+	return append(a, deepCopy(a[r.IntN(len(a))])), true
+}
+
+// stretchString makes a string much longer.
+func stretchString(r *rand.Rand, v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	return s + strings.Repeat("x", 256), true
+}
+
+// emptyString replaces a string with the empty string.
+func emptyString(r *rand.Rand, v any) (any, bool) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return nil, false
+	}
+	return "", true
+}
+
+// pushNumber moves a number far outside of its likely range.
+func pushNumber(r *rand.Rand, v any) (any, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, false
+	}
+	if r.IntN(2) == 0 {
+		return f + 1e9, true
+	}
+	return f - 1e9, true
+}
+
+// fractionalNumber adds a fractional part to a number.
+func fractionalNumber(r *rand.Rand, v any) (any, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, false
+	}
+	return f + 0.5, true
+}
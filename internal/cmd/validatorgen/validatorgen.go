@@ -12,7 +12,6 @@ import (
 	"go/format"
 	"os"
 
-	"github.com/altshiftab/jsonschema/internal/argtype"
 	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
 )
 
@@ -33,7 +32,7 @@ package validator
 import (
 	"fmt"
 
-	"github.com/ianlancetaylor/jsonschema/pkg/types"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
 `
 
@@ -46,8 +45,8 @@ func main() {
 // %[2] is the function name.
 const validatorFn = `// %[2]s converts a validator function that accepts
 // [types.ArgType%[1]s] to one that can be stored in a [types.Keyword].
-func %[2]s(fn func(arg types.Part%[1]s, instance any, state *types.ValidationState) error) func(types.PartValue, any, *types.ValidationState) error {
-	return func(arg types.PartValue, instance any, state *types.ValidationState) error {
+func %[2]s(fn func(arg schema.Part%[1]s, instance any, state *schema.ValidationState) error) func(schema.PartValue, any, *schema.ValidationState) error {
+	return func(arg schema.PartValue, instance any, state *schema.ValidationState) error {
 		v, err := To%[1]s(arg)
 		if err != nil {
 			return err
@@ -60,10 +59,10 @@ func %[2]s(fn func(arg types.Part%[1]s, instance any, state *types.ValidationSta
 // %[1] is the schema argument type.
 // %[2] is a description for an error message.
 const convertorFn = `// To%[1]s converts arg into a [types.Part%[1]s].
-func To%[1]s(arg types.PartValue) (types.Part%[1]s, error) {
-	v, ok := arg.(types.Part%[1]s)
+func To%[1]s(arg schema.PartValue) (schema.Part%[1]s, error) {
+	v, ok := arg.(schema.Part%[1]s)
 	if !ok {
-		var zero types.Part%[1]s
+		var zero schema.Part%[1]s
 		return zero, fmt.Errorf("got %%T, expect %[2]s", arg)
 	}
 	return v, nil
@@ -81,8 +80,8 @@ func printValidatorConvertors() {
 	fmt.Fprint(buf, header)
 	fmt.Fprintln(buf)
 
-	for t := arg_type.ArgTypeBool; t <= arg_type.ArgTypeAny; t++ {
-		tn := argtype.Name(t)
+	for _, t := range arg_type.Values() {
+		tn := t.String()
 		name := "ArgType" + tn
 		fmt.Fprintln(buf)
 		fmt.Fprintf(buf, validatorFn, tn, name)
@@ -113,6 +112,8 @@ func printValidatorConvertors() {
 			desc = "a single schema or an array of schemas"
 		case arg_type.ArgTypeMapArrayOrSchema:
 			desc = "a mapping from strings to either a schema or an array of strings"
+		case arg_type.ArgTypeMapMapSchema:
+			desc = "a mapping from strings to mappings from strings to schemas"
 		case arg_type.ArgTypeAny:
 			desc = "any type"
 		default:
@@ -0,0 +1,75 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynscope_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// strictTree is the extensible tree example from the specification.
+const strictTree = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$id": "https://example.com/strict-tree",
+	"$dynamicAnchor": "node",
+	"$ref": "tree",
+	"unevaluatedProperties": false,
+	"$defs": {
+		"tree": {
+			"$id": "https://example.com/tree",
+			"$dynamicAnchor": "node",
+			"type": "object",
+			"properties": {
+				"data": true,
+				"children": {
+					"type": "array",
+					"items": {"$dynamicRef": "#node"}
+				}
+			}
+		}
+	}
+}`
+
+// TestConcurrentValidation validates with the same schema from
+// several goroutines. Run with -race to check that validation
+// doesn't change the schema.
+func TestConcurrentValidation(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(strictTree), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"children": [{"data": 1, "children": [{"data": 2}]}]}`, true},
+		{`{"children": [{"data": 1, "children": [{"daat": 2}]}]}`, false},
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		for _, test := range tests {
+			var instance any
+			if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			wg.Go(func() {
+				for range 100 {
+					err := s.Validate(instance)
+					if (err == nil) != test.valid {
+						t.Errorf("%s: got error %v, want valid %t", test.instance, err, test.valid)
+						return
+					}
+				}
+			})
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dynscope tracks the dynamic scope of a validation,
+// for the keywords that are resolved against it: $dynamicRef
+// in draft 2020-12 and later, and $recursiveRef in draft 2019-09.
+//
+// A resolver calls [AddAnchor] for each dynamic anchor it finds.
+// This adds generated keywords to the schema resource that holds
+// the anchor, which record the anchor while that resource is being
+// validated. A validator calls [Lookup] to find the schema that an
+// anchor name refers to in the current dynamic scope.
+//
+// A $recursiveAnchor is treated as a dynamic anchor named
+// [RecursiveAnchor], so both drafts share one code path.
+//
+// The keywords are added when the schema is resolved, and are not
+// changed afterward. The dynamic scope itself is kept in the
+// VersionData of the [schema.ValidationState], which is created
+// for each validation, so concurrent validations using the same
+// schema don't share any mutable state.
+package dynscope
+
+import (
+	"fmt"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// RecursiveAnchor is the anchor name used for "$recursiveAnchor": true.
+// It can't conflict with a $dynamicAnchor, which may not be empty.
+const RecursiveAnchor = ""
+
+// anchor is the type of the value stored with
+// recordKeyword and clearKeyword.
+// It is shared by all validations, and must not be modified.
+type anchor struct {
+	name   string
+	schema *schema.Schema
+}
+
+// recordKeyword is a special Keyword that records a
+// dynamic anchor while a schema resource is validated.
+var recordKeyword = schema.Keyword{
+	Name:      schema.RecordDynamicAnchorKeywordName,
+	ArgType:   arg_type.ArgTypeString,
+	Validate:  validator.ArgTypeAny(validateRecord),
+	Generated: true,
+}
+
+// clearKeyword is a special Keyword that removes a
+// dynamic anchor stored during validation.
+var clearKeyword = schema.Keyword{
+	Name:      schema.ClearDynamicAnchorKeywordName,
+	ArgType:   arg_type.ArgTypeString,
+	Validate:  validator.ArgTypeAny(validateClear),
+	Generated: true,
+}
+
+// scope is the dynamic scope of a validation.
+// It is stored in the VersionData of the validation state.
+// For each anchor name, it holds a stack of the schemas that
+// define that anchor, one for each schema resource that is
+// being validated, with the outermost first.
+// A schema resource may be entered again while it is being
+// validated, so the same schema may appear more than once.
+type scope struct {
+	anchors map[string][]*schema.Schema
+}
+
+// getScope returns the dynamic scope of state, creating it if needed.
+func getScope(state *schema.ValidationState) *scope {
+	if sc, ok := (*state.VersionData).(*scope); ok {
+		return sc
+	}
+	sc := &scope{
+		anchors: make(map[string][]*schema.Schema),
+	}
+	*state.VersionData = sc
+	return sc
+}
+
+// AddAnchor records that the schema target, within the schema
+// resource base, has a dynamic anchor called name.
+// It adds keywords to base that record the anchor during
+// validation, unless base already records an anchor called name.
+// A dynamic anchor resolves to the outermost schema in the
+// dynamic scope that has it, so a second record is not needed.
+func AddAnchor(base, target *schema.Schema, name string) {
+	for _, part := range base.Parts {
+		if part.Keyword == &recordKeyword && part.Value.(schema.PartAny).V.(*anchor).name == name {
+			return
+		}
+	}
+
+	val := &anchor{
+		name:   name,
+		schema: target,
+	}
+	record := schema.Part{
+		Keyword: &recordKeyword,
+		Value:   schema.PartAny{V: val},
+	}
+	base.Parts = append([]schema.Part{record}, base.Parts...)
+	base.Parts = append(base.Parts,
+		schema.Part{
+			Keyword: &clearKeyword,
+			Value:   schema.PartAny{V: val},
+		},
+	)
+}
+
+// Lookup returns the schema that the anchor name refers to in
+// the current dynamic scope, or nil if it is not in scope.
+// This is the outermost schema in the scope that defines the anchor.
+func Lookup(state *schema.ValidationState, name string) *schema.Schema {
+	sc, ok := (*state.VersionData).(*scope)
+	if !ok {
+		return nil
+	}
+	if stack := sc.anchors[name]; len(stack) > 0 {
+		return stack[0]
+	}
+	return nil
+}
+
+// Recursive returns the schema that a $recursiveRef refers to,
+// given the schema initial that its URI refers to, and whether
+// initial has "$recursiveAnchor": true. If it does, the reference
+// is to the outermost schema in the dynamic scope that also has
+// "$recursiveAnchor": true. Otherwise it is to initial.
+func Recursive(state *schema.ValidationState, initial *schema.Schema, recursiveAnchor bool) *schema.Schema {
+	if !recursiveAnchor {
+		return initial
+	}
+	if s := Lookup(state, RecursiveAnchor); s != nil {
+		return s
+	}
+	return initial
+}
+
+// validateRecord records a dynamic anchor during validation.
+// This is added by AddAnchor, so that the anchor is
+// visible while validating the schema resource.
+func validateRecord(arg schema.PartAny, instance any, state *schema.ValidationState) error {
+	da := arg.V.(*anchor)
+	sc := getScope(state)
+	sc.anchors[da.name] = append(sc.anchors[da.name], da.schema)
+	return nil
+}
+
+// validateClear clears a dynamic anchor during validation.
+// This is added by AddAnchor at the end of the schema resource.
+// This removes the dynamic anchor added by validateRecord,
+// so that the anchor is only visible while processing
+// the schema resource that defines it.
+func validateClear(arg schema.PartAny, instance any, state *schema.ValidationState) error {
+	da := arg.V.(*anchor)
+	sc := getScope(state)
+	stack := sc.anchors[da.name]
+	if len(stack) == 0 || stack[len(stack)-1] != da.schema {
+		return fmt.Errorf("dynamic anchor %q cleared when not in scope", da.name)
+	}
+	stack[len(stack)-1] = nil
+	if len(stack) == 1 {
+		delete(sc.anchors, da.name)
+	} else {
+		sc.anchors[da.name] = stack[:len(stack)-1]
+	}
+	return nil
+}
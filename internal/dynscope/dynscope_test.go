@@ -0,0 +1,93 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynscope
+
+import (
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// run validates the generated parts of s in order,
+// calling fn once between the record and clear keywords.
+func run(t *testing.T, s *schema.Schema, state *schema.ValidationState, fn func()) {
+	t.Helper()
+	for _, part := range s.Parts {
+		if part.Keyword == &clearKeyword && fn != nil {
+			fn()
+			fn = nil
+		}
+		if err := part.Keyword.Validate(part.Value, nil, state); err != nil {
+			t.Fatalf("%s: %v", part.Keyword.Name, err)
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	outer, outerNode, outerRec := &schema.Schema{}, &schema.Schema{}, &schema.Schema{}
+	AddAnchor(outer, outerNode, "node")
+	AddAnchor(outer, outerNode, "node")
+	AddAnchor(outer, outerRec, RecursiveAnchor)
+	if got := len(outer.Parts); got != 4 {
+		t.Fatalf("outer has %d parts, want 4", got)
+	}
+
+	inner, innerNode := &schema.Schema{}, &schema.Schema{}
+	AddAnchor(inner, innerNode, "node")
+	AddAnchor(inner, innerNode, RecursiveAnchor)
+
+	var vd any
+	state := &schema.ValidationState{VersionData: &vd}
+	if got := Lookup(state, "node"); got != nil {
+		t.Errorf("Lookup before validation = %p, want nil", got)
+	}
+	if got := Recursive(state, innerNode, true); got != innerNode {
+		t.Errorf("Recursive with empty scope = %p, want %p", got, innerNode)
+	}
+
+	run(t, outer, state, func() {
+		run(t, inner, state, func() {
+			// The outermost anchor wins.
+			if got := Lookup(state, "node"); got != outerNode {
+				t.Errorf("Lookup(node) = %p, want %p", got, outerNode)
+			}
+			if got := Recursive(state, innerNode, true); got != outerRec {
+				t.Errorf("Recursive = %p, want %p", got, outerRec)
+			}
+			if got := Recursive(state, innerNode, false); got != innerNode {
+				t.Errorf("Recursive without anchor = %p, want %p", got, innerNode)
+			}
+		})
+		if got := Lookup(state, "node"); got != outerNode {
+			t.Errorf("Lookup(node) after inner = %p, want %p", got, outerNode)
+		}
+	})
+
+	if got := Lookup(state, "node"); got != nil {
+		t.Errorf("Lookup after validation = %p, want nil", got)
+	}
+	if got := Lookup(state, RecursiveAnchor); got != nil {
+		t.Errorf("Lookup(RecursiveAnchor) after validation = %p, want nil", got)
+	}
+}
+
+func TestReenter(t *testing.T) {
+	// A schema resource that is entered again while it is
+	// being validated, as by a recursive $ref.
+	res, node := &schema.Schema{}, &schema.Schema{}
+	AddAnchor(res, node, "node")
+
+	var vd any
+	state := &schema.ValidationState{VersionData: &vd}
+	run(t, res, state, func() {
+		run(t, res, state, nil)
+		if got := Lookup(state, "node"); got != node {
+			t.Errorf("Lookup(node) after reentry = %p, want %p", got, node)
+		}
+	})
+	if got := Lookup(state, "node"); got != nil {
+		t.Errorf("Lookup after validation = %p, want nil", got)
+	}
+}
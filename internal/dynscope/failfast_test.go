@@ -0,0 +1,54 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynscope_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestFailFastScope checks that a schema resource that fails with
+// FailFast still removes its dynamic anchor from the scope, so that
+// a later branch resolves $dynamicRef as it does without FailFast.
+func TestFailFastScope(t *testing.T) {
+	const src = `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/root",
+		"anyOf": [
+			{
+				"$id": "https://example.com/named",
+				"$dynamicAnchor": "node",
+				"type": "object",
+				"required": ["name"]
+			},
+			{
+				"$id": "https://example.com/object",
+				"$dynamicAnchor": "node",
+				"type": "object",
+				"properties": {
+					"child": {"$dynamicRef": "#node"}
+				}
+			}
+		]
+	}`
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatal(err)
+	}
+	var instance any
+	if err := json.Unmarshal([]byte(`{"child": {"child": {}}}`), &instance); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, failFast := range []bool{false, true} {
+		opts := &schema.ValidateOpts{FailFast: failFast}
+		if err := s.ValidateWithOpts(instance, opts); err != nil {
+			t.Errorf("FailFast %t: %v", failFast, err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pointertoken escapes and unescapes the tokens of a JSON pointer.
+// Package jsonpointer exports these functions as EscapeToken and
+// UnescapeToken; this package is for the packages, such as the schema
+// package, that package jsonpointer itself imports.
+package pointertoken
+
+import "strings"
+
+// Escape returns tok escaped as a token of a JSON pointer:
+// "~" is written as "~0" and "/" as "~1".
+func Escape(tok string) string {
+	tok = strings.ReplaceAll(tok, "~", "~0")
+	return strings.ReplaceAll(tok, "/", "~1")
+}
+
+// Unescape is the inverse of [Escape].
+func Unescape(tok string) string {
+	tok = strings.ReplaceAll(tok, "~1", "/")
+	return strings.ReplaceAll(tok, "~0", "~")
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// AllOfMergedKeyword is a special Keyword that records the branches
+// of an allOf keyword merged into one object constraint, when every
+// branch has only properties and required keywords. ValidateAllOf
+// uses it to check an object in a single pass, rather than a pass
+// for each branch. The value is created by [MergeAllOf].
+var AllOfMergedKeyword = schema.Keyword{
+	Name:      schema.AllOfMergedKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Validate:  ValidateTrue,
+	Generated: true,
+}
+
+// mergedAllOf is the value recorded by [AllOfMergedKeyword].
+type mergedAllOf struct {
+	// properties are the properties of all the branches,
+	// sorted by name, each with the schemas that apply to it.
+	properties []mergedProperty
+	// required are the required properties of all the branches.
+	required []string
+}
+
+// mergedProperty is a property of the merged branches.
+type mergedProperty struct {
+	name    string
+	schemas []*schema.Schema
+}
+
+// MergeAllOf returns a value merging the branches of an allOf keyword,
+// for use with [AllOfMergedKeyword]. The bool result is false if the
+// branches can't be merged, because a branch has a keyword other than
+// properties and required, or if there is only one branch.
+func MergeAllOf(branches schema.PartSchemas) (schema.PartAny, bool) {
+	if len(branches) < 2 {
+		return schema.PartAny{}, false
+	}
+
+	props := make(map[string][]*schema.Schema)
+	var required []string
+	for _, branch := range branches {
+		for _, part := range branch.Parts {
+			switch {
+			case part.Keyword.Generated:
+			case part.Keyword.Name == "properties" && part.Keyword.ArgType == arg_type.ArgTypeMapSchema:
+				for name, s := range part.Value.(schema.PartMapSchema) {
+					props[name] = append(props[name], s)
+				}
+			case part.Keyword.Name == "required" && part.Keyword.ArgType == arg_type.ArgTypeStrings:
+				for _, name := range part.Value.(schema.PartStrings) {
+					if !slices.Contains(required, name) {
+						required = append(required, name)
+					}
+				}
+			default:
+				return schema.PartAny{}, false
+			}
+		}
+	}
+
+	m := &mergedAllOf{required: required}
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		m.properties = append(m.properties, mergedProperty{name, props[name]})
+	}
+	return schema.PartAny{V: m}, true
+}
+
+// mergedBranches returns the merged branches recorded for the allOf
+// keyword being validated, or nil if the branches must be validated
+// one at a time: if none were recorded, if the notes of the branches
+// may be read, if defaults are applied, or if every subschema must
+// be evaluated. The branches are kept for reporting errors, so
+// this is only used to check that an instance is valid.
+func mergedBranches(state *schema.ValidationState) *mergedAllOf {
+	if state.Exhaustive() || state.NotesNeeded() {
+		return nil
+	}
+	if state.Opts != nil && state.Opts.ApplyDefaults {
+		return nil
+	}
+	pv, ok := generatedValue(&AllOfMergedKeyword, state)
+	if !ok {
+		return nil
+	}
+	return pv.(schema.PartAny).V.(*mergedAllOf)
+}
+
+// valid reports whether instance matches every merged branch.
+// It returns an error other than a validation error,
+// such as a canceled context, if there is one.
+func (m *mergedAllOf) valid(instance any, state *schema.ValidationState) (bool, error) {
+	if names, ok := stateFieldNames(instance, state); ok {
+		for _, name := range m.required {
+			if _, found := names.byExactName[name]; !found {
+				return false, nil
+			}
+		}
+	}
+
+	for _, p := range m.properties {
+		f, jsonName, ok := instanceField(p.name, instance)
+		if !ok {
+			continue
+		}
+		state.PushInstanceToken(jsonName)
+		for _, s := range p.schemas {
+			if err := s.ValidateSubSchema(f, state); err != nil {
+				state.PopInstanceToken()
+				if !schema.IsValidationError(err) {
+					return false, err
+				}
+				return false, nil
+			}
+		}
+		state.PopInstanceToken()
+	}
+	return true, nil
+}
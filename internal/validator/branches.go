@@ -0,0 +1,185 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// AnyOfTypesKeyword is a special Keyword that records which JSON types
+// each branch of an anyOf keyword can match. ValidateAnyOf uses it to
+// skip branches that can't match the instance. The value is created
+// by [BranchTypes].
+var AnyOfTypesKeyword = schema.Keyword{
+	Name:      schema.AnyOfTypesKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Validate:  ValidateTrue,
+	Generated: true,
+}
+
+// OneOfTypesKeyword is like [AnyOfTypesKeyword], for oneOf.
+var OneOfTypesKeyword = schema.Keyword{
+	Name:      schema.OneOfTypesKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Validate:  ValidateTrue,
+	Generated: true,
+}
+
+// typeSet is a set of JSON schema types.
+type typeSet uint8
+
+const (
+	typeNull typeSet = 1 << iota
+	typeBoolean
+	typeObject
+	typeArray
+	typeNumber
+	typeString
+	typeInteger
+
+	typeAll = typeNull | typeBoolean | typeObject | typeArray | typeNumber | typeString | typeInteger
+)
+
+// typeBits maps a "type" keyword argument to a typeSet.
+// Every integer is also a number.
+var typeBits = map[string]typeSet{
+	"null":    typeNull,
+	"boolean": typeBoolean,
+	"object":  typeObject,
+	"array":   typeArray,
+	"number":  typeNumber | typeInteger,
+	"string":  typeString,
+	"integer": typeInteger,
+}
+
+// BranchTypes returns a value recording the JSON types that each
+// of branches can match, for use with [AnyOfTypesKeyword] or
+// [OneOfTypesKeyword]. This only looks at the "type" keyword of
+// each branch. The bool result is false if no branch restricts
+// the type, in which case there is nothing to record.
+func BranchTypes(branches schema.PartSchemas) (schema.PartAny, bool) {
+	types := make([]typeSet, len(branches))
+	useful := false
+	for i, branch := range branches {
+		types[i] = schemaTypes(branch)
+		if types[i] != typeAll {
+			useful = true
+		}
+	}
+	if !useful {
+		return schema.PartAny{}, false
+	}
+	return schema.PartAny{V: types}, true
+}
+
+// schemaTypes returns the types that s can match.
+func schemaTypes(s *schema.Schema) typeSet {
+	ret := typeAll
+	for _, part := range s.Parts {
+		switch {
+		case part.Keyword == &schema.BoolKeyword:
+			if !part.Value.(schema.PartBool) {
+				return 0
+			}
+		case !part.Keyword.Generated && part.Keyword.Name == "type":
+			pv, ok := part.Value.(schema.PartStringOrStrings)
+			if !ok {
+				continue
+			}
+			typs := pv.Strings
+			if typs == nil {
+				typs = []string{pv.String}
+			}
+			var ts typeSet
+			for _, typ := range typs {
+				bits, ok := typeBits[typ]
+				if !ok {
+					// Let the type keyword report the error.
+					return typeAll
+				}
+				ts |= bits
+			}
+			ret &= ts
+		}
+	}
+	return ret
+}
+
+// branchTypes returns the types recorded for keyword in the
+// schema being validated, or nil if none were recorded
+// or if every branch must be evaluated.
+func branchTypes(keyword *schema.Keyword, state *schema.ValidationState) []typeSet {
+	if state.Exhaustive() {
+		return nil
+	}
+	pv, ok := generatedValue(keyword, state)
+	if !ok {
+		return nil
+	}
+	return pv.(schema.PartAny).V.([]typeSet)
+}
+
+// instanceTypes returns the types matched by instance.
+// This uses the same rules as the type keyword. The values
+// of decoded JSON are classified by a single type switch;
+// other Go values are checked against each type.
+func instanceTypes(instance any) typeSet {
+	switch v := instance.(type) {
+	case nil:
+		return typeNull
+	case bool:
+		return typeBoolean
+	case string:
+		return typeString
+	case map[string]any:
+		return typeObject
+	case []any:
+		return typeArray
+	case float64:
+		switch {
+		case math.IsInf(v, 0) || math.IsNaN(v):
+			return 0
+		case math.Trunc(v) == v:
+			return typeNumber | typeInteger
+		}
+		return typeNumber
+	case json.Number:
+		r, ok := numberRat(v)
+		switch {
+		case !ok:
+			return 0
+		case r.IsInt():
+			return typeNumber | typeInteger
+		}
+		return typeNumber
+	}
+
+	var ret typeSet
+	for _, t := range instanceTypeList {
+		if ok, _ := matchType(t.name, instance); ok {
+			ret |= t.bit
+		}
+	}
+	return ret
+}
+
+// instanceTypeList is the list of types checked by instanceTypes.
+// An integer instance matches both "integer" and "number".
+var instanceTypeList = []struct {
+	name string
+	bit  typeSet
+}{
+	{"null", typeNull},
+	{"boolean", typeBoolean},
+	{"object", typeObject},
+	{"array", typeArray},
+	{"number", typeNumber},
+	{"string", typeString},
+	{"integer", typeInteger},
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// TestInstanceTypes checks that instanceTypes agrees with the
+// type keyword for the values it classifies without matchType.
+func TestInstanceTypes(t *testing.T) {
+	values := []any{
+		nil, true, false, "", "x",
+		map[string]any{}, []any{}, []any{1.0},
+		0.0, 1.0, -3.0, 1.5, 1e300, math.Inf(1), math.NaN(),
+		json.Number("1"), json.Number("1.0"), json.Number("1.5"),
+		json.Number("1e2"), json.Number("9007199254740993"), json.Number("abc"),
+		3, uint8(2), float32(2.5), struct{}{}, &map[string]any{},
+	}
+	for _, v := range values {
+		var want typeSet
+		for _, tl := range instanceTypeList {
+			if ok, _ := matchType(tl.name, v); ok {
+				want |= tl.bit
+			}
+		}
+		if got := instanceTypes(v); got != want {
+			t.Errorf("instanceTypes(%#v) = %b, want %b", v, got, want)
+		}
+	}
+}
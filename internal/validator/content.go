@@ -0,0 +1,136 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// ValidateContentEncoding implements the contentEncoding keyword.
+// It is an annotation unless [schema.ValidateOpts.ValidateContent]
+// is set, in which case a string must be decoded by the encoding,
+// if it is one that we know.
+func ValidateContentEncoding(arg schema.PartString, instance any, state *schema.ValidationState) error {
+	s, ok := instance.(string)
+	if !ok || state.Opts == nil || !state.Opts.ValidateContent {
+		return nil
+	}
+	if _, _, err := decodeContent(string(arg), s); err != nil {
+		return &errors2.ValidationError{
+			Message: fmt.Sprintf("string is not valid %s: %v", arg, err),
+		}
+	}
+	return nil
+}
+
+// ValidateContentMediaType implements the contentMediaType keyword.
+// It is an annotation unless [schema.ValidateOpts.ValidateContent]
+// is set, in which case a string, decoded by the contentEncoding
+// keyword next to this one, must be a document of the media type,
+// if it is one that we know: JSON is the only one.
+func ValidateContentMediaType(arg schema.PartString, instance any, state *schema.ValidationState) error {
+	s, ok := instance.(string)
+	if !ok || state.Opts == nil || !state.Opts.ValidateContent || !isJSONMediaType(string(arg)) {
+		return nil
+	}
+	data, ok := content(s, state)
+	if !ok {
+		return nil
+	}
+	if !json.Valid(data) {
+		return &errors2.ValidationError{
+			Message: fmt.Sprintf("content is not valid %s", arg),
+		}
+	}
+	return nil
+}
+
+// ValidateContentSchema implements the contentSchema keyword.
+// It is an annotation unless [schema.ValidateOpts.ValidateContent]
+// is set, in which case a string whose contentMediaType is JSON
+// is decoded, and the result must match the schema.
+func ValidateContentSchema(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
+	s, ok := instance.(string)
+	if !ok || state.Opts == nil || !state.Opts.ValidateContent {
+		return nil
+	}
+	pv, ok := state.Schema.LookupKeyword("contentMediaType")
+	if !ok {
+		// The spec says that contentSchema
+		// is ignored without contentMediaType.
+		return nil
+	}
+	if mt, ok := pv.(schema.PartString); !ok || !isJSONMediaType(string(mt)) {
+		return nil
+	}
+	data, ok := content(s, state)
+	if !ok {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		// The contentMediaType keyword reports this.
+		return nil
+	}
+	return arg.S.ValidateSubSchema(v, state)
+}
+
+// content returns the content of the string s, decoded by the
+// contentEncoding keyword of the schema being validated, if any.
+// It reports false if s can't be decoded; the contentEncoding
+// keyword reports that.
+func content(s string, state *schema.ValidationState) ([]byte, bool) {
+	pv, ok := state.Schema.LookupKeyword("contentEncoding")
+	if !ok {
+		return []byte(s), true
+	}
+	enc, _ := pv.(schema.PartString)
+	data, known, err := decodeContent(string(enc), s)
+	if err != nil || !known {
+		return nil, false
+	}
+	return data, true
+}
+
+// decodeContent decodes s by the content encoding enc.
+// It reports false if the encoding is not one that we know.
+func decodeContent(enc, s string) ([]byte, bool, error) {
+	var data []byte
+	var err error
+	switch strings.ToLower(enc) {
+	case "base64":
+		data, err = base64.StdEncoding.DecodeString(s)
+	case "base64url":
+		data, err = base64.URLEncoding.DecodeString(s)
+	case "base32":
+		data, err = base32.StdEncoding.DecodeString(s)
+	case "base16":
+		data, err = hex.DecodeString(s)
+	case "7bit", "8bit", "binary":
+		data = []byte(s)
+	default:
+		return nil, false, nil
+	}
+	return data, true, err
+}
+
+// isJSONMediaType reports whether mt is the JSON media type,
+// or a media type with a +json suffix.
+func isJSONMediaType(mt string) bool {
+	mt, _, err := mime.ParseMediaType(mt)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// ValidateOneOfDiscriminator implements the oneOf keyword of
+// OpenAPI 3.1, which may be selected by a discriminator keyword.
+func ValidateOneOfDiscriminator(arg schema.PartSchemas, instance any, state *schema.ValidationState) error {
+	if ok, err := validateDiscriminated("oneOf", arg, instance, state); ok {
+		return err
+	}
+	return ValidateOneOf(arg, instance, state)
+}
+
+// ValidateAnyOfDiscriminator implements the anyOf keyword of
+// OpenAPI 3.1, which may be selected by a discriminator keyword.
+func ValidateAnyOfDiscriminator(arg schema.PartSchemas, instance any, state *schema.ValidationState) error {
+	if ok, err := validateDiscriminated("anyOf", arg, instance, state); ok {
+		return err
+	}
+	return ValidateAnyOf(arg, instance, state)
+}
+
+// validateDiscriminated validates instance against the branch of
+// the oneOf or anyOf keyword, with argument arg, that is selected
+// by a discriminator keyword of the same schema, if the options ask
+// for that. It reports false if there is no selected branch,
+// in which case the keyword should be validated as usual.
+//
+// The discriminator names a property whose string value selects
+// the branch. The branch is the one whose $ref is the value's
+// entry in the discriminator mapping. Without an entry, or if the
+// entry is a plain name, it is the one whose $ref ends with that
+// name, as in "#/components/schemas/Cat".
+func validateDiscriminated(keyword string, arg schema.PartSchemas, instance any, state *schema.ValidationState) (bool, error) {
+	if state.Opts == nil || !state.Opts.Discriminator {
+		return false, nil
+	}
+	pv, ok := state.Schema.LookupKeyword("discriminator")
+	if !ok {
+		return false, nil
+	}
+	d, ok := pv.(schema.PartAny).V.(map[string]any)
+	if !ok {
+		return false, nil
+	}
+	propertyName, ok := d["propertyName"].(string)
+	if !ok {
+		return false, nil
+	}
+	fv, _, ok := instanceField(propertyName, instance)
+	if !ok {
+		return false, nil
+	}
+	val, ok := fv.(string)
+	if !ok {
+		return false, nil
+	}
+
+	target := val
+	if mapping, ok := d["mapping"].(map[string]any); ok {
+		if t, ok := mapping[val].(string); ok {
+			target = t
+		}
+	}
+
+	branch := -1
+	haveRefs := false
+	for i, s := range arg {
+		rv, ok := s.LookupKeyword("$ref")
+		if !ok {
+			continue
+		}
+		haveRefs = true
+		ref := string(rv.(schema.PartString))
+		if ref == target || (!strings.ContainsAny(target, "#/") && strings.HasSuffix(ref, "/"+target)) {
+			branch = i
+			break
+		}
+	}
+	if branch < 0 {
+		if !haveRefs {
+			return false, nil
+		}
+		return true, &errors2.ValidationError{
+			Message: fmt.Sprintf(`%q value %q selects no %q schema`, propertyName, val, keyword),
+		}
+	}
+
+	subState, err := state.Child()
+	if err != nil {
+		return true, err
+	}
+	if err := arg[branch].ValidateInPlaceSchema(instance, subState); err != nil {
+		if !errors2.IsValidationError(err) {
+			return true, err
+		}
+		var topErr error
+		errors2.AddError(&topErr, err, keyword+"/"+strconv.Itoa(branch))
+		return true, topErr
+	}
+	state.Notes.AddNotes(subState.Notes)
+	if keyword == "oneOf" && state.Opts.OneOfAnnotations {
+		state.Annotate(branch)
+	}
+	return true, nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// equalJSON reports whether a and b are equal as JSON values:
+// whether they have the same JSON encoding, up to the order
+// of the properties of objects and the spelling of numbers.
+// This is how the enum and const keywords compare an instance,
+// which may be any Go value, such as a struct or a typed slice,
+// with their arguments, which are decoded from JSON.
+func equalJSON(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// equalValues reports whether a and b are equal as JSON values.
+func equalValues(a, b reflect.Value) bool {
+	a, b = jsonValue(a), jsonValue(b)
+	if !a.IsValid() || !b.IsValid() {
+		// A nil is a JSON null.
+		return !a.IsValid() && !b.IsValid()
+	}
+
+	if ar, ok := numberValue(a); ok {
+		br, ok := numberValue(b)
+		return ok && ar != nil && br != nil && ar.Cmp(br) == 0
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return b.Kind() == reflect.Bool && a.Bool() == b.Bool()
+
+	case reflect.String:
+		return b.Kind() == reflect.String && a.String() == b.String()
+
+	case reflect.Slice, reflect.Array:
+		if b.Kind() != reflect.Slice && b.Kind() != reflect.Array {
+			return false
+		}
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Map, reflect.Struct:
+		am, ok := objectValues(a)
+		if !ok {
+			return false
+		}
+		bm, ok := objectValues(b)
+		if !ok || len(am) != len(bm) {
+			return false
+		}
+		for k, av := range am {
+			bv, ok := bm[k]
+			if !ok || !equalValues(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// jsonValue returns v without any interfaces or pointers around it.
+// A nil value, and a nil slice or map, which encode
+// as a JSON null, are returned as the zero Value.
+func jsonValue(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		case reflect.Slice, reflect.Map:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			return v
+		default:
+			return v
+		}
+	}
+	return v
+}
+
+// numberValue returns the exact value of v, if it is a number,
+// so that 1, 1.0 and json.Number("1e0") are equal, and large
+// integers are not rounded. The value is nil for a number that
+// is not finite, or a json.Number that does not parse; it is not
+// equal to anything.
+func numberValue(v reflect.Value) (*big.Rat, bool) {
+	if v.Type() == reflect.TypeFor[json.Number]() {
+		r, _ := numberRat(json.Number(v.String()))
+		return r, true
+	}
+	switch {
+	case v.CanInt():
+		return new(big.Rat).SetInt64(v.Int()), true
+	case v.CanUint():
+		return new(big.Rat).SetUint64(v.Uint()), true
+	case v.CanFloat():
+		return floatRat(v.Float(), v.Type().Bits()), true
+	}
+	return nil, false
+}
+
+// maxNumberExp is the largest exponent of a json.Number
+// that numberRat accepts. An exponent of 1e9 would have
+// big.Rat build a number of a billion digits.
+const maxNumberExp = 10000
+
+// numberRat returns the exact value of n,
+// and reports whether n is a number that it can represent.
+func numberRat(n json.Number) (*big.Rat, bool) {
+	if i := strings.IndexAny(string(n), "eE"); i >= 0 {
+		exp, err := strconv.Atoi(string(n[i+1:]))
+		if err != nil || exp > maxNumberExp || exp < -maxNumberExp {
+			return nil, false
+		}
+	}
+	return new(big.Rat).SetString(string(n))
+}
+
+// numberParts returns the integer m and the exponent e such that
+// n is m × 10^e, for a number n of any exponent. It reports false
+// if n is not a JSON number, or its exponent does not fit in an int.
+func numberParts(n json.Number) (*big.Int, int, bool) {
+	if !isNumberText(n) {
+		return nil, 0, false
+	}
+	s, exp := string(n), 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, 0, false
+		}
+		s, exp = s[:i], e
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if exp < math.MinInt+len(frac) {
+		return nil, 0, false
+	}
+	m, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		return nil, 0, false
+	}
+	return m, exp - len(frac), true
+}
+
+// isNumberText reports whether n is written as a JSON number.
+func isNumberText(n json.Number) bool {
+	return n != "" && (n[0] == '-' || (n[0] >= '0' && n[0] <= '9')) && json.Valid([]byte(n))
+}
+
+// floatRat returns the value of f, a float of the given size,
+// in its shortest decimal form, which is how f is encoded,
+// so that float32(1.1), 1.1 and json.Number("1.10") are equal.
+// It returns nil for NaN and infinities.
+func floatRat(f float64, bits int) *big.Rat {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bits))
+	return r
+}
+
+// objectValues returns the properties of v, a map with string keys
+// or a struct, as JSON encodes them. It reports false if v is not
+// an object.
+func objectValues(v reflect.Value) (map[string]reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		ret := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			ret[iter.Key().String()] = iter.Value()
+		}
+		return ret, true
+
+	case reflect.Struct:
+		fields := cachedTypeFields(v.Type())
+		ret := make(map[string]reflect.Value, len(fields.byExactName))
+		for name, f := range fields.byExactName {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// A nil embedded pointer.
+				continue
+			}
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			ret[name] = fv
+		}
+		return ret, true
+	}
+	return nil, false
+}
+
+// isEmptyValue reports whether v is empty,
+// as for the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
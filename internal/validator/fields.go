@@ -6,12 +6,16 @@ package validator
 
 import (
 	"cmp"
+	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"unicode"
 	"unicode/utf8"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
 
 // instanceField returns the value of a field name in an instance,
@@ -21,14 +25,22 @@ func instanceField(name string, instance any) (any, string, bool) {
 		return nil, "", false
 	}
 
-	v := reflect.Indirect(reflect.ValueOf(instance))
-
-	if m, ok := v.Interface().(map[string]any); ok {
+	// Skip reflection for common map types.
+	switch m := instance.(type) {
+	case map[string]any:
 		// This is a JSON object.
 		v, ok := m[name]
 		return v, name, ok
+	case *map[string]any:
+		v, ok := (*m)[name]
+		return v, name, ok
+	case map[string]string:
+		v, ok := m[name]
+		return v, name, ok
 	}
 
+	v := reflect.Indirect(reflect.ValueOf(instance))
+
 	if v.Kind() != reflect.Struct {
 		return nil, "", false
 	}
@@ -47,6 +59,41 @@ func instanceField(name string, instance any) (any, string, bool) {
 	return vf.Interface(), field.name, true
 }
 
+// InstanceElement returns the value that the JSON pointer token tok
+// selects in instance: a property of an object, which may be a map
+// or a struct, or an element of an array. It reports false if there
+// is no such value.
+func InstanceElement(instance any, tok string) (any, bool) {
+	v := reflect.ValueOf(instance)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(tok)
+		if err != nil || i < 0 || i >= v.Len() || (tok != "0" && tok[0] == '0') {
+			return nil, false
+		}
+		return v.Index(i).Interface(), true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		e := v.MapIndex(reflect.ValueOf(tok).Convert(v.Type().Key()))
+		if !e.IsValid() {
+			return nil, false
+		}
+		return e.Interface(), true
+	case reflect.Struct:
+		e, _, ok := instanceField(tok, v.Interface())
+		return e, ok
+	}
+	return nil, false
+}
+
 // instanceFieldNames returns the field names found in an instance,
 // and reports whether the instance is an object.
 // For efficiency this returns a structFields value,
@@ -58,32 +105,64 @@ func instanceFieldNames(instance any) (structFields, bool) {
 		return structFields{}, false
 	}
 
-	v := reflect.Indirect(reflect.ValueOf(instance))
-
-	if m, ok := v.Interface().(map[string]any); ok {
-		mf := make(map[string]*field)
-		for k := range m {
-			mf[k] = nil
-		}
-		return structFields{byExactName: mf}, true
+	// Skip reflection for common map types.
+	switch m := instance.(type) {
+	case map[string]any:
+		return mapFieldNames(m), true
+	case *map[string]any:
+		return mapFieldNames(*m), true
+	case map[string]string:
+		return mapFieldNames(m), true
 	}
 
-	typ := v.Type()
+	typ := reflect.Indirect(reflect.ValueOf(instance)).Type()
 	if typ.Kind() != reflect.Struct {
 		return structFields{}, false
 	}
 	return cachedTypeFields(typ), true
 }
 
+// mapFieldNames returns the field names of a map,
+// in the form returned by instanceFieldNames.
+func mapFieldNames[V any](m map[string]V) structFields {
+	mf := make(map[string]*field, len(m))
+	for k := range m {
+		mf[k] = nil
+	}
+	return structFields{byExactName: mf}
+}
+
+// stateFieldNames is like instanceFieldNames, but the result is
+// shared with the other keywords evaluating the same instance.
+func stateFieldNames(instance any, state *schema.ValidationState) (structFields, bool) {
+	v, ok := state.InstanceFields(func() (any, bool) {
+		return instanceFieldNames(instance)
+	})
+	if !ok {
+		return structFields{}, false
+	}
+	return v.(structFields), true
+}
+
 // setField sets the value of a field in instance.
 func setField(instance any, jsonName string, val any) error {
-	v := reflect.Indirect(reflect.ValueOf(instance))
-
-	if m, ok := v.Interface().(map[string]any); ok {
+	switch m := instance.(type) {
+	case map[string]any:
 		m[jsonName] = val
 		return nil
+	case *map[string]any:
+		(*m)[jsonName] = val
+		return nil
+	case map[string]string:
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("can't set default value of type %T in map[string]string", val)
+		}
+		m[jsonName] = s
+		return nil
 	}
 
+	v := reflect.Indirect(reflect.ValueOf(instance))
 	fields := cachedTypeFields(v.Type())
 	field := fields.byExactName[jsonName]
 	if field == nil {
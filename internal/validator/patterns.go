@@ -0,0 +1,166 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// PatternPropertiesRegexpsKeyword is a special Keyword that records
+// the compiled regexps of a patternProperties keyword, so that
+// ValidatePatternProperties doesn't have to compile them each time.
+// The value is created by [PatternPropertiesRegexps].
+var PatternPropertiesRegexpsKeyword = schema.Keyword{
+	Name:      schema.PatternPropertiesRegexpsKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Validate:  ValidateTrue,
+	Generated: true,
+}
+
+// patternCache maps the arguments of pattern keywords to their
+// compiled regexps, so that ValidatePattern doesn't have to compile
+// them each time. The pattern keyword is a leaf keyword, which is
+// validated without its schema in the [schema.ValidationState],
+// so the regexp can't be recorded in the schema by a generated
+// keyword, as it is for patternProperties.
+var patternCache sync.Map // map[string]schema.Regexp
+
+// patternCacheSize is the number of entries in patternCache.
+var patternCacheSize atomic.Int64
+
+// maxPatternCache is the most regexps that patternCache holds,
+// so that a program that decodes many schemas doesn't grow it
+// without limit. Other regexps are compiled each time.
+const maxPatternCache = 4096
+
+// CompilePattern compiles the argument of a pattern keyword,
+// and caches the result for ValidatePattern.
+// It is called when the schema is resolved.
+func CompilePattern(arg schema.PartString) (schema.Regexp, error) {
+	if re, ok := patternCache.Load(string(arg)); ok {
+		return re.(schema.Regexp), nil
+	}
+	re, err := schema.CompileRegexp(string(arg))
+	if err != nil {
+		return nil, err
+	}
+	if patternCacheSize.Load() < maxPatternCache {
+		if _, loaded := patternCache.LoadOrStore(string(arg), re); !loaded {
+			patternCacheSize.Add(1)
+		}
+	}
+	return re, nil
+}
+
+// regexpSchema is a compiled patternProperties regexp
+// and the corresponding schema.
+type regexpSchema struct {
+	re schema.Regexp
+	s  *schema.Schema
+}
+
+// patternSet is a compiled patternProperties argument.
+type patternSet struct {
+	// combined matches a property name if any of the regexps do.
+	// It lets us check a name once, rather than once per regexp,
+	// in the common case that the name matches none of them.
+	// It is nil if there is only one regexp, or if the regexps
+	// could not be combined.
+	combined schema.Regexp
+	// The individual regexps, sorted by pattern.
+	res []regexpSchema
+}
+
+// PatternPropertiesRegexps returns a value holding the compiled
+// regexps of a patternProperties argument, for use with
+// [PatternPropertiesRegexpsKeyword]. The bool result is false if
+// any of the regexps is invalid; that will be reported when
+// validating the patternProperties keyword.
+func PatternPropertiesRegexps(arg schema.PartMapSchema) (schema.PartAny, bool) {
+	ps, err := compilePatternProperties(arg)
+	if err != nil {
+		return schema.PartAny{}, false
+	}
+	return schema.PartAny{V: ps}, true
+}
+
+// compilePatternProperties compiles a patternProperties argument.
+func compilePatternProperties(arg schema.PartMapSchema) (*patternSet, error) {
+	patterns := make([]string, 0, len(arg))
+	for reString := range arg {
+		patterns = append(patterns, reString)
+	}
+	slices.Sort(patterns)
+
+	ps := &patternSet{
+		res: make([]regexpSchema, 0, len(patterns)),
+	}
+	for _, reString := range patterns {
+		re, err := schema.CompileRegexp(reString)
+		if err != nil {
+			return nil, fmt.Errorf(`"patternProperties" regexp %q failed: %v`, reString, err)
+		}
+		ps.res = append(ps.res, regexpSchema{re, arg[reString]})
+	}
+
+	if len(patterns) > 1 {
+		ps.combined = combineRegexps(ps.res)
+	}
+
+	return ps, nil
+}
+
+// combineRegexps returns a regexp that matches if any of res do,
+// or nil if they can't be combined. They can only be combined if
+// they are all compiled by the regexp package, as they are by
+// default: another engine, set with [schema.SetRegexpCompiler],
+// may have a syntax in which a regexp means something else when
+// it is put in a group, as with numbered backreferences.
+func combineRegexps(res []regexpSchema) schema.Regexp {
+	var sb strings.Builder
+	for i, r := range res {
+		re, ok := r.re.(*regexp.Regexp)
+		if !ok {
+			return nil
+		}
+		if i > 0 {
+			sb.WriteByte('|')
+		}
+		// Each regexp is put in its own group, so flags
+		// and anchors only apply to that regexp.
+		sb.WriteString("(?:")
+		sb.WriteString(re.String())
+		sb.WriteString(")")
+	}
+	// If this fails, perhaps because the combined regexp
+	// is too large, we just check each regexp in turn.
+	combined, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil
+	}
+	return combined
+}
+
+// matching returns the regexps that match name.
+func (ps *patternSet) matching(name string) []regexpSchema {
+	if ps.combined != nil && !ps.combined.MatchString(name) {
+		return nil
+	}
+	var ret []regexpSchema
+	for _, r := range ps.res {
+		if r.re.MatchString(name) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
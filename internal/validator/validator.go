@@ -8,15 +8,18 @@
 package validator
 
 import (
+	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/maphash"
+	"maps"
 	"math"
+	"math/big"
 	"reflect"
-	"regexp"
 	"slices"
 	"strconv"
 	"sync"
-	"unicode/utf8"
 
 	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
 	"github.com/altshiftab/jsonschema/pkg/notes"
@@ -58,6 +61,19 @@ func ValidateTrue(schema.PartValue, any, *schema.ValidationState) error {
 	return nil
 }
 
+// generatedValue returns the value of a generated keyword
+// added by the resolver to the schema being validated.
+func generatedValue(keyword *schema.Keyword, state *schema.ValidationState) (schema.PartValue, bool) {
+	// Generated keywords are at the end of the schema.
+	parts := state.Schema.Parts
+	for i := len(parts) - 1; i > state.Index; i-- {
+		if parts[i].Keyword == keyword {
+			return parts[i].Value, true
+		}
+	}
+	return nil, false
+}
+
 // ValidateAllOf implements the allOf keyword.
 func ValidateAllOf(arg schema.PartSchemas, instance any, state *schema.ValidationState) error {
 	subState, err := state.Child()
@@ -65,11 +81,26 @@ func ValidateAllOf(arg schema.PartSchemas, instance any, state *schema.Validatio
 		return err
 	}
 
+	// Check merged object branches in one pass. If that fails,
+	// validate the branches one at a time to report the errors.
+	if m := mergedBranches(state); m != nil {
+		ok, err := m.valid(instance, subState)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+
 	var keepNotes []notes.Notes
 	var topErr error
 	for i, s := range arg {
 		if err := s.ValidateInPlaceSchema(instance, subState); err != nil {
 			errors2.AddError(&topErr, err, fmt.Sprintf("allOf/%d", i))
+			if state.FailFast() {
+				return topErr
+			}
 		} else {
 			if !subState.Notes.IsEmpty() {
 				keepNotes = append(keepNotes, subState.Notes)
@@ -92,10 +123,20 @@ func ValidateAnyOf(arg schema.PartSchemas, instance any, state *schema.Validatio
 		return err
 	}
 
+	// Skip branches that can't match the type of the instance.
+	var types []typeSet
+	var instTypes typeSet
+	if types = branchTypes(&AnyOfTypesKeyword, state); types != nil {
+		instTypes = instanceTypes(instance)
+	}
+
 	var keepNotes []notes.Notes
 	ok := false
 	var topErr error
-	for _, s := range arg {
+	for i, s := range arg {
+		if types != nil && types[i]&instTypes == 0 {
+			continue
+		}
 		if err := s.ValidateInPlaceSchema(instance, subState); err != nil {
 			if !errors2.IsValidationError(err) {
 				errors2.AddError(&topErr, err, "")
@@ -105,6 +146,11 @@ func ValidateAnyOf(arg schema.PartSchemas, instance any, state *schema.Validatio
 			if !subState.Notes.IsEmpty() {
 				keepNotes = append(keepNotes, subState.Notes)
 			}
+			if !state.NotesNeeded() {
+				// Nothing will read the notes of
+				// the other subschemas.
+				break
+			}
 
 			// Continue to check all subschemas to
 			// check for errors and collect notes.
@@ -127,28 +173,47 @@ func ValidateOneOf(arg schema.PartSchemas, instance any, state *schema.Validatio
 		return err
 	}
 
+	// Skip branches that can't match the type of the instance.
+	var types []typeSet
+	var instTypes typeSet
+	if types = branchTypes(&OneOfTypesKeyword, state); types != nil {
+		instTypes = instanceTypes(instance)
+	}
+
+	// Stop at the second match: the keyword fails no matter
+	// what the other branches do, and its notes are discarded.
 	var keepNotes notes.Notes
-	c := 0
+	first, second := -1, -1
 	var topErr error
-	for _, s := range arg {
+	for i, s := range arg {
+		if types != nil && types[i]&instTypes == 0 {
+			continue
+		}
 		if err := s.ValidateInPlaceSchema(instance, subState); err != nil {
 			if !errors2.IsValidationError(err) {
 				errors2.AddError(&topErr, err, "")
 			}
-		} else {
-			c++
+		} else if first < 0 {
+			first = i
 			keepNotes = subState.Notes
+		} else if second < 0 {
+			second = i
+			if !state.Exhaustive() {
+				break
+			}
 		}
 		subState.Notes.Clear()
 	}
-	if c != 1 {
-		if c == 0 {
-			errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{Message: `no match for "oneof" schema`})
-		} else {
-			errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{Message: fmt.Sprintf(`%d matches for "oneof" schema`, c)})
-		}
-	} else {
+	switch {
+	case first < 0:
+		errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{Message: `no match for "oneof" schema`})
+	case second >= 0:
+		errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{Message: fmt.Sprintf(`more than one match for "oneof" schema: %d and %d`, first, second)})
+	default:
 		state.Notes.AddNotes(keepNotes)
+		if state.Opts != nil && state.Opts.OneOfAnnotations {
+			state.Annotate(first)
+		}
 	}
 	return topErr
 }
@@ -248,6 +313,55 @@ func ValidateDependentSchemas(arg schema.PartMapSchema, instance any, state *sch
 		}
 		if err := s.ValidateInPlaceSchema(instance, subState); err != nil {
 			errors2.AddError(&topErr, err, "dependentSchemas/"+name)
+			if state.FailFast() {
+				return topErr
+			}
+		} else {
+			if !subState.Notes.IsEmpty() {
+				keepNotes = append(keepNotes, subState.Notes)
+			}
+		}
+	}
+
+	if topErr == nil {
+		state.Notes.AddNotes(keepNotes...)
+	}
+
+	return topErr
+}
+
+// ValidatePropertyDependencies implements the draft-next
+// propertyDependencies keyword.
+func ValidatePropertyDependencies(arg schema.PartMapMapSchema, instance any, state *schema.ValidationState) error {
+	subState, err := state.Child()
+	if err != nil {
+		return err
+	}
+
+	var keepNotes []notes.Notes
+	var topErr error
+	// Visit the properties in order, so that the errors,
+	// and the one reported with FailFast, don't vary.
+	for _, name := range slices.Sorted(maps.Keys(arg)) {
+		m := arg[name]
+		fv, _, ok := instanceField(name, instance)
+		if !ok || fv == nil {
+			continue
+		}
+		rv := reflect.Indirect(reflect.ValueOf(fv))
+		if rv.Kind() != reflect.String {
+			continue
+		}
+		val := rv.String()
+		s, ok := m[val]
+		if !ok {
+			continue
+		}
+		if err := s.ValidateInPlaceSchema(instance, subState); err != nil {
+			errors2.AddError(&topErr, err, "propertyDependencies/"+name+"/"+val)
+			if state.FailFast() {
+				return topErr
+			}
 		} else {
 			if !subState.Notes.IsEmpty() {
 				keepNotes = append(keepNotes, subState.Notes)
@@ -272,11 +386,13 @@ type prefixItemsNote struct {
 
 // ValidatePrefixItems implements the prefixItems keyword.
 func ValidatePrefixItems(arg schema.PartSchemas, instance any, state *schema.ValidationState) error {
-	note := prefixItemsNote{
-		idx:    len(arg),
-		schema: state.Schema,
+	if needNotes(state, "items") {
+		note := prefixItemsNote{
+			idx:    len(arg),
+			schema: state.Schema,
+		}
+		notes.AppendNote(&state.Notes, "prefixItems", note)
 	}
-	notes.AppendNote(&state.Notes, "prefixItems", note)
 
 	applyDefaults := state.Opts != nil && state.Opts.ApplyDefaults
 
@@ -296,7 +412,10 @@ func ValidatePrefixItems(arg schema.PartSchemas, instance any, state *schema.Val
 				}
 			}
 
-			if err := s.ValidateSubSchema(val, state); err != nil {
+			state.PushInstanceToken(strconv.Itoa(i))
+			err := s.ValidateSubSchema(val, state)
+			state.PopInstanceToken()
+			if err != nil {
 				return err
 			}
 		}
@@ -326,7 +445,10 @@ func ValidatePrefixItems(arg schema.PartSchemas, instance any, state *schema.Val
 				}
 			}
 
-			if err := s.ValidateSubSchema(val, state); err != nil {
+			state.PushInstanceToken(strconv.Itoa(i))
+			err := s.ValidateSubSchema(val, state)
+			state.PopInstanceToken()
+			if err != nil {
 				return err
 			}
 		}
@@ -335,6 +457,44 @@ func ValidatePrefixItems(arg schema.PartSchemas, instance any, state *schema.Val
 	return nil
 }
 
+// arrayLen returns the length of instance,
+// and reports whether it is an array.
+func arrayLen(instance any) (int, bool) {
+	// Skip reflection for common slice types.
+	switch a := instance.(type) {
+	case []any:
+		return len(a), true
+	case []string:
+		return len(a), true
+	case []int:
+		return len(a), true
+	case []float64:
+		return len(a), true
+	}
+
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return 0, false
+	}
+	return v.Len(), true
+}
+
+// arrayElem returns element i of instance,
+// which arrayLen has reported to be an array.
+func arrayElem(instance any, i int) any {
+	switch a := instance.(type) {
+	case []any:
+		return a[i]
+	case []string:
+		return a[i]
+	case []int:
+		return a[i]
+	case []float64:
+		return a[i]
+	}
+	return reflect.ValueOf(instance).Index(i).Interface()
+}
+
 // ValidateItems implements the items keyword.
 func ValidateItems(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
 	idx := 0
@@ -347,38 +507,64 @@ func ValidateItems(arg schema.PartSchema, instance any, state *schema.Validation
 		}
 	}
 
-	if a, ok := instance.([]any); ok {
-		// Skip reflection in the common case of a JSON array.
+	ln, ok := arrayLen(instance)
+	if !ok {
+		return nil
+	}
 
-		if idx < len(a) {
-			state.Notes.Set("items", true)
-		}
+	if idx < ln && state.NotesNeeded() {
+		state.Notes.Set("items", true)
+	}
 
-		for ; idx < len(a); idx++ {
-			if err := arg.S.ValidateSubSchema(a[idx], state); err != nil {
-				return err
-			}
-		}
-	} else {
-		v := reflect.ValueOf(instance)
-		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
-			return nil
+	for ; idx < ln; idx++ {
+		state.PushInstanceToken(strconv.Itoa(idx))
+		err := arg.S.ValidateSubSchema(arrayElem(instance, idx), state)
+		state.PopInstanceToken()
+		if err != nil {
+			return err
 		}
+	}
 
-		ln := v.Len()
+	return nil
+}
 
-		if idx < ln {
-			state.Notes.Set("items", true)
-		}
+// ValidateItemsSchemaOrSchemas implements the items keyword of drafts
+// before 2020-12, whose argument is either a schema for every element,
+// as for the items keyword of draft 2020-12, or a list of schemas
+// for the leading elements, as for prefixItems.
+func ValidateItemsSchemaOrSchemas(arg schema.PartSchemaOrSchemas, instance any, state *schema.ValidationState) error {
+	if arg.Schema != nil {
+		return ValidateItems(schema.PartSchema{S: arg.Schema}, instance, state)
+	}
+	return ValidatePrefixItems(arg.Schemas, instance, state)
+}
 
-		for ; idx < ln; idx++ {
-			e := v.Index(idx).Interface()
-			if err := arg.S.ValidateSubSchema(e, state); err != nil {
-				return err
-			}
-		}
+// ValidateAdditionalItems implements the additionalItems keyword
+// of drafts before 2020-12. It applies to the elements after those
+// matched by an items keyword with a list of schemas,
+// and is ignored otherwise.
+func ValidateAdditionalItems(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
+	pv, ok := state.Schema.LookupKeyword("items")
+	if !ok {
+		return nil
+	}
+	items, ok := pv.(schema.PartSchemaOrSchemas)
+	if !ok || items.Schema != nil {
+		return nil
 	}
 
+	ln, ok := arrayLen(instance)
+	if !ok {
+		return nil
+	}
+	for idx := len(items.Schemas); idx < ln; idx++ {
+		state.PushInstanceToken(strconv.Itoa(idx))
+		err := arg.S.ValidateSubSchema(arrayElem(instance, idx), state)
+		state.PopInstanceToken()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -399,45 +585,104 @@ func ValidateContains(arg schema.PartSchema, instance any, state *schema.Validat
 		}
 	}
 
+	ln, ok := arrayLen(instance)
+	if !ok {
+		if state.Opts != nil && state.Opts.ObjectContains {
+			return validateObjectContains(arg, instance, state, hasMinContainsZero)
+		}
+		return nil
+	}
+
 	topOK := hasMinContainsZero
 	var matched []int
-	if s, ok := instance.([]any); ok {
-		// Skip reflection in the common case of a JSON array.
-
-		for i, e := range s {
-			if err := arg.S.ValidateSubSchema(e, state); err == nil {
-				topOK = true
-				matched = append(matched, i)
-			}
+	for i := range ln {
+		state.PushInstanceToken(strconv.Itoa(i))
+		err := arg.S.ValidateSubSchema(arrayElem(instance, i), state)
+		state.PopInstanceToken()
+		if err != nil && !errors2.IsValidationError(err) {
+			return err
 		}
-	} else {
-		v := reflect.ValueOf(instance)
-		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
-			return nil
+		if err == nil {
+			topOK = true
+			matched = append(matched, i)
 		}
+	}
 
-		ln := v.Len()
+	if !topOK {
+		return &errors2.ValidationError{
+			Message: `no array element matches "contains" schema`,
+		}
+	}
 
-		for i := 0; i < ln; i++ {
-			e := v.Index(i).Interface()
-			if err := arg.S.ValidateSubSchema(e, state); err == nil {
+	if needNotes(state, "minContains", "maxContains") {
+		notes.AppendNote(&state.Notes, "contains", matched...)
+	}
+
+	return nil
+}
+
+// validateObjectContains implements the contains keyword
+// for an object, when [schema.ValidateOpts.ObjectContains] is set.
+// The matching properties are recorded in a "containsProperties" note.
+func validateObjectContains(arg schema.PartSchema, instance any, state *schema.ValidationState, hasMinContainsZero bool) error {
+	names, ok := stateFieldNames(instance, state)
+	if !ok {
+		return nil
+	}
+
+	topOK := hasMinContainsZero
+	var matched []propertiesNote
+	for name := range names.byExactName {
+		if vf, jsonName, ok := instanceField(name, instance); ok {
+			state.PushInstanceToken(jsonName)
+			err := arg.S.ValidateSubSchema(vf, state)
+			state.PopInstanceToken()
+			if err != nil && !errors2.IsValidationError(err) {
+				return err
+			}
+			if err == nil {
 				topOK = true
-				matched = append(matched, i)
+				matched = append(matched, propertiesNote{
+					field:  jsonName,
+					schema: state.Schema,
+				})
 			}
 		}
 	}
 
 	if !topOK {
 		return &errors2.ValidationError{
-			Message: `no array element matches "contains" schema`,
+			Message: `no property value matches "contains" schema`,
 		}
 	}
 
-	notes.AppendNote(&state.Notes, "contains", matched...)
+	if needNotes(state, "minContains", "maxContains") {
+		notes.AppendNote(&state.Notes, "containsProperties", matched...)
+	}
 
 	return nil
 }
 
+// containsCount returns the number of array elements or
+// property values matched by the contains keyword, and a
+// description of what was counted for error messages.
+// It reports false if contains did not record a count.
+func containsCount(state *schema.ValidationState) (int, string, bool) {
+	if matched, ok := state.Notes.Get("contains"); ok {
+		return len(matched.([]int)), "array length", true
+	}
+	if matched, ok := state.Notes.Get("containsProperties"); ok {
+		n := 0
+		for _, note := range matched.([]propertiesNote) {
+			if note.schema == state.Schema {
+				n++
+			}
+		}
+		return n, "number of matching properties", true
+	}
+	return 0, "", false
+}
+
 // propertiesNote is the type of the node recorded for properties.
 // We need to track the field and the schema,
 // as additionalProperties looks for properties in the same types.
@@ -459,6 +704,8 @@ func ValidateProperties(arg schema.PartMapSchema, instance any, state *schema.Va
 	m, isMap := instance.(map[string]any)
 	pm, isPtrToMap := instance.(*map[string]any)
 
+	record := needNotes(state, "additionalProperties")
+
 	var topErr error
 	for name, s := range arg {
 		var (
@@ -487,11 +734,13 @@ func ValidateProperties(arg schema.PartMapSchema, instance any, state *schema.Va
 				}
 
 				// Add a note for additionalProperties to read.
-				note := propertiesNote{
-					field:  jsonName,
-					schema: state.Schema,
+				if record {
+					note := propertiesNote{
+						field:  jsonName,
+						schema: state.Schema,
+					}
+					notes.AppendNote(&state.Notes, "properties", note)
 				}
-				notes.AppendNote(&state.Notes, "properties", note)
 			}
 
 			continue
@@ -512,6 +761,7 @@ func ValidateProperties(arg schema.PartMapSchema, instance any, state *schema.Va
 				if err := setField(instance, jsonName, defaultVal); err != nil {
 					return err
 				}
+				state.InvalidateInstanceFields()
 				f = defaultVal
 			}
 		}
@@ -524,13 +774,18 @@ func ValidateProperties(arg schema.PartMapSchema, instance any, state *schema.Va
 			errors2.AddError(&topErr, err, "properties/"+name)
 		}
 		state.PopInstanceToken()
+		if topErr != nil && state.FailFast() {
+			return topErr
+		}
 
 		// Add a note for additionalProperties to read.
-		note := propertiesNote{
-			field:  jsonName,
-			schema: state.Schema,
+		if record {
+			note := propertiesNote{
+				field:  jsonName,
+				schema: state.Schema,
+			}
+			notes.AppendNote(&state.Notes, "properties", note)
 		}
-		notes.AppendNote(&state.Notes, "properties", note)
 	}
 	return topErr
 }
@@ -538,47 +793,48 @@ func ValidateProperties(arg schema.PartMapSchema, instance any, state *schema.Va
 // ValidatePatternProperties implements the patternProperties keyword.
 func ValidatePatternProperties(arg schema.PartMapSchema, instance any, state *schema.ValidationState) error {
 	// The argument is a mapping from regexp strings to schemas.
-	// Compile the regexp strings.
-	// TODO: Cache the regexp compilation somewhere?
-	type regexpSchema struct {
-		re *regexp.Regexp
-		s  *schema.Schema
-	}
-	var res []regexpSchema
-	for reString, s := range arg {
-		re, err := regexp.Compile(reString)
-		if err != nil {
-			return fmt.Errorf(`"patternProperties" regexp %q failed: %v`, reString, err)
+	// Normally the resolver has compiled the regexps.
+	var ps *patternSet
+	if pv, ok := generatedValue(&PatternPropertiesRegexpsKeyword, state); ok {
+		ps = pv.(schema.PartAny).V.(*patternSet)
+	} else {
+		var err error
+		if ps, err = compilePatternProperties(arg); err != nil {
+			return err
 		}
-		res = append(res, regexpSchema{re, s})
 	}
 
 	// Fetch all the field names found in the instance.
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
 
+	record := needNotes(state, "additionalProperties")
+
 	// For each field name in the instance, look in the regexps.
 	// If there is a match, validate against the corresponding types.
 	var topErr error
 	for name := range names.byExactName {
-		for _, r := range res {
-			if !r.re.MatchString(name) {
-				continue
-			}
-
+		for _, r := range ps.matching(name) {
 			if vf, jsonName, ok := instanceField(name, instance); ok {
+				state.PushInstanceToken(jsonName)
 				if err := r.s.ValidateSubSchema(vf, state); err != nil {
 					errors2.AddError(&topErr, err, "patternProperties/"+name)
 				}
+				state.PopInstanceToken()
+				if topErr != nil && state.FailFast() {
+					return topErr
+				}
 
 				// Add a note for additionalProperties to read.
-				note := propertiesNote{
-					field:  jsonName,
-					schema: state.Schema,
+				if record {
+					note := propertiesNote{
+						field:  jsonName,
+						schema: state.Schema,
+					}
+					notes.AppendNote(&state.Notes, "patternProperties", note)
 				}
-				notes.AppendNote(&state.Notes, "patternProperties", note)
 			}
 		}
 	}
@@ -587,7 +843,7 @@ func ValidatePatternProperties(arg schema.PartMapSchema, instance any, state *sc
 
 // ValidateAdditionalProperties implements the additionalProperties keyword.
 func ValidateAdditionalProperties(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -608,8 +864,11 @@ func ValidateAdditionalProperties(arg schema.PartSchema, instance any, state *sc
 		if found[name] {
 			continue
 		}
-		if vf, _, ok := instanceField(name, instance); ok {
-			if err := arg.S.ValidateSubSchema(vf, state); err != nil {
+		if vf, jsonName, ok := instanceField(name, instance); ok {
+			state.PushInstanceToken(jsonName)
+			err := arg.S.ValidateSubSchema(vf, state)
+			state.PopInstanceToken()
+			if err != nil {
 				var validationError *errors2.ValidationError
 				// NOTE: This should always be true?
 				if errors.As(err, &validationError) {
@@ -617,20 +876,41 @@ func ValidateAdditionalProperties(arg schema.PartSchema, instance any, state *sc
 					validationError.KeywordLocation = "#"
 				}
 				errors2.AddError(&topErr, err, "additionalProperties")
+				if state.FailFast() {
+					return topErr
+				}
 			}
 		}
-		note := propertiesNote{
-			field:  name,
-			schema: state.Schema,
+		if state.NotesNeeded() {
+			note := propertiesNote{
+				field:  name,
+				schema: state.Schema,
+			}
+			notes.AppendNote(&state.Notes, "additionalProperties", note)
 		}
-		notes.AppendNote(&state.Notes, "additionalProperties", note)
 	}
 	return topErr
 }
 
+// needNotes reports whether the notes of the keyword being
+// validated may be read: either by a keyword such as
+// unevaluatedProperties that is in scope, or by one of
+// the keywords named by readers in the same schema.
+func needNotes(state *schema.ValidationState, readers ...string) bool {
+	if state.NotesNeeded() {
+		return true
+	}
+	for _, reader := range readers {
+		if _, ok := state.Schema.LookupKeyword(reader); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidatePropertyNames implements the propertyNames keyword.
 func ValidatePropertyNames(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -638,6 +918,9 @@ func ValidatePropertyNames(arg schema.PartSchema, instance any, state *schema.Va
 	for name := range names.byExactName {
 		if err := arg.S.ValidateSubSchema(name, state); err != nil {
 			errors2.AddError(&topErr, err, "propertyNames/"+name)
+			if state.FailFast() {
+				return topErr
+			}
 		}
 	}
 	return topErr
@@ -679,7 +962,10 @@ func ValidateUnevaluatedItems(arg schema.PartSchema, instance any, state *schema
 			if slices.Contains(contains, idx) {
 				continue
 			}
-			if err := arg.S.ValidateSubSchema(a[idx], state); err != nil {
+			state.PushInstanceToken(strconv.Itoa(idx))
+			err := arg.S.ValidateSubSchema(a[idx], state)
+			state.PopInstanceToken()
+			if err != nil {
 				return err
 			}
 		}
@@ -700,7 +986,10 @@ func ValidateUnevaluatedItems(arg schema.PartSchema, instance any, state *schema
 				continue
 			}
 			e := v.Index(idx).Interface()
-			if err := arg.S.ValidateSubSchema(e, state); err != nil {
+			state.PushInstanceToken(strconv.Itoa(idx))
+			err := arg.S.ValidateSubSchema(e, state)
+			state.PopInstanceToken()
+			if err != nil {
 				return err
 			}
 		}
@@ -712,11 +1001,12 @@ func ValidateUnevaluatedItems(arg schema.PartSchema, instance any, state *schema
 // ValidateUnevaluatedProperties implements the unevaluatedProperties keyword.
 func ValidateUnevaluatedProperties(arg schema.PartSchema, instance any, state *schema.ValidationState) error {
 	// Collect all the names seen by the properties or
-	// patternProperties or additionalProperties keywords.
+	// patternProperties or additionalProperties keywords,
+	// or matched by contains when it applies to objects.
 	// The keyword sorting order must ensure that unevaluatedProperties
 	// follows those keywords.
 	found := make(map[string]bool)
-	for _, key := range []string{"properties", "patternProperties", "additionalProperties", "unevaluatedProperties"} {
+	for _, key := range []string{"properties", "patternProperties", "additionalProperties", "containsProperties", "unevaluatedProperties"} {
 		if notes, ok := state.Notes.Get(key); ok {
 			for _, note := range notes.([]propertiesNote) {
 				found[note.field] = true
@@ -724,7 +1014,7 @@ func ValidateUnevaluatedProperties(arg schema.PartSchema, instance any, state *s
 		}
 	}
 
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -734,10 +1024,15 @@ func ValidateUnevaluatedProperties(arg schema.PartSchema, instance any, state *s
 		if found[name] {
 			continue
 		}
-		if vf, _, ok := instanceField(name, instance); ok {
+		if vf, jsonName, ok := instanceField(name, instance); ok {
+			state.PushInstanceToken(jsonName)
 			if err := arg.S.ValidateSubSchema(vf, state); err != nil {
 				errors2.AddError(&topErr, err, "unevaluatedProperties/"+name)
 			}
+			state.PopInstanceToken()
+			if topErr != nil && state.FailFast() {
+				return topErr
+			}
 		}
 		note := propertiesNote{
 			field:  name,
@@ -749,73 +1044,121 @@ func ValidateUnevaluatedProperties(arg schema.PartSchema, instance any, state *s
 	return topErr
 }
 
-// ValidateType implements the type keyword.
-func ValidateType(arg schema.PartStringOrStrings, instance any, state *schema.ValidationState) error {
-	match := func(typ string) (bool, error) {
-		switch typ {
-		case "null":
-			return instance == nil, nil
-		case "boolean":
-			_, ok := instance.(bool)
-			return ok, nil
-		case "object":
-			if _, ok := instance.(map[string]any); ok {
-				// JSON object
-				return true, nil
-			}
-			if _, ok := instance.(*map[string]any); ok {
-				// JSON object
-				return true, nil
-			}
-			if instance == nil {
-				return false, nil
-			}
-			typ := reflect.TypeOf(instance)
-			if typ.Kind() == reflect.Pointer {
-				typ = typ.Elem()
-			}
-			return typ.Kind() == reflect.Struct, nil
-		case "array":
-			typ := reflect.TypeOf(instance)
-			return typ != nil && (typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice), nil
-		case "number":
-			if instance == nil {
-				return false, nil
-			}
-			switch reflect.TypeOf(instance).Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
-				reflect.Float32, reflect.Float64:
-
-				return true, nil
-			default:
-				return false, nil
-			}
-		case "string":
-			_, ok := instance.(string)
-			return ok, nil
-		case "integer":
-			if instance == nil {
-				return false, nil
-			}
-			v := reflect.ValueOf(instance)
-			if v.CanInt() || v.CanUint() {
-				return true, nil
-			}
-			if v.CanFloat() {
-				f := v.Float()
-				return math.Trunc(f) == f && !math.IsInf(f, 0), nil
-			}
+// matchType reports whether instance matches typ,
+// an argument of the type keyword.
+func matchType(typ string, instance any) (bool, error) {
+	switch typ {
+	case "null":
+		return instance == nil, nil
+	case "boolean":
+		_, ok := instance.(bool)
+		return ok, nil
+	case "object":
+		if _, ok := instance.(map[string]any); ok {
+			// JSON object
+			return true, nil
+		}
+		if _, ok := instance.(*map[string]any); ok {
+			// JSON object
+			return true, nil
+		}
+		if _, ok := instance.(map[string]string); ok {
+			return true, nil
+		}
+		if instance == nil {
 			return false, nil
+		}
+		typ := reflect.TypeOf(instance)
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		return typ.Kind() == reflect.Struct, nil
+	case "array":
+		typ := reflect.TypeOf(instance)
+		return typ != nil && (typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice), nil
+	case "number":
+		if instance == nil {
+			return false, nil
+		}
+		if n, ok := instance.(json.Number); ok {
+			_, ok := numberRat(n)
+			return ok, nil
+		}
+		switch reflect.TypeOf(instance).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return true, nil
+		case reflect.Float32, reflect.Float64:
+			// NaN and the infinities are not JSON numbers.
+			f := reflect.ValueOf(instance).Float()
+			return !math.IsInf(f, 0) && !math.IsNaN(f), nil
 		default:
-			return false, fmt.Errorf(`"type" argument is unsupported string %q`, typ)
+			return false, nil
+		}
+	case "string":
+		_, ok := instance.(string)
+		return ok, nil
+	case "integer":
+		if instance == nil {
+			return false, nil
+		}
+		if n, ok := instance.(json.Number); ok {
+			r, ok := numberRat(n)
+			return ok && r.IsInt(), nil
+		}
+		v := reflect.ValueOf(instance)
+		if v.CanInt() || v.CanUint() {
+			return true, nil
+		}
+		if v.CanFloat() {
+			f := v.Float()
+			return math.Trunc(f) == f && !math.IsInf(f, 0), nil
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf(`"type" argument is unsupported string %q`, typ)
+	}
+}
+
+// checkNumber reports a json.Number instance that is not a number
+// that can be validated, rather than reporting that it is not of
+// the type "number".
+func checkNumber(n json.Number) error {
+	if _, ok := numberRat(n); ok {
+		return nil
+	}
+	if !isNumberText(n) {
+		return &errors2.ValidationError{
+			Message: fmt.Sprintf("instance %q is not a valid JSON number", string(n)),
 		}
 	}
+	return &errors2.ValidationError{
+		Message: fmt.Sprintf("instance %s has an exponent out of range", n),
+	}
+}
+
+// ValidateType implements the type keyword.
+func ValidateType(arg schema.PartStringOrStrings, instance any, state *schema.ValidationState) error {
+	if n, ok := instance.(json.Number); ok {
+		if err := checkNumber(n); err != nil {
+			return err
+		}
+	}
+
+	match := func(typ string) (bool, error) {
+		return matchType(typ, instance)
+	}
 
 	typeForError := func(instance any) string {
 		if instance == nil {
 			return "null"
 		}
+		if n, ok := instance.(json.Number); ok {
+			if r, ok := numberRat(n); ok && r.IsInt() {
+				return "integer"
+			}
+			return "number"
+		}
 
 		switch reflect.TypeOf(instance).Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
@@ -823,7 +1166,10 @@ func ValidateType(arg schema.PartStringOrStrings, instance any, state *schema.Va
 			return "integer"
 		case reflect.Float32, reflect.Float64:
 			f := reflect.ValueOf(instance).Float()
-			if math.Trunc(f) == f && !math.IsInf(f, 0) {
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return "non-finite number"
+			}
+			if math.Trunc(f) == f {
 				return "integer"
 			}
 			return "number"
@@ -869,14 +1215,12 @@ func ValidateType(arg schema.PartStringOrStrings, instance any, state *schema.Va
 
 // ValidateEnum implements the enum keyword.
 func ValidateEnum(arg schema.PartAny, instance any, state *schema.ValidationState) error {
-	// TODO: we have to be able to compare a map[string]any to a struct,
-	// and a []any to a slice of some other type.
 	s, ok := arg.V.([]any)
 	if !ok {
 		return fmt.Errorf(`"enum" argument is %T, must be []any`, arg.V)
 	}
 	for _, e := range s {
-		if reflect.DeepEqual(instance, e) {
+		if equalJSON(instance, e) {
 			return nil
 		}
 	}
@@ -887,9 +1231,7 @@ func ValidateEnum(arg schema.PartAny, instance any, state *schema.ValidationStat
 
 // ValidateConst implements the const keyword.
 func ValidateConst(arg schema.PartAny, instance any, state *schema.ValidationState) error {
-	// TODO: we have to be able to compare a map[string]any to a struct,
-	// and a []any to a slice of some other type.
-	if !reflect.DeepEqual(instance, arg.V) {
+	if !equalJSON(instance, arg.V) {
 		return &errors2.ValidationError{
 			Message: fmt.Sprintf(`"const" failed: got %v, want %v`, instance, arg.V),
 		}
@@ -899,6 +1241,42 @@ func ValidateConst(arg schema.PartAny, instance any, state *schema.ValidationSta
 
 // ValidateMultipleOf implements the multipleOf keyword.
 func ValidateMultipleOf(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
+	if err := checkFinite(instance); err != nil {
+		return err
+	}
+	d, exact := limitValue("multipleOf", arg, state)
+	r, ok := exactNumber(instance)
+	if !ok && exact {
+		f, fok := instanceFloat(instance)
+		if !fok {
+			return nil
+		}
+		r, ok = floatRat(f, 64), true
+	}
+	if ok {
+		// Divide exactly, so that a large or precise
+		// number is not rounded.
+		if d == nil || d.Sign() == 0 {
+			return nil
+		}
+		var multiple bool
+		if r != nil {
+			multiple = r.Quo(r, d).IsInt()
+		} else {
+			n := instance.(json.Number)
+			m, e, ok := numberParts(n)
+			if !ok {
+				return checkNumber(n)
+			}
+			multiple = isMultiple(m, e, d)
+		}
+		if !multiple {
+			return &errors2.ValidationError{
+				Message: fmt.Sprintf(`"multipleof" failed: value %v is not a multiple of %v`, instance, arg),
+			}
+		}
+		return nil
+	}
 	f, ok := instanceFloat(instance)
 	if !ok {
 		return nil
@@ -914,11 +1292,14 @@ func ValidateMultipleOf(arg schema.PartFloat, instance any, state *schema.Valida
 
 // ValidateMaximum implements the maximum keyword.
 func ValidateMaximum(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
-	f, ok := instanceFloat(instance)
+	if err := checkFinite(instance); err != nil {
+		return err
+	}
+	c, ok, err := compareLimit(instance, "maximum", arg, state)
 	if !ok {
-		return nil
+		return err
 	}
-	if schema.PartFloat(f) > arg {
+	if c > 0 {
 		return &errors2.ValidationError{
 			Message: fmt.Sprintf(`value %v is larger than "maximum" limit %v`, instance, arg),
 		}
@@ -928,11 +1309,20 @@ func ValidateMaximum(arg schema.PartFloat, instance any, state *schema.Validatio
 
 // ValidateExclusiveMaximum implements the exclusiveMaximum keyword.
 func ValidateExclusiveMaximum(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
-	f, ok := instanceFloat(instance)
+	return exclusiveMaximum("exclusiveMaximum", arg, instance, state)
+}
+
+// exclusiveMaximum checks that instance is less than arg,
+// the argument of keyword.
+func exclusiveMaximum(keyword string, arg schema.PartFloat, instance any, state *schema.ValidationState) error {
+	if err := checkFinite(instance); err != nil {
+		return err
+	}
+	c, ok, err := compareLimit(instance, keyword, arg, state)
 	if !ok {
-		return nil
+		return err
 	}
-	if schema.PartFloat(f) >= arg {
+	if c >= 0 {
 		return &errors2.ValidationError{
 			Message: fmt.Sprintf(`value %v is larger than "exclusiveMaximum" limit %v`, instance, arg),
 		}
@@ -942,11 +1332,14 @@ func ValidateExclusiveMaximum(arg schema.PartFloat, instance any, state *schema.
 
 // ValidateMinimum implements the minimum keyword.
 func ValidateMinimum(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
-	f, ok := instanceFloat(instance)
+	if err := checkFinite(instance); err != nil {
+		return err
+	}
+	c, ok, err := compareLimit(instance, "minimum", arg, state)
 	if !ok {
-		return nil
+		return err
 	}
-	if schema.PartFloat(f) < arg {
+	if c < 0 {
 		return &errors2.ValidationError{
 			Message: fmt.Sprintf(`value %v is larger than "minimum" limit %v`, instance, arg),
 		}
@@ -956,11 +1349,20 @@ func ValidateMinimum(arg schema.PartFloat, instance any, state *schema.Validatio
 
 // ValidateExclusiveMinimum implements the exclusiveMinimum keyword.
 func ValidateExclusiveMinimum(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
-	f, ok := instanceFloat(instance)
+	return exclusiveMinimum("exclusiveMinimum", arg, instance, state)
+}
+
+// exclusiveMinimum checks that instance is greater than arg,
+// the argument of keyword.
+func exclusiveMinimum(keyword string, arg schema.PartFloat, instance any, state *schema.ValidationState) error {
+	if err := checkFinite(instance); err != nil {
+		return err
+	}
+	c, ok, err := compareLimit(instance, keyword, arg, state)
 	if !ok {
-		return nil
+		return err
 	}
-	if schema.PartFloat(f) <= arg {
+	if c <= 0 {
 		return &errors2.ValidationError{
 			Message: fmt.Sprintf(`value %v is larger than "exclusiveMinimum" limit %v`, instance, arg),
 		}
@@ -968,13 +1370,54 @@ func ValidateExclusiveMinimum(arg schema.PartFloat, instance any, state *schema.
 	return nil
 }
 
+// ValidateMaximumDraft04 implements the maximum keyword of draft-04,
+// in which the limit is exclusive if the exclusiveMaximum keyword
+// of the same schema is true.
+func ValidateMaximumDraft04(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
+	if siblingTrue("exclusiveMaximum", state) {
+		return exclusiveMaximum("maximum", arg, instance, state)
+	}
+	return ValidateMaximum(arg, instance, state)
+}
+
+// ValidateMinimumDraft04 implements the minimum keyword of draft-04,
+// in which the limit is exclusive if the exclusiveMinimum keyword
+// of the same schema is true.
+func ValidateMinimumDraft04(arg schema.PartFloat, instance any, state *schema.ValidationState) error {
+	if siblingTrue("exclusiveMinimum", state) {
+		return exclusiveMinimum("minimum", arg, instance, state)
+	}
+	return ValidateMinimum(arg, instance, state)
+}
+
+// exclusiveDraft04 reports whether the boolean keyword name
+// of the schema being validated is true.
+func siblingTrue(name string, state *schema.ValidationState) bool {
+	pv, ok := state.Schema.LookupKeyword(name)
+	if !ok {
+		return false
+	}
+	b, ok := pv.(schema.PartBool)
+	return ok && bool(b)
+}
+
+// ValidateTypeNullable implements the type keyword of OpenAPI 3.0,
+// which also permits null if the nullable keyword of the same
+// schema is true.
+func ValidateTypeNullable(arg schema.PartStringOrStrings, instance any, state *schema.ValidationState) error {
+	if instance == nil && siblingTrue("nullable", state) {
+		return nil
+	}
+	return ValidateType(arg, instance, state)
+}
+
 // ValidateMaxLength implements the maxLength keyword.
 func ValidateMaxLength(arg schema.PartInt, instance any, state *schema.ValidationState) error {
 	if arg < 0 {
 		return fmt.Errorf(`"maxLength" argument is %d, must be non-negative`, arg)
 	}
 	if s, ok := instance.(string); ok {
-		if schema.PartInt(utf8.RuneCountInString(s)) > arg {
+		if schema.PartInt(state.RuneCount(s)) > arg {
 			return &errors2.ValidationError{
 				Message: fmt.Sprintf(`value %q too long for "maxLength" argument %d`, s, arg),
 			}
@@ -989,7 +1432,7 @@ func ValidateMinLength(arg schema.PartInt, instance any, state *schema.Validatio
 		return fmt.Errorf(`"maxLength" argument is %d, must be non-negative`, arg)
 	}
 	if s, ok := instance.(string); ok {
-		if schema.PartInt(utf8.RuneCountInString(s)) < arg {
+		if schema.PartInt(state.RuneCount(s)) < arg {
 			return &errors2.ValidationError{
 				Message: fmt.Sprintf(`value %q too short for "minLength" argument %d`, s, arg),
 			}
@@ -1005,7 +1448,8 @@ func ValidatePattern(arg schema.PartString, instance any, state *schema.Validati
 		return nil
 	}
 
-	re, err := regexp.Compile(string(arg))
+	// Normally the resolver has compiled the regexp.
+	re, err := CompilePattern(arg)
 	if err != nil {
 		return fmt.Errorf(`"pattern" regexp %q failed: %v`, arg, err)
 	}
@@ -1021,15 +1465,9 @@ func ValidatePattern(arg schema.PartString, instance any, state *schema.Validati
 
 // ValidateMaxItems implements the maxItems keyword.
 func ValidateMaxItems(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	var ln int
-	if a, ok := instance.([]any); ok {
-		ln = len(a)
-	} else {
-		v := reflect.ValueOf(instance)
-		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
-			return nil
-		}
-		ln = v.Len()
+	ln, ok := arrayLen(instance)
+	if !ok {
+		return nil
 	}
 
 	if schema.PartInt(ln) > arg {
@@ -1043,15 +1481,9 @@ func ValidateMaxItems(arg schema.PartInt, instance any, state *schema.Validation
 
 // ValidateMinItems implements the minItems keyword.
 func ValidateMinItems(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	var ln int
-	if a, ok := instance.([]any); ok {
-		ln = len(a)
-	} else {
-		v := reflect.ValueOf(instance)
-		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
-			return nil
-		}
-		ln = v.Len()
+	ln, ok := arrayLen(instance)
+	if !ok {
+		return nil
 	}
 
 	if schema.PartInt(ln) < arg {
@@ -1063,12 +1495,37 @@ func ValidateMinItems(arg schema.PartInt, instance any, state *schema.Validation
 	return nil
 }
 
+// uniqueComparable implements the uniqueItems keyword
+// for a slice of a comparable type.
+func uniqueComparable[T comparable](a []T) error {
+	m := make(map[T]bool, len(a))
+	for _, e := range a {
+		if m[e] {
+			return &errors2.ValidationError{
+				Message: fmt.Sprintf(`"uniqueItems" failure: %v appears more than once`, e),
+			}
+		}
+		m[e] = true
+	}
+	return nil
+}
+
 // ValidateUniqueItems implements the uniqueItems keyword.
 func ValidateUniqueItems(arg schema.PartBool, instance any, state *schema.ValidationState) error {
 	if !arg {
 		return nil
 	}
 
+	// Skip reflection for common slice types.
+	switch a := instance.(type) {
+	case []string:
+		return uniqueComparable(a)
+	case []int:
+		return uniqueComparable(a)
+	case []float64:
+		return uniqueComparable(a)
+	}
+
 	v := reflect.ValueOf(instance)
 	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
 		return nil
@@ -1095,6 +1552,12 @@ func ValidateUniqueItems(arg schema.PartBool, instance any, state *schema.Valida
 			}
 			m[evi] = true
 		}
+	} else if dup, ok := duplicateByHash(v); ok {
+		if dup >= 0 {
+			return &errors2.ValidationError{
+				Message: fmt.Sprintf(`"uniqueItems" failure: %v appears more than once`, v.Index(dup).Interface()),
+			}
+		}
 	} else {
 		for i := 0; i < ln; i++ {
 			for j := i + 1; j < ln; j++ {
@@ -1110,13 +1573,39 @@ func ValidateUniqueItems(arg schema.PartBool, instance any, state *schema.Valida
 	return nil
 }
 
+// duplicateByHash looks for an element of the slice or array v that
+// is equal to an earlier one, as reflect.DeepEqual reports, without
+// comparing every pair of elements. Elements are hashed by their JSON
+// encoding, which is the same for equal elements, as json.Marshal
+// sorts the keys of maps; only elements with the same hash are
+// compared. It returns the index of the later element, or -1 if
+// there is none. The bool result is false if an element
+// can't be encoded, such as one that contains a cycle.
+func duplicateByHash(v reflect.Value) (int, bool) {
+	seed := maphash.MakeSeed()
+	seen := make(map[uint64][]int)
+	for i := range v.Len() {
+		data, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			return 0, false
+		}
+		h := maphash.Bytes(seed, data)
+		for _, j := range seen[h] {
+			if reflect.DeepEqual(v.Index(j).Interface(), v.Index(i).Interface()) {
+				return i, true
+			}
+		}
+		seen[h] = append(seen[h], i)
+	}
+	return -1, true
+}
+
 // ValidateMaxContains implements the maxContains keyword.
 func ValidateMaxContains(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	if matched, ok := state.Notes.Get("contains"); ok {
-		ln := len(matched.([]int))
+	if ln, what, ok := containsCount(state); ok {
 		if schema.PartInt(ln) > arg {
 			return &errors2.ValidationError{
-				Message: fmt.Sprintf(`array length %d is more than "maxContains" requirement %d`, ln, arg),
+				Message: fmt.Sprintf(`%s %d is more than "maxContains" requirement %d`, what, ln, arg),
 			}
 		}
 	}
@@ -1125,11 +1614,10 @@ func ValidateMaxContains(arg schema.PartInt, instance any, state *schema.Validat
 
 // ValidateMinContains implements the minContains keyword.
 func ValidateMinContains(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	if matched, ok := state.Notes.Get("contains"); ok {
-		ln := len(matched.([]int))
+	if ln, what, ok := containsCount(state); ok {
 		if schema.PartInt(ln) < arg {
 			return &errors2.ValidationError{
-				Message: fmt.Sprintf(`array length %d is less than "minContains" requirement %d`, ln, arg),
+				Message: fmt.Sprintf(`%s %d is less than "minContains" requirement %d`, what, ln, arg),
 			}
 		}
 	}
@@ -1138,7 +1626,7 @@ func ValidateMinContains(arg schema.PartInt, instance any, state *schema.Validat
 
 // ValidateMaxProperties implements the maxProperties keyword.
 func ValidateMaxProperties(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -1151,9 +1639,18 @@ func ValidateMaxProperties(arg schema.PartInt, instance any, state *schema.Valid
 	return nil
 }
 
+// deferRequired reports whether the keywords that require
+// properties always match, as for an incomplete document.
+func deferRequired(state *schema.ValidationState) bool {
+	return state.Opts != nil && state.Opts.DeferRequired
+}
+
 // ValidateMinProperties implements the minProperties keyword.
 func ValidateMinProperties(arg schema.PartInt, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	if deferRequired(state) {
+		return nil
+	}
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -1168,7 +1665,10 @@ func ValidateMinProperties(arg schema.PartInt, instance any, state *schema.Valid
 
 // ValidateRequired implements the required keyword.
 func ValidateRequired(arg schema.PartStrings, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	if deferRequired(state) {
+		return nil
+	}
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -1180,6 +1680,9 @@ func ValidateRequired(arg schema.PartStrings, instance any, state *schema.Valida
 				Message: fmt.Sprintf("missing required property %q", s),
 			}
 			errors2.AddError(&topErr, err, "required")
+			if state.FailFast() {
+				return topErr
+			}
 		}
 	}
 	return topErr
@@ -1191,8 +1694,11 @@ func ValidateDependentRequired(arg schema.PartAny, instance any, state *schema.V
 	if !ok {
 		return fmt.Errorf(`"dependentRequired" argument type %T, want map[string]any`, arg)
 	}
+	if deferRequired(state) {
+		return nil
+	}
 
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -1249,9 +1755,17 @@ func ValidateFormat(arg schema.PartString, instance any, state *schema.Validatio
 		return nil
 	}
 
-	formatValidatorsLock.Lock()
-	defer formatValidatorsLock.Unlock()
-	fv := formatValidators[string(arg)]
+	var fv formatValidator
+	if env := state.Opts.Environment; env != nil {
+		if efv, ok := env.LookupFormat(string(arg)); ok {
+			fv = formatValidator(efv)
+		}
+	}
+	if fv == nil {
+		formatValidatorsLock.Lock()
+		fv = formatValidators[string(arg)]
+		formatValidatorsLock.Unlock()
+	}
 	if fv == nil {
 		return nil
 	}
@@ -1270,12 +1784,167 @@ func ValidateDefault(arg schema.PartAny, instance any, state *schema.ValidationS
 	return nil
 }
 
+// checkFinite returns a validation error if instance is
+// a floating-point NaN or infinity, which are not JSON numbers
+// and can't be compared with the arguments of keywords.
+func checkFinite(instance any) error {
+	v := reflect.ValueOf(instance)
+	if !v.CanFloat() {
+		return nil
+	}
+	if f := v.Float(); math.IsInf(f, 0) || math.IsNaN(f) {
+		return &errors2.ValidationError{
+			Message: fmt.Sprintf("value %v is not a finite number", f),
+		}
+	}
+	return nil
+}
+
+// compareLimit compares instance, if it is a number, with limit,
+// the argument of keyword, returning -1, 0 or +1 as the instance
+// is less than, equal to or greater than the limit. A number that
+// a float64 can't represent is compared exactly; see exactNumber.
+// So is a limit that is exact; see limitValue.
+// It reports false if instance is not a number, or is NaN;
+// the error is set if instance is a json.Number that is not
+// a valid number, which fails any limit.
+func compareLimit(instance any, keyword string, limit schema.PartFloat, state *schema.ValidationState) (int, bool, error) {
+	l, exact := limitValue(keyword, limit, state)
+	r, ok := exactNumber(instance)
+	if !ok {
+		f, ok := instanceFloat(instance)
+		if !ok || math.IsNaN(f) {
+			return 0, false, nil
+		}
+		if !exact {
+			return cmp.Compare(f, float64(limit)), true, nil
+		}
+		if r = floatRat(f, 64); r == nil {
+			return 0, false, nil
+		}
+	}
+	if l == nil {
+		return 0, false, nil
+	}
+	if r != nil {
+		return r.Cmp(l), true, nil
+	}
+	n := instance.(json.Number)
+	m, e, ok := numberParts(n)
+	if !ok {
+		return 0, false, checkNumber(n)
+	}
+	return compareParts(m, e, l), true, nil
+}
+
+// limitValue returns the value of arg, the argument of keyword,
+// or nil if it is not finite. It reports whether the value is
+// exact: an integer written in the schema that arg, a float64,
+// only approximates, such as 18446744073709551615.
+func limitValue(keyword string, arg schema.PartFloat, state *schema.ValidationState) (*big.Rat, bool) {
+	f := float64(arg)
+	// Only an integer of at least 2^53 may be approximated.
+	if state != nil && state.Schema != nil && math.Abs(f) >= 1<<53 {
+		if i, ok := state.Schema.ExactNumber(keyword, f); ok {
+			return new(big.Rat).SetInt(i), true
+		}
+	}
+	return floatRat(f, 64), false
+}
+
+// limitDigits bounds the magnitude of the limits of
+// compareParts: a float64 is less than 10^309, and a nonzero
+// float64 is at least 10^-324.
+const limitDigits = 400
+
+// compareParts compares m × 10^e with l, which is a float64,
+// as compareLimit does. If the number is far larger or smaller
+// than any float64, it is compared by its sign, without building
+// its exact value, whose exponent may be very large.
+func compareParts(m *big.Int, e int, l *big.Rat) int {
+	if m.Sign() == 0 {
+		return -l.Sign()
+	}
+	// The number is at least 10^(mag-1) and less than 10^mag.
+	mag := e + len(new(big.Int).Abs(m).String())
+	switch {
+	case mag > limitDigits:
+		return m.Sign()
+	case mag < -limitDigits:
+		// The number is nearer to zero than l, unless l is zero.
+		if l.Sign() == 0 {
+			return m.Sign()
+		}
+		return -l.Sign()
+	}
+	return exactParts(m, e).Cmp(l)
+}
+
+// exactParts returns m × 10^e.
+func exactParts(m *big.Int, e int) *big.Rat {
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(e, -e))), nil)
+	if e < 0 {
+		return new(big.Rat).SetFrac(m, p)
+	}
+	return new(big.Rat).SetInt(p.Mul(p, m))
+}
+
+// isMultiple reports whether m × 10^e is an integer multiple of d,
+// which is not zero, without building the number, whose exponent
+// may be very large.
+func isMultiple(m *big.Int, e int, d *big.Rat) bool {
+	// The quotient is m × q × 10^e / p, where d is p/q.
+	p := new(big.Int).Abs(d.Num())
+	num := new(big.Int).Mul(m, d.Denom())
+	if num.Sign() == 0 {
+		return true
+	}
+	if e >= 0 {
+		// The quotient is an integer if p divides num × 10^e,
+		// which is so if the product is 0 modulo p.
+		t := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(e)), p)
+		return t.Mul(t, num).Mod(t, p).Sign() == 0
+	}
+	// If num has no more than -e digits, it is less than
+	// 10^-e, so the quotient is less than 1.
+	if len(new(big.Int).Abs(num).String()) <= -e {
+		return false
+	}
+	den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-e)), nil)
+	return num.Mod(num, den.Mul(den, p)).Sign() == 0
+}
+
+// exactNumber returns the exact value of instance, if it is a number
+// that may lose precision as a float64: a [json.Number], or an
+// integer of more than 53 bits, such as a large uint64.
+// The value is nil if instance is a json.Number that numberRat
+// can't represent; numberParts splits such a number.
+func exactNumber(instance any) (*big.Rat, bool) {
+	if n, ok := instance.(json.Number); ok {
+		r, _ := numberRat(n)
+		return r, true
+	}
+	const maxExact = 1 << 53
+	v := reflect.ValueOf(instance)
+	switch {
+	case v.CanInt():
+		if i := v.Int(); i > maxExact || i < -maxExact {
+			return new(big.Rat).SetInt64(i), true
+		}
+	case v.CanUint():
+		if u := v.Uint(); u > maxExact {
+			return new(big.Rat).SetUint64(u), true
+		}
+	}
+	return nil, false
+}
+
 // instanceFloat returns instance as a floating-point number,
 // and reports whether the conversion succeeded.
 func instanceFloat(instance any) (float64, bool) {
 	if s, ok := instance.(string); ok {
 		f, err := strconv.ParseFloat(s, 64)
-		if err != nil {
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
 			return 0, false
 		}
 		return f, true
@@ -1297,7 +1966,7 @@ func instanceFloat(instance any) (float64, bool) {
 // ValidateDependencies validates the draft7 dependencies keyword.
 // This is also used for later drafts, as an optional feature.
 func ValidateDependencies(arg schema.PartMapArrayOrSchema, instance any, state *schema.ValidationState) error {
-	names, ok := instanceFieldNames(instance)
+	names, ok := stateFieldNames(instance, state)
 	if !ok {
 		return nil
 	}
@@ -1317,6 +1986,9 @@ func ValidateDependencies(arg schema.PartMapArrayOrSchema, instance any, state *
 		if as.Schema != nil {
 			if err := as.Schema.ValidateInPlaceSchema(instance, subState); err != nil {
 				errors2.AddError(&topErr, err, "dependencies/"+name)
+				if state.FailFast() {
+					return topErr
+				}
 			} else {
 				if !subState.Notes.IsEmpty() {
 					keepNotes = append(keepNotes, subState.Notes)
@@ -0,0 +1,215 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in draft 2020-12.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+func TestTypeNumber(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance json.Number
+		// err is part of the error, or "" if valid.
+		err string
+	}{
+		{`{"type": "integer"}`, "9007199254740993", ""},
+		{`{"type": "integer"}`, "1e2", ""},
+		{`{"type": "integer"}`, "1.5", `has type "number", want "integer"`},
+		{`{"type": "number"}`, "12", ""},
+		{`{"type": "number"}`, "abc", `"abc" is not a valid JSON number`},
+		{`{"type": "number"}`, "1e99999", "exponent out of range"},
+		{`{"const": 9007199254740993}`, "9007199254740993", ""},
+		{`{"const": 9007199254740993}`, "9007199254740992", `"const" failed`},
+	}
+	for _, test := range tests {
+		err := decode(t, test.schema).Validate(test.instance)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s with %s: unexpected error %v", test.schema, test.instance, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s with %s: got error %v, want %q", test.schema, test.instance, err, test.err)
+		}
+	}
+}
+
+// TestLargeExponents checks that the numeric keywords compare
+// json.Number instances whose exponents are too large to compute
+// their exact values, and fail those that are not numbers.
+func TestLargeExponents(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance json.Number
+		// err is part of the error, or "" if valid.
+		err string
+	}{
+		{`{"maximum": 9007199254740992}`, "1e20000", `larger than "maximum"`},
+		{`{"maximum": 9007199254740992}`, "-1e20000", ""},
+		{`{"maximum": 9007199254740992}`, "1E+20000", `larger than "maximum"`},
+		{`{"exclusiveMaximum": 0}`, "1e-20000", `"exclusiveMaximum"`},
+		{`{"exclusiveMaximum": 0}`, "-1e-20000", ""},
+		{`{"exclusiveMaximum": 1e-300}`, "1e-20000", ""},
+		{`{"minimum": -1e300}`, "-1e20000", `"minimum"`},
+		{`{"minimum": 0}`, "0e99999", ""},
+		{`{"exclusiveMinimum": 0}`, "0e99999", `"exclusiveMinimum"`},
+		// The exponent is large, but the value is not.
+		{`{"maximum": 2}`, "0." + json.Number(strings.Repeat("0", 20000)) + "1e20001", ""},
+		{`{"maximum": 0.5}`, "0." + json.Number(strings.Repeat("0", 20000)) + "1e20001", `larger than "maximum"`},
+		{`{"maximum": 1}`, "1e99999999999999999999", "exponent out of range"},
+		{`{"minimum": 1}`, "abc", "not a valid JSON number"},
+		{`{"multipleOf": 3}`, "3e20000", ""},
+		{`{"multipleOf": 3}`, "1e20000", `"multipleof" failed`},
+		{`{"multipleOf": 0.5}`, "1e20000", ""},
+		{`{"multipleOf": 0.5}`, "1e-20000", `"multipleof" failed`},
+		{`{"multipleOf": 1}`, "1" + json.Number(strings.Repeat("0", 20000)) + "e-20000", ""},
+		{`{"multipleOf": 1}`, "0e-20000", ""},
+		{`{"multipleOf": 1}`, "abc", "not a valid JSON number"},
+	}
+	for _, test := range tests {
+		s := decode(t, test.schema)
+		check := func(kind string, err error) {
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("%s with %.40s (%s): unexpected error %v", test.schema, test.instance, kind, err)
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("%s with %.40s (%s): got error %.200v, want %q", test.schema, test.instance, kind, err, test.err)
+			}
+		}
+		check("Validate", s.Validate(test.instance))
+		if test.instance != "abc" {
+			check("ValidateBytes", s.ValidateBytes([]byte(test.instance)))
+		}
+	}
+}
+
+// TestMultipleOf checks that multipleOf divides json.Number instances
+// exactly, by the decimal value of the divisor, and float64 instances
+// in floating point.
+func TestMultipleOf(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		// numErr and floatErr are part of the error for the instance
+		// as a json.Number and as a float64, or "" if valid.
+		numErr, floatErr string
+	}{
+		{`{"multipleOf": 0.1}`, "0.3", "", `"multipleof" failed`},
+		{`{"multipleOf": 0.1}`, "-0.3", "", `"multipleof" failed`},
+		{`{"multipleOf": 0.1}`, "0.35", `"multipleof" failed`, `"multipleof" failed`},
+		{`{"multipleOf": 0.1}`, "0", "", ""},
+		{`{"multipleOf": 0.01}`, "19.99", "", `"multipleof" failed`},
+		{`{"multipleOf": 0.01}`, "19.995", `"multipleof" failed`, `"multipleof" failed`},
+		{`{"multipleOf": 0.0001}`, "0.0075", "", ""},
+		{`{"multipleOf": 1e-300}`, "3e-300", "", ""},
+		{`{"multipleOf": 1e-300}`, "3.5e-300", `"multipleof" failed`, `"multipleof" failed`},
+		{`{"multipleOf": 5e-324}`, "1e-323", "", ""},
+		{`{"multipleOf": 1e300}`, "2e300", "", ""},
+		{`{"multipleOf": 1e300}`, "2.5e300", `"multipleof" failed`, `"multipleof" failed`},
+		{`{"multipleOf": 0.1}`, "1e300", "", ""},
+		{`{"multipleOf": 1e308}`, "1.7e308", `"multipleof" failed`, `"multipleof" failed`},
+		// A float64 can't represent the instance, which is a multiple of 3.
+		{`{"multipleOf": 3}`, "9007199254740993", "", `"multipleof" failed`},
+		// The divisor is the exact value of the literal.
+		{`{"multipleOf": 18446744073709551615}`, "36893488147419103230", "", `"multipleof" failed`},
+		{`{"multipleOf": 18446744073709551615}`, "36893488147419103232", `"multipleof" failed`, `"multipleof" failed`},
+	}
+	for _, test := range tests {
+		s := decode(t, test.schema)
+		check := func(kind, want string, err error) {
+			switch {
+			case want == "" && err != nil:
+				t.Errorf("%s with %s (%s): unexpected error %v", test.schema, test.instance, kind, err)
+			case want != "" && (err == nil || !strings.Contains(err.Error(), want)):
+				t.Errorf("%s with %s (%s): got error %v, want %q", test.schema, test.instance, kind, err, want)
+			}
+		}
+		check("json.Number", test.numErr, s.Validate(json.Number(test.instance)))
+		check("ValidateBytes", test.numErr, s.ValidateBytes([]byte(test.instance)))
+		f, err := strconv.ParseFloat(test.instance, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check("float64", test.floatErr, s.Validate(f))
+	}
+}
+
+// TestStringLength checks minLength and maxLength of strings long
+// enough for the rune count to be cached, including strings of the
+// same length in bytes but not in runes.
+func TestStringLength(t *testing.T) {
+	s := decode(t, `{"items": {"minLength": 100, "maxLength": 100}}`)
+	ascii := strings.Repeat("a", 200)
+	wide := strings.Repeat("é", 100)
+	tests := []struct {
+		instance []any
+		valid    bool
+	}{
+		{[]any{wide}, true},
+		{[]any{ascii}, false},
+		{[]any{wide, ascii}, false},
+		{[]any{ascii[:100], wide, ascii[100:]}, true},
+		{[]any{wide, wide[:198] + "ab"}, false},
+	}
+	for i, test := range tests {
+		if err := s.Validate(test.instance); (err == nil) != test.valid {
+			t.Errorf("#%d: got error %v, want valid %t", i, err, test.valid)
+		}
+	}
+}
+
+// substring is a regexp engine in which a pattern
+// matches the strings that contain it.
+type substring string
+
+func (s substring) MatchString(str string) bool {
+	return strings.Contains(str, string(s))
+}
+
+// TestPatternPropertiesEngine checks patternProperties with
+// a regexp engine other than the regexp package.
+func TestPatternPropertiesEngine(t *testing.T) {
+	old := schema.SetRegexpCompiler(func(pattern string) (schema.Regexp, error) {
+		return substring(pattern), nil
+	})
+	defer schema.SetRegexpCompiler(old)
+
+	s := decode(t, `{
+		"patternProperties": {
+			"a(": {"type": "integer"},
+			"b)": {"type": "string"}
+		}
+	}`)
+	tests := []struct {
+		instance map[string]any
+		valid    bool
+	}{
+		{map[string]any{"xa(": 1.0, "b)y": "s"}, true},
+		{map[string]any{"xa(": "s"}, false},
+		{map[string]any{"b)y": 1.0}, false},
+		{map[string]any{"a": "s", "b": 1.0}, true},
+	}
+	for _, test := range tests {
+		if err := s.Validate(test.instance); (err == nil) != test.valid {
+			t.Errorf("%v: got error %v, want valid %t", test.instance, err, test.valid)
+		}
+	}
+}
@@ -255,3 +255,25 @@ func ToAny(arg schema.PartValue) (schema.PartAny, error) {
 	}
 	return v, nil
 }
+
+// ArgTypeMapMapSchema converts a validator function that accepts
+// [types.ArgTypeMapMapSchema] to one that can be stored in a [types.Keyword].
+func ArgTypeMapMapSchema(fn func(arg schema.PartMapMapSchema, instance any, state *schema.ValidationState) error) func(schema.PartValue, any, *schema.ValidationState) error {
+	return func(arg schema.PartValue, instance any, state *schema.ValidationState) error {
+		v, err := ToMapMapSchema(arg)
+		if err != nil {
+			return err
+		}
+		return fn(v, instance, state)
+	}
+}
+
+// ToMapMapSchema converts arg into a [types.PartMapMapSchema].
+func ToMapMapSchema(arg schema.PartValue) (schema.PartMapMapSchema, error) {
+	v, ok := arg.(schema.PartMapMapSchema)
+	if !ok {
+		var zero schema.PartMapMapSchema
+		return zero, fmt.Errorf("got %T, expect a mapping from strings to mappings from strings to schemas", arg)
+	}
+	return v, nil
+}
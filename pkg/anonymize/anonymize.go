@@ -0,0 +1,484 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package anonymize hides the business details of a schema, so that
+// a proprietary schema can be shared, for example in a bug report,
+// while it still behaves as the original does.
+//
+// An [Anonymizer] replaces property names, the names of definitions,
+// titles, descriptions, comments, and string values in enum, const,
+// default and examples, by generated names such as "prop1", "Def2"
+// and "value3". The same string is always replaced by the same name,
+// so that the required keyword still names the properties of the
+// properties keyword, and a $ref to "#/$defs/Address" becomes a
+// $ref to the renamed definition. Instances can be anonymized
+// with the same names, and the [Mapping] restores the originals.
+//
+// Patterns, formats, numbers and the URIs of $id and $ref are not
+// changed, nor are the arguments of keywords that are not in the
+// vocabulary.
+package anonymize
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// A kind is a kind of string that is replaced.
+type kind int
+
+const (
+	kindProperty kind = iota
+	kindDef
+	kindText
+	kindValue
+)
+
+// prefixes are the prefixes of the generated names of each kind.
+var prefixes = [...]string{
+	kindProperty: "prop",
+	kindDef:      "Def",
+	kindText:     "text",
+	kindValue:    "value",
+}
+
+// An Anonymizer replaces strings by generated names.
+// Use one Anonymizer for a schema and its instances, so that
+// they are renamed consistently. An Anonymizer may not be
+// used concurrently.
+type Anonymizer struct {
+	// names maps original strings to generated names.
+	names map[string]string
+	// orig maps generated names to original strings.
+	orig map[string]string
+	// counts are the numbers of names generated of each kind.
+	counts [len(prefixes)]int
+	// used are the strings of the documents seen so far,
+	// which a generated name must not be.
+	used map[string]bool
+}
+
+// New returns a new [Anonymizer].
+func New() *Anonymizer {
+	return &Anonymizer{
+		names: make(map[string]string),
+		orig:  make(map[string]string),
+		used:  make(map[string]bool),
+	}
+}
+
+// A Mapping maps the generated names of an [Anonymizer]
+// to the original strings. It encodes as a JSON object.
+type Mapping map[string]string
+
+// Mapping returns the mapping of the names generated so far.
+func (a *Anonymizer) Mapping() Mapping {
+	return Mapping(maps.Clone(a.orig))
+}
+
+// SchemaJSON is like [Anonymizer.Schema], for the JSON encoding
+// of a schema. The result is indented.
+func (a *Anonymizer) SchemaJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, err := a.Schema(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(v, "", "\t")
+}
+
+// Schema returns an anonymized copy of the schema v, a tree of Go
+// values as json.Unmarshal produces. The keywords are those of the
+// vocabulary of the $schema keyword of v, or of the default
+// vocabulary. Schema does not modify v.
+//
+// Names are generated in the order of the keys of the objects of v,
+// so that a new Anonymizer always gives a schema the same names.
+func (a *Anonymizer) Schema(v any) (any, error) {
+	vocab := schema.DefaultVocabulary()
+	if m, ok := v.(map[string]any); ok {
+		if s, ok := m["$schema"].(string); ok {
+			vocab = schema.LookupVocabulary(s)
+			if vocab == nil {
+				return nil, fmt.Errorf("unknown $schema %q", s)
+			}
+		}
+	}
+	if vocab == nil {
+		return nil, fmt.Errorf("no default vocabulary")
+	}
+	a.collect(v)
+	w := &walker{a: a, vocab: vocab}
+	return w.schema(v), nil
+}
+
+// Instance returns a copy of the instance v, a tree of Go values
+// as json.Unmarshal produces, with the property names and strings
+// that the Anonymizer has replaced in schemas replaced in the same
+// way, so that it can be validated against the anonymized schemas.
+// Other strings are not changed.
+func (a *Anonymizer) Instance(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, e := range v {
+			if n, ok := a.names[k]; ok {
+				k = n
+			}
+			ret[k] = a.Instance(e)
+		}
+		return ret
+	case []any:
+		ret := make([]any, len(v))
+		for i, e := range v {
+			ret[i] = a.Instance(e)
+		}
+		return ret
+	case string:
+		if n, ok := a.names[v]; ok {
+			return n
+		}
+	}
+	return v
+}
+
+// Restore returns a copy of v, an anonymized schema or instance
+// or any other tree of Go values as json.Unmarshal produces, with
+// the generated names of m replaced by the original strings.
+// This includes the names in the JSON pointers of $ref values.
+func (m Mapping) Restore(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, e := range v {
+			ret[m.String(k)] = m.Restore(e)
+		}
+		return ret
+	case []any:
+		ret := make([]any, len(v))
+		for i, e := range v {
+			ret[i] = m.Restore(e)
+		}
+		return ret
+	case string:
+		return m.String(v)
+	}
+	return v
+}
+
+// String returns the original of the generated name s. If s is a
+// reference with a JSON pointer fragment, such as "#/$defs/Def1",
+// or a JSON pointer, as in a validation error, the names in
+// the pointer are restored. Other strings are returned unchanged.
+func (m Mapping) String(s string) string {
+	if o, ok := m[s]; ok {
+		return o
+	}
+	return mapPointer(s, func(tok string) string {
+		if o, ok := m[tok]; ok {
+			return o
+		}
+		return tok
+	})
+}
+
+// collect records the strings of v in a.used.
+func (a *Anonymizer) collect(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			a.used[k] = true
+			a.collect(e)
+		}
+	case []any:
+		for _, e := range v {
+			a.collect(e)
+		}
+	case string:
+		a.used[v] = true
+	}
+}
+
+// name returns the generated name for s,
+// generating one of kind k if there is none.
+func (a *Anonymizer) name(s string, k kind) string {
+	if n, ok := a.names[s]; ok {
+		return n
+	}
+	var n string
+	for {
+		a.counts[k]++
+		n = prefixes[k] + strconv.Itoa(a.counts[k])
+		if _, ok := a.orig[n]; !ok && !a.used[n] {
+			break
+		}
+	}
+	a.names[s] = n
+	a.orig[n] = s
+	return n
+}
+
+// walker holds the state used by Anonymizer.Schema.
+type walker struct {
+	a     *Anonymizer
+	vocab *schema.Vocabulary
+}
+
+// keyword returns the keyword called name, if it is known.
+func (w *walker) keyword(name string) (*schema.Keyword, bool) {
+	k, ok := w.vocab.Keywords[name]
+	if !ok {
+		k, _, ok = schema.LookupExtension(name)
+	}
+	return k, ok
+}
+
+// schema returns the anonymized schema v.
+func (w *walker) schema(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		// A boolean schema, or not a schema,
+		// which building the schema will report.
+		return v
+	}
+	ret := make(map[string]any, len(m))
+	for _, name := range slices.Sorted(maps.Keys(m)) {
+		arg := m[name]
+		k, known := w.keyword(name)
+		if !known {
+			ret[name] = arg
+			continue
+		}
+		ret[name] = w.argument(name, k.ArgType, arg)
+	}
+	return ret
+}
+
+// argument returns the anonymized argument arg of the keyword name,
+// whose argument type is at.
+func (w *walker) argument(name string, at arg_type.ArgType, arg any) any {
+	switch name {
+	case "title", "description", "$comment":
+		if s, ok := arg.(string); ok {
+			return w.a.name(s, kindText)
+		}
+		return arg
+	case "enum", "const", "default", "examples":
+		return w.value(arg)
+	case "required", "x-propertyOrder":
+		return w.names(arg, kindProperty)
+	case "dependentRequired":
+		m, ok := arg.(map[string]any)
+		if !ok {
+			return arg
+		}
+		ret := make(map[string]any, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			e := m[k]
+			ret[w.a.name(k, kindProperty)] = w.names(e, kindProperty)
+		}
+		return ret
+	case "$ref", "$dynamicRef", "$recursiveRef":
+		if s, ok := arg.(string); ok {
+			return w.ref(s)
+		}
+		return arg
+	}
+
+	switch at {
+	case arg_type.ArgTypeSchema:
+		return w.schema(arg)
+	case arg_type.ArgTypeSchemas:
+		return w.list(arg)
+	case arg_type.ArgTypeSchemaOrSchemas:
+		if _, ok := arg.([]any); ok {
+			return w.list(arg)
+		}
+		return w.schema(arg)
+	case arg_type.ArgTypeMapSchema, arg_type.ArgTypeMapArrayOrSchema:
+		m, ok := arg.(map[string]any)
+		if !ok {
+			return arg
+		}
+		ret := make(map[string]any, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			sub := m[k]
+			k = w.mapKey(name, k)
+			if _, ok := sub.([]any); ok {
+				// The array of property names of a MapArrayOrSchema.
+				ret[k] = w.names(sub, kindProperty)
+				continue
+			}
+			ret[k] = w.schema(sub)
+		}
+		return ret
+	case arg_type.ArgTypeMapMapSchema:
+		// As for propertyDependencies, which maps
+		// property names and then their values to schemas.
+		m, ok := arg.(map[string]any)
+		if !ok {
+			return arg
+		}
+		ret := make(map[string]any, len(m))
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			sub := m[k]
+			sm, ok := sub.(map[string]any)
+			if !ok {
+				ret[w.a.name(k, kindProperty)] = sub
+				continue
+			}
+			rs := make(map[string]any, len(sm))
+			for _, v := range slices.Sorted(maps.Keys(sm)) {
+				s := sm[v]
+				rs[w.a.name(v, kindValue)] = w.schema(s)
+			}
+			ret[w.a.name(k, kindProperty)] = rs
+		}
+		return ret
+	}
+	return arg
+}
+
+// mapKey returns the anonymized key k of the argument
+// of the keyword name, which maps names to schemas.
+func (w *walker) mapKey(name, k string) string {
+	switch name {
+	case "$defs", "definitions":
+		return w.a.name(k, kindDef)
+	case "patternProperties":
+		// A regular expression.
+		return k
+	}
+	return w.a.name(k, kindProperty)
+}
+
+// list returns the anonymized array of schemas arg.
+func (w *walker) list(arg any) any {
+	a, ok := arg.([]any)
+	if !ok {
+		return arg
+	}
+	ret := make([]any, len(a))
+	for i, sub := range a {
+		ret[i] = w.schema(sub)
+	}
+	return ret
+}
+
+// names returns arg, an array of strings, with each
+// string replaced by a generated name of kind k.
+func (w *walker) names(arg any, k kind) any {
+	a, ok := arg.([]any)
+	if !ok {
+		return arg
+	}
+	ret := make([]any, len(a))
+	for i, e := range a {
+		if s, ok := e.(string); ok {
+			e = w.a.name(s, k)
+		}
+		ret[i] = e
+	}
+	return ret
+}
+
+// value returns the anonymized instance value v, as in an enum:
+// its strings and the property names of its objects are replaced.
+func (w *walker) value(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			e := v[k]
+			ret[w.a.name(k, kindProperty)] = w.value(e)
+		}
+		return ret
+	case []any:
+		ret := make([]any, len(v))
+		for i, e := range v {
+			ret[i] = w.value(e)
+		}
+		return ret
+	case string:
+		return w.a.name(v, kindValue)
+	}
+	return v
+}
+
+// ref returns the reference ref with the names in its
+// JSON pointer fragment anonymized. Each name is anonymized
+// as for the keyword that precedes it in the pointer.
+func (w *walker) ref(ref string) string {
+	base, frag, ok := strings.Cut(ref, "#")
+	if !ok || !strings.HasPrefix(frag, "/") {
+		return ref
+	}
+	toks := strings.Split(frag[1:], "/")
+	for i := 0; i < len(toks); i++ {
+		name := jsonpointer.UnescapeToken(toks[i])
+		k, known := w.keyword(name)
+		if !known {
+			continue
+		}
+		n := 0
+		switch k.ArgType {
+		case arg_type.ArgTypeMapSchema, arg_type.ArgTypeMapArrayOrSchema:
+			n = 1
+		case arg_type.ArgTypeMapMapSchema:
+			n = 2
+		case arg_type.ArgTypeSchemas, arg_type.ArgTypeSchemaOrSchemas:
+			if i+1 < len(toks) {
+				if _, err := strconv.Atoi(toks[i+1]); err == nil {
+					// Skip the index.
+					i++
+				}
+			}
+		}
+		for j := 1; j <= n && i+j < len(toks); j++ {
+			key := jsonpointer.UnescapeToken(toks[i+j])
+			switch {
+			case j == 2:
+				key = w.a.name(key, kindValue)
+			case k.ArgType == arg_type.ArgTypeMapMapSchema:
+				key = w.a.name(key, kindProperty)
+			default:
+				key = w.mapKey(name, key)
+			}
+			toks[i+j] = jsonpointer.EscapeToken(key)
+		}
+		i += n
+	}
+	return base + "#/" + strings.Join(toks, "/")
+}
+
+// mapPointer returns s with fn applied to each token, if s is a JSON
+// pointer or a URI reference with a JSON pointer fragment. Otherwise
+// it returns s unchanged.
+func mapPointer(s string, fn func(string) string) string {
+	prefix, ptr := "", s
+	if i := strings.Index(s, "#/"); i >= 0 {
+		prefix, ptr = s[:i+1], s[i+1:]
+	} else if !strings.HasPrefix(s, "/") {
+		return s
+	}
+	toks := strings.Split(ptr[1:], "/")
+	if !slices.ContainsFunc(toks, func(tok string) bool { return fn(jsonpointer.UnescapeToken(tok)) != jsonpointer.UnescapeToken(tok) }) {
+		return s
+	}
+	for i, tok := range toks {
+		toks[i] = jsonpointer.EscapeToken(fn(jsonpointer.UnescapeToken(tok)))
+	}
+	return prefix + "/" + strings.Join(toks, "/")
+}
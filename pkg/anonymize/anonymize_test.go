@@ -0,0 +1,176 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package anonymize_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/anonymize"
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// unmarshal decodes the JSON value src.
+func unmarshal(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return v
+}
+
+const customer = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Customer",
+	"description": "A customer of ours.",
+	"type": "object",
+	"required": ["name", "tier"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"tier": {"enum": ["gold", "silver"], "default": "silver"},
+		"address": {"$ref": "#/$defs/Address"},
+		"prop1": {"type": "integer"}
+	},
+	"patternProperties": {"^x-": {"type": "string", "format": "email"}},
+	"dependentRequired": {"address": ["name"]},
+	"$defs": {
+		"Address": {
+			"$comment": "Postal only.",
+			"properties": {"city": {"const": "Paris"}},
+			"x-unknown": "Secret"
+		}
+	}
+}`
+
+func TestSchema(t *testing.T) {
+	a := anonymize.New()
+	got, err := a.Schema(unmarshal(t, customer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "prop1" is in the schema, so it is not a generated name.
+	want := unmarshal(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "text3",
+		"description": "text2",
+		"type": "object",
+		"required": ["prop4", "prop6"],
+		"properties": {
+			"prop4": {"type": "string", "minLength": 1},
+			"prop6": {"enum": ["value3", "value2"], "default": "value2"},
+			"prop3": {"$ref": "#/$defs/Def1"},
+			"prop5": {"type": "integer"}
+		},
+		"patternProperties": {"^x-": {"type": "string", "format": "email"}},
+		"dependentRequired": {"prop3": ["prop4"]},
+		"$defs": {
+			"Def1": {
+				"$comment": "text1",
+				"properties": {"prop2": {"const": "value1"}},
+				"x-unknown": "Secret"
+			}
+		}
+	}`)
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "\t")
+		t.Errorf("got %s", gotJSON)
+	}
+
+	// The same schema gets the same names.
+	again, err := anonymize.New().Schema(unmarshal(t, customer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, got) {
+		t.Error("a new Anonymizer gives different names")
+	}
+
+	// The mapping restores the original.
+	if restored := a.Mapping().Restore(got); !reflect.DeepEqual(restored, unmarshal(t, customer)) {
+		restoredJSON, _ := json.MarshalIndent(restored, "", "\t")
+		t.Errorf("restored %s", restoredJSON)
+	}
+}
+
+func TestInstance(t *testing.T) {
+	a := anonymize.New()
+	v, err := a.Schema(unmarshal(t, customer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig, err := schema.FromValue(unmarshal(t, customer), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anon, err := schema.FromValue(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, src := range []string{
+		`{"name": "Ann", "tier": "gold"}`,
+		`{"name": "Ann", "tier": "gold", "address": {"city": "Paris"}, "x-mail": "ann@example.com"}`,
+		`{"name": "Ann", "tier": "bronze"}`,
+		`{"name": "", "tier": "gold"}`,
+		`{"tier": "gold"}`,
+		`{"name": "Ann", "tier": "gold", "address": {"city": "Rome"}}`,
+		`{"name": "Ann", "tier": "gold", "prop1": "x"}`,
+	} {
+		instance := unmarshal(t, src)
+		origErr := orig.Validate(instance)
+		anonErr := anon.Validate(a.Instance(instance))
+		if (origErr == nil) != (anonErr == nil) {
+			t.Errorf("%s: original error %v, anonymized error %v", src, origErr, anonErr)
+		}
+	}
+
+	// Strings that are not in the schema are not replaced.
+	got := a.Instance(unmarshal(t, `{"name": "Ann", "other": ["gold", "Ann"]}`))
+	want := unmarshal(t, `{"prop4": "Ann", "other": ["value3", "Ann"]}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMappingString(t *testing.T) {
+	m := anonymize.Mapping{"prop1": "name", "Def1": "Address", "prop2": "a/b"}
+	tests := []struct {
+		in, want string
+	}{
+		{"prop1", "name"},
+		{"other", "other"},
+		{"#/$defs/Def1/properties/prop1", "#/$defs/Address/properties/name"},
+		{"https://example.com/s#/$defs/Def1", "https://example.com/s#/$defs/Address"},
+		{"/prop2/0", "/a~1b/0"},
+		{"/other", "/other"},
+		{"#anchor", "#anchor"},
+	}
+	for _, test := range tests {
+		if got := m.String(test.in); got != test.want {
+			t.Errorf("String(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	a := anonymize.New()
+	got, err := a.SchemaJSON([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "T"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n\t\"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n\t\"title\": \"text1\"\n}"
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if _, err := a.SchemaJSON([]byte(`{"$schema": "https://example.com/unknown"}`)); err == nil {
+		t.Error("unknown $schema: no error")
+	}
+	if _, err := a.SchemaJSON([]byte(`{`)); err == nil {
+		t.Error("invalid JSON: no error")
+	}
+}
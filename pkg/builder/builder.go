@@ -23,7 +23,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/altshiftab/jsonschema/internal/argtype"
 	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
@@ -38,8 +37,9 @@ import (
 // via $ref or $dynamicRef. Similarly there is no way to define anchors
 // via $anchor, $dynamicAnchor, or $defs.
 type Builder struct {
-	s schema.Schema
-	v *schema.Vocabulary
+	s      schema.Schema
+	v      *schema.Vocabulary
+	strict bool
 }
 
 // New returns a new [Builder] to build a [*types.Schema]
@@ -49,15 +49,32 @@ func New(v *schema.Vocabulary) *Builder {
 }
 
 // Build builds and returns the [*jsonschema.Schema].
+// In strict mode, this panics if an argument is out of range,
+// as reported by [schema.Schema.CheckArguments].
 func (b *Builder) Build() *schema.Schema {
 	s := b.s
+	if b.strict {
+		if err := s.CheckArguments(); err != nil {
+			panic(err.Error())
+		}
+	}
 	s.Finalize(b.v)
 	return &s
 }
 
-// NewBuilder returns a new Builder with the same vocabulary.
+// SetStrict sets whether b is in strict mode. In strict mode,
+// Build checks that the arguments of the keywords are in range,
+// such as that minLength is not negative, as decoding a schema
+// from JSON does. Strict mode is off by default.
+func (b *Builder) SetStrict(strict bool) *Builder {
+	b.strict = strict
+	return b
+}
+
+// NewBuilder returns a new Builder with the same vocabulary,
+// in strict mode if b is.
 func (b *Builder) NewBuilder() *Builder {
-	return New(b.v)
+	return New(b.v).SetStrict(b.strict)
 }
 
 // AddBool adds a keyword whose argument is a bool.
@@ -158,6 +175,15 @@ func (b *Builder) AddMapArrayOrSchema(keyword *schema.Keyword, pv schema.PartMap
 	return b
 }
 
+// AddMapMapSchema adds a keyword whose argument is
+// a map from strings to maps from strings to schemas.
+// This is like the draft-next "propertyDependencies" keyword.
+func (b *Builder) AddMapMapSchema(keyword *schema.Keyword, pv schema.PartMapMapSchema) *Builder {
+	b.check(keyword, arg_type.ArgTypeMapMapSchema)
+	b.s.Parts = append(b.s.Parts, schema.MakePart(keyword, pv))
+	return b
+}
+
 // AddAny adds a keyword whose argument has any type.
 func (b *Builder) AddAny(keyword *schema.Keyword, v any) *Builder {
 	b.s.Parts = append(b.s.Parts, schema.MakePart(keyword, schema.PartAny{V: v}))
@@ -169,8 +195,7 @@ func (b *Builder) check(keyword *schema.Keyword, want arg_type.ArgType) {
 	switch keyword.ArgType {
 	case want, arg_type.ArgTypeAny:
 	default:
-		argName := argtype.Name(keyword.ArgType)
-		panic(fmt.Sprintf("Add%s called for %s which expects %s", argName, keyword.Name, argName))
+		panic(fmt.Sprintf("Add%s called for %s which expects %s", want, keyword.Name, keyword.ArgType))
 	}
 }
 
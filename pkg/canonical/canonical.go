@@ -0,0 +1,346 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package canonical encodes instances of a schema as canonical JSON,
+// so that equal payloads have the same bytes, as is needed to sign
+// them or to compare them.
+//
+// The encoding of an instance by [Marshal] follows RFC 8785, the
+// JSON Canonicalization Scheme, except for the order of the properties
+// of objects: they are in the order in which the schema declares them.
+// The instance also has the defaults of the schema filled in.
+package canonical
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Marshal returns the canonical JSON encoding of instance,
+// which must be valid against s; if it is not, Marshal
+// returns the validation error. The instance is not modified.
+//
+// Defaults are applied as for the ApplyDefaults field of
+// [schema.ValidateOpts]. There is no whitespace, strings escape
+// only the characters that must be escaped, and numbers are
+// written as JavaScript writes them, as RFC 8785 requires.
+//
+// The properties of an object are written in the order of the
+// x-propertyOrder keyword of the schemas that apply to the object,
+// if any, followed by the order of their properties keywords, and
+// then by name. The schemas are those reached through properties,
+// items and the like, following $ref, allOf, anyOf and oneOf.
+// The order of a properties keyword is only known if the schema was
+// decoded with its source, as by [schema.Decoder] or
+// [schema.UnmarshalWithSource]; otherwise its properties are
+// in order by name.
+func Marshal(s *schema.Schema, instance any) ([]byte, error) {
+	// Work on a copy, decoded as JSON is,
+	// as applying defaults modifies the instance.
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if err := s.ValidateWithOpts(v, &schema.ValidateOpts{ApplyDefaults: true}); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, v, []*schema.Schema{s}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encode writes the canonical encoding of v, to which
+// the schemas ss apply, to buf.
+func encode(buf *bytes.Buffer, v any, ss []*schema.Schema) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case string:
+		writeString(buf, v)
+	case map[string]any:
+		as := applicators(ss)
+		buf.WriteByte('{')
+		for i, key := range order(v, as) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, key)
+			buf.WriteByte(':')
+			if err := encode(buf, v[key], propertySchemas(as, key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		as := applicators(ss)
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encode(buf, e, itemSchemas(as, i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		f, ok := number(v)
+		if !ok {
+			return fmt.Errorf("canonical: can't encode value of type %T", v)
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("canonical: can't encode number %v", f)
+		}
+		buf.WriteString(formatNumber(f))
+	}
+	return nil
+}
+
+// order returns the keys of m in canonical order,
+// given the schemas as that apply to it.
+func order(m map[string]any, as []*schema.Schema) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if _, ok := m[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, s := range as {
+		if pv, ok := s.LookupKeyword("x-propertyOrder"); ok {
+			if names, ok := pv.(schema.PartStrings); ok {
+				for _, key := range names {
+					add(key)
+				}
+			}
+		}
+	}
+	for _, s := range as {
+		for _, key := range declared(s) {
+			add(key)
+		}
+	}
+	var rest []string
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	return append(keys, rest...)
+}
+
+// declared returns the names in the properties keyword of s,
+// in the order of their sources if they all have one,
+// and otherwise in order by name.
+func declared(s *schema.Schema) []string {
+	pv, ok := s.LookupKeyword("properties")
+	if !ok {
+		return nil
+	}
+	props, ok := pv.(schema.PartMapSchema)
+	if !ok {
+		return nil
+	}
+	offsets := make(map[string]int64, len(props))
+	for name, sub := range props {
+		src, ok := sub.Source()
+		if !ok {
+			offsets = nil
+			break
+		}
+		offsets[name] = src.Offset
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	if offsets == nil {
+		slices.Sort(names)
+	} else {
+		slices.SortFunc(names, func(a, b string) int {
+			return cmp.Compare(offsets[a], offsets[b])
+		})
+	}
+	return names
+}
+
+// applicators returns the schemas ss, and the schemas that
+// they apply in place to the same instance.
+func applicators(ss []*schema.Schema) []*schema.Schema {
+	var ret []*schema.Schema
+	seen := make(map[*schema.Schema]bool)
+	var walk func(s *schema.Schema)
+	walk = func(s *schema.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		ret = append(ret, s)
+		for _, part := range s.Parts {
+			switch part.Keyword.Name {
+			case schema.ResolvedRefKeywordName, schema.ResolvedDynamicRefKeywordName:
+				walk(part.Value.(schema.PartSchema).S)
+			case "allOf", "anyOf", "oneOf":
+				if part.Keyword.ArgType == arg_type.ArgTypeSchemas {
+					for _, sub := range part.Value.(schema.PartSchemas) {
+						walk(sub)
+					}
+				}
+			}
+		}
+	}
+	for _, s := range ss {
+		walk(s)
+	}
+	return ret
+}
+
+// propertySchemas returns the schemas that apply to the
+// property name of an object to which the schemas as apply.
+func propertySchemas(as []*schema.Schema, name string) []*schema.Schema {
+	var ret []*schema.Schema
+	for _, s := range as {
+		matched := false
+		if pv, ok := s.LookupKeyword("properties"); ok {
+			if sub, ok := pv.(schema.PartMapSchema)[name]; ok {
+				ret = append(ret, sub)
+				matched = true
+			}
+		}
+		if pv, ok := s.LookupKeyword("patternProperties"); ok {
+			for pat, sub := range pv.(schema.PartMapSchema) {
+				if re, err := schema.CompileRegexp(pat); err == nil && re.MatchString(name) {
+					ret = append(ret, sub)
+					matched = true
+				}
+			}
+		}
+		if pv, ok := s.LookupKeyword("additionalProperties"); ok && !matched {
+			if sub, ok := pv.(schema.PartSchema); ok {
+				ret = append(ret, sub.S)
+			}
+		}
+	}
+	return ret
+}
+
+// itemSchemas returns the schemas that apply to the
+// element i of an array to which the schemas as apply.
+func itemSchemas(as []*schema.Schema, i int) []*schema.Schema {
+	var ret []*schema.Schema
+	for _, s := range as {
+		if pv, ok := s.LookupKeyword("prefixItems"); ok {
+			if subs, ok := pv.(schema.PartSchemas); ok && i < len(subs) {
+				ret = append(ret, subs[i])
+				continue
+			}
+		}
+		pv, ok := s.LookupKeyword("items")
+		if !ok {
+			continue
+		}
+		switch items := pv.(type) {
+		case schema.PartSchema:
+			ret = append(ret, items.S)
+		case schema.PartSchemaOrSchemas:
+			// Before draft 2020-12, items may be a list.
+			if items.Schema != nil {
+				ret = append(ret, items.Schema)
+			} else if i < len(items.Schemas) {
+				ret = append(ret, items.Schemas[i])
+			}
+		}
+	}
+	return ret
+}
+
+// number returns the value of v, if it is a number.
+func number(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// formatNumber formats f as JavaScript's Number.prototype.toString
+// does, which is how RFC 8785 writes numbers.
+func formatNumber(f float64) string {
+	if f == 0 {
+		// This includes -0.
+		return "0"
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	// Go writes the exponent with at least two digits,
+	// as in 1e-07; JavaScript writes 1e-7.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mant, exp, _ := strings.Cut(s, "e")
+	sign := exp[:1]
+	exp = strings.TrimLeft(exp[1:], "0")
+	return mant + "e" + sign + exp
+}
+
+// writeString writes the JSON string s to buf, escaping only the
+// characters that must be escaped, as RFC 8785 requires.
+func writeString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\b':
+			buf.WriteString(`\b`)
+		case c == '\f':
+			buf.WriteString(`\f`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hex[c>>4])
+			buf.WriteByte(hex[c&0xf])
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package canonical_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/canonical"
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/extension/propertyorder"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode decodes src, which is a schema without its $schema
+// keyword, with its source.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	rest := src[1:]
+	if rest != "}" {
+		rest = ", " + rest
+	}
+	s, err := schema.UnmarshalWithSource([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema"`+rest), nil)
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return s
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		want     string
+	}{
+		{`{}`, `null`, `null`},
+		{`{}`, `[true, false, "x"]`, `[true,false,"x"]`},
+		{`{}`, `{"b": 1, "a": 2, "c": {"z": 1, "y": 2}}`, `{"a":2,"b":1,"c":{"y":2,"z":1}}`},
+		{
+			`{"properties": {"z": {}, "b": {}, "m": {}}}`,
+			`{"a": 1, "b": 2, "m": 3, "z": 4}`,
+			`{"z":4,"b":2,"m":3,"a":1}`,
+		},
+		{
+			`{"x-propertyOrder": ["m", "z"], "properties": {"z": {}, "b": {}, "m": {}}}`,
+			`{"b": 2, "m": 3, "z": 4}`,
+			`{"m":3,"z":4,"b":2}`,
+		},
+		{
+			`{"properties": {"z": {"default": 1}, "a": {"default": "x"}}}`,
+			`{"a": "y"}`,
+			`{"z":1,"a":"y"}`,
+		},
+		{
+			`{"allOf": [{"$ref": "#/$defs/d"}], "$defs": {"d": {"properties": {"z": {}, "a": {}}}}}`,
+			`{"a": 1, "z": 2}`,
+			`{"z":2,"a":1}`,
+		},
+		{
+			`{"items": {"properties": {"z": {}, "a": {}}}}`,
+			`[{"a": 1, "z": 2}]`,
+			`[{"z":2,"a":1}]`,
+		},
+		{
+			`{"prefixItems": [{"properties": {"z": {}, "a": {}}}]}`,
+			`[{"a": 1, "z": 2}, {"z": 2, "a": 1}]`,
+			`[{"z":2,"a":1},{"a":1,"z":2}]`,
+		},
+		{
+			`{"patternProperties": {"^p": {"properties": {"z": {}, "a": {}}}}}`,
+			`{"p1": {"a": 1, "z": 2}, "q": {"z": 2, "a": 1}}`,
+			`{"p1":{"z":2,"a":1},"q":{"a":1,"z":2}}`,
+		},
+		{
+			`{"additionalProperties": {"properties": {"z": {}, "a": {}}}}`,
+			`{"p": {"a": 1, "z": 2}}`,
+			`{"p":{"z":2,"a":1}}`,
+		},
+		{`{}`, `"a\"\\\b\f\n\r\t\u0001\u001f/é€"`, `"a\"\\\b\f\n\r\t\u0001\u001f/é€"`},
+		{`{}`, `[0, -0, 1, -1, 1.5, 100, 1e20, 1e21, 1.5e-6, 1e-7, 123456789012345680000, 4.5e-324]`,
+			`[0,0,1,-1,1.5,100,100000000000000000000,1e+21,0.0000015,1e-7,123456789012345680000,5e-324]`},
+	}
+	for _, test := range tests {
+		s := decode(t, test.schema)
+		var instance any
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Fatal(err)
+		}
+		got, err := canonical.Marshal(s, instance)
+		if err != nil {
+			t.Errorf("%s, %s: %v", test.schema, test.instance, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s, %s: got %s, want %s", test.schema, test.instance, got, test.want)
+		}
+	}
+}
+
+func TestMarshalUnmodified(t *testing.T) {
+	s := decode(t, `{"properties": {"a": {"default": 1}}}`)
+	instance := map[string]any{}
+	got, err := canonical.Marshal(s, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":1}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if len(instance) != 0 {
+		t.Errorf("instance modified to %v", instance)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	s := decode(t, `{"type": "object", "required": ["a"]}`)
+	if _, err := canonical.Marshal(s, map[string]any{}); err == nil {
+		t.Error("invalid instance: no error")
+	}
+	if _, err := canonical.Marshal(decode(t, `{}`), math.Inf(1)); err == nil {
+		t.Error("infinity: no error")
+	}
+	if _, err := canonical.Marshal(decode(t, `{}`), func() {}); err == nil {
+		t.Error("func: no error")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docset supports documents that contain many schemas
+// at different locations, such as the components/schemas section
+// of an OpenAPI document. The schemas are resolved together,
+// so that references from one to another work, and an instance
+// can be validated against any of them by JSON pointer or by name.
+package docset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// DefaultPointers are the locations searched for schemas
+// when [Opts.Pointers] is empty. Each entry found is an object
+// whose values are the member schemas.
+var DefaultPointers = []string{
+	"/components/schemas", // OpenAPI 3
+	"/definitions",        // OpenAPI 2
+	"/$defs",
+}
+
+// Opts describes options for [New] and [Load].
+type Opts struct {
+	// The default schema ID, such as [draft202012.SchemaID].
+	// If empty, the default vocabulary is used.
+	SchemaID string
+	// URI is where the document was loaded from.
+	// It is the base URI of references to other documents.
+	URI *url.URL
+	// Pointers are JSON pointers to the member schemas.
+	// If empty, the members are the values of the objects
+	// found at [DefaultPointers].
+	Pointers []string
+	// Loader loads schemas referenced from other documents,
+	// as for [schema.ResolveOpts]. If nil, references to other
+	// documents are errors.
+	Loader schema.Loader
+}
+
+// DocumentSet is a set of schemas from a single document.
+type DocumentSet struct {
+	// schemas maps a JSON pointer to its schema.
+	schemas map[string]*schema.Schema
+	// names maps an unambiguous name to a JSON pointer.
+	names map[string]string
+}
+
+// Load is like [New] for a document in JSON format.
+// For other formats, such as YAML, decode the document
+// into an empty interface value and call New.
+func Load(data []byte, opts *Opts) (*DocumentSet, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return New(doc, opts)
+}
+
+// New returns a DocumentSet for the schemas in the document doc,
+// which has the form of JSON parsed into an empty interface value.
+// References in the form of a JSON pointer fragment, such as
+// "#/components/schemas/Pet", refer to locations in doc,
+// and must be within one of the member schemas.
+func New(doc any, opts *Opts) (*DocumentSet, error) {
+	if opts == nil {
+		opts = &Opts{}
+	}
+
+	pointers := opts.Pointers
+	if len(pointers) == 0 {
+		pointers = defaultMembers(doc)
+		if len(pointers) == 0 {
+			return nil, errors.New("document has no schemas at the default locations")
+		}
+	}
+
+	// Gather the members under a synthetic root schema,
+	// and rewrite document references to refer to them there.
+	keys := make(map[string]string, len(pointers))
+	for i, ptr := range pointers {
+		if _, ok := keys[ptr]; ok {
+			return nil, fmt.Errorf("duplicate pointer %q", ptr)
+		}
+		keys[ptr] = strconv.Itoa(i)
+	}
+	defs := make(map[string]any, len(pointers))
+	for _, ptr := range pointers {
+		v, err := lookup(doc, ptr)
+		if err != nil {
+			return nil, err
+		}
+		nv, err := rewriteRefs(v, keys)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ptr, err)
+		}
+		defs[keys[ptr]] = nv
+	}
+
+	root, err := schema.SchemaFromJSON(opts.SchemaID, opts.URI, map[string]any{"$defs": defs})
+	if err != nil {
+		return nil, err
+	}
+	// The root has no $schema keyword, so the
+	// vocabulary must be given to Resolve.
+	vocab := schema.DefaultVocabulary()
+	if opts.SchemaID != "" {
+		vocab = schema.LookupVocabulary(opts.SchemaID)
+	}
+	ropts := &schema.ResolveOpts{
+		Vocabulary: vocab,
+		URI:        opts.URI,
+		Loader:     opts.Loader,
+	}
+	if err := root.Resolve(ropts); err != nil {
+		return nil, err
+	}
+
+	v, _ := root.LookupKeyword("$defs")
+	members := v.(schema.PartMapSchema)
+	ds := &DocumentSet{
+		schemas: make(map[string]*schema.Schema, len(pointers)),
+		names:   make(map[string]string, len(pointers)),
+	}
+	ambiguous := make(map[string]bool)
+	for _, ptr := range pointers {
+		ds.schemas[ptr] = members[keys[ptr]]
+
+		name := jsonpointer.UnescapeToken(ptr[strings.LastIndex(ptr, "/")+1:])
+		if _, ok := ds.names[name]; ok {
+			ambiguous[name] = true
+		}
+		ds.names[name] = ptr
+	}
+	for name := range ambiguous {
+		delete(ds.names, name)
+	}
+
+	return ds, nil
+}
+
+// Pointers returns the sorted JSON pointers of the member schemas.
+func (ds *DocumentSet) Pointers() []string {
+	return slices.Sorted(maps.Keys(ds.schemas))
+}
+
+// Schema returns the member schema with the given JSON pointer,
+// or with the given name. The name of a member is the last element
+// of its pointer, such as "Pet" for "/components/schemas/Pet".
+// Names shared by more than one member can't be used.
+func (ds *DocumentSet) Schema(ref string) (*schema.Schema, bool) {
+	if s, ok := ds.schemas[ref]; ok {
+		return s, true
+	}
+	if ptr, ok := ds.names[ref]; ok {
+		return ds.schemas[ptr], true
+	}
+	return nil, false
+}
+
+// Validate validates instance against the member schema
+// with the given JSON pointer or name, as for [DocumentSet.Schema].
+func (ds *DocumentSet) Validate(ref string, instance any) error {
+	s, ok := ds.Schema(ref)
+	if !ok {
+		return fmt.Errorf("no schema %q in document", ref)
+	}
+	return s.Validate(instance)
+}
+
+// defaultMembers returns the pointers of the members
+// found at [DefaultPointers], in sorted order.
+func defaultMembers(doc any) []string {
+	var ret []string
+	for _, ptr := range DefaultPointers {
+		v, err := lookup(doc, ptr)
+		if err != nil {
+			continue
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(m)) {
+			ret = append(ret, ptr+"/"+jsonpointer.EscapeToken(name))
+		}
+	}
+	return ret
+}
+
+// lookup returns the value in doc at the JSON pointer ptr.
+func lookup(doc any, ptr string) (any, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+	v := doc
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = jsonpointer.UnescapeToken(tok)
+		switch tv := v.(type) {
+		case map[string]any:
+			e, ok := tv[tok]
+			if !ok {
+				return nil, fmt.Errorf("%s: no value in document", ptr)
+			}
+			v = e
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(tv) {
+				return nil, fmt.Errorf("%s: no value in document", ptr)
+			}
+			v = tv[i]
+		default:
+			return nil, fmt.Errorf("%s: no value in document", ptr)
+		}
+	}
+	return v, nil
+}
+
+// rewriteRefs returns a copy of the JSON value v in which
+// references to locations within a member schema of the document
+// are changed to refer to the member under the synthetic root.
+// keys maps member pointers to their keys in the root "$defs".
+// We don't rewrite within a schema with an "$id",
+// as its references are relative to that schema.
+func rewriteRefs(v any, keys map[string]string) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if _, ok := v["$id"]; ok {
+			return v, nil
+		}
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			if ref, ok := e.(string); ok && (k == "$ref" || k == "$dynamicRef") {
+				nref, err := rewriteRef(ref, keys)
+				if err != nil {
+					return nil, err
+				}
+				m[k] = nref
+				continue
+			}
+			ne, err := rewriteRefs(e, keys)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = ne
+		}
+		return m, nil
+
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			ne, err := rewriteRefs(e, keys)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = ne
+		}
+		return a, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// rewriteRef rewrites a single reference for rewriteRefs.
+// Only references that are a JSON pointer fragment are changed.
+func rewriteRef(ref string, keys map[string]string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(ref, "#") || (u.Fragment != "" && !strings.HasPrefix(u.Fragment, "/")) {
+		return ref, nil
+	}
+
+	// Use the longest matching member pointer.
+	best := ""
+	found := false
+	for ptr := range keys {
+		if (u.Fragment == ptr || strings.HasPrefix(u.Fragment, ptr+"/")) && (!found || len(ptr) > len(best)) {
+			best = ptr
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("reference %q is not within a member schema", ref)
+	}
+
+	nu := url.URL{Fragment: "/$defs/" + keys[best] + strings.TrimPrefix(u.Fragment, best)}
+	return nu.String(), nil
+}
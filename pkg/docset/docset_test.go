@@ -0,0 +1,118 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docset_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/docset"
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+)
+
+const openAPI = `{
+	"openapi": "3.1.0",
+	"components": {
+		"schemas": {
+			"Pet": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"owner": {"$ref": "#/components/schemas/Person"},
+					"tags": {"$ref": "#/components/schemas/Person/properties/tags"}
+				}
+			},
+			"Person": {
+				"type": "object",
+				"properties": {"tags": {"type": "array", "items": {"type": "string"}}}
+			}
+		}
+	},
+	"$defs": {
+		"Pet": {"type": "string"}
+	}
+}`
+
+func TestLoad(t *testing.T) {
+	ds, err := docset.Load([]byte(openAPI), &docset.Opts{SchemaID: draft202012.SchemaID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/$defs/Pet", "/components/schemas/Person", "/components/schemas/Pet"}
+	if got := ds.Pointers(); !slices.Equal(got, want) {
+		t.Errorf("Pointers = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		ref      string
+		instance any
+		valid    bool
+	}{
+		{"/components/schemas/Pet", map[string]any{"name": "Rex"}, true},
+		{"/components/schemas/Pet", map[string]any{}, false},
+		{"/components/schemas/Pet", map[string]any{"name": "Rex", "owner": map[string]any{"tags": []any{1.0}}}, false},
+		{"/components/schemas/Pet", map[string]any{"name": "Rex", "tags": []any{"a"}}, true},
+		{"/components/schemas/Pet", map[string]any{"name": "Rex", "tags": "a"}, false},
+		{"Person", map[string]any{"tags": []any{"a"}}, true},
+		{"/$defs/Pet", "Rex", true},
+	}
+	for _, test := range tests {
+		if err := ds.Validate(test.ref, test.instance); (err == nil) != test.valid {
+			t.Errorf("%s with %v: got error %v, want valid %t", test.ref, test.instance, err, test.valid)
+		}
+	}
+
+	// "Pet" names two members, so it can't be used.
+	if _, ok := ds.Schema("Pet"); ok {
+		t.Error(`Schema("Pet") found an ambiguous name`)
+	}
+	if err := ds.Validate("Dog", nil); err == nil {
+		t.Error(`Validate("Dog"): no error`)
+	}
+
+	// Without options, the default vocabulary is used.
+	ds, err = docset.Load([]byte(openAPI), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Validate("Person", map[string]any{"tags": "a"}); err == nil {
+		t.Error("default vocabulary: no error")
+	}
+}
+
+func TestPointers(t *testing.T) {
+	opts := &docset.Opts{
+		SchemaID: draft202012.SchemaID,
+		Pointers: []string{"/components/schemas/Person"},
+	}
+	ds, err := docset.Load([]byte(openAPI), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ds.Pointers(); !slices.Equal(got, opts.Pointers) {
+		t.Errorf("Pointers = %q, want %q", got, opts.Pointers)
+	}
+
+	// Pet refers to Person, which is a member, and to
+	// the name of Pet, which is not.
+	opts.Pointers = []string{"/components/schemas/Pet", "/components/schemas/Person/properties/tags"}
+	_, err = docset.Load([]byte(openAPI), opts)
+	if err == nil || !strings.Contains(err.Error(), "is not within a member schema") {
+		t.Errorf("reference outside the members: got error %v", err)
+	}
+
+	for _, ptrs := range [][]string{{"/nowhere"}, {"components"}, {"/$defs/Pet", "/$defs/Pet"}} {
+		opts.Pointers = ptrs
+		if _, err := docset.Load([]byte(openAPI), opts); err == nil {
+			t.Errorf("pointers %q: no error", ptrs)
+		}
+	}
+
+	if _, err := docset.Load([]byte(`{"openapi": "3.1.0"}`), nil); err == nil {
+		t.Error("document without schemas: no error")
+	}
+}
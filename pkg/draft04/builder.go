@@ -0,0 +1,14 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draft04
+
+import (
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// AddItemsSchema is for builder.Infer. Use the AddItems method instead.
+func (b *Builder) AddItemsSchema(s *schema.Schema) *Builder {
+	return b.AddItems(schema.PartSchemaOrSchemas{Schema: s})
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run ../../cmd/keywordgen -d dialect.json
+
+// Package draft04 defines the keywords used by JSON schema draft-04,
+// so that schemas written for it can be used without conversion.
+//
+// The differences from draft 2020-12 that matter most are:
+//   - Keywords next to "$ref" are ignored.
+//   - Reusable subschemas are in "definitions", not "$defs".
+//   - The schema URI is set by "id", not "$id". An "id" with a
+//     plain-name fragment, such as "#item", defines an anchor;
+//     there is no "$anchor" keyword.
+//   - "exclusiveMaximum" and "exclusiveMinimum" are booleans that
+//     make "maximum" and "minimum" exclusive.
+//   - "items" may be a list of schemas for the leading elements
+//     of an array, and "additionalItems" applies to the rest.
+//   - There is no "if", "then", "else", "$comment", "const",
+//     "contains", "propertyNames", "examples",
+//     "dependentSchemas", "dependentRequired", "prefixItems",
+//     "unevaluatedItems" or "unevaluatedProperties".
+//
+// Importing this package registers the vocabulary, but does not
+// make it the default; a schema must name [SchemaID] in its
+// "$schema" keyword to use it.
+package draft04
@@ -0,0 +1,728 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by keywordgen; DO NOT EDIT.
+
+package draft04
+
+import (
+	"cmp"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+var (
+	refKeyword = schema.Keyword{
+		Name:      "$ref",
+		ArgType:   arg_type.ArgTypeString,
+		Validate:  validator.ArgTypeString(validateRef),
+		Generated: false,
+		Leaf:      false,
+	}
+)
+
+var (
+	idKeyword = schema.Keyword{
+		Name:      "id",
+		ArgType:   arg_type.ArgTypeString,
+		Validate:  validator.ValidateTrue,
+		Generated: false,
+		Leaf:      true,
+	}
+
+	definitionsKeyword = schema.Keyword{
+		Name:      "definitions",
+		ArgType:   arg_type.ArgTypeMapSchema,
+		Validate:  validator.ValidateTrue,
+		Generated: false,
+		Leaf:      true,
+	}
+
+	allOfKeyword = schema.Keyword{
+		Name:      "allOf",
+		ArgType:   arg_type.ArgTypeSchemas,
+		Validate:  validator.ArgTypeSchemas(validator.ValidateAllOf),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	anyOfKeyword = schema.Keyword{
+		Name:      "anyOf",
+		ArgType:   arg_type.ArgTypeSchemas,
+		Validate:  validator.ArgTypeSchemas(validator.ValidateAnyOf),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	oneOfKeyword = schema.Keyword{
+		Name:      "oneOf",
+		ArgType:   arg_type.ArgTypeSchemas,
+		Validate:  validator.ArgTypeSchemas(validator.ValidateOneOf),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	notKeyword = schema.Keyword{
+		Name:      "not",
+		ArgType:   arg_type.ArgTypeSchema,
+		Validate:  validator.ArgTypeSchema(validator.ValidateNot),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	itemsKeyword = schema.Keyword{
+		Name:      "items",
+		ArgType:   arg_type.ArgTypeSchemaOrSchemas,
+		Validate:  validator.ArgTypeSchemaOrSchemas(validator.ValidateItemsSchemaOrSchemas),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	additionalItemsKeyword = schema.Keyword{
+		Name:      "additionalItems",
+		ArgType:   arg_type.ArgTypeSchema,
+		Validate:  validator.ArgTypeSchema(validator.ValidateAdditionalItems),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	propertiesKeyword = schema.Keyword{
+		Name:      "properties",
+		ArgType:   arg_type.ArgTypeMapSchema,
+		Validate:  validator.ArgTypeMapSchema(validator.ValidateProperties),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	patternPropertiesKeyword = schema.Keyword{
+		Name:      "patternProperties",
+		ArgType:   arg_type.ArgTypeMapSchema,
+		Validate:  validator.ArgTypeMapSchema(validator.ValidatePatternProperties),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	additionalPropertiesKeyword = schema.Keyword{
+		Name:      "additionalProperties",
+		ArgType:   arg_type.ArgTypeSchema,
+		Validate:  validator.ArgTypeSchema(validator.ValidateAdditionalProperties),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	dependenciesKeyword = schema.Keyword{
+		Name:      "dependencies",
+		ArgType:   arg_type.ArgTypeMapArrayOrSchema,
+		Validate:  validator.ArgTypeMapArrayOrSchema(validator.ValidateDependencies),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	typeKeyword = schema.Keyword{
+		Name:      "type",
+		ArgType:   arg_type.ArgTypeStringOrStrings,
+		Validate:  validator.ArgTypeStringOrStrings(validator.ValidateType),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	enumKeyword = schema.Keyword{
+		Name:      "enum",
+		ArgType:   arg_type.ArgTypeAny,
+		Validate:  validator.ArgTypeAny(validator.ValidateEnum),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	multipleOfKeyword = schema.Keyword{
+		Name:      "multipleOf",
+		ArgType:   arg_type.ArgTypeFloat,
+		Validate:  validator.ArgTypeFloat(validator.ValidateMultipleOf),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	maximumKeyword = schema.Keyword{
+		Name:      "maximum",
+		ArgType:   arg_type.ArgTypeFloat,
+		Validate:  validator.ArgTypeFloat(validator.ValidateMaximumDraft04),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	exclusiveMaximumKeyword = schema.Keyword{
+		Name:      "exclusiveMaximum",
+		ArgType:   arg_type.ArgTypeBool,
+		Validate:  validator.ValidateTrue,
+		Generated: false,
+		Leaf:      true,
+	}
+
+	minimumKeyword = schema.Keyword{
+		Name:      "minimum",
+		ArgType:   arg_type.ArgTypeFloat,
+		Validate:  validator.ArgTypeFloat(validator.ValidateMinimumDraft04),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	exclusiveMinimumKeyword = schema.Keyword{
+		Name:      "exclusiveMinimum",
+		ArgType:   arg_type.ArgTypeBool,
+		Validate:  validator.ValidateTrue,
+		Generated: false,
+		Leaf:      true,
+	}
+
+	maxLengthKeyword = schema.Keyword{
+		Name:      "maxLength",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMaxLength),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	minLengthKeyword = schema.Keyword{
+		Name:      "minLength",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMinLength),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	patternKeyword = schema.Keyword{
+		Name:      "pattern",
+		ArgType:   arg_type.ArgTypeString,
+		Validate:  validator.ArgTypeString(validator.ValidatePattern),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	maxItemsKeyword = schema.Keyword{
+		Name:      "maxItems",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMaxItems),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	minItemsKeyword = schema.Keyword{
+		Name:      "minItems",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMinItems),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	uniqueItemsKeyword = schema.Keyword{
+		Name:      "uniqueItems",
+		ArgType:   arg_type.ArgTypeBool,
+		Validate:  validator.ArgTypeBool(validator.ValidateUniqueItems),
+		Generated: false,
+		Leaf:      true,
+	}
+
+	maxPropertiesKeyword = schema.Keyword{
+		Name:      "maxProperties",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMaxProperties),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	minPropertiesKeyword = schema.Keyword{
+		Name:      "minProperties",
+		ArgType:   arg_type.ArgTypeInt,
+		Validate:  validator.ArgTypeInt(validator.ValidateMinProperties),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	requiredKeyword = schema.Keyword{
+		Name:      "required",
+		ArgType:   arg_type.ArgTypeStrings,
+		Validate:  validator.ArgTypeStrings(validator.ValidateRequired),
+		Generated: false,
+		Leaf:      false,
+	}
+
+	formatKeyword = schema.Keyword{
+		Name:       "format",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ArgTypeString(validator.ValidateFormat),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
+	}
+
+	titleKeyword = schema.Keyword{
+		Name:       "title",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
+	}
+
+	descriptionKeyword = schema.Keyword{
+		Name:       "description",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
+	}
+
+	defaultKeyword = schema.Keyword{
+		Name:       "default",
+		ArgType:    arg_type.ArgTypeAny,
+		Validate:   validator.ArgTypeAny(validator.ValidateDefault),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
+	}
+)
+
+// keywordMap maps keyword names to [types.Keyword] values.
+var keywordMap = map[string]*schema.Keyword{
+	"$ref":                 &refKeyword,
+	"id":                   &idKeyword,
+	"definitions":          &definitionsKeyword,
+	"allOf":                &allOfKeyword,
+	"anyOf":                &anyOfKeyword,
+	"oneOf":                &oneOfKeyword,
+	"not":                  &notKeyword,
+	"items":                &itemsKeyword,
+	"additionalItems":      &additionalItemsKeyword,
+	"properties":           &propertiesKeyword,
+	"patternProperties":    &patternPropertiesKeyword,
+	"additionalProperties": &additionalPropertiesKeyword,
+	"dependencies":         &dependenciesKeyword,
+	"type":                 &typeKeyword,
+	"enum":                 &enumKeyword,
+	"multipleOf":           &multipleOfKeyword,
+	"maximum":              &maximumKeyword,
+	"exclusiveMaximum":     &exclusiveMaximumKeyword,
+	"minimum":              &minimumKeyword,
+	"exclusiveMinimum":     &exclusiveMinimumKeyword,
+	"maxLength":            &maxLengthKeyword,
+	"minLength":            &minLengthKeyword,
+	"pattern":              &patternKeyword,
+	"maxItems":             &maxItemsKeyword,
+	"minItems":             &minItemsKeyword,
+	"uniqueItems":          &uniqueItemsKeyword,
+	"maxProperties":        &maxPropertiesKeyword,
+	"minProperties":        &minPropertiesKeyword,
+	"required":             &requiredKeyword,
+	"format":               &formatKeyword,
+	"title":                &titleKeyword,
+	"description":          &descriptionKeyword,
+	"default":              &defaultKeyword,
+}
+
+// keywordDocs maps keyword names to their documentation.
+var keywordDocs = map[string]schema.KeywordDoc{
+	"$ref": {
+		Description: "Applies the schema at the given URI reference to the instance. Keywords next to it are ignored.",
+		Link:        "https://json-schema.org/draft-04/draft-zyp-json-schema-04",
+	},
+	"id": {
+		Description: "Sets the canonical URI of the schema and the base URI for relative references; a plain-name fragment defines an anchor.",
+		Link:        "https://json-schema.org/draft-04/draft-zyp-json-schema-04",
+	},
+	"definitions": {
+		Description: "Holds subschemas for reuse by references.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"allOf": {
+		Description: "The instance must be valid against all of the schemas.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"anyOf": {
+		Description: "The instance must be valid against at least one of the schemas.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"oneOf": {
+		Description: "The instance must be valid against exactly one of the schemas.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"not": {
+		Description: "The instance must not be valid against the schema.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"items": {
+		Description: "Applies a schema to every element of an array, or a list of schemas to the leading elements.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"additionalItems": {
+		Description: "Applies a schema to the array elements after those matched by an items list.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"properties": {
+		Description: "Applies each schema to the object property with the same name.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"patternProperties": {
+		Description: "Applies each schema to the object properties whose names match the regular expression.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"additionalProperties": {
+		Description: "Applies the schema to object properties not covered by properties or patternProperties.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"dependencies": {
+		Description: "If the given property is present, requires the listed properties, or applies the schema.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"type": {
+		Description: "The instance must have the given JSON type, or one of the given types.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"enum": {
+		Description: "The instance must be equal to one of the values.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"multipleOf": {
+		Description: "A number must be a multiple of the value.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"maximum": {
+		Description: "The instance must be less than or equal to the value, or less than it if exclusiveMaximum is true.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"exclusiveMaximum": {
+		Description: "If true, the maximum keyword is an exclusive limit.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"minimum": {
+		Description: "The instance must be greater than or equal to the value, or greater than it if exclusiveMinimum is true.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"exclusiveMinimum": {
+		Description: "If true, the minimum keyword is an exclusive limit.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"maxLength": {
+		Description: "A string must have at most this many characters.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"minLength": {
+		Description: "A string must have at least this many characters.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"pattern": {
+		Description: "A string must match the regular expression.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"maxItems": {
+		Description: "An array must have at most this many elements.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"minItems": {
+		Description: "An array must have at least this many elements.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"uniqueItems": {
+		Description: "If true, the elements of an array must all be different.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"maxProperties": {
+		Description: "An object must have at most this many properties.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"minProperties": {
+		Description: "An object must have at least this many properties.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"required": {
+		Description: "An object must have all of the named properties.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"format": {
+		Description: "Names a semantic format, such as date-time or email, that a string should follow.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"title": {
+		Description: "A short title for the instance.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"description": {
+		Description: "An explanation of the purpose of the instance.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+	"default": {
+		Description: "A default value for the instance.",
+		Link:        "https://json-schema.org/draft-04/draft-fge-json-schema-validation-00",
+	},
+}
+
+// AddBool adds a keyword with an argument of type Bool.
+func (b *Builder) AddBool(keyword *schema.Keyword, v bool) *Builder {
+	b.b = b.b.AddBool(keyword, v)
+	return b
+}
+
+// AddString adds a keyword with an argument of type String.
+func (b *Builder) AddString(keyword *schema.Keyword, v string) *Builder {
+	b.b = b.b.AddString(keyword, v)
+	return b
+}
+
+// AddStrings adds a keyword with an argument of type Strings.
+func (b *Builder) AddStrings(keyword *schema.Keyword, v []string) *Builder {
+	b.b = b.b.AddStrings(keyword, v)
+	return b
+}
+
+// AddInt adds a keyword with an argument of type Int.
+func (b *Builder) AddInt(keyword *schema.Keyword, v int64) *Builder {
+	b.b = b.b.AddInt(keyword, v)
+	return b
+}
+
+// AddFloat adds a keyword with an argument of type Float.
+func (b *Builder) AddFloat(keyword *schema.Keyword, v float64) *Builder {
+	b.b = b.b.AddFloat(keyword, v)
+	return b
+}
+
+// AddSchema adds a keyword with an argument of type Schema.
+func (b *Builder) AddSchema(keyword *schema.Keyword, v *schema.Schema) *Builder {
+	b.b = b.b.AddSchema(keyword, v)
+	return b
+}
+
+// AddSchemas adds a keyword with an argument of type Schemas.
+func (b *Builder) AddSchemas(keyword *schema.Keyword, v []*schema.Schema) *Builder {
+	b.b = b.b.AddSchemas(keyword, v)
+	return b
+}
+
+// AddMapSchema adds a keyword with an argument of type MapSchema.
+func (b *Builder) AddMapSchema(keyword *schema.Keyword, v map[string]*schema.Schema) *Builder {
+	b.b = b.b.AddMapSchema(keyword, v)
+	return b
+}
+
+// AddSchemaOrSchemas adds a keyword with an argument of type SchemaOrSchemas.
+func (b *Builder) AddSchemaOrSchemas(keyword *schema.Keyword, v schema.PartSchemaOrSchemas) *Builder {
+	b.b = b.b.AddSchemaOrSchemas(keyword, v)
+	return b
+}
+
+// AddMapArrayOrSchema adds a keyword with an argument of type MapArrayOrSchema.
+func (b *Builder) AddMapArrayOrSchema(keyword *schema.Keyword, v map[string]schema.ArrayOrSchema) *Builder {
+	b.b = b.b.AddMapArrayOrSchema(keyword, v)
+	return b
+}
+
+// AddAny adds a keyword with an argument of type Any.
+func (b *Builder) AddAny(keyword *schema.Keyword, v any) *Builder {
+	b.b = b.b.AddAny(keyword, v)
+	return b
+}
+
+// AddMapMapSchema adds a keyword with an argument of type MapMapSchema.
+func (b *Builder) AddMapMapSchema(keyword *schema.Keyword, v map[string]map[string]*schema.Schema) *Builder {
+	b.b = b.b.AddMapMapSchema(keyword, v)
+	return b
+}
+
+// AddAllOf adds the allOf keyword to the schema.
+func (b *Builder) AddAllOf(arg []*schema.Schema) *Builder {
+	return b.AddSchemas(&allOfKeyword, arg)
+}
+
+// AddAnyOf adds the anyOf keyword to the schema.
+func (b *Builder) AddAnyOf(arg []*schema.Schema) *Builder {
+	return b.AddSchemas(&anyOfKeyword, arg)
+}
+
+// AddOneOf adds the oneOf keyword to the schema.
+func (b *Builder) AddOneOf(arg []*schema.Schema) *Builder {
+	return b.AddSchemas(&oneOfKeyword, arg)
+}
+
+// AddNot adds the not keyword to the schema.
+func (b *Builder) AddNot(arg *schema.Schema) *Builder {
+	return b.AddSchema(&notKeyword, arg)
+}
+
+// AddItems adds the items keyword to the schema.
+func (b *Builder) AddItems(arg schema.PartSchemaOrSchemas) *Builder {
+	return b.AddSchemaOrSchemas(&itemsKeyword, arg)
+}
+
+// AddAdditionalItems adds the additionalItems keyword to the schema.
+func (b *Builder) AddAdditionalItems(arg *schema.Schema) *Builder {
+	return b.AddSchema(&additionalItemsKeyword, arg)
+}
+
+// AddProperties adds the properties keyword to the schema.
+func (b *Builder) AddProperties(arg map[string]*schema.Schema) *Builder {
+	return b.AddMapSchema(&propertiesKeyword, arg)
+}
+
+// AddPatternProperties adds the patternProperties keyword to the schema.
+func (b *Builder) AddPatternProperties(arg map[string]*schema.Schema) *Builder {
+	return b.AddMapSchema(&patternPropertiesKeyword, arg)
+}
+
+// AddAdditionalProperties adds the additionalProperties keyword to the schema.
+func (b *Builder) AddAdditionalProperties(arg *schema.Schema) *Builder {
+	return b.AddSchema(&additionalPropertiesKeyword, arg)
+}
+
+// AddDependencies adds the dependencies keyword to the schema.
+func (b *Builder) AddDependencies(arg map[string]schema.ArrayOrSchema) *Builder {
+	return b.AddMapArrayOrSchema(&dependenciesKeyword, arg)
+}
+
+// AddType adds the type keyword with one or more strings to the schema.
+func (b *Builder) AddType(args ...string) *Builder {
+	if len(args) == 1 {
+		return b.AddString(&typeKeyword, args[0])
+	} else {
+		return b.AddStrings(&typeKeyword, args)
+	}
+}
+
+// AddEnum adds the enum keyword to the schema.
+func (b *Builder) AddEnum(arg any) *Builder {
+	return b.AddAny(&enumKeyword, arg)
+}
+
+// AddMultipleOf adds the multipleOf keyword to the schema.
+func (b *Builder) AddMultipleOf(arg float64) *Builder {
+	return b.AddFloat(&multipleOfKeyword, arg)
+}
+
+// AddMaximum adds the maximum keyword to the schema.
+func (b *Builder) AddMaximum(arg float64) *Builder {
+	return b.AddFloat(&maximumKeyword, arg)
+}
+
+// AddExclusiveMaximum adds the exclusiveMaximum keyword to the schema.
+func (b *Builder) AddExclusiveMaximum(arg bool) *Builder {
+	return b.AddBool(&exclusiveMaximumKeyword, arg)
+}
+
+// AddMinimum adds the minimum keyword to the schema.
+func (b *Builder) AddMinimum(arg float64) *Builder {
+	return b.AddFloat(&minimumKeyword, arg)
+}
+
+// AddExclusiveMinimum adds the exclusiveMinimum keyword to the schema.
+func (b *Builder) AddExclusiveMinimum(arg bool) *Builder {
+	return b.AddBool(&exclusiveMinimumKeyword, arg)
+}
+
+// AddMaxLength adds the maxLength keyword to the schema.
+func (b *Builder) AddMaxLength(arg int64) *Builder {
+	return b.AddInt(&maxLengthKeyword, arg)
+}
+
+// AddMinLength adds the minLength keyword to the schema.
+func (b *Builder) AddMinLength(arg int64) *Builder {
+	return b.AddInt(&minLengthKeyword, arg)
+}
+
+// AddPattern adds the pattern keyword to the schema.
+func (b *Builder) AddPattern(arg string) *Builder {
+	return b.AddString(&patternKeyword, arg)
+}
+
+// AddMaxItems adds the maxItems keyword to the schema.
+func (b *Builder) AddMaxItems(arg int64) *Builder {
+	return b.AddInt(&maxItemsKeyword, arg)
+}
+
+// AddMinItems adds the minItems keyword to the schema.
+func (b *Builder) AddMinItems(arg int64) *Builder {
+	return b.AddInt(&minItemsKeyword, arg)
+}
+
+// AddUniqueItems adds the uniqueItems keyword to the schema.
+func (b *Builder) AddUniqueItems(arg bool) *Builder {
+	return b.AddBool(&uniqueItemsKeyword, arg)
+}
+
+// AddMaxProperties adds the maxProperties keyword to the schema.
+func (b *Builder) AddMaxProperties(arg int64) *Builder {
+	return b.AddInt(&maxPropertiesKeyword, arg)
+}
+
+// AddMinProperties adds the minProperties keyword to the schema.
+func (b *Builder) AddMinProperties(arg int64) *Builder {
+	return b.AddInt(&minPropertiesKeyword, arg)
+}
+
+// AddRequired adds the required keyword to the schema.
+func (b *Builder) AddRequired(arg []string) *Builder {
+	return b.AddStrings(&requiredKeyword, arg)
+}
+
+// AddFormat adds the format keyword to the schema.
+func (b *Builder) AddFormat(arg string) *Builder {
+	return b.AddString(&formatKeyword, arg)
+}
+
+// AddTitle adds the title keyword to the schema.
+func (b *Builder) AddTitle(arg string) *Builder {
+	return b.AddString(&titleKeyword, arg)
+}
+
+// AddDescription adds the description keyword to the schema.
+func (b *Builder) AddDescription(arg string) *Builder {
+	return b.AddString(&descriptionKeyword, arg)
+}
+
+// AddDefault adds the default keyword to the schema.
+func (b *Builder) AddDefault(arg any) *Builder {
+	return b.AddAny(&defaultKeyword, arg)
+}
+
+// sortRank is the ranking of each keyword when sorting
+var sortRank = map[string]int{
+	"allOf":                0,
+	"anyOf":                1,
+	"default":              2,
+	"dependencies":         3,
+	"description":          4,
+	"enum":                 5,
+	"exclusiveMaximum":     6,
+	"exclusiveMinimum":     7,
+	"format":               8,
+	"id":                   9,
+	"$ref":                 10,
+	"definitions":          11,
+	"items":                12,
+	"additionalItems":      13,
+	"maxItems":             14,
+	"maxLength":            15,
+	"maxProperties":        16,
+	"maximum":              17,
+	"minItems":             18,
+	"minLength":            19,
+	"minProperties":        20,
+	"minimum":              21,
+	"multipleOf":           22,
+	"not":                  23,
+	"oneOf":                24,
+	"pattern":              25,
+	"patternProperties":    26,
+	"properties":           27,
+	"additionalProperties": 28,
+	"required":             29,
+	"title":                30,
+	"type":                 31,
+	"uniqueItems":          32,
+}
+
+// keywordCmp is the keyword comparison routine.
+func keywordCmp(a, b string) int {
+	return cmp.Compare(sortRank[a], sortRank[b])
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draft04
+
+import (
+	"fmt"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// validateRef validates a $ref keyword.
+// The reference is resolved by the draft 2020-12 resolver,
+// which records it with a generated keyword.
+func validateRef(arg schema.PartString, instance any, state *schema.ValidationState) error {
+	for _, part := range state.Schema.Parts {
+		if part.Keyword.Generated && part.Keyword.Name == schema.ResolvedRefKeywordName {
+			return part.Value.(schema.PartSchema).S.ValidateInPlaceSchema(instance, state)
+		}
+	}
+	// This should never happen.
+	return fmt.Errorf(`reference %q unresolved`, arg)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draft06_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/draft06"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in draft-06.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "` + draft06.SchemaID + `", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		valid    bool
+	}{
+		// Keywords next to $ref are ignored.
+		{`{"definitions": {"n": {"type": "number"}}, "$ref": "#/definitions/n", "maximum": 1}`, `5`, true},
+		{`{"definitions": {"n": {"type": "number"}}, "$ref": "#/definitions/n", "maximum": 1}`, `"x"`, false},
+		// A plain-name fragment in $id is an anchor.
+		{`{"definitions": {"s": {"$id": "#str", "type": "string"}}, "properties": {"a": {"$ref": "#str"}}}`, `{"a": "x"}`, true},
+		{`{"definitions": {"s": {"$id": "#str", "type": "string"}}, "properties": {"a": {"$ref": "#str"}}}`, `{"a": 1}`, false},
+		// items as a list, with additionalItems.
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`, `["a", 1, 2]`, true},
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`, `["a", "b"]`, false},
+		{`{"items": [{"type": "string"}], "additionalItems": false}`, `["a", 1]`, false},
+		// Numeric exclusive limits, const, contains and propertyNames.
+		{`{"exclusiveMaximum": 3}`, `3`, false},
+		{`{"exclusiveMinimum": 3}`, `3.5`, true},
+		{`{"const": {"a": 1}}`, `{"a": 1}`, true},
+		{`{"contains": {"type": "integer"}}`, `["a"]`, false},
+		{`{"propertyNames": {"maxLength": 2}}`, `{"abc": 1}`, false},
+		{`{"dependencies": {"a": ["b"], "c": {"required": ["d"]}}}`, `{"a": 1, "b": 2, "c": 3}`, false},
+		// Keywords from later drafts are not known.
+		{`{"if": {"type": "string"}, "then": {"maxLength": 1}}`, `"abc"`, true},
+		{`{"prefixItems": [{"type": "string"}]}`, `[1]`, true},
+		{`{"dependentRequired": {"a": ["b"]}}`, `{"a": 1}`, true},
+		{`{"unevaluatedProperties": false}`, `{"a": 1}`, true},
+	}
+	for _, test := range tests {
+		s := decode(t, test.schema)
+		var v any
+		if err := json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: %s: got error %v, want valid %t", test.schema, test.instance, err, test.valid)
+		}
+	}
+}
+
+func TestMetaSchema(t *testing.T) {
+	meta := draft06.MetaSchema()
+	tests := []struct {
+		schema string
+		valid  bool
+	}{
+		{`{"type": "string", "maxLength": 3}`, true},
+		{`{"items": [{}, {}], "additionalItems": false}`, true},
+		{`{"exclusiveMaximum": 3}`, true},
+		{`{"exclusiveMaximum": true}`, false},
+		{`{"maxLength": -1}`, false},
+		{`{"type": "strings"}`, false},
+	}
+	for _, test := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(test.schema), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := meta.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.schema, err, test.valid)
+		}
+	}
+}
@@ -5,12 +5,15 @@
 package draft202012
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"reflect"
 	"strings"
 
+	"github.com/altshiftab/jsonschema/internal/dynscope"
 	"github.com/altshiftab/jsonschema/internal/schemacache"
+	"github.com/altshiftab/jsonschema/internal/validator"
 	"github.com/altshiftab/jsonschema/pkg/builder"
 	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
@@ -49,8 +52,16 @@ func (b *Builder) Build() *schema.Schema {
 // NewSubBuilder returns a new [Builder] with the same vocabulary.
 // This is like the [NewSubBuilder] function in that it is for schemas
 // that will be part of some larger schema.
+// The new Builder is in strict mode if b is.
 func (b *Builder) NewSubBuilder() *Builder {
-	return &Builder{builder.New(Vocabulary)}
+	return &Builder{b.b.NewBuilder()}
+}
+
+// SetStrict sets whether b is in strict mode.
+// See [builder.Builder.SetStrict].
+func (b *Builder) SetStrict(strict bool) *Builder {
+	b.b.SetStrict(strict)
+	return b
 }
 
 // BoolSchema returns a newly built schema.
@@ -89,12 +100,25 @@ func (b *Builder) AddItemsSchema(s *schema.Schema) *Builder {
 
 // resolveState holds state during resolveSchema.
 type resolveState struct {
-	ropts   *schema.ResolveOpts
-	root    *schema.Schema
-	schemas map[*schema.Schema]schemaData
-	uris    map[string]*schema.Schema
-	anchors map[string]anchorData
-	cache   schemacache.Cache
+	ropts     *schema.ResolveOpts
+	idKeyword string // the name of the $id keyword
+	idAnchors bool   // whether $id may define an anchor
+	root      *schema.Schema
+	schemas   map[*schema.Schema]schemaData
+	uris      map[string]*schema.Schema
+	anchors   map[string]anchorData
+	cache     schemacache.Cache
+	// loads is the number of loaded schemas being resolved,
+	// each referred to by the one before.
+	loads int
+}
+
+// addURI records that uri refers to s.
+func (state *resolveState) addURI(uri string, s *schema.Schema) {
+	if state.uris == nil {
+		state.uris = make(map[string]*schema.Schema)
+	}
+	state.uris[uri] = s
 }
 
 // schemaData is information we keep for some schemas.
@@ -123,14 +147,37 @@ func (si subInfo) Name() string {
 // It is called to resolve a schema decoded from JSON to
 // handle $ref and friends.
 func resolveSchema(schema *schema.Schema, ropts *schema.ResolveOpts) error {
+	return resolve(schema, ropts, "$id", false)
+}
+
+// ResolveWithIDAnchors resolves references in s as the Resolve
+// field of [Vocabulary] does, but the keyword that sets the base
+// URI is named idKeyword, such as "$id", or "id" for draft-04,
+// and it may end in a plain-name fragment, as in "#item" or
+// "other.json#item", which defines an anchor. This is how drafts
+// before 2019-09 define anchors. It is for the vocabularies of
+// those drafts, whose other core keywords are handled as for
+// draft 2020-12.
+func ResolveWithIDAnchors(s *schema.Schema, ropts *schema.ResolveOpts, idKeyword string) error {
+	return resolve(s, ropts, idKeyword, true)
+}
+
+// resolve implements resolveSchema and ResolveWithIDAnchors.
+func resolve(schema *schema.Schema, ropts *schema.ResolveOpts, idKeyword string, idAnchors bool) error {
 	state := &resolveState{
-		ropts: ropts,
-		root:  schema,
+		ropts:     ropts,
+		idKeyword: idKeyword,
+		idAnchors: idAnchors,
+		root:      schema,
 	}
 	var uri *url.URL
 	if ropts != nil {
 		uri = ropts.URI
 	}
+	if uri != nil {
+		// References to the root by its URI refer to this schema.
+		state.addURI(uri.String(), schema)
+	}
 	return resolveRefSchema(uri, schema, state)
 }
 
@@ -142,7 +189,74 @@ func resolveRefSchema(uri *url.URL, schema *schema.Schema, state *resolveState)
 	if err := resolveIDs(schema, schema, state, subData); err != nil {
 		return err
 	}
-	return resolveRefs(schema, state, subData)
+	if err := resolveRefs(schema, state, subData); err != nil {
+		return err
+	}
+	precompute(schema)
+	return nil
+}
+
+// precompute records information that speeds up validation:
+// the types matched by the branches of the anyOf and oneOf keywords,
+// so that validation can skip branches that can't match,
+// the branches of the allOf keyword merged into one, if they are
+// simple object schemas, and the compiled regexps of the
+// patternProperties keyword. It also compiles the regexps of the
+// pattern keyword, which the validator caches. The regexp keywords
+// are found by name, so that the other drafts, which resolve
+// schemas here, compile them too.
+func precompute(subSchema *schema.Schema) {
+	if subSchema == nil {
+		return
+	}
+
+	var add []schema.Part
+	for _, part := range subSchema.Parts {
+		var (
+			keyword *schema.Keyword
+			pv      schema.PartValue
+			ok      bool
+		)
+		switch part.Keyword {
+		case &anyOfKeyword:
+			keyword = &validator.AnyOfTypesKeyword
+			pv, ok = validator.BranchTypes(part.Value.(schema.PartSchemas))
+		case &oneOfKeyword:
+			keyword = &validator.OneOfTypesKeyword
+			pv, ok = validator.BranchTypes(part.Value.(schema.PartSchemas))
+		case &allOfKeyword:
+			keyword = &validator.AllOfMergedKeyword
+			pv, ok = validator.MergeAllOf(part.Value.(schema.PartSchemas))
+		case &validator.AnyOfTypesKeyword, &validator.OneOfTypesKeyword, &validator.AllOfMergedKeyword, &validator.PatternPropertiesRegexpsKeyword:
+			// Already done.
+			return
+		default:
+			switch arg := part.Value.(type) {
+			case schema.PartMapSchema:
+				if part.Keyword.Name == "patternProperties" {
+					keyword = &validator.PatternPropertiesRegexpsKeyword
+					pv, ok = validator.PatternPropertiesRegexps(arg)
+				}
+			case schema.PartString:
+				if part.Keyword.Name == "pattern" {
+					// An invalid regexp is reported
+					// when validating the keyword.
+					validator.CompilePattern(arg)
+				}
+			}
+		}
+		if ok {
+			add = append(add, schema.Part{
+				Keyword: keyword,
+				Value:   pv,
+			})
+		}
+	}
+	subSchema.Parts = append(subSchema.Parts, add...)
+
+	for _, subsub := range subSchema.Children() {
+		precompute(subsub)
+	}
 }
 
 // resolveIDs finds the IDs and anchors in a schema.
@@ -155,7 +269,7 @@ func resolveIDs(subSchema, base *schema.Schema, state *resolveState, subData sub
 	for _, part := range subSchema.Parts {
 		var err error
 		switch part.Keyword.Name {
-		case "$id":
+		case state.idKeyword:
 			err, subData = resolveID(subSchema, part.Value, state, subData)
 			base = subSchema
 		case "$anchor":
@@ -178,36 +292,10 @@ func resolveIDs(subSchema, base *schema.Schema, state *resolveState, subData sub
 	}
 
 	if dynamicAnchor != "" {
-		// Add special keywords to set and clear the dynamic
-		// anchor during validation. These keywords need to be
-		// added to the root schema, but only if the root doesn't
-		// already have a dynamic anchor. This implements the
-		// dynamic scoping that resolves to the outermost anchor.
-
-		sawDynamicAnchor := false
-		for _, part := range base.Parts {
-			if part.Keyword == &recordDynamicAnchorKeyword {
-				sawDynamicAnchor = true
-				break
-			}
-		}
-		if !sawDynamicAnchor {
-			val := &recordDynamicAnchor{
-				anchor: dynamicAnchor,
-				schema: subSchema,
-			}
-			recordDynamicAnchor := schema.Part{
-				Keyword: &recordDynamicAnchorKeyword,
-				Value:   schema.PartAny{val},
-			}
-			base.Parts = append([]schema.Part{recordDynamicAnchor}, base.Parts...)
-			base.Parts = append(base.Parts,
-				schema.Part{
-					Keyword: &clearDynamicAnchorKeyword,
-					Value:   schema.PartAny{val},
-				},
-			)
-		}
+		// Record the dynamic anchor on the root schema
+		// during validation. This implements the dynamic
+		// scoping that resolves to the outermost anchor.
+		dynscope.AddAnchor(base, subSchema, dynamicAnchor)
 	}
 
 	for name, subsub := range subSchema.Children() {
@@ -230,24 +318,37 @@ func resolveID(subSchema *schema.Schema, value schema.PartValue, state *resolveS
 	if err != nil {
 		return fmt.Errorf(`%s: failed to parse "$id" %q: %v`, subData.Name(), arg, err), subInfo{}
 	}
+	var anchor string
 	if uri.Fragment != "" {
-		return fmt.Errorf(`%s: "$id" %q contains non-empty fragment`, subData.Name(), err), subInfo{}
-	}
-	var newURI *url.URL
-	if uri.IsAbs() || subData.uri == nil {
-		newURI = uri
-	} else {
-		newURI = subData.uri.ResolveReference(uri)
-	}
+		if !state.idAnchors || strings.HasPrefix(uri.Fragment, "/") {
+			return fmt.Errorf(`%s: "$id" %q contains non-empty fragment`, subData.Name(), err), subInfo{}
+		}
+		anchor = uri.Fragment
+		uri.Fragment = ""
+		uri.RawFragment = ""
+	}
+
+	si := subData
+	if anchor == "" || uri.String() != "" {
+		var newURI *url.URL
+		if uri.IsAbs() || subData.uri == nil {
+			newURI = uri
+		} else {
+			newURI = subData.uri.ResolveReference(uri)
+		}
 
-	if state.uris == nil {
-		state.uris = make(map[string]*schema.Schema)
+		state.addURI(newURI.String(), subSchema)
+
+		si = subInfo{
+			uri:  newURI,
+			name: subData.name,
+		}
 	}
-	state.uris[newURI.String()] = subSchema
 
-	si := subInfo{
-		uri:  newURI,
-		name: subData.name,
+	if anchor != "" {
+		if _, err := resolveAnchor(subSchema, false, schema.PartString(anchor), state, si); err != nil {
+			return err, subInfo{}
+		}
 	}
 	return nil, si
 }
@@ -356,14 +457,14 @@ func resolveRef(subSchema *schema.Schema, dynamic bool, value schema.PartValue,
 		if detached {
 			// This is a backup for a $dynamicRef to a
 			// $dynamicAnchor, to be used if we skip over
-			// the recordDynamicAnchor.
+			// the dynamic anchor record.
 			resolvedKey = &detachedDynamicRefKeyword
 		}
 
 		subSchema.Parts = append(subSchema.Parts,
 			schema.Part{
 				Keyword: resolvedKey,
-				Value:   schema.PartSchema{refSchema},
+				Value:   schema.PartSchema{S: refSchema},
 			},
 		)
 	}
@@ -451,8 +552,20 @@ func resolveURI(refURI *url.URL, state *resolveState, subData subInfo) (*schema.
 		return refSchema, nil
 	}
 
+	limit := state.ropts.MaxDepth
+	if limit <= 0 {
+		limit = schema.DefaultMaxDepth
+	}
+	if state.loads >= limit {
+		return nil, fmt.Errorf("%s: loading of URI %q: %w", subData.Name(), noFragURI, &schema.MaxDepthError{Op: "resolving", Limit: limit})
+	}
+
 	// Load the schema remotely.
-	refSchema, err = state.ropts.Loader(SchemaID, noFragURI)
+	ctx := state.ropts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	refSchema, err = state.ropts.Loader.Load(ctx, SchemaID, noFragURI)
 	if err != nil {
 		return nil, fmt.Errorf("%s: loading of URI %q failed: %v", subData.Name(), noFragURI, err)
 	}
@@ -465,8 +578,11 @@ func resolveURI(refURI *url.URL, state *resolveState, subData subInfo) (*schema.
 	state.cache.Store(SchemaID, noFragStr, refSchema)
 
 	// Resolve the schema in the current resolution state.
-	if err := resolveRefSchema(noFragURI, refSchema, state); err != nil {
-		return nil, fmt.Errorf("%s: resolving schema at URI %q failed: %v", subData.Name(), noFragURI, err)
+	state.loads++
+	err = resolveRefSchema(noFragURI, refSchema, state)
+	state.loads--
+	if err != nil {
+		return nil, fmt.Errorf("%s: resolving schema at URI %q failed: %w", subData.Name(), noFragURI, err)
 	}
 
 	return refSchema, nil
@@ -2,10 +2,11 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:generate go run ../internal/cmd/keywordgen -p draft202012 -o keywords.go corekeywords.json applicatorkeywords.json
+//go:generate go run ../../cmd/keywordgen -p draft202012 -o keywords.go corekeywords.json applicatorkeywords.json
 
 // Package draft202012 defines the keywords used by
 // JSON schema version 2020-12.
+// Programs may add their own keywords with [RegisterKeyword].
 package draft202012
 
 import (
@@ -18,10 +19,55 @@ var Vocabulary = &schema.Vocabulary{
 	Name:     "draft2020-12",
 	Schema:   SchemaID,
 	Keywords: keywordMap,
-	Cmp:      keywordCmp,
+	Cmp:      vocabularyCmp,
 	Resolve:  resolveSchema,
+	Docs:     keywordDocs,
+	// Keywords next to $ref apply along with the referenced schema.
+	IgnoreRefSiblings: false,
+}
+
+// vocabularyURIs maps the vocabulary URIs of draft 2020-12,
+// which a meta-schema may name in its $vocabulary keyword,
+// to their keywords.
+var vocabularyURIs = map[string][]string{
+	"https://json-schema.org/draft/2020-12/vocab/core": {
+		"$id", "$ref", "$anchor", "$dynamicRef", "$dynamicAnchor",
+		"$vocabulary", "$comment", "$defs",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/applicator": {
+		"prefixItems", "items", "contains", "additionalProperties",
+		"properties", "patternProperties", "dependentSchemas",
+		"propertyNames", "if", "then", "else", "allOf", "anyOf",
+		"oneOf", "not",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/unevaluated": {
+		"unevaluatedItems", "unevaluatedProperties",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/validation": {
+		"type", "const", "enum", "multipleOf", "maximum",
+		"exclusiveMaximum", "minimum", "exclusiveMinimum",
+		"maxLength", "minLength", "pattern", "maxItems", "minItems",
+		"uniqueItems", "maxContains", "minContains", "maxProperties",
+		"minProperties", "required", "dependentRequired",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/meta-data": {
+		"title", "description", "default", "deprecated",
+		"readOnly", "writeOnly", "examples",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/format-annotation": {
+		"format",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/format-assertion": {
+		"format",
+	},
+	"https://json-schema.org/draft/2020-12/vocab/content": {
+		"contentEncoding", "contentMediaType", "contentSchema",
+	},
 }
 
 func init() {
 	schema.RegisterVocabulary(Vocabulary, true)
+	for uri, keywords := range vocabularyURIs {
+		schema.RegisterVocabularyURI(uri, Vocabulary, keywords)
+	}
 }
@@ -14,14 +14,13 @@ import (
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
 
-var _ = validator.ValidateTrue // avoid warning if we don't use validator below
-
 var (
 	vocabularyKeyword = schema.Keyword{
 		Name:      "$vocabulary",
 		ArgType:   arg_type.ArgTypeAny,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 
 	idKeyword = schema.Keyword{
@@ -29,6 +28,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 
 	anchorKeyword = schema.Keyword{
@@ -36,6 +36,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 
 	dynamicAnchorKeyword = schema.Keyword{
@@ -43,6 +44,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 
 	refKeyword = schema.Keyword{
@@ -50,6 +52,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ArgTypeString(validateRef),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	dynamicRefKeyword = schema.Keyword{
@@ -57,6 +60,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ArgTypeString(validateDynamicRef),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	defsKeyword = schema.Keyword{
@@ -64,6 +68,7 @@ var (
 		ArgType:   arg_type.ArgTypeMapSchema,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 
 	commentKeyword = schema.Keyword{
@@ -71,6 +76,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ValidateTrue,
 		Generated: false,
+		Leaf:      true,
 	}
 )
 
@@ -80,6 +86,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchemas,
 		Validate:  validator.ArgTypeSchemas(validator.ValidateAllOf),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	anyOfKeyword = schema.Keyword{
@@ -87,6 +94,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchemas,
 		Validate:  validator.ArgTypeSchemas(validator.ValidateAnyOf),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	oneOfKeyword = schema.Keyword{
@@ -94,6 +102,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchemas,
 		Validate:  validator.ArgTypeSchemas(validator.ValidateOneOf),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	notKeyword = schema.Keyword{
@@ -101,6 +110,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateNot),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	ifKeyword = schema.Keyword{
@@ -108,6 +118,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateIf),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	thenKeyword = schema.Keyword{
@@ -115,6 +126,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateThen),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	elseKeyword = schema.Keyword{
@@ -122,6 +134,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateElse),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	dependentSchemasKeyword = schema.Keyword{
@@ -129,6 +142,7 @@ var (
 		ArgType:   arg_type.ArgTypeMapSchema,
 		Validate:  validator.ArgTypeMapSchema(validator.ValidateDependentSchemas),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	prefixItemsKeyword = schema.Keyword{
@@ -136,6 +150,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchemas,
 		Validate:  validator.ArgTypeSchemas(validator.ValidatePrefixItems),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	itemsKeyword = schema.Keyword{
@@ -143,6 +158,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateItems),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	containsKeyword = schema.Keyword{
@@ -150,6 +166,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateContains),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	propertiesKeyword = schema.Keyword{
@@ -157,6 +174,7 @@ var (
 		ArgType:   arg_type.ArgTypeMapSchema,
 		Validate:  validator.ArgTypeMapSchema(validator.ValidateProperties),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	patternPropertiesKeyword = schema.Keyword{
@@ -164,6 +182,7 @@ var (
 		ArgType:   arg_type.ArgTypeMapSchema,
 		Validate:  validator.ArgTypeMapSchema(validator.ValidatePatternProperties),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	additionalPropertiesKeyword = schema.Keyword{
@@ -171,6 +190,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateAdditionalProperties),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	propertyNamesKeyword = schema.Keyword{
@@ -178,6 +198,7 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidatePropertyNames),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	unevaluatedItemsKeyword = schema.Keyword{
@@ -185,6 +206,8 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateUnevaluatedItems),
 		Generated: false,
+		Leaf:      false,
+		UsesNotes: true,
 	}
 
 	unevaluatedPropertiesKeyword = schema.Keyword{
@@ -192,6 +215,8 @@ var (
 		ArgType:   arg_type.ArgTypeSchema,
 		Validate:  validator.ArgTypeSchema(validator.ValidateUnevaluatedProperties),
 		Generated: false,
+		Leaf:      false,
+		UsesNotes: true,
 	}
 
 	typeKeyword = schema.Keyword{
@@ -199,6 +224,7 @@ var (
 		ArgType:   arg_type.ArgTypeStringOrStrings,
 		Validate:  validator.ArgTypeStringOrStrings(validator.ValidateType),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	enumKeyword = schema.Keyword{
@@ -206,6 +232,7 @@ var (
 		ArgType:   arg_type.ArgTypeAny,
 		Validate:  validator.ArgTypeAny(validator.ValidateEnum),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	constKeyword = schema.Keyword{
@@ -213,6 +240,7 @@ var (
 		ArgType:   arg_type.ArgTypeAny,
 		Validate:  validator.ArgTypeAny(validator.ValidateConst),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	multipleOfKeyword = schema.Keyword{
@@ -220,6 +248,7 @@ var (
 		ArgType:   arg_type.ArgTypeFloat,
 		Validate:  validator.ArgTypeFloat(validator.ValidateMultipleOf),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	maximumKeyword = schema.Keyword{
@@ -227,6 +256,7 @@ var (
 		ArgType:   arg_type.ArgTypeFloat,
 		Validate:  validator.ArgTypeFloat(validator.ValidateMaximum),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	exclusiveMaximumKeyword = schema.Keyword{
@@ -234,6 +264,7 @@ var (
 		ArgType:   arg_type.ArgTypeFloat,
 		Validate:  validator.ArgTypeFloat(validator.ValidateExclusiveMaximum),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	minimumKeyword = schema.Keyword{
@@ -241,6 +272,7 @@ var (
 		ArgType:   arg_type.ArgTypeFloat,
 		Validate:  validator.ArgTypeFloat(validator.ValidateMinimum),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	exclusiveMinimumKeyword = schema.Keyword{
@@ -248,6 +280,7 @@ var (
 		ArgType:   arg_type.ArgTypeFloat,
 		Validate:  validator.ArgTypeFloat(validator.ValidateExclusiveMinimum),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	maxLengthKeyword = schema.Keyword{
@@ -255,6 +288,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMaxLength),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	minLengthKeyword = schema.Keyword{
@@ -262,6 +296,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMinLength),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	patternKeyword = schema.Keyword{
@@ -269,6 +304,7 @@ var (
 		ArgType:   arg_type.ArgTypeString,
 		Validate:  validator.ArgTypeString(validator.ValidatePattern),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	maxItemsKeyword = schema.Keyword{
@@ -276,6 +312,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMaxItems),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	minItemsKeyword = schema.Keyword{
@@ -283,6 +320,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMinItems),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	uniqueItemsKeyword = schema.Keyword{
@@ -290,6 +328,7 @@ var (
 		ArgType:   arg_type.ArgTypeBool,
 		Validate:  validator.ArgTypeBool(validator.ValidateUniqueItems),
 		Generated: false,
+		Leaf:      true,
 	}
 
 	maxContainsKeyword = schema.Keyword{
@@ -297,6 +336,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMaxContains),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	minContainsKeyword = schema.Keyword{
@@ -304,6 +344,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMinContains),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	maxPropertiesKeyword = schema.Keyword{
@@ -311,6 +352,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMaxProperties),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	minPropertiesKeyword = schema.Keyword{
@@ -318,6 +360,7 @@ var (
 		ArgType:   arg_type.ArgTypeInt,
 		Validate:  validator.ArgTypeInt(validator.ValidateMinProperties),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	requiredKeyword = schema.Keyword{
@@ -325,6 +368,7 @@ var (
 		ArgType:   arg_type.ArgTypeStrings,
 		Validate:  validator.ArgTypeStrings(validator.ValidateRequired),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	dependentRequiredKeyword = schema.Keyword{
@@ -332,83 +376,105 @@ var (
 		ArgType:   arg_type.ArgTypeAny,
 		Validate:  validator.ArgTypeAny(validator.ValidateDependentRequired),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	formatKeyword = schema.Keyword{
-		Name:      "format",
-		ArgType:   arg_type.ArgTypeString,
-		Validate:  validator.ArgTypeString(validator.ValidateFormat),
-		Generated: false,
+		Name:       "format",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ArgTypeString(validator.ValidateFormat),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
 	}
 
 	contentEncodingKeyword = schema.Keyword{
-		Name:      "contentEncoding",
-		ArgType:   arg_type.ArgTypeString,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "contentEncoding",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ArgTypeString(validator.ValidateContentEncoding),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
 	}
 
 	contentMediaTypeKeyword = schema.Keyword{
-		Name:      "contentMediaType",
-		ArgType:   arg_type.ArgTypeString,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "contentMediaType",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ArgTypeString(validator.ValidateContentMediaType),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
 	}
 
 	contentSchemaKeyword = schema.Keyword{
 		Name:      "contentSchema",
 		ArgType:   arg_type.ArgTypeSchema,
-		Validate:  validator.ValidateTrue,
+		Validate:  validator.ArgTypeSchema(validator.ValidateContentSchema),
 		Generated: false,
+		Leaf:      false,
 	}
 
 	titleKeyword = schema.Keyword{
-		Name:      "title",
-		ArgType:   arg_type.ArgTypeString,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "title",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	descriptionKeyword = schema.Keyword{
-		Name:      "description",
-		ArgType:   arg_type.ArgTypeString,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "description",
+		ArgType:    arg_type.ArgTypeString,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	defaultKeyword = schema.Keyword{
-		Name:      "default",
-		ArgType:   arg_type.ArgTypeAny,
-		Validate:  validator.ArgTypeAny(validator.ValidateDefault),
-		Generated: false,
+		Name:       "default",
+		ArgType:    arg_type.ArgTypeAny,
+		Validate:   validator.ArgTypeAny(validator.ValidateDefault),
+		Generated:  false,
+		Leaf:       false,
+		Annotation: true,
 	}
 
 	deprecatedKeyword = schema.Keyword{
-		Name:      "deprecated",
-		ArgType:   arg_type.ArgTypeBool,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "deprecated",
+		ArgType:    arg_type.ArgTypeBool,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	readOnlyKeyword = schema.Keyword{
-		Name:      "readOnly",
-		ArgType:   arg_type.ArgTypeBool,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "readOnly",
+		ArgType:    arg_type.ArgTypeBool,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	writeOnlyKeyword = schema.Keyword{
-		Name:      "writeOnly",
-		ArgType:   arg_type.ArgTypeBool,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "writeOnly",
+		ArgType:    arg_type.ArgTypeBool,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	examplesKeyword = schema.Keyword{
-		Name:      "examples",
-		ArgType:   arg_type.ArgTypeAny,
-		Validate:  validator.ValidateTrue,
-		Generated: false,
+		Name:       "examples",
+		ArgType:    arg_type.ArgTypeAny,
+		Validate:   validator.ValidateTrue,
+		Generated:  false,
+		Leaf:       true,
+		Annotation: true,
 	}
 
 	dependenciesKeyword = schema.Keyword{
@@ -416,6 +482,7 @@ var (
 		ArgType:   arg_type.ArgTypeMapArrayOrSchema,
 		Validate:  validator.ArgTypeMapArrayOrSchema(validator.ValidateDependencies),
 		Generated: false,
+		Leaf:      false,
 	}
 )
 
@@ -480,6 +547,238 @@ var keywordMap = map[string]*schema.Keyword{
 	"dependencies":          &dependenciesKeyword,
 }
 
+// keywordDocs maps keyword names to their documentation.
+var keywordDocs = map[string]schema.KeywordDoc{
+	"$vocabulary": {
+		Description: "Declares the vocabularies used by a meta-schema, and whether each is required.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.1.2",
+	},
+	"$id": {
+		Description: "Sets the canonical URI of the schema, and the base URI for relative references within it.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.1",
+	},
+	"$anchor": {
+		Description: "Defines a plain name fragment that can be used to refer to the schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.2",
+	},
+	"$dynamicAnchor": {
+		Description: "Defines a name fragment that a $dynamicRef can resolve to dynamically.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.2",
+	},
+	"$ref": {
+		Description: "Applies the schema at the given URI reference to the instance.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.3.1",
+	},
+	"$dynamicRef": {
+		Description: "Like $ref, but resolved using the dynamic scope of the evaluation.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.3.2",
+	},
+	"$defs": {
+		Description: "Holds reusable schemas that other schemas can refer to.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.2.4",
+	},
+	"$comment": {
+		Description: "A comment for schema maintainers; it has no effect on validation.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-8.3",
+	},
+	"allOf": {
+		Description: "The instance must be valid against all of the schemas.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.1.1",
+	},
+	"anyOf": {
+		Description: "The instance must be valid against at least one of the schemas.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.1.2",
+	},
+	"oneOf": {
+		Description: "The instance must be valid against exactly one of the schemas.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.1.3",
+	},
+	"not": {
+		Description: "The instance must not be valid against the schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.1.4",
+	},
+	"if": {
+		Description: "Selects whether then or else applies, based on whether the instance is valid against this schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.2.1",
+	},
+	"then": {
+		Description: "Applies to the instance if it is valid against the if schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.2.2",
+	},
+	"else": {
+		Description: "Applies to the instance if it is not valid against the if schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.2.3",
+	},
+	"dependentSchemas": {
+		Description: "Applies a schema to the object if it has the given property.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.2.2.4",
+	},
+	"prefixItems": {
+		Description: "Applies each schema to the array element at the same position.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.1.1",
+	},
+	"items": {
+		Description: "Applies the schema to every array element not covered by prefixItems.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.1.2",
+	},
+	"contains": {
+		Description: "At least one array element must be valid against the schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.1.3",
+	},
+	"properties": {
+		Description: "Applies each schema to the object property with the same name.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.2.1",
+	},
+	"patternProperties": {
+		Description: "Applies each schema to the object properties whose names match the regular expression.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.2.2",
+	},
+	"additionalProperties": {
+		Description: "Applies the schema to object properties not covered by properties or patternProperties.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.2.3",
+	},
+	"propertyNames": {
+		Description: "Every object property name must be valid against the schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-10.3.2.4",
+	},
+	"unevaluatedItems": {
+		Description: "Applies the schema to array elements not evaluated by any other keyword.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-11.2",
+	},
+	"unevaluatedProperties": {
+		Description: "Applies the schema to object properties not evaluated by any other keyword.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-core.html#section-11.3",
+	},
+	"type": {
+		Description: "The instance must have the given JSON type, or one of the given types.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.1.1",
+	},
+	"enum": {
+		Description: "The instance must be equal to one of the values.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.1.2",
+	},
+	"const": {
+		Description: "The instance must be equal to the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.1.3",
+	},
+	"multipleOf": {
+		Description: "A number must be a multiple of the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.2.1",
+	},
+	"maximum": {
+		Description: "A number must be less than or equal to the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.2.2",
+	},
+	"exclusiveMaximum": {
+		Description: "A number must be less than the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.2.3",
+	},
+	"minimum": {
+		Description: "A number must be greater than or equal to the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.2.4",
+	},
+	"exclusiveMinimum": {
+		Description: "A number must be greater than the value.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.2.5",
+	},
+	"maxLength": {
+		Description: "A string must have at most this many characters.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.3.1",
+	},
+	"minLength": {
+		Description: "A string must have at least this many characters.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.3.2",
+	},
+	"pattern": {
+		Description: "A string must match the regular expression.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.3.3",
+	},
+	"maxItems": {
+		Description: "An array must have at most this many elements.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.4.1",
+	},
+	"minItems": {
+		Description: "An array must have at least this many elements.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.4.2",
+	},
+	"uniqueItems": {
+		Description: "If true, the elements of an array must all be different.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.4.3",
+	},
+	"maxContains": {
+		Description: "At most this many array elements may be valid against contains.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.4.4",
+	},
+	"minContains": {
+		Description: "At least this many array elements must be valid against contains.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.4.5",
+	},
+	"maxProperties": {
+		Description: "An object must have at most this many properties.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.5.1",
+	},
+	"minProperties": {
+		Description: "An object must have at least this many properties.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.5.2",
+	},
+	"required": {
+		Description: "An object must have all of the named properties.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.5.3",
+	},
+	"dependentRequired": {
+		Description: "If an object has the given property, it must also have the listed properties.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-6.5.4",
+	},
+	"format": {
+		Description: "Names a semantic format, such as date-time or email, that a string should follow.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-7",
+	},
+	"contentEncoding": {
+		Description: "Names the encoding, such as base64, used to store binary data in a string.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-8.3",
+	},
+	"contentMediaType": {
+		Description: "Names the media type of the contents of a string.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-8.4",
+	},
+	"contentSchema": {
+		Description: "Describes the decoded contents of a string.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-8.5",
+	},
+	"title": {
+		Description: "A short title for the instance.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.1",
+	},
+	"description": {
+		Description: "An explanation of the purpose of the instance.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.1",
+	},
+	"default": {
+		Description: "A default value for the instance.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.2",
+	},
+	"deprecated": {
+		Description: "If true, the instance should no longer be used.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.3",
+	},
+	"readOnly": {
+		Description: "If true, the value is managed by its owner and should not be modified.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.4",
+	},
+	"writeOnly": {
+		Description: "If true, the value is never returned when the instance is retrieved.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.4",
+	},
+	"examples": {
+		Description: "Sample values that are valid against the schema.",
+		Link:        "https://json-schema.org/draft/2020-12/json-schema-validation.html#section-9.5",
+	},
+	"dependencies": {
+		Description: "Replaced by dependentSchemas and dependentRequired; kept for compatibility with draft 7.",
+		Link:        "https://json-schema.org/draft-07/json-schema-validation.html#rfc.section.6.5.7",
+	},
+}
+
 // AddBool adds a keyword with an argument of type Bool.
 func (b *Builder) AddBool(keyword *schema.Keyword, v bool) *Builder {
 	b.b = b.b.AddBool(keyword, v)
@@ -546,6 +845,12 @@ func (b *Builder) AddAny(keyword *schema.Keyword, v any) *Builder {
 	return b
 }
 
+// AddMapMapSchema adds a keyword with an argument of type MapMapSchema.
+func (b *Builder) AddMapMapSchema(keyword *schema.Keyword, v map[string]map[string]*schema.Schema) *Builder {
+	b.b = b.b.AddMapMapSchema(keyword, v)
+	return b
+}
+
 // AddComment adds the $comment keyword to the schema.
 func (b *Builder) AddComment(arg string) *Builder {
 	return b.AddString(&commentKeyword, arg)
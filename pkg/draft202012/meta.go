@@ -6,6 +6,7 @@ package draft202012
 
 import (
 	"embed"
+	"fmt"
 	"net/url"
 
 	"github.com/altshiftab/jsonschema/internal/metaschema"
@@ -21,3 +22,21 @@ var metaFS embed.FS
 func checkMetaSchema(uri *url.URL, ropts *schema.ResolveOpts) (*schema.Schema, error) {
 	return metaschema.Load(SchemaID, "/draft/2020-12/", &metaFS, uri, ropts)
 }
+
+// MetaSchema returns the draft 2020-12 meta-schema, which describes
+// valid draft 2020-12 schemas. It can be used to check a schema
+// before using it. The meta-schema is loaded from a copy embedded
+// in this package, so this does not access the network.
+//
+// The result is shared and must not be modified.
+func MetaSchema() *schema.Schema {
+	uri, err := url.Parse(SchemaID)
+	if err != nil {
+		panic(err)
+	}
+	s, err := checkMetaSchema(uri, nil)
+	if err != nil {
+		panic(fmt.Sprintf("loading embedded meta-schema: %v", err))
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draft202012
+
+import (
+	"cmp"
+	"fmt"
+	"sync"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// customKeywords records the keywords added by [RegisterKeyword].
+var customKeywords struct {
+	mu sync.Mutex
+	// after maps the name of an added keyword to the name of
+	// the keyword that it is sorted after.
+	after map[string]string
+}
+
+// RegisterKeyword adds the keyword k, with its documentation,
+// to [Vocabulary]. Unlike an extension keyword registered with
+// [schema.RegisterExtension], it is only recognized in
+// draft 2020-12 schemas, and it may have any name that
+// the vocabulary doesn't already define.
+//
+// Keywords are validated in sorted order, which matters for a
+// keyword that reads the notes or annotations of other keywords.
+// If after is not empty, it is the name of a keyword of the
+// vocabulary, possibly one added earlier by RegisterKeyword,
+// and k is sorted just after it. Otherwise k is sorted
+// with the keywords that come first, such as $comment.
+// Use [Builder.AddAny] and the similar methods to add k to a
+// [Builder].
+//
+// RegisterKeyword is meant to be called from an init function.
+// It panics if the vocabulary already has a keyword named k.Name,
+// or if after is not a keyword of the vocabulary.
+// It must not be called while schemas of the vocabulary are
+// being decoded or validated. Vocabularies that are built from
+// [Vocabulary], such as that of package draftnext,
+// do not see keywords registered after they were built.
+func RegisterKeyword(k *schema.Keyword, doc schema.KeywordDoc, after string) {
+	customKeywords.mu.Lock()
+	defer customKeywords.mu.Unlock()
+	if _, found := Vocabulary.Keywords[k.Name]; found {
+		panic(fmt.Sprintf("draft2020-12 already has a keyword %q", k.Name))
+	}
+	if after != "" {
+		if _, found := Vocabulary.Keywords[after]; !found {
+			panic(fmt.Sprintf("can't sort keyword %q after unknown keyword %q", k.Name, after))
+		}
+	}
+	if customKeywords.after == nil {
+		customKeywords.after = make(map[string]string)
+	}
+	customKeywords.after[k.Name] = after
+	Vocabulary.Keywords[k.Name] = k
+	Vocabulary.Docs[k.Name] = doc
+}
+
+// vocabularyCmp is the keyword comparison routine of [Vocabulary].
+// It sorts the keywords added by [RegisterKeyword] after the keywords
+// they name, and otherwise uses the generated ranking.
+func vocabularyCmp(a, b string) int {
+	ra, da := customRank(a)
+	rb, db := customRank(b)
+	return cmp.Or(cmp.Compare(ra, rb), cmp.Compare(da, db))
+}
+
+// customRank returns the rank of the keyword name and its depth,
+// the number of added keywords that it is sorted after.
+func customRank(name string) (rank, depth int) {
+	for {
+		after, ok := customKeywords.after[name]
+		if !ok {
+			return sortRank[name], depth
+		}
+		depth++
+		if after == "" {
+			return 0, depth
+		}
+		name = after
+	}
+}
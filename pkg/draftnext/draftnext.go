@@ -0,0 +1,40 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package draftnext defines the keywords used by the in-progress
+// release of JSON schema, often called draft-next.
+//
+// This package is EXPERIMENTAL. It tracks a specification that
+// has not been published, and its keywords and their behavior
+// will change as the specification changes, without regard to
+// compatibility. It is intended for trying out upcoming keywords,
+// not for production use.
+//
+// The vocabulary is the draft 2020-12 vocabulary plus the new
+// keywords, currently just "propertyDependencies".
+// Importing this package registers the vocabulary, but does not
+// make it the default; a schema must name [SchemaID] in its
+// "$schema" keyword to use it.
+package draftnext
+
+import (
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+const SchemaID = "https://json-schema.org/draft/next/schema"
+
+var Vocabulary = &schema.Vocabulary{
+	Name:     "draft-next",
+	Schema:   SchemaID,
+	Keywords: keywordMap(),
+	Cmp:      keywordCmp,
+	Resolve:  resolveSchema,
+	Docs:     keywordDocs(),
+	// Keywords next to $ref apply along with the referenced schema.
+	IgnoreRefSiblings: false,
+}
+
+func init() {
+	schema.RegisterVocabulary(Vocabulary, false)
+}
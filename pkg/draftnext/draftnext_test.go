@@ -0,0 +1,104 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draftnext_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/draftnext"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in draft-next.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "` + draftnext.SchemaID + `", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+func TestPropertyDependencies(t *testing.T) {
+	s := decode(t, `{
+		"propertyDependencies": {
+			"kind": {
+				"circle": {"required": ["radius"]},
+				"square": {"required": ["side"]}
+			}
+		}
+	}`)
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"kind": "circle", "radius": 1}`, true},
+		{`{"kind": "circle", "side": 1}`, false},
+		{`{"kind": "square", "side": 1}`, true},
+		{`{"kind": "triangle"}`, true},
+		{`{"kind": 1}`, true},
+		{`{}`, true},
+		{`"circle"`, true},
+	}
+	for _, test := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.instance, err, test.valid)
+		}
+	}
+}
+
+// TestPropertyDependenciesOrder checks that the properties are
+// visited in order, so that the same error is reported each time.
+func TestPropertyDependenciesOrder(t *testing.T) {
+	s := decode(t, `{
+		"propertyDependencies": {
+			"a": {"x": {"required": ["a1"]}},
+			"b": {"x": {"required": ["b1"]}},
+			"c": {"x": {"required": ["c1"]}},
+			"d": {"x": {"required": ["d1"]}}
+		}
+	}`)
+	instance := map[string]any{"a": "x", "b": "x", "c": "x", "d": "x"}
+	for _, failFast := range []bool{false, true} {
+		var first string
+		for range 20 {
+			err := s.ValidateWithOpts(instance, &schema.ValidateOpts{FailFast: failFast})
+			if err == nil {
+				t.Fatalf("FailFast %t: no error", failFast)
+			}
+			if first == "" {
+				first = err.Error()
+			} else if err.Error() != first {
+				t.Fatalf("FailFast %t: got %q, then %q", failFast, first, err)
+			}
+		}
+		if failFast && !strings.Contains(first, "propertyDependencies/a/x") {
+			t.Errorf("FailFast: got %q, want the error for a", first)
+		}
+	}
+}
+
+func TestMetaSchema(t *testing.T) {
+	meta := draftnext.MetaSchema()
+	s := map[string]any{
+		"propertyDependencies": map[string]any{
+			"kind": map[string]any{"circle": map[string]any{"required": []any{"radius"}}},
+		},
+	}
+	if err := meta.Validate(s); err != nil {
+		t.Errorf("valid schema: %v", err)
+	}
+	s["propertyDependencies"] = map[string]any{"kind": map[string]any{"circle": 1.0}}
+	if err := meta.Validate(s); err == nil {
+		t.Error("invalid schema: no error")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draftnext
+
+import (
+	"maps"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// propertyDependenciesKeyword is the "propertyDependencies" keyword.
+// It maps a property name and a property value to a schema
+// that the instance must match if that property has that value.
+var propertyDependenciesKeyword = schema.Keyword{
+	Name:     "propertyDependencies",
+	ArgType:  arg_type.ArgTypeMapMapSchema,
+	Validate: validator.ArgTypeMapMapSchema(validator.ValidatePropertyDependencies),
+}
+
+// keywordMap returns the keywords of the vocabulary:
+// the draft 2020-12 keywords plus the new ones.
+func keywordMap() map[string]*schema.Keyword {
+	m := maps.Clone(draft202012.Vocabulary.Keywords)
+	m["propertyDependencies"] = &propertyDependenciesKeyword
+	return m
+}
+
+// keywordDocs returns the documentation of the keywords.
+func keywordDocs() map[string]schema.KeywordDoc {
+	m := maps.Clone(draft202012.Vocabulary.Docs)
+	m["propertyDependencies"] = schema.KeywordDoc{
+		Description: "Applies a schema to the object if the given property has the given string value.",
+	}
+	return m
+}
+
+// sortAs maps a new keyword to the draft 2020-12 keyword
+// that it sorts with. Like dependentSchemas, propertyDependencies
+// must run before unevaluatedProperties.
+var sortAs = map[string]string{
+	"propertyDependencies": "dependentSchemas",
+}
+
+// keywordCmp is the keyword comparison routine.
+func keywordCmp(a, b string) int {
+	if s, ok := sortAs[a]; ok {
+		a = s
+	}
+	if s, ok := sortAs[b]; ok {
+		b = s
+	}
+	return draft202012.Vocabulary.Cmp(a, b)
+}
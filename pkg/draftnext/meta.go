@@ -0,0 +1,68 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package draftnext
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/metaschema"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+//go:embed metaschema/*.json metaschema/*/*.json
+var metaFS embed.FS
+
+// loadMetaSchema checks whether uri refers to the meta-schema,
+// and loads the schema if it does. If uri is not the meta-schema,
+// this returns nil, nil.
+//
+// Unlike the draft 2020-12 meta-schema, the result is not resolved,
+// as it is returned by a loader; see [schema.ResolveOpts].
+func loadMetaSchema(uri *url.URL) (*schema.Schema, error) {
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, nil
+	}
+	if uri.Host != "json-schema.org" {
+		return nil, nil
+	}
+	path, ok := strings.CutPrefix(uri.Path, "/draft/next/")
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := metaFS.ReadFile("metaschema/" + path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("can't find meta-schema URI %q: %v", uri, err)
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("can't parse meta-schema URI %q: %v", uri, err)
+	}
+	return schema.SchemaFromJSON(SchemaID, uri, v)
+}
+
+// MetaSchema returns the draft-next meta-schema, which describes
+// valid draft-next schemas. Like the rest of this package,
+// it will change as the specification changes.
+// The meta-schema is loaded from a copy embedded in this package,
+// so this does not access the network.
+//
+// The result is shared and must not be modified.
+func MetaSchema() *schema.Schema {
+	uri, err := url.Parse(SchemaID)
+	if err != nil {
+		panic(err)
+	}
+	s, err := metaschema.Load(SchemaID, "/draft/next/", &metaFS, uri, nil)
+	if err != nil {
+		panic(fmt.Sprintf("loading embedded meta-schema: %v", err))
+	}
+	return s
+}
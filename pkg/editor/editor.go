@@ -0,0 +1,259 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package editor provides information about schema documents
+// for editor integrations, such as hover text and completion
+// in a language server. Locations in a document are given as
+// JSON pointers; mapping a cursor position in the text to a
+// pointer is up to the editor integration.
+package editor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// KeywordInfo describes a keyword.
+type KeywordInfo struct {
+	// Name is the keyword name.
+	Name string
+	// Known reports whether the keyword is in the vocabulary.
+	// Unknown keywords are ignored during validation.
+	Known bool
+	// ArgType is the type of the keyword argument.
+	ArgType arg_type.ArgType
+	// Expected describes the expected argument, such as
+	// "array of strings".
+	Expected string
+	// Description and Link are the keyword documentation,
+	// if the vocabulary has any.
+	Description string
+	Link        string
+}
+
+// Info describes a location in a schema document.
+type Info struct {
+	// Vocabulary is the name of the active vocabulary.
+	Vocabulary string
+	// SchemaPointer is the JSON pointer to the innermost schema
+	// that contains the location.
+	SchemaPointer string
+	// Keyword is the keyword at the location, or the keyword whose
+	// argument contains the location. It is nil if the location
+	// is the schema at SchemaPointer itself.
+	Keyword *KeywordInfo
+	// InArgument reports whether the location is within
+	// the argument of Keyword, rather than the keyword itself.
+	InArgument bool
+	// Siblings are the keywords of the vocabulary that are not yet
+	// used by the schema at SchemaPointer, sorted by name.
+	// These are the candidates for completing a new keyword.
+	Siblings []KeywordInfo
+}
+
+// LookupJSON is like [Lookup] for a document in JSON format.
+func LookupJSON(data []byte, pointer string) (*Info, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return Lookup(doc, pointer)
+}
+
+// Lookup returns information about the location pointer in the
+// schema document doc, which has the form of JSON parsed into an
+// empty interface value. The location need not exist in doc,
+// so that an editor can ask about a keyword that is being typed.
+func Lookup(doc any, pointer string) (*Info, error) {
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	voc, err := vocabulary(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var toks []string
+	if pointer != "" {
+		toks = strings.Split(pointer[1:], "/")
+	}
+
+	info := &Info{Vocabulary: voc.Name}
+	cur := doc      // the current schema value, may be nil
+	schemaPtr := "" // the pointer to cur
+	for i := 0; i < len(toks); {
+		kwName := jsonpointer.UnescapeToken(toks[i])
+		kwPtr := schemaPtr + "/" + toks[i]
+		i++
+
+		obj, _ := cur.(map[string]any)
+		arg, hasArg := obj[kwName]
+		ki := keywordInfo(voc, kwName)
+		if i == len(toks) {
+			// The location is the keyword.
+			info.Keyword = &ki
+			break
+		}
+
+		// Work out whether the remaining tokens
+		// lead to a subschema within the argument.
+		sub, n, ok := subschema(ki, arg, hasArg, toks[i:])
+		if !ok {
+			info.Keyword = &ki
+			info.InArgument = true
+			break
+		}
+		cur = sub
+		schemaPtr = kwPtr
+		for _, tok := range toks[i : i+n] {
+			schemaPtr += "/" + tok
+		}
+		i += n
+	}
+
+	info.SchemaPointer = schemaPtr
+	obj, _ := cur.(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(voc.Keywords)) {
+		if voc.Keywords[name].Generated {
+			continue
+		}
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		info.Siblings = append(info.Siblings, keywordInfo(voc, name))
+	}
+
+	return info, nil
+}
+
+// vocabulary returns the vocabulary of the schema document doc.
+func vocabulary(doc any) (*schema.Vocabulary, error) {
+	if obj, ok := doc.(map[string]any); ok {
+		if id, ok := obj["$schema"]; ok {
+			s, ok := id.(string)
+			if !ok {
+				return nil, errors.New("$schema does not have a string value")
+			}
+			voc := schema.LookupVocabulary(s)
+			if voc == nil {
+				return nil, fmt.Errorf("JSON schema version %q not recognized", s)
+			}
+			return voc, nil
+		}
+	}
+	voc := schema.DefaultVocabulary()
+	if voc == nil {
+		return nil, errors.New("JSON schema version not specified and there is no default")
+	}
+	return voc, nil
+}
+
+// subschema reports whether the tokens toks, which follow
+// the keyword described by ki, lead to a subschema.
+// If they do, it returns the subschema value, which may be nil
+// if it is not in the document, and the number of tokens used.
+// arg is the argument of the keyword, if hasArg is true.
+func subschema(ki KeywordInfo, arg any, hasArg bool, toks []string) (any, int, bool) {
+	index := func(v any, tok string) (any, bool) {
+		i, err := strconv.Atoi(tok)
+		if err != nil || i < 0 {
+			return nil, false
+		}
+		if a, ok := v.([]any); ok && i < len(a) {
+			return a[i], true
+		}
+		return nil, true
+	}
+	member := func(v any, tok string) any {
+		m, _ := v.(map[string]any)
+		return m[jsonpointer.UnescapeToken(tok)]
+	}
+
+	switch ki.ArgType {
+	case arg_type.ArgTypeSchema:
+		return arg, 0, true
+
+	case arg_type.ArgTypeSchemas:
+		sub, ok := index(arg, toks[0])
+		return sub, 1, ok
+
+	case arg_type.ArgTypeMapSchema:
+		return member(arg, toks[0]), 1, true
+
+	case arg_type.ArgTypeSchemaOrSchemas:
+		if _, isArray := arg.([]any); isArray || !hasArg {
+			if sub, ok := index(arg, toks[0]); ok {
+				return sub, 1, true
+			}
+		}
+		return arg, 0, true
+
+	case arg_type.ArgTypeMapArrayOrSchema:
+		sub := member(arg, toks[0])
+		if _, isArray := sub.([]any); isArray {
+			return nil, 0, false
+		}
+		return sub, 1, true
+
+	case arg_type.ArgTypeMapMapSchema:
+		if len(toks) < 2 {
+			return nil, 0, false
+		}
+		return member(member(arg, toks[0]), toks[1]), 2, true
+
+	default:
+		return nil, 0, false
+	}
+}
+
+// keywordInfo returns information about the keyword name.
+func keywordInfo(voc *schema.Vocabulary, name string) KeywordInfo {
+	k, ok := voc.Keywords[name]
+	doc := voc.Docs[name]
+	if !ok {
+		k, doc, ok = schema.LookupExtension(name)
+	}
+	if !ok || k.Generated {
+		return KeywordInfo{
+			Name:     name,
+			ArgType:  arg_type.ArgTypeAny,
+			Expected: "any value (unknown keywords are ignored)",
+		}
+	}
+	return KeywordInfo{
+		Name:        name,
+		Known:       true,
+		ArgType:     k.ArgType,
+		Expected:    expected[k.ArgType],
+		Description: doc.Description,
+		Link:        doc.Link,
+	}
+}
+
+// expected describes the arguments of each argument type.
+var expected = map[arg_type.ArgType]string{
+	arg_type.ArgTypeBool:             "boolean",
+	arg_type.ArgTypeString:           "string",
+	arg_type.ArgTypeStrings:          "array of strings",
+	arg_type.ArgTypeStringOrStrings:  "string or array of strings",
+	arg_type.ArgTypeInt:              "integer",
+	arg_type.ArgTypeFloat:            "number",
+	arg_type.ArgTypeSchema:           "schema",
+	arg_type.ArgTypeSchemas:          "array of schemas",
+	arg_type.ArgTypeMapSchema:        "object whose values are schemas",
+	arg_type.ArgTypeSchemaOrSchemas:  "schema or array of schemas",
+	arg_type.ArgTypeMapArrayOrSchema: "object whose values are schemas or arrays of strings",
+	arg_type.ArgTypeAny:              "any value",
+	arg_type.ArgTypeMapMapSchema:     "object whose values are objects whose values are schemas",
+}
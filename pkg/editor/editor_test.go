@@ -0,0 +1,119 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package editor_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/editor"
+)
+
+const doc = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "x-internal": true},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	},
+	"anyOf": [{"required": ["name"]}]
+}`
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		pointer       string
+		schemaPointer string
+		// keyword is the name of Info.Keyword, or "".
+		keyword    string
+		known      bool
+		inArgument bool
+	}{
+		{"", "", "", false, false},
+		{"/type", "", "type", true, false},
+		{"/properties", "", "properties", true, false},
+		{"/properties/name", "/properties/name", "", false, false},
+		{"/properties/name/type", "/properties/name", "type", true, false},
+		{"/properties/name/x-internal", "/properties/name", "x-internal", false, false},
+		{"/properties/tags/items/type", "/properties/tags/items", "type", true, false},
+		{"/anyOf/0/required", "/anyOf/0", "required", true, false},
+		{"/anyOf/0/required/0", "/anyOf/0", "required", true, true},
+		{"/anyOf/x", "", "anyOf", true, true},
+		// A schema that isn't in the document yet.
+		{"/properties/age/minimum", "/properties/age", "minimum", true, false},
+	}
+	for _, test := range tests {
+		info, err := editor.LookupJSON([]byte(doc), test.pointer)
+		if err != nil {
+			t.Errorf("%q: %v", test.pointer, err)
+			continue
+		}
+		if info.Vocabulary != "draft2020-12" {
+			t.Errorf("%q: Vocabulary = %q", test.pointer, info.Vocabulary)
+		}
+		if info.SchemaPointer != test.schemaPointer {
+			t.Errorf("%q: SchemaPointer = %q, want %q", test.pointer, info.SchemaPointer, test.schemaPointer)
+		}
+		switch {
+		case test.keyword == "" && info.Keyword != nil:
+			t.Errorf("%q: Keyword = %q, want none", test.pointer, info.Keyword.Name)
+		case test.keyword != "" && info.Keyword == nil:
+			t.Errorf("%q: no Keyword, want %q", test.pointer, test.keyword)
+		case test.keyword != "":
+			if info.Keyword.Name != test.keyword || info.Keyword.Known != test.known || info.InArgument != test.inArgument {
+				t.Errorf("%q: Keyword %q, Known %t, InArgument %t; want %q, %t, %t",
+					test.pointer, info.Keyword.Name, info.Keyword.Known, info.InArgument,
+					test.keyword, test.known, test.inArgument)
+			}
+		}
+	}
+}
+
+func TestSiblings(t *testing.T) {
+	info, err := editor.LookupJSON([]byte(doc), "/properties/name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, ki := range info.Siblings {
+		names = append(names, ki.Name)
+		if ki.Name == "minLength" && (ki.Expected != "integer" || ki.Description == "") {
+			t.Errorf("minLength: Expected %q, Description %q", ki.Expected, ki.Description)
+		}
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("Siblings not sorted: %q", names)
+	}
+	if slices.Contains(names, "type") {
+		t.Error("Siblings has type, which the schema uses")
+	}
+	for _, name := range []string{"minLength", "pattern", "$ref"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("Siblings lacks %q", name)
+		}
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, "$$") {
+			t.Errorf("Siblings has generated keyword %q", name)
+		}
+	}
+}
+
+func TestLookupErrors(t *testing.T) {
+	tests := []struct {
+		doc, pointer string
+	}{
+		{doc, "type"},
+		{`{"$schema": 1}`, ""},
+		{`{"$schema": "https://example.com/unknown"}`, ""},
+		{`{`, ""},
+	}
+	for _, test := range tests {
+		if _, err := editor.LookupJSON([]byte(test.doc), test.pointer); err == nil {
+			t.Errorf("%s at %q: no error", test.doc, test.pointer)
+		}
+	}
+}
@@ -19,6 +19,44 @@ type ValidationError struct {
 	Message          string `json:"error"`
 	KeywordLocation  string `json:"keywordLocation"`
 	InstanceLocation string `json:"instanceLocation"`
+
+	// AbsoluteKeywordLocation is the location of the failing
+	// keyword as an absolute URI, such as
+	// "https://example.com/geo.json#/$defs/point/required", made
+	// from the $id of the schema resource that holds the keyword.
+	// Unlike the other locations it does not depend on the $ref
+	// keywords that were followed to reach the keyword.
+	// It is a fragment, such as "#/$defs/point/required",
+	// if the root schema has no URI.
+	AbsoluteKeywordLocation string `json:"absoluteKeywordLocation,omitempty"`
+
+	// EvaluationPath is the path of keywords that were followed
+	// from the root schema to the failing keyword, including
+	// each $ref and $dynamicRef, such as
+	// "#/properties/location/$ref/required".
+	EvaluationPath string `json:"evaluationPath,omitempty"`
+
+	// Source is the location of the failing schema in the text
+	// it was decoded from, as uri:line:column, if known.
+	Source string `json:"source,omitempty"`
+
+	// InstanceSource is the location of the failing instance value
+	// in the text it was decoded from, as uri:line:column, if known.
+	InstanceSource string `json:"instanceSource,omitempty"`
+
+	// InstancePosition is the position of the failing instance value
+	// in the text it was decoded from, if known. It is the same
+	// location as InstanceSource, for tools that point at it.
+	InstancePosition *Position `json:"instancePosition,omitempty"`
+}
+
+// Position is a position in a JSON text.
+type Position struct {
+	// Offset is the 0-based byte offset.
+	Offset int64 `json:"offset"`
+	// Line and Column are 1-based. Column counts bytes.
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
 
 // Error returns the error message that a user should see.
@@ -28,7 +66,19 @@ func (ve *ValidationError) Error() string {
 	if kl == "" {
 		kl = "#"
 	}
-	return fmt.Sprintf("%s: %s", kl, ve.Message)
+	msg := fmt.Sprintf("%s: %s", kl, ve.Message)
+	if ve.InstanceSource != "" {
+		msg = fmt.Sprintf("%s: %s", ve.InstanceSource, msg)
+	}
+	if ve.Source != "" {
+		msg += fmt.Sprintf(" (schema at %s)", ve.Source)
+	}
+	return msg
+}
+
+// Is reports whether target is [ErrInvalidInstance].
+func (ve *ValidationError) Is(target error) bool {
+	return target == ErrInvalidInstance
 }
 
 // ValidationErrors is a collection of ValidationError values.
@@ -49,6 +99,85 @@ func (ves *ValidationErrors) Error() string {
 	return errors2.Join(errs...).Error()
 }
 
+// Is reports whether target is [ErrInvalidInstance].
+func (ves *ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidInstance
+}
+
+// ErrInvalidInstance matches, using [errors.Is], the errors
+// returned when an instance fails validation.
+// These are the errors for which [IsValidationError] is true.
+// They are the fault of whoever supplied the instance;
+// a server would normally respond with a 400 status.
+var ErrInvalidInstance = errors2.New("instance is not valid")
+
+// ErrSchema matches, using [errors.Is], the errors caused by
+// a problem with a schema rather than with an instance, such as
+// a keyword argument of the wrong type, or a reference that
+// can't be resolved. These are the fault of whoever wrote the
+// schema; a server would normally respond with a 500 status.
+var ErrSchema = errors2.New("schema problem")
+
+// SchemaError is an error caused by a problem with a schema.
+// It matches [ErrSchema].
+type SchemaError struct {
+	Err error
+}
+
+// Error returns the message of the underlying error.
+func (se *SchemaError) Error() string {
+	return se.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (se *SchemaError) Unwrap() error {
+	return se.Err
+}
+
+// Is reports whether target is [ErrSchema].
+func (se *SchemaError) Is(target error) bool {
+	return target == ErrSchema
+}
+
+// AsSchemaError returns err, wrapped in a [SchemaError] if
+// it doesn't already match [ErrSchema]. It returns nil if err is nil.
+// An error that matches [ErrMaxDepth] is not wrapped,
+// as the schema need not be wrong.
+func AsSchemaError(err error) error {
+	if err == nil || errors2.Is(err, ErrSchema) || errors2.Is(err, ErrMaxDepth) {
+		return err
+	}
+	return &SchemaError{Err: err}
+}
+
+// ErrMaxDepth matches, using [errors.Is], the errors returned
+// when validating or resolving a schema goes deeper than its
+// depth limit. This may be caused by a schema that refers to
+// itself without consuming the instance, but also by a deeply
+// nested instance or a long chain of references, so it is
+// not reported as a problem with the schema.
+var ErrMaxDepth = errors2.New("maximum depth exceeded")
+
+// MaxDepthError is the error returned when validating or
+// resolving a schema goes deeper than its depth limit.
+// It matches [ErrMaxDepth].
+type MaxDepthError struct {
+	// Op is what went too deep: "validating" or "resolving".
+	Op string
+	// Limit is the depth limit.
+	Limit int
+}
+
+// Error returns the error message.
+func (e *MaxDepthError) Error() string {
+	return fmt.Sprintf("recursion while %s schema too deep (limit %d)", e.Op, e.Limit)
+}
+
+// Is reports whether target is [ErrMaxDepth].
+func (e *MaxDepthError) Is(target error) bool {
+	return target == ErrMaxDepth
+}
+
 // IsValidationError reports whether err is a validation error.
 func IsValidationError(err error) bool {
 	// There is another version of this function in
@@ -108,6 +237,11 @@ func AddError(perr *error, err error, loc string) {
 				}
 				return ve.InstanceLocation
 			}(),
+			AbsoluteKeywordLocation: ve.AbsoluteKeywordLocation,
+			EvaluationPath:          ve.EvaluationPath,
+			Source:                  ve.Source,
+			InstanceSource:          ve.InstanceSource,
+			InstancePosition:        ve.InstancePosition,
 		}
 		AddValidationErrorStruct(perr, nev)
 		return
@@ -0,0 +1,157 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package assert implements the x-assert extension keyword,
+// which asserts relations between the values in an object,
+// such as the total of an invoice being the sum of its lines.
+// Import this package for its side effect of registering the keyword:
+//
+//	import _ "github.com/altshiftab/jsonschema/pkg/extension/assert"
+//
+// The argument of the keyword is an expression, or an array of
+// expressions, each of which must be true for the instance:
+//
+//	{
+//		"type": "object",
+//		"x-assert": "total == sum(items[].price * items[].quantity)"
+//	}
+//
+// The expression language is small and has no side effects.
+// It has:
+//
+//   - number, string ('single' or "double" quoted), true, false and null literals
+//   - property paths starting at the instance, such as customer.name,
+//     items[0].price, and items[].price, which is the list of the price
+//     of every element of items; the instance itself is $
+//   - arithmetic + - * / % on numbers, where an operation on a list and
+//     a number, or on two lists of the same length, is applied elementwise,
+//     and where adding two strings concatenates them
+//   - comparisons == != < <= > >=, where numbers are equal if they are
+//     within a relative tolerance of 1e-9, to allow for rounding in
+//     decimal fractions, and a number that is not finite, such as
+//     a sum that overflows, is not equal to anything
+//   - logical && || ! and parentheses
+//   - the functions sum, min, max and count of a list,
+//     len of a string, list or object, abs, floor, ceil,
+//     and round(x) or round(x, digits)
+//
+// An expression that refers to a property that is not present
+// is not checked; use the required keyword to require properties.
+// An expression that does not parse is an error in the schema,
+// reported when the schema is decoded.
+package assert
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// KeywordName is the name of the extension keyword.
+const KeywordName = "x-assert"
+
+// keyword is the extension keyword.
+var keyword = schema.Keyword{
+	Name:     KeywordName,
+	ArgType:  arg_type.ArgTypeStringOrStrings,
+	Validate: validateAssert,
+	CheckArg: checkAssert,
+}
+
+func init() {
+	schema.RegisterExtension(&keyword, schema.KeywordDoc{
+		Description: "Each expression must evaluate to true for the instance.",
+	})
+}
+
+// compiled caches parsed expressions, keyed by source text.
+var compiled sync.Map // map[string]*compileResult
+
+// compiledSize is the number of entries in compiled.
+var compiledSize atomic.Int64
+
+// maxCompiled is the most expressions that compiled holds,
+// so that a program that decodes many schemas doesn't grow it
+// without limit. Other expressions are parsed each time.
+const maxCompiled = 4096
+
+// compileResult is a cached result of compile.
+type compileResult struct {
+	e   expr
+	err error
+}
+
+// Check reports whether src is a valid expression.
+func Check(src string) error {
+	_, err := compile(src)
+	return err
+}
+
+// compile returns the parsed form of src, using the cache.
+func compile(src string) (expr, error) {
+	if cr, ok := compiled.Load(src); ok {
+		return cr.(*compileResult).e, cr.(*compileResult).err
+	}
+	e, err := parse(src)
+	if compiledSize.Load() < maxCompiled {
+		if _, loaded := compiled.LoadOrStore(src, &compileResult{e, err}); !loaded {
+			compiledSize.Add(1)
+		}
+	}
+	return e, err
+}
+
+// checkAssert reports an expression of the x-assert
+// keyword that does not parse.
+func checkAssert(arg schema.PartValue) error {
+	pv := arg.(schema.PartStringOrStrings)
+	srcs := pv.Strings
+	if srcs == nil {
+		srcs = []string{pv.String}
+	}
+	for _, src := range srcs {
+		if _, err := compile(src); err != nil {
+			return fmt.Errorf("invalid expression %q: %v", src, err)
+		}
+	}
+	return nil
+}
+
+// validateAssert implements the x-assert keyword.
+func validateAssert(arg schema.PartValue, instance any, state *schema.ValidationState) error {
+	pv := arg.(schema.PartStringOrStrings)
+	srcs := pv.Strings
+	if srcs == nil {
+		srcs = []string{pv.String}
+	}
+
+	var topErr error
+	for _, src := range srcs {
+		e, err := compile(src)
+		if err != nil {
+			// A problem with the schema, not the instance.
+			return fmt.Errorf("%s: invalid expression %q: %v", KeywordName, src, err)
+		}
+		v, err := e.eval(instance)
+		if err == errMissing {
+			continue
+		}
+		if err != nil {
+			errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{
+				Message: fmt.Sprintf("assertion %q failed: %v", src, err),
+			})
+			continue
+		}
+		if b, ok := v.(bool); !ok || !b {
+			errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{
+				Message: fmt.Sprintf("assertion %q failed", src),
+			})
+		}
+	}
+	return topErr
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		x, y any
+		want bool
+	}{
+		{1.0, 1.0, true},
+		{0.1 + 0.2, 0.3, true},
+		{1e12, 1e12 + 1e-3, true},
+		{1e-12, 2e-12, false},
+		{0.0, 1e-12, false},
+		{math.Inf(1), 1.0, false},
+		{1.0, math.Inf(1), false},
+		{math.Inf(1), math.Inf(1), false},
+		{math.NaN(), math.NaN(), false},
+		{"a", "a", true},
+		{nil, nil, true},
+	}
+	for _, test := range tests {
+		got, err := equal(test.x, test.y)
+		if err != nil {
+			t.Errorf("equal(%v, %v): %v", test.x, test.y, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("equal(%v, %v) = %t, want %t", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+// decode returns the draft 2020-12 schema with
+// the x-assert keyword argument arg.
+func decode(arg string) (*schema.Schema, error) {
+	var s schema.Schema
+	src := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "x-assert": ` + arg + `}`
+	err := json.Unmarshal([]byte(src), &s)
+	return &s, err
+}
+
+func TestAssert(t *testing.T) {
+	tests := []struct {
+		expr     string
+		instance string
+		valid    bool
+	}{
+		{`total == sum(items[].price)`, `{"total": 3.3, "items": [{"price": 1.1}, {"price": 2.2}]}`, true},
+		{`total == sum(items[].price)`, `{"total": 3.4, "items": [{"price": 1.1}, {"price": 2.2}]}`, false},
+		// The sum overflows to infinity.
+		{`total == sum(items[].price)`, `{"total": 1, "items": [{"price": 1e308}, {"price": 1e308}]}`, false},
+		{`total == sum(items[].price)`, `{"items": []}`, true},
+	}
+	for _, test := range tests {
+		arg, _ := json.Marshal(test.expr)
+		s, err := decode(string(arg))
+		if err != nil {
+			t.Fatalf("%s: %v", test.expr, err)
+		}
+		var instance any
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(instance); (err == nil) != test.valid {
+			t.Errorf("%s with %s: got error %v, want valid %t", test.expr, test.instance, err, test.valid)
+		}
+	}
+}
+
+func TestInvalidExpression(t *testing.T) {
+	for _, arg := range []string{`"total =="`, `["a == 1", "sum("]`} {
+		_, err := decode(arg)
+		if err == nil || !strings.Contains(err.Error(), "invalid expression") {
+			t.Errorf("%s: got error %v, want invalid expression", arg, err)
+		}
+	}
+}
@@ -0,0 +1,480 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// errMissing is returned when an expression
+// refers to a property that is not present.
+var errMissing = errors.New("missing property")
+
+// An expr is a parsed expression.
+// Values are float64, string, bool, nil, []any for lists,
+// and the instance values that paths refer to.
+type expr interface {
+	eval(instance any) (any, error)
+}
+
+// literal is a literal value.
+type literal struct {
+	v any
+}
+
+func (e *literal) eval(any) (any, error) {
+	return e.v, nil
+}
+
+// step is a step in a property path.
+type step struct {
+	name    string // property name
+	index   int    // array index, if isIndex
+	isIndex bool
+	all     bool // every element of an array
+}
+
+// pathExpr is a property path.
+type pathExpr struct {
+	steps []step
+}
+
+func (e *pathExpr) eval(instance any) (any, error) {
+	vals := []any{instance}
+	list := false
+	for _, st := range e.steps {
+		next := make([]any, 0, len(vals))
+		for _, v := range vals {
+			switch {
+			case st.all:
+				a, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("[] applied to %s", typeName(v))
+				}
+				next = append(next, a...)
+			case st.isIndex:
+				a, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("[%d] applied to %s", st.index, typeName(v))
+				}
+				if st.index >= len(a) {
+					return nil, errMissing
+				}
+				next = append(next, a[st.index])
+			default:
+				m, ok := v.(map[string]any)
+				if !ok {
+					return nil, errMissing
+				}
+				fv, ok := m[st.name]
+				if !ok {
+					return nil, errMissing
+				}
+				next = append(next, fv)
+			}
+		}
+		vals = next
+		if st.all {
+			list = true
+		}
+	}
+	if list {
+		return vals, nil
+	}
+	return vals[0], nil
+}
+
+// unaryExpr is a unary operation.
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+func (e *unaryExpr) eval(instance any) (any, error) {
+	x, err := e.x.eval(instance)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "!" {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("! applied to %s", typeName(x))
+		}
+		return !b, nil
+	}
+	return elementwise(x, -1.0, "-", func(a, b float64) (any, error) { return a * b, nil })
+}
+
+// binaryExpr is a binary operation.
+type binaryExpr struct {
+	op   string
+	x, y expr
+}
+
+func (e *binaryExpr) eval(instance any) (any, error) {
+	x, err := e.x.eval(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit the logical operators.
+	if e.op == "&&" || e.op == "||" {
+		xb, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s applied to %s", e.op, typeName(x))
+		}
+		if xb == (e.op == "||") {
+			return xb, nil
+		}
+		y, err := e.y.eval(instance)
+		if err != nil {
+			return nil, err
+		}
+		yb, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s applied to %s", e.op, typeName(y))
+		}
+		return yb, nil
+	}
+
+	y, err := e.y.eval(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==", "!=":
+		eq, err := equal(x, y)
+		if err != nil {
+			return nil, err
+		}
+		return eq == (e.op == "=="), nil
+	case "<", "<=", ">", ">=":
+		return compare(e.op, x, y)
+	case "+":
+		if xs, ok := x.(string); ok {
+			if ys, ok := y.(string); ok {
+				return xs + ys, nil
+			}
+		}
+	}
+	return elementwise(x, y, e.op, arith[e.op])
+}
+
+// arith are the arithmetic operators.
+var arith = map[string]func(a, b float64) (any, error){
+	"+": func(a, b float64) (any, error) { return a + b, nil },
+	"-": func(a, b float64) (any, error) { return a - b, nil },
+	"*": func(a, b float64) (any, error) { return a * b, nil },
+	"/": func(a, b float64) (any, error) {
+		if b == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return a / b, nil
+	},
+	"%": func(a, b float64) (any, error) {
+		if b == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return math.Mod(a, b), nil
+	},
+}
+
+// elementwise applies fn to numbers x and y,
+// or to each element if either is a list.
+func elementwise(x, y any, op string, fn func(a, b float64) (any, error)) (any, error) {
+	xl, xIsList := x.([]any)
+	yl, yIsList := y.([]any)
+	switch {
+	case xIsList && yIsList:
+		if len(xl) != len(yl) {
+			return nil, fmt.Errorf("%s applied to lists of length %d and %d", op, len(xl), len(yl))
+		}
+		ret := make([]any, len(xl))
+		for i := range xl {
+			v, err := elementwise(xl[i], yl[i], op, fn)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = v
+		}
+		return ret, nil
+	case xIsList:
+		ret := make([]any, len(xl))
+		for i := range xl {
+			v, err := elementwise(xl[i], y, op, fn)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = v
+		}
+		return ret, nil
+	case yIsList:
+		ret := make([]any, len(yl))
+		for i := range yl {
+			v, err := elementwise(x, yl[i], op, fn)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = v
+		}
+		return ret, nil
+	}
+
+	a, ok := number(x)
+	if !ok {
+		return nil, fmt.Errorf("%s applied to %s", op, typeName(x))
+	}
+	b, ok := number(y)
+	if !ok {
+		return nil, fmt.Errorf("%s applied to %s", op, typeName(y))
+	}
+	return fn(a, b)
+}
+
+// equal reports whether x and y are equal.
+// Numbers are compared with a small relative tolerance.
+// A number that is not finite, as when a sum overflows,
+// is not equal to anything.
+func equal(x, y any) (bool, error) {
+	if a, ok := number(x); ok {
+		b, ok := number(y)
+		if !ok || !finite(a) || !finite(b) {
+			return false, nil
+		}
+		return a == b || math.Abs(a-b) <= 1e-9*max(math.Abs(a), math.Abs(b)), nil
+	}
+	switch x := x.(type) {
+	case string, bool, nil:
+		return x == y, nil
+	}
+	return false, fmt.Errorf("== applied to %s", typeName(x))
+}
+
+// finite reports whether f is neither infinite nor NaN.
+func finite(f float64) bool {
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// compare implements the ordered comparison operators.
+func compare(op string, x, y any) (any, error) {
+	var c int
+	if a, ok := number(x); ok {
+		b, ok := number(y)
+		if !ok {
+			return nil, fmt.Errorf("%s applied to number and %s", op, typeName(y))
+		}
+		if eq, _ := equal(a, b); !eq {
+			if a < b {
+				c = -1
+			} else {
+				c = 1
+			}
+		}
+	} else if a, ok := x.(string); ok {
+		b, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s applied to string and %s", op, typeName(y))
+		}
+		c = strings.Compare(a, b)
+	} else {
+		return nil, fmt.Errorf("%s applied to %s", op, typeName(x))
+	}
+
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// callExpr is a function call.
+type callExpr struct {
+	name string
+	fn   function
+	args []expr
+}
+
+func (e *callExpr) eval(instance any) (any, error) {
+	args := make([]any, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(instance)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := e.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", e.name, err)
+	}
+	return v, nil
+}
+
+// function is a built-in function.
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []any) (any, error)
+}
+
+// funcs are the built-in functions.
+var funcs map[string]function
+
+func init() {
+	funcs = map[string]function{
+		"sum":   {1, 1, listFunc(func(ns []float64) (any, error) { return sumOf(ns), nil })},
+		"min":   {1, 1, listFunc(extreme(func(a, b float64) bool { return a < b }))},
+		"max":   {1, 1, listFunc(extreme(func(a, b float64) bool { return a > b }))},
+		"count": {1, 1, countFunc},
+		"len":   {1, 1, lenFunc},
+		"abs":   {1, 1, numFunc(math.Abs)},
+		"floor": {1, 1, numFunc(math.Floor)},
+		"ceil":  {1, 1, numFunc(math.Ceil)},
+		"round": {1, 2, roundFunc},
+	}
+}
+
+// listFunc returns a function of a list of numbers.
+func listFunc(fn func([]float64) (any, error)) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		l, ok := args[0].([]any)
+		if !ok {
+			return nil, fmt.Errorf("argument is %s, want list", typeName(args[0]))
+		}
+		ns := make([]float64, len(l))
+		for i, v := range l {
+			n, ok := number(v)
+			if !ok {
+				return nil, fmt.Errorf("list element is %s, want number", typeName(v))
+			}
+			ns[i] = n
+		}
+		return fn(ns)
+	}
+}
+
+// sumOf returns the sum of ns.
+func sumOf(ns []float64) float64 {
+	var s float64
+	for _, n := range ns {
+		s += n
+	}
+	return s
+}
+
+// extreme returns a function that finds the element of a list
+// for which better reports true against every other element.
+func extreme(better func(a, b float64) bool) func([]float64) (any, error) {
+	return func(ns []float64) (any, error) {
+		if len(ns) == 0 {
+			return nil, errors.New("empty list")
+		}
+		r := ns[0]
+		for _, n := range ns[1:] {
+			if better(n, r) {
+				r = n
+			}
+		}
+		return r, nil
+	}
+}
+
+// countFunc implements count.
+func countFunc(args []any) (any, error) {
+	l, ok := args[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("argument is %s, want list", typeName(args[0]))
+	}
+	return float64(len(l)), nil
+}
+
+// lenFunc implements len.
+func lenFunc(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(len([]rune(v))), nil
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	}
+	return nil, fmt.Errorf("argument is %s", typeName(args[0]))
+}
+
+// numFunc returns a function of a single number.
+func numFunc(fn func(float64) float64) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		return elementwise(args[0], 0.0, "function", func(a, _ float64) (any, error) { return fn(a), nil })
+	}
+}
+
+// roundFunc implements round.
+func roundFunc(args []any) (any, error) {
+	digits := 0.0
+	if len(args) > 1 {
+		d, ok := number(args[1])
+		if !ok || d != math.Trunc(d) {
+			return nil, errors.New("digits must be an integer")
+		}
+		digits = d
+	}
+	scale := math.Pow(10, digits)
+	return elementwise(args[0], 0.0, "round", func(a, _ float64) (any, error) {
+		return math.Round(a*scale) / scale, nil
+	})
+}
+
+// number returns v as a float64, if it is a number.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case nil, string, bool, []any, map[string]any:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// typeName returns the JSON type name of v, for error messages.
+func typeName(v any) string {
+	if _, ok := number(v); ok {
+		return "number"
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,331 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a lexical token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokPunct
+)
+
+// token is a lexical token.
+type token struct {
+	kind tokenKind
+	text string  // the punctuation or identifier
+	num  float64 // for tokNumber
+	str  string  // for tokString
+	pos  int     // byte offset in the source
+}
+
+// puncts are the punctuation tokens, longest first.
+var puncts = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"(", ")", "[", "]", ".", ",", "+", "-", "*", "/", "%", "<", ">", "!", "$",
+}
+
+// lex splits src into tokens.
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E' ||
+				((src[j] == '+' || src[j] == '-') && (src[j-1] == 'e' || src[j-1] == 'E'))) {
+				j++
+			}
+			f, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("offset %d: invalid number %q", i, src[i:j])
+			}
+			toks = append(toks, token{kind: tokNumber, num: f, pos: i})
+			i = j
+
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				sb.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("offset %d: unterminated string", i)
+			}
+			toks = append(toks, token{kind: tokString, str: sb.String(), pos: i})
+			i = j + 1
+
+		case c == '_' || c < utf8.RuneSelf && unicode.IsLetter(rune(c)) || c >= utf8.RuneSelf:
+			j := i
+			for j < len(src) {
+				r, size := utf8.DecodeRuneInString(src[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			if j == i {
+				return nil, fmt.Errorf("offset %d: unexpected character %q", i, src[i:i+1])
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+
+		default:
+			found := false
+			for _, p := range puncts {
+				if strings.HasPrefix(src[i:], p) {
+					toks = append(toks, token{kind: tokPunct, text: p, pos: i})
+					i += len(p)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("offset %d: unexpected character %q", i, src[i:i+1])
+			}
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(src)})
+	return toks, nil
+}
+
+// parser is a recursive descent parser for expressions.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// parse parses an expression.
+func parse(src string) (expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	e, err := p.binary(1)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", describe(t))
+	}
+	return e, nil
+}
+
+// precedence is the precedence of the binary operators.
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// binary parses a binary expression whose operators
+// have at least the given precedence.
+func (p *parser) binary(prec int) (expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		opPrec, ok := precedence[t.text]
+		if t.kind != tokPunct || !ok || opPrec < prec {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.binary(opPrec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryExpr{op: t.text, x: x, y: y}
+	}
+}
+
+// unary parses a unary expression.
+func (p *parser) unary() (expr, error) {
+	if t := p.peek(); t.kind == tokPunct && (t.text == "!" || t.text == "-") {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: t.text, x: x}, nil
+	}
+	return p.primary()
+}
+
+// primary parses a literal, path, function call,
+// or parenthesized expression.
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literal{v: t.num}, nil
+	case tokString:
+		return &literal{v: t.str}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literal{v: true}, nil
+		case "false":
+			return &literal{v: false}, nil
+		case "null":
+			return &literal{v: nil}, nil
+		}
+		if n := p.peek(); n.kind == tokPunct && n.text == "(" {
+			return p.call(t)
+		}
+		return p.path([]step{{name: t.text}})
+	case tokPunct:
+		switch t.text {
+		case "$":
+			return p.path(nil)
+		case "(":
+			e, err := p.binary(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	}
+	return nil, p.errorf(t, "unexpected %s", describe(t))
+}
+
+// call parses the arguments of a call of the function named by t.
+func (p *parser) call(t token) (expr, error) {
+	fn, ok := funcs[t.text]
+	if !ok {
+		return nil, p.errorf(t, "unknown function %q", t.text)
+	}
+	p.pos++ // skip (
+	var args []expr
+	if n := p.peek(); !(n.kind == tokPunct && n.text == ")") {
+		for {
+			a, err := p.binary(1)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			if n := p.peek(); n.kind == tokPunct && n.text == "," {
+				p.pos++
+				continue
+			}
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		return nil, p.errorf(t, "wrong number of arguments to %s", t.text)
+	}
+	return &callExpr{name: t.text, fn: fn, args: args}, nil
+}
+
+// path parses the rest of a property path.
+func (p *parser) path(steps []step) (expr, error) {
+	for {
+		t := p.peek()
+		if t.kind != tokPunct {
+			break
+		}
+		switch t.text {
+		case ".":
+			p.pos++
+			n := p.next()
+			if n.kind != tokIdent {
+				return nil, p.errorf(n, "expected property name, found %s", describe(n))
+			}
+			steps = append(steps, step{name: n.text})
+			continue
+		case "[":
+			p.pos++
+			n := p.next()
+			switch {
+			case n.kind == tokPunct && n.text == "]":
+				steps = append(steps, step{all: true})
+				continue
+			case n.kind == tokNumber && n.num >= 0 && n.num == float64(int(n.num)):
+				steps = append(steps, step{index: int(n.num), isIndex: true})
+			case n.kind == tokString:
+				steps = append(steps, step{name: n.str})
+			default:
+				return nil, p.errorf(n, "expected index, found %s", describe(n))
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	return &pathExpr{steps: steps}, nil
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+// next consumes and returns the next token.
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// expect consumes the punctuation s.
+func (p *parser) expect(s string) error {
+	t := p.next()
+	if t.kind != tokPunct || t.text != s {
+		return p.errorf(t, "expected %q, found %s", s, describe(t))
+	}
+	return nil
+}
+
+// errorf returns an error at the position of t.
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", t.pos, fmt.Sprintf(format, args...))
+}
+
+// describe returns a description of t for error messages.
+func describe(t token) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokNumber:
+		return "number"
+	case tokString:
+		return "string"
+	default:
+		return strconv.Quote(t.text)
+	}
+}
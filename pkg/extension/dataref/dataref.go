@@ -0,0 +1,387 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dataref implements $data references, as in the ajv
+// validator: a keyword argument that is taken from the instance
+// being validated, rather than written in the schema. This allows
+// constraints between the values of an instance, such as:
+//
+//	{
+//		"properties": {
+//			"budgetLimit": {"type": "number"},
+//			"cost": {"type": "number", "maximum": {"$data": "1/budgetLimit"}}
+//		}
+//	}
+//
+// The value of $data is a JSON pointer, which starts at the root
+// of the instance, or a relative JSON pointer, which starts at the
+// instance being validated. In "1/budgetLimit", the 1 goes up from
+// the cost property to the object, and /budgetLimit then selects
+// a property of the object. If there is no value at the pointer,
+// the keyword is ignored. If there is a value that is not a valid
+// argument for the keyword, the instance is invalid.
+//
+// A $data reference is not a valid argument for most keywords,
+// so the references must be taken out of a schema before it is
+// built: [Unmarshal] and [Prepare] replace each of them with an
+// entry in the argument of the [KeywordName] extension keyword,
+// which this package registers.
+//
+// $data references are found for keywords whose argument is not
+// a schema, in every subschema. For enum and const, an object
+// with only a $data property is a reference, not a value.
+package dataref
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// KeywordName is the name of the extension keyword.
+// Its argument maps the names of keywords to the $data pointers
+// of their arguments, as in
+//
+//	"x-data": {"maximum": "1/budgetLimit"}
+//
+// It may also have a $schema property: the URI of the vocabulary
+// of the keywords, if it is not the default one.
+const KeywordName = "x-data"
+
+// keyword is the extension keyword.
+var keyword = schema.Keyword{
+	Name:     KeywordName,
+	ArgType:  arg_type.ArgTypeAny,
+	Validate: validateData,
+}
+
+func init() {
+	schema.RegisterExtension(&keyword, schema.KeywordDoc{
+		Description: "Keywords whose arguments are values of the instance, at the given JSON pointers.",
+	})
+}
+
+// Unmarshal decodes a schema in JSON that may have $data references,
+// as [Prepare] describes, and resolves it.
+func Unmarshal(data []byte) (*schema.Schema, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	v, err := Prepare(v)
+	if err != nil {
+		return nil, err
+	}
+	return schema.FromValue(v, nil)
+}
+
+// Prepare returns the schema v, a tree of Go values as
+// json.Unmarshal produces, with each $data reference replaced by an
+// entry in the [KeywordName] keyword of the same subschema.
+// The result can be built by [schema.FromValue].
+// The keywords are those of the vocabulary of the $schema keyword
+// of v, or of the default vocabulary. Prepare does not modify v.
+func Prepare(v any) (any, error) {
+	vocab := schema.DefaultVocabulary()
+	uri := ""
+	if m, ok := v.(map[string]any); ok {
+		if s, ok := m["$schema"].(string); ok {
+			uri = s
+			vocab = schema.LookupVocabulary(s)
+			if vocab == nil {
+				return nil, fmt.Errorf("%s: unknown $schema %q", KeywordName, s)
+			}
+		}
+	}
+	if vocab == nil {
+		return nil, fmt.Errorf("%s: no default vocabulary", KeywordName)
+	}
+	p := &preparer{vocab: vocab, uri: uri}
+	return p.schema(v, "")
+}
+
+// preparer holds the state used by Prepare.
+type preparer struct {
+	vocab *schema.Vocabulary
+	// uri is the $schema of the document, if any.
+	uri string
+}
+
+// schema returns the schema v, whose JSON pointer is ptr,
+// with its $data references replaced.
+func (p *preparer) schema(v any, ptr string) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		// A boolean schema, or not a schema,
+		// which building the schema will report.
+		return v, nil
+	}
+	ret := make(map[string]any, len(m))
+	refs := make(map[string]any)
+	for name, arg := range m {
+		loc := ptr + "/" + name
+		k, known := p.vocab.Keywords[name]
+		if !known {
+			k, _, known = schema.LookupExtension(name)
+		}
+		if ref, ok := dataRef(arg); ok && known && !takesSchemas(k.ArgType) {
+			if _, err := parsePointer(ref); err != nil {
+				return nil, fmt.Errorf("%s: %v", loc, err)
+			}
+			refs[name] = ref
+			continue
+		}
+		if !known {
+			ret[name] = arg
+			continue
+		}
+		sub, err := p.subschemas(k.ArgType, arg, loc)
+		if err != nil {
+			return nil, err
+		}
+		ret[name] = sub
+	}
+	if len(refs) > 0 {
+		if _, ok := ret[KeywordName]; ok {
+			return nil, fmt.Errorf("%s: schema has both %s and $data references", ptr, KeywordName)
+		}
+		if p.uri != "" {
+			refs["$schema"] = p.uri
+		}
+		ret[KeywordName] = refs
+	}
+	return ret, nil
+}
+
+// subschemas returns arg, the argument of a keyword of type at,
+// with the $data references in its subschemas replaced.
+func (p *preparer) subschemas(at arg_type.ArgType, arg any, ptr string) (any, error) {
+	switch at {
+	case arg_type.ArgTypeSchema:
+		return p.schema(arg, ptr)
+	case arg_type.ArgTypeSchemas:
+		return p.list(arg, ptr)
+	case arg_type.ArgTypeSchemaOrSchemas:
+		if _, ok := arg.([]any); ok {
+			return p.list(arg, ptr)
+		}
+		return p.schema(arg, ptr)
+	case arg_type.ArgTypeMapSchema, arg_type.ArgTypeMapArrayOrSchema:
+		m, ok := arg.(map[string]any)
+		if !ok {
+			return arg, nil
+		}
+		ret := make(map[string]any, len(m))
+		for name, sub := range m {
+			if _, ok := sub.([]any); ok {
+				// The array of a MapArrayOrSchema.
+				ret[name] = sub
+				continue
+			}
+			s, err := p.schema(sub, ptr+"/"+name)
+			if err != nil {
+				return nil, err
+			}
+			ret[name] = s
+		}
+		return ret, nil
+	case arg_type.ArgTypeMapMapSchema:
+		m, ok := arg.(map[string]any)
+		if !ok {
+			return arg, nil
+		}
+		ret := make(map[string]any, len(m))
+		for name, sub := range m {
+			s, err := p.subschemas(arg_type.ArgTypeMapSchema, sub, ptr+"/"+name)
+			if err != nil {
+				return nil, err
+			}
+			ret[name] = s
+		}
+		return ret, nil
+	}
+	return arg, nil
+}
+
+// list returns arg, an array of schemas,
+// with their $data references replaced.
+func (p *preparer) list(arg any, ptr string) (any, error) {
+	a, ok := arg.([]any)
+	if !ok {
+		return arg, nil
+	}
+	ret := make([]any, len(a))
+	for i, sub := range a {
+		s, err := p.schema(sub, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = s
+	}
+	return ret, nil
+}
+
+// takesSchemas reports whether the argument of a keyword of type at
+// is or has schemas.
+func takesSchemas(at arg_type.ArgType) bool {
+	switch at {
+	case arg_type.ArgTypeSchema, arg_type.ArgTypeSchemas, arg_type.ArgTypeMapSchema,
+		arg_type.ArgTypeSchemaOrSchemas, arg_type.ArgTypeMapArrayOrSchema, arg_type.ArgTypeMapMapSchema:
+		return true
+	}
+	return false
+}
+
+// dataRef returns the pointer of arg, if it is a $data reference.
+func dataRef(arg any) (string, bool) {
+	m, ok := arg.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ref, ok := m["$data"].(string)
+	return ref, ok
+}
+
+// A pointer is a parsed $data pointer.
+type pointer struct {
+	// up is the number of levels to go up from the
+	// current instance, or -1 for an absolute pointer.
+	up int
+	// toks are the tokens to follow from there.
+	toks []string
+}
+
+// parsePointer parses a JSON pointer or a relative JSON pointer.
+func parsePointer(ref string) (pointer, error) {
+	p := pointer{up: -1}
+	rest := ref
+	if ref != "" && ref[0] != '/' {
+		i := strings.IndexFunc(ref, func(r rune) bool { return r < '0' || r > '9' })
+		if i < 0 {
+			i = len(ref)
+		}
+		n, err := strconv.Atoi(ref[:i])
+		if err != nil || (i > 1 && ref[0] == '0') {
+			return pointer{}, fmt.Errorf("invalid $data pointer %q", ref)
+		}
+		p.up = n
+		rest = ref[i:]
+	}
+	if rest == "" {
+		return p, nil
+	}
+	if rest[0] != '/' {
+		// This includes the "#" of a relative JSON pointer,
+		// which is for the name of a property, not a value.
+		return pointer{}, fmt.Errorf("invalid $data pointer %q", ref)
+	}
+	for _, tok := range strings.Split(rest[1:], "/") {
+		tok = jsonpointer.UnescapeToken(tok)
+		p.toks = append(p.toks, tok)
+	}
+	return p, nil
+}
+
+// resolve returns the value at the $data pointer ref,
+// for the instance being validated with state.
+// It reports false if there is no such value.
+func resolve(ref string, state *schema.ValidationState) (any, bool, error) {
+	p, err := parsePointer(ref)
+	if err != nil {
+		return nil, false, err
+	}
+	var toks []string
+	if p.up >= 0 {
+		if p.up > len(state.InstancePath) {
+			return nil, false, nil
+		}
+		toks = slices.Clone(state.InstancePath[:len(state.InstancePath)-p.up])
+	}
+	toks = append(toks, p.toks...)
+
+	v := state.RootInstance
+	for _, tok := range toks {
+		var ok bool
+		v, ok = validator.InstanceElement(v, tok)
+		if !ok {
+			return nil, false, nil
+		}
+	}
+	return v, true, nil
+}
+
+// validateData implements the x-data keyword.
+func validateData(arg schema.PartValue, instance any, state *schema.ValidationState) error {
+	refs, ok := arg.(schema.PartAny).V.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s argument is %T, want object", KeywordName, arg.(schema.PartAny).V)
+	}
+	vocab := schema.DefaultVocabulary()
+	if uri, ok := refs["$schema"].(string); ok {
+		vocab = schema.LookupVocabulary(uri)
+		if vocab == nil {
+			return fmt.Errorf("%s: unknown $schema %q", KeywordName, uri)
+		}
+	}
+
+	var topErr error
+	for _, name := range slices.Sorted(func(yield func(string) bool) {
+		for name := range refs {
+			if name != "$schema" && !yield(name) {
+				return
+			}
+		}
+	}) {
+		ref, ok := refs[name].(string)
+		if !ok {
+			return fmt.Errorf("%s: pointer for %q is %T, want string", KeywordName, name, refs[name])
+		}
+		v, found, err := resolve(ref, state)
+		if err != nil {
+			return fmt.Errorf("%s: %v", KeywordName, err)
+		}
+		if !found {
+			continue
+		}
+		v, err = plainValue(v)
+		if err != nil {
+			return err
+		}
+		sub, err := schema.FromValue(map[string]any{name: v}, vocab)
+		if err != nil {
+			errors2.AddValidationErrorStruct(&topErr, &errors2.ValidationError{
+				Message: fmt.Sprintf("value at $data pointer %q is not a valid %q argument", ref, name),
+			})
+			continue
+		}
+		errors2.AddError(&topErr, sub.ValidateInPlaceSchema(instance, state), "")
+	}
+	return topErr
+}
+
+// plainValue returns v as json.Unmarshal would produce it,
+// as v may be any part of a Go value that is being validated.
+func plainValue(v any) (any, error) {
+	switch v.(type) {
+	case nil, bool, float64, string:
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var ret any
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataref_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/extension/dataref"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// unmarshal decodes src, which is a schema without its $schema
+// keyword, with dataref.Unmarshal.
+func unmarshal(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	s, err := dataref.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + src[1:]))
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return s
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		ok       bool
+	}{
+		{
+			`{"properties": {"limit": {"type": "number"}, "cost": {"maximum": {"$data": "1/limit"}}}}`,
+			`{"limit": 10, "cost": 5}`,
+			true,
+		},
+		{
+			`{"properties": {"limit": {"type": "number"}, "cost": {"maximum": {"$data": "1/limit"}}}}`,
+			`{"limit": 10, "cost": 11}`,
+			false,
+		},
+		// With no value at the pointer, the keyword is ignored.
+		{
+			`{"properties": {"cost": {"maximum": {"$data": "1/limit"}}}}`,
+			`{"cost": 11}`,
+			true,
+		},
+		// A value that is not a valid argument makes the instance invalid.
+		{
+			`{"properties": {"cost": {"maximum": {"$data": "1/limit"}}}}`,
+			`{"limit": "ten", "cost": 11}`,
+			false,
+		},
+		// Absolute pointers start at the root.
+		{
+			`{"properties": {"a": {"items": {"minimum": {"$data": "/min"}}}}}`,
+			`{"min": 2, "a": [2, 3]}`,
+			true,
+		},
+		{
+			`{"properties": {"a": {"items": {"minimum": {"$data": "/min"}}}}}`,
+			`{"min": 2, "a": [2, 1]}`,
+			false,
+		},
+		// Going up beyond the root finds nothing.
+		{
+			`{"properties": {"a": {"minimum": {"$data": "5/min"}}}}`,
+			`{"min": 2, "a": 1}`,
+			true,
+		},
+		// 0 is the instance itself.
+		{
+			`{"properties": {"a": {"minLength": {"$data": "0/n"}}}}`,
+			`{"a": {"n": 1}}`,
+			true,
+		},
+		{
+			`{"properties": {"a": {"const": {"$data": "1/b"}}}}`,
+			`{"a": [1, {"x": null}], "b": [1, {"x": null}]}`,
+			true,
+		},
+		{
+			`{"properties": {"a": {"const": {"$data": "1/b"}}}}`,
+			`{"a": 1, "b": 2}`,
+			false,
+		},
+		{
+			`{"properties": {"a": {"enum": {"$data": "1/b"}}}}`,
+			`{"a": 2, "b": [1, 2]}`,
+			true,
+		},
+		{
+			`{"properties": {"a~/b": {}, "c": {"exclusiveMinimum": {"$data": "1/a~0~1b"}}}}`,
+			`{"a~/b": 3, "c": 3}`,
+			false,
+		},
+		// References in keywords that take schemas are found.
+		{
+			`{"allOf": [{"properties": {"c": {"maximum": {"$data": "1/m"}}}}]}`,
+			`{"m": 1, "c": 2}`,
+			false,
+		},
+		{
+			`{"$defs": {"d": {"maximum": {"$data": "1/m"}}}, "properties": {"c": {"$ref": "#/$defs/d"}}}`,
+			`{"m": 1, "c": 2}`,
+			false,
+		},
+	}
+	for _, test := range tests {
+		s := unmarshal(t, test.schema)
+		var instance any
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Fatal(err)
+		}
+		err := s.Validate(instance)
+		if got := err == nil; got != test.ok {
+			t.Errorf("%s, %s: got error %v, want ok %t", test.schema, test.instance, err, test.ok)
+		}
+	}
+}
+
+// TestGoValue checks that a $data value taken from a Go
+// value, rather than from JSON, is a valid argument.
+func TestGoValue(t *testing.T) {
+	s := unmarshal(t, `{"properties": {"Cost": {"maximum": {"$data": "1/Limit"}}}}`)
+	type budget struct {
+		Limit int
+		Cost  int
+	}
+	if err := s.Validate(budget{Limit: 10, Cost: 5}); err != nil {
+		t.Errorf("valid: %v", err)
+	}
+	if err := s.Validate(budget{Limit: 10, Cost: 11}); err == nil {
+		t.Error("invalid: no error")
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	var v any
+	if err := json.Unmarshal([]byte(`{
+		"properties": {
+			"a": {"maximum": {"$data": "1/b"}, "minimum": 0},
+			"c": {"const": {"$data": "/d"}}
+		}
+	}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	orig, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dataref.Prepare(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want any
+	if err := json.Unmarshal([]byte(`{
+		"properties": {
+			"a": {"x-data": {"maximum": "1/b"}, "minimum": 0},
+			"c": {"x-data": {"const": "/d"}}
+		}
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	after, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(orig) {
+		t.Errorf("Prepare modified its argument to %s", after)
+	}
+}
+
+func TestPrepareErrors(t *testing.T) {
+	for _, src := range []string{
+		`{"$schema": "https://example.com/unknown"}`,
+		`{"maximum": {"$data": "x"}}`,
+		`{"maximum": {"$data": "01/a"}}`,
+		`{"maximum": {"$data": "1#"}}`,
+		`{"maximum": {"$data": "1/a"}, "x-data": {}}`,
+		`{"properties": {"a": {"maximum": {"$data": "a/b"}}}}`,
+	} {
+		if _, err := dataref.Unmarshal([]byte(src)); err == nil {
+			t.Errorf("%s: no error", src)
+		}
+	}
+}
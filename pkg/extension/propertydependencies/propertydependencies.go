@@ -0,0 +1,58 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package propertydependencies makes the propertyDependencies keyword
+// of draft-next available to schemas of other drafts, such as
+// draft 2020-12, as an extension keyword.
+// Import this package for its side effect of registering the keyword:
+//
+//	import _ "github.com/altshiftab/jsonschema/pkg/extension/propertydependencies"
+//
+// The argument of the keyword maps a property name and a value of
+// that property to a schema. If the instance has the property with
+// that string value, the instance must also match the schema.
+// This describes an object whose shape depends on a field such as
+// "kind", without an if/then/else for each kind:
+//
+//	{
+//		"type": "object",
+//		"required": ["kind"],
+//		"propertyDependencies": {
+//			"kind": {
+//				"circle": {"required": ["radius"]},
+//				"rectangle": {"required": ["width", "height"]}
+//			}
+//		}
+//	}
+//
+// The properties evaluated by the matching schema count as
+// evaluated for unevaluatedProperties, as for allOf.
+//
+// Unlike most extension keywords, the name does not start with "x-",
+// so that a schema using it can move to draft-next unchanged.
+// The draft-next vocabulary defines the keyword itself, and
+// does not use the extension.
+package propertydependencies
+
+import (
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// KeywordName is the name of the extension keyword.
+const KeywordName = "propertyDependencies"
+
+// keyword is the extension keyword.
+var keyword = schema.Keyword{
+	Name:     KeywordName,
+	ArgType:  arg_type.ArgTypeMapMapSchema,
+	Validate: validator.ArgTypeMapMapSchema(validator.ValidatePropertyDependencies),
+}
+
+func init() {
+	schema.RegisterExtension(&keyword, schema.KeywordDoc{
+		Description: "Applies a schema to the object if the given property has the given string value.",
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package propertydependencies_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/extension/propertydependencies"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestPropertyDependencies(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["kind"],
+		"properties": {"kind": {"type": "string"}},
+		"propertyDependencies": {
+			"kind": {
+				"circle": {"properties": {"radius": {"type": "number"}}, "required": ["radius"]},
+				"rectangle": {"properties": {"width": {"type": "number"}, "height": {"type": "number"}}, "required": ["width", "height"]}
+			}
+		},
+		"unevaluatedProperties": false
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		instance string
+		valid    bool
+	}{
+		{`{"kind": "circle", "radius": 1}`, true},
+		{`{"kind": "circle"}`, false},
+		{`{"kind": "rectangle", "width": 1, "height": 2}`, true},
+		{`{"kind": "rectangle", "width": 1}`, false},
+		// The properties evaluated by the matching schema
+		// count for unevaluatedProperties.
+		{`{"kind": "circle", "radius": 1, "width": 1}`, false},
+		{`{"kind": "triangle"}`, true},
+		{`{"kind": "triangle", "radius": 1}`, false},
+		{`{}`, false},
+	}
+	for _, test := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.instance, err, test.valid)
+		}
+	}
+}
+
+func TestBadArgument(t *testing.T) {
+	for _, src := range []string{
+		`{"propertyDependencies": {"kind": {"circle": 1}}}`,
+		`{"propertyDependencies": {"kind": []}}`,
+		`{"propertyDependencies": 1}`,
+	} {
+		var s schema.Schema
+		src = `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + src[1:]
+		if err := json.Unmarshal([]byte(src), &s); err == nil {
+			t.Errorf("%s: no error", src)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package propertyorder implements the x-propertyOrder extension
+// keyword, for systems such as signed canonical JSON payloads
+// where the order of the properties of an object matters.
+// Import this package for its side effect of registering the keyword:
+//
+//	import _ "github.com/altshiftab/jsonschema/pkg/extension/propertyorder"
+//
+// The argument of the keyword is an array of property names.
+// The properties of the instance object that are in the array
+// must appear in the same order as in the array.
+// Other properties may appear anywhere.
+//
+// A Go map does not record the order of its keys, so the keyword
+// needs the instance to be decoded by [Decode], and the resulting
+// [*Order] to be passed to the validator; [Validate] does both.
+// Without an Order the keyword can't check a map, and accepts it.
+package propertyorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"unsafe"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// KeywordName is the name of the extension keyword.
+const KeywordName = "x-propertyOrder"
+
+// keyword is the extension keyword.
+var keyword = schema.Keyword{
+	Name:     KeywordName,
+	ArgType:  arg_type.ArgTypeStrings,
+	Validate: validatePropertyOrder,
+}
+
+func init() {
+	schema.RegisterExtension(&keyword, schema.KeywordDoc{
+		Description: "The listed properties of an object must appear in this order.",
+	})
+}
+
+// Order records the order of the keys of the objects
+// in an instance decoded by [Decode].
+type Order struct {
+	keys map[unsafe.Pointer][]string
+}
+
+// Keys returns the keys of the object m in the order
+// in which they were decoded.
+func (o *Order) Keys(m map[string]any) ([]string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	keys, ok := o.keys[reflect.ValueOf(m).UnsafePointer()]
+	return keys, ok
+}
+
+// Decode decodes JSON data into an empty interface value,
+// as [json.Unmarshal] does, and also records the order of the
+// keys of each object.
+func Decode(data []byte) (any, *Order, error) {
+	d := &decoder{
+		dec:   json.NewDecoder(bytes.NewReader(data)),
+		order: &Order{keys: make(map[unsafe.Pointer][]string)},
+	}
+	v, err := d.value()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, nil, errors.New("invalid character after top-level value")
+	}
+	return v, d.order, nil
+}
+
+// Validate decodes the JSON encoding of an instance with [Decode],
+// and validates it against s with the key order.
+func Validate(s *schema.Schema, data []byte) error {
+	instance, order, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	opts := &schema.ValidateOpts{
+		ValidateFormat: true,
+		Extensions:     map[string]any{KeywordName: order},
+	}
+	return s.ValidateWithOpts(instance, opts)
+}
+
+// decoder holds the state used by Decode.
+type decoder struct {
+	dec   *json.Decoder
+	order *Order
+}
+
+// value decodes the next value.
+func (d *decoder) value() (any, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]any)
+		var keys []string
+		for d.dec.More() {
+			tok, err := d.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := m[key]; dup {
+				// As with json.Unmarshal, the last value wins.
+				keys = slices.DeleteFunc(keys, func(k string) bool { return k == key })
+			}
+			m[key] = v
+			keys = append(keys, key)
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+		d.order.keys[reflect.ValueOf(m).UnsafePointer()] = keys
+		return m, nil
+
+	case json.Delim('['):
+		a := []any{}
+		for d.dec.More() {
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+		return a, nil
+
+	default:
+		return tok, nil
+	}
+}
+
+// validatePropertyOrder implements the x-propertyOrder keyword.
+func validatePropertyOrder(arg schema.PartValue, instance any, state *schema.ValidationState) error {
+	m, ok := instance.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var order *Order
+	if state.Opts != nil {
+		order, _ = state.Opts.Extensions[KeywordName].(*Order)
+	}
+	keys, ok := order.Keys(m)
+	if !ok {
+		return nil
+	}
+
+	want := arg.(schema.PartStrings)
+	next := 0
+	for _, key := range keys {
+		i := slices.Index(want, key)
+		if i < 0 {
+			continue
+		}
+		if i < next {
+			return &errors2.ValidationError{
+				Message: fmt.Sprintf("property %q must appear before %q", key, want[next-1]),
+			}
+		}
+		next = i + 1
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package propertyorder_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/extension/propertyorder"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestValidate(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"x-propertyOrder": ["a", "b", "c"],
+		"properties": {
+			"nested": {"x-propertyOrder": ["x", "y"]}
+		}
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		instance string
+		ok       bool
+	}{
+		{`{}`, true},
+		{`{"a": 1, "b": 2, "c": 3}`, true},
+		{`{"a": 1, "c": 3}`, true},
+		{`{"z": 0, "a": 1, "q": 0, "b": 2}`, true},
+		{`{"b": 2, "a": 1}`, false},
+		{`{"a": 1, "c": 3, "b": 2}`, false},
+		// The last of duplicate keys determines the position.
+		{`{"b": 2, "a": 1, "b": 2}`, true},
+		{`{"nested": {"x": 1, "y": 2}}`, true},
+		{`{"nested": {"y": 2, "x": 1}}`, false},
+		{`[{"b": 2, "a": 1}]`, true},
+		{`"b"`, true},
+	}
+	for _, test := range tests {
+		err := propertyorder.Validate(&s, []byte(test.instance))
+		if got := err == nil; got != test.ok {
+			t.Errorf("%s: got error %v, want ok %t", test.instance, err, test.ok)
+		}
+	}
+
+	// Without an Order, the keyword accepts any object.
+	var instance any
+	if err := json.Unmarshal([]byte(`{"b": 2, "a": 1}`), &instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(instance); err != nil {
+		t.Errorf("without an Order: %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	v, order, err := propertyorder.Decode([]byte(`{"b": {"z": 1, "y": 2}, "a": [{"q": 1, "p": 2}], "c": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := v.(map[string]any)
+	tests := []struct {
+		obj  map[string]any
+		want []string
+	}{
+		{m, []string{"b", "a", "c"}},
+		{m["b"].(map[string]any), []string{"z", "y"}},
+		{m["a"].([]any)[0].(map[string]any), []string{"q", "p"}},
+	}
+	for _, test := range tests {
+		got, ok := order.Keys(test.obj)
+		if !ok || !slices.Equal(got, test.want) {
+			t.Errorf("got %v, %t, want %v", got, ok, test.want)
+		}
+	}
+	if _, ok := order.Keys(map[string]any{}); ok {
+		t.Error("Keys of an undecoded map: ok")
+	}
+
+	for _, bad := range []string{``, `{"a": 1`, `{"a": 1} 2`, `[1,]`} {
+		if _, _, err := propertyorder.Decode([]byte(bad)); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package form converts schemas into form models: trees of fields
+// that describe what a user interface should show to edit an instance,
+// without saying how. Front ends render a [Field], which encodes
+// as JSON for those written in other languages.
+//
+// A field has the type, label, help text, constraints and options
+// of its schema. The fields of an object are in the order of the
+// x-propertyOrder keyword, then in the order of the properties
+// keyword, then by name, so that a form does not change from one
+// run to the next. A property schema may name a group with the
+// [GroupKeyword] extension keyword, which this package registers:
+//
+//	"properties": {
+//		"street": {"type": "string", "x-group": "Address"},
+//		"city":   {"type": "string", "x-group": "Address"}
+//	}
+package form
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// GroupKeyword is the extension keyword that puts a property
+// in a named group of the fields of its object.
+// It is an annotation, and does not affect validation.
+const GroupKeyword = "x-group"
+
+// groupKeyword is the extension keyword.
+var groupKeyword = schema.Keyword{
+	Name:       GroupKeyword,
+	ArgType:    arg_type.ArgTypeString,
+	Validate:   func(schema.PartValue, any, *schema.ValidationState) error { return nil },
+	Leaf:       true,
+	Annotation: true,
+}
+
+func init() {
+	schema.RegisterExtension(&groupKeyword, schema.KeywordDoc{
+		Description: "The name of the group of form fields that the property is in.",
+	})
+}
+
+// A Field describes the form field for a value.
+type Field struct {
+	// Name is the property name of the field,
+	// or "" for the root field and the items of an array.
+	Name string `json:"name,omitempty"`
+	// Type is the JSON type of the value, such as "string"
+	// or "object", or "" if the schema does not say.
+	Type string `json:"type,omitempty"`
+	// Nullable reports whether the value may also be null.
+	Nullable bool `json:"nullable,omitempty"`
+	// Label is the title of the schema, or else the name.
+	Label string `json:"label,omitempty"`
+	// Help is the description of the schema.
+	Help string `json:"help,omitempty"`
+	// Group is the name of the group of the field, from the
+	// GroupKeyword keyword, or "" if it is in no group.
+	Group string `json:"group,omitempty"`
+	// Required reports whether the property is required.
+	Required bool `json:"required,omitempty"`
+	// ReadOnly reports whether the schema marks the value read-only.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Default is the default value, if any.
+	Default any `json:"default,omitempty"`
+	// Constraints holds the arguments of the keywords that
+	// constrain the value, such as minLength or pattern,
+	// by keyword name.
+	Constraints map[string]any `json:"constraints,omitempty"`
+	// Options are the values that the field may have,
+	// from the enum or const keyword, or from a oneOf or anyOf
+	// keyword whose subschemas each have a const keyword.
+	Options []Option `json:"options,omitempty"`
+	// Fields are the fields of the properties of an object.
+	// The fields of a group are together, where the first of them
+	// would be.
+	Fields []*Field `json:"fields,omitempty"`
+	// Groups are the names of the groups of Fields, in order.
+	Groups []string `json:"groups,omitempty"`
+	// Items is the field of the elements of an array.
+	Items *Field `json:"items,omitempty"`
+	// Recursive reports that the schema of the field is one of
+	// the schemas of the fields that contain it, so that the
+	// field is not expanded again.
+	Recursive bool `json:"recursive,omitempty"`
+}
+
+// An Option is a value that a field may have.
+type Option struct {
+	Value any `json:"value"`
+	// Label is the title of the subschema of the option, if any,
+	// or else the value, as JSON unless it is a string.
+	Label string `json:"label"`
+}
+
+// constraintKeywords are the keywords whose
+// arguments are reported as constraints.
+var constraintKeywords = []string{
+	"minimum",
+	"maximum",
+	"exclusiveMinimum",
+	"exclusiveMaximum",
+	"multipleOf",
+	"minLength",
+	"maxLength",
+	"pattern",
+	"format",
+	"minItems",
+	"maxItems",
+	"uniqueItems",
+	"minProperties",
+	"maxProperties",
+}
+
+// New returns the form model of s, which must be a resolved
+// schema for an object. The subschemas of s are found through
+// properties and items, and the schemas that those apply in place
+// through $ref and allOf; anyOf and oneOf only provide options,
+// as which of their branches applies depends on the instance.
+func New(s *schema.Schema) (*Field, error) {
+	b := &builder{active: make(map[*schema.Schema]bool)}
+	f := b.field("", s)
+	if f.Type != "object" {
+		return nil, fmt.Errorf("form: schema is for type %q, not an object", f.Type)
+	}
+	return f, nil
+}
+
+// builder holds the state used by New.
+type builder struct {
+	// active are the schemas of the fields being built.
+	active map[*schema.Schema]bool
+}
+
+// field returns the field called name for the schema s.
+func (b *builder) field(name string, s *schema.Schema) *Field {
+	f := &Field{Name: name, Label: name}
+	if b.active[s] {
+		f.Recursive = true
+		return f
+	}
+	b.active[s] = true
+	defer delete(b.active, s)
+
+	as := inPlaceSchemas(s)
+	lookup := func(keyword string) (schema.PartValue, bool) {
+		for _, a := range as {
+			if pv, ok := a.LookupKeyword(keyword); ok {
+				return pv, true
+			}
+		}
+		return nil, false
+	}
+
+	if pv, ok := lookup("title"); ok {
+		f.Label = string(pv.(schema.PartString))
+	}
+	if pv, ok := lookup("description"); ok {
+		f.Help = string(pv.(schema.PartString))
+	}
+	if pv, ok := lookup(GroupKeyword); ok {
+		f.Group = string(pv.(schema.PartString))
+	}
+	if pv, ok := lookup("readOnly"); ok {
+		f.ReadOnly = bool(pv.(schema.PartBool))
+	}
+	if pv, ok := lookup("default"); ok {
+		f.Default = pv.(schema.PartAny).V
+	}
+	for _, keyword := range constraintKeywords {
+		if pv, ok := lookup(keyword); ok {
+			if f.Constraints == nil {
+				f.Constraints = make(map[string]any)
+			}
+			f.Constraints[keyword] = constraintValue(pv)
+		}
+	}
+	f.Options = options(lookup)
+
+	if pv, ok := lookup("type"); ok {
+		types := []string{pv.(schema.PartStringOrStrings).String}
+		if ss := pv.(schema.PartStringOrStrings).Strings; ss != nil {
+			types = ss
+		}
+		for _, t := range types {
+			if t == "null" {
+				f.Nullable = true
+			} else if f.Type == "" {
+				f.Type = t
+			}
+		}
+	} else if _, ok := lookup("properties"); ok {
+		f.Type = "object"
+	} else if _, ok := lookup("items"); ok {
+		f.Type = "array"
+	}
+
+	switch f.Type {
+	case "object":
+		b.objectFields(f, as)
+	case "array":
+		if pv, ok := lookup("items"); ok {
+			switch items := pv.(type) {
+			case schema.PartSchema:
+				f.Items = b.field("", items.S)
+			case schema.PartSchemaOrSchemas:
+				if items.Schema != nil {
+					f.Items = b.field("", items.Schema)
+				}
+			}
+		}
+	}
+	return f
+}
+
+// objectFields sets the fields of f, the field of an object
+// to which the schemas as apply.
+func (b *builder) objectFields(f *Field, as []*schema.Schema) {
+	props := make(map[string]*schema.Schema)
+	required := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if _, ok := props[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	for _, a := range as {
+		if pv, ok := a.LookupKeyword("properties"); ok {
+			for name, sub := range pv.(schema.PartMapSchema) {
+				if _, ok := props[name]; !ok {
+					props[name] = sub
+				}
+			}
+		}
+		if pv, ok := a.LookupKeyword("required"); ok {
+			if ss, ok := pv.(schema.PartStrings); ok {
+				for _, name := range ss {
+					required[name] = true
+				}
+			}
+		}
+	}
+	for _, a := range as {
+		for _, name := range propertyOrder(a) {
+			add(name)
+		}
+	}
+	for _, a := range as {
+		for _, name := range declared(a) {
+			add(name)
+		}
+	}
+
+	var fields []*Field
+	for _, name := range names {
+		pf := b.field(name, props[name])
+		pf.Required = required[name]
+		fields = append(fields, pf)
+	}
+
+	// Gather the fields of each group where its first field is.
+	groups := make(map[string][]*Field)
+	for _, pf := range fields {
+		if pf.Group == "" {
+			continue
+		}
+		if _, ok := groups[pf.Group]; !ok {
+			f.Groups = append(f.Groups, pf.Group)
+		}
+		groups[pf.Group] = append(groups[pf.Group], pf)
+	}
+	for _, pf := range fields {
+		switch {
+		case pf.Group == "":
+			f.Fields = append(f.Fields, pf)
+		case groups[pf.Group] != nil:
+			f.Fields = append(f.Fields, groups[pf.Group]...)
+			groups[pf.Group] = nil
+		}
+	}
+}
+
+// options returns the options of a field
+// whose schemas have the keywords that lookup finds.
+func options(lookup func(string) (schema.PartValue, bool)) []Option {
+	if pv, ok := lookup("const"); ok {
+		v := pv.(schema.PartAny).V
+		return []Option{{Value: v, Label: label(v)}}
+	}
+	if pv, ok := lookup("enum"); ok {
+		vals, _ := pv.(schema.PartAny).V.([]any)
+		opts := make([]Option, 0, len(vals))
+		for _, v := range vals {
+			opts = append(opts, Option{Value: v, Label: label(v)})
+		}
+		return opts
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		pv, ok := lookup(keyword)
+		if !ok {
+			continue
+		}
+		var opts []Option
+		for _, sub := range pv.(schema.PartSchemas) {
+			cv, ok := sub.LookupKeyword("const")
+			if !ok {
+				opts = nil
+				break
+			}
+			v := cv.(schema.PartAny).V
+			opt := Option{Value: v, Label: label(v)}
+			if tv, ok := sub.LookupKeyword("title"); ok {
+				opt.Label = string(tv.(schema.PartString))
+			}
+			opts = append(opts, opt)
+		}
+		if opts != nil {
+			return opts
+		}
+	}
+	return nil
+}
+
+// label returns the label of an option whose value is v:
+// a string is its own label, and other values are JSON.
+func label(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// constraintValue returns the argument of a constraint keyword.
+func constraintValue(pv schema.PartValue) any {
+	switch v := pv.(type) {
+	case schema.PartBool:
+		return bool(v)
+	case schema.PartString:
+		return string(v)
+	case schema.PartInt:
+		return int64(v)
+	case schema.PartFloat:
+		return float64(v)
+	case schema.PartAny:
+		return v.V
+	}
+	return pv
+}
+
+// inPlaceSchemas returns s and the schemas that it
+// applies in place through $ref and allOf.
+func inPlaceSchemas(s *schema.Schema) []*schema.Schema {
+	var ret []*schema.Schema
+	seen := make(map[*schema.Schema]bool)
+	var walk func(s *schema.Schema)
+	walk = func(s *schema.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		ret = append(ret, s)
+		for _, part := range s.Parts {
+			switch {
+			case part.Keyword.Name == schema.ResolvedRefKeywordName:
+				walk(part.Value.(schema.PartSchema).S)
+			case part.Keyword.Name == "allOf" && part.Keyword.ArgType == arg_type.ArgTypeSchemas:
+				for _, sub := range part.Value.(schema.PartSchemas) {
+					walk(sub)
+				}
+			}
+		}
+	}
+	walk(s)
+	return ret
+}
+
+// propertyOrder returns the names in the x-propertyOrder
+// keyword of s, whether or not the keyword is registered.
+func propertyOrder(s *schema.Schema) []string {
+	pv, ok := s.LookupKeyword("x-propertyOrder")
+	if !ok {
+		return nil
+	}
+	switch v := pv.(type) {
+	case schema.PartStrings:
+		return v
+	case schema.PartAny:
+		vals, _ := v.V.([]any)
+		var names []string
+		for _, val := range vals {
+			if name, ok := val.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// declared returns the names in the properties keyword of s,
+// in the order of their sources if they all have one,
+// and otherwise in order by name.
+func declared(s *schema.Schema) []string {
+	pv, ok := s.LookupKeyword("properties")
+	if !ok {
+		return nil
+	}
+	props := pv.(schema.PartMapSchema)
+	offsets := make(map[string]int64, len(props))
+	for name, sub := range props {
+		src, ok := sub.Source()
+		if !ok {
+			offsets = nil
+			break
+		}
+		offsets[name] = src.Offset
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	if offsets == nil {
+		slices.Sort(names)
+	} else {
+		slices.SortFunc(names, func(a, b string) int {
+			return cmp.Compare(offsets[a], offsets[b])
+		})
+	}
+	return names
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package form_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/form"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode decodes src, which is a schema without its $schema
+// keyword, with its source.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	s, err := schema.UnmarshalWithSource([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", `+src[1:]), nil)
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return s
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			"order",
+			`{"properties": {"z": {}, "b": {}, "m": {}}}`,
+			`{"type":"object","fields":[{"name":"z","label":"z"},{"name":"b","label":"b"},{"name":"m","label":"m"}]}`,
+		},
+		{
+			"propertyOrder",
+			`{"x-propertyOrder": ["m"], "properties": {"z": {}, "b": {}, "m": {}}}`,
+			`{"type":"object","fields":[{"name":"m","label":"m"},{"name":"z","label":"z"},{"name":"b","label":"b"}]}`,
+		},
+		{
+			"annotations",
+			`{
+				"title": "Root",
+				"description": "The root.",
+				"required": ["n"],
+				"properties": {
+					"n": {
+						"type": ["integer", "null"],
+						"title": "Number",
+						"readOnly": true,
+						"default": 3,
+						"minimum": 1,
+						"multipleOf": 0.5
+					},
+					"s": {"type": "string", "maxLength": 10, "pattern": "^a", "format": "email"}
+				}
+			}`,
+			`{"type":"object","label":"Root","help":"The root.","fields":[` +
+				`{"name":"n","type":"integer","nullable":true,"label":"Number","required":true,"readOnly":true,"default":3,"constraints":{"minimum":1,"multipleOf":0.5}},` +
+				`{"name":"s","type":"string","label":"s","constraints":{"format":"email","maxLength":10,"pattern":"^a"}}]}`,
+		},
+		{
+			"options",
+			`{"properties": {
+				"c": {"const": "x"},
+				"e": {"enum": [1, "two", null]},
+				"o": {"oneOf": [{"const": "a", "title": "A"}, {"const": "b"}]},
+				"n": {"anyOf": [{"const": "a"}, {"type": "string"}]}
+			}}`,
+			`{"type":"object","fields":[` +
+				`{"name":"c","label":"c","options":[{"value":"x","label":"x"}]},` +
+				`{"name":"e","label":"e","options":[{"value":1,"label":"1"},{"value":"two","label":"two"},{"value":null,"label":"null"}]},` +
+				`{"name":"o","label":"o","options":[{"value":"a","label":"A"},{"value":"b","label":"b"}]},` +
+				`{"name":"n","label":"n"}]}`,
+		},
+		{
+			"groups",
+			`{"properties": {
+				"name": {},
+				"street": {"x-group": "Address"},
+				"phone": {"x-group": "Contact"},
+				"city": {"x-group": "Address"}
+			}}`,
+			`{"type":"object","fields":[` +
+				`{"name":"name","label":"name"},` +
+				`{"name":"street","label":"street","group":"Address"},` +
+				`{"name":"city","label":"city","group":"Address"},` +
+				`{"name":"phone","label":"phone","group":"Contact"}],` +
+				`"groups":["Address","Contact"]}`,
+		},
+		{
+			"in place",
+			`{
+				"$ref": "#/$defs/base",
+				"allOf": [{"properties": {"b": {"title": "B"}}, "required": ["b"]}],
+				"$defs": {"base": {"properties": {"a": {"type": "string"}}}}
+			}`,
+			`{"type":"object","fields":[` +
+				`{"name":"b","label":"B","required":true},` +
+				`{"name":"a","type":"string","label":"a"}]}`,
+		},
+		{
+			"items",
+			`{"properties": {"tags": {"type": "array", "items": {"type": "string", "minLength": 1}}}}`,
+			`{"type":"object","fields":[` +
+				`{"name":"tags","type":"array","label":"tags","items":{"type":"string","constraints":{"minLength":1}}}]}`,
+		},
+		{
+			"recursive",
+			`{"$defs": {"node": {"properties": {"child": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+			`{"type":"object","fields":[` +
+				`{"name":"child","type":"object","label":"child","fields":[{"name":"child","label":"child","recursive":true}]}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := form.New(decode(t, test.schema))
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(f); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.TrimSuffix(buf.String(), "\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewNotObject(t *testing.T) {
+	for _, src := range []string{`{"type": "string"}`, `{"items": {}}`, `{"enum": [1]}`} {
+		if _, err := form.New(decode(t, src)); err == nil {
+			t.Errorf("%s: no error", src)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package core defines format checkers for the formats that need
+// only the standard library: the date and time formats, the IP
+// address, URI, IRI, JSON pointer, regex and UUID formats.
+// Package [github.com/altshiftab/jsonschema/pkg/format] registers
+// these and the email and hostname formats; import this package
+// for its side effects instead to leave out the dependencies of
+// those formats:
+//
+//	import _ "github.com/altshiftab/jsonschema/pkg/format/core"
+package core
+
+import "github.com/altshiftab/jsonschema/internal/validator"
+
+// init registers the defined formats.
+func init() {
+	validator.RegisterFormatValidator("date", dateFormat)
+	validator.RegisterFormatValidator("date-time", dateTimeFormat)
+	validator.RegisterFormatValidator("duration", durationFormat)
+	validator.RegisterFormatValidator("ipv4", ipv4Format)
+	validator.RegisterFormatValidator("ipv6", ipv6Format)
+	validator.RegisterFormatValidator("iri", iriFormat)
+	validator.RegisterFormatValidator("iri-reference", iriReferenceFormat)
+	validator.RegisterFormatValidator("json-pointer", jsonPointerFormat)
+	validator.RegisterFormatValidator("regex", regexFormat)
+	validator.RegisterFormatValidator("relative-json-pointer", relativeJSONPointerFormat)
+	validator.RegisterFormatValidator("time", timeFormat)
+	validator.RegisterFormatValidator("uri", uriFormat)
+	validator.RegisterFormatValidator("uri-reference", uriReferenceFormat)
+	validator.RegisterFormatValidator("uuid", uuidFormat)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format/core"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		ok     bool
+	}{
+		{"date", "2025-02-28", true},
+		{"date", "2024-02-29", true},
+		{"date", "2025-02-29", false},
+		{"date", "2025-13-01", false},
+		{"date", "2025-1-01", false},
+		{"date", 1.0, true},
+		{"date-time", "2025-02-28T12:30:00Z", true},
+		{"date-time", "2025-02-28t12:30:00.123+01:00", true},
+		{"date-time", "2016-12-31T23:59:60Z", true},
+		{"date-time", "2025-02-28T12:30:00", false},
+		{"date-time", "2025-02-28 12:30:00Z", false},
+		{"time", "12:30:00Z", true},
+		{"time", "12:30:00.5-05:00", true},
+		{"time", "23:59:60Z", true},
+		{"time", "15:59:60-08:00", true},
+		{"time", "12:30:60Z", false},
+		{"time", "24:00:00Z", false},
+		{"time", "12:30:00", false},
+		{"time", "12:30:00.Z", false},
+		{"time", "12:30:00+1:00", false},
+		{"duration", "P1Y2M3DT4H5M6S", true},
+		{"duration", "P4W", true},
+		{"duration", "PT1M", true},
+		{"duration", "P1D", true},
+		{"duration", "P", false},
+		{"duration", "PT", false},
+		{"duration", "P1H", false},
+		{"duration", "P1Y2D", false},
+		{"duration", "1D", false},
+		{"duration", false, true},
+		{"ipv4", "192.168.0.1", true},
+		{"ipv4", "192.168.0.256", false},
+		{"uuid", "2eb8aa08-aa98-11ea-b4aa-73b441d16380", true},
+		{"uuid", "2eb8aa08-aa98-11ea-b4aa-73b441d1638", false},
+		// The email and hostname formats are not checked.
+		{"email", "nope", true},
+		{"hostname", "-a.com", true},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		if err := json.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "format": "`+test.format+`"}`), &s); err != nil {
+			t.Fatal(err)
+		}
+		err := s.ValidateWithOpts(test.value, &schema.ValidateOpts{ValidateFormat: true})
+		if got := err == nil; got != test.ok {
+			t.Errorf("%s %#v: got error %v, want ok %t", test.format, test.value, err, test.ok)
+		}
+	}
+}
@@ -2,11 +2,10 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package format
+package core
 
 import (
 	"fmt"
-	"regexp/syntax"
 
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
@@ -17,8 +16,8 @@ func regexFormat(instance any, state *schema.ValidationState) error {
 	if !ok {
 		return nil
 	}
-	if _, err := syntax.Parse(s, syntax.Perl); err != nil {
-		return fmt.Errorf("%q is not a valid regexp (note that only Go style regexps are supported)", s)
+	if _, err := schema.CompileRegexp(s); err != nil {
+		return fmt.Errorf("%q is not a valid regexp: %v", s, err)
 	}
 	return nil
 }
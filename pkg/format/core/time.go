@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package format
+package core
 
 import (
 	"fmt"
@@ -145,7 +145,7 @@ func isValidTime(s string) bool {
 	s = s[8:]
 	if len(s) > 0 && s[0] == '.' {
 		s = s[1:]
-		if len(s) == 0 {
+		if len(s) == 0 || s[0] < '0' || s[0] > '9' {
 			return false
 		}
 		for len(s) > 0 && s[0] >= '0' && s[0] <= '9' {
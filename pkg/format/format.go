@@ -6,35 +6,23 @@
 // By default the format keyword is always accepted.
 // If this package is imported, the format keyword will be verified
 // as described by the JSON schema docs.
+//
+// The checkers are defined by two subpackages, which this package
+// imports: package [github.com/altshiftab/jsonschema/pkg/format/core]
+// checks the formats that need only the standard library, and package
+// [github.com/altshiftab/jsonschema/pkg/format/net] checks the email
+// and hostname formats. A program that doesn't need the email and
+// hostname formats may import package core alone, to keep the
+// dependencies of package net out of the program.
 package format
 
 import (
 	"github.com/altshiftab/jsonschema/internal/validator"
+	_ "github.com/altshiftab/jsonschema/pkg/format/core"
+	_ "github.com/altshiftab/jsonschema/pkg/format/net"
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
 
-// init registers the defined formats.
-func init() {
-	validator.RegisterFormatValidator("date", dateFormat)
-	validator.RegisterFormatValidator("date-time", dateTimeFormat)
-	validator.RegisterFormatValidator("duration", durationFormat)
-	validator.RegisterFormatValidator("email", emailFormat)
-	validator.RegisterFormatValidator("hostname", hostnameFormat)
-	validator.RegisterFormatValidator("idn-email", idnEmailFormat)
-	validator.RegisterFormatValidator("idn-hostname", idnHostnameFormat)
-	validator.RegisterFormatValidator("ipv4", ipv4Format)
-	validator.RegisterFormatValidator("ipv6", ipv6Format)
-	validator.RegisterFormatValidator("iri", iriFormat)
-	validator.RegisterFormatValidator("iri-reference", iriReferenceFormat)
-	validator.RegisterFormatValidator("json-pointer", jsonPointerFormat)
-	validator.RegisterFormatValidator("regex", regexFormat)
-	validator.RegisterFormatValidator("relative-json-pointer", relativeJSONPointerFormat)
-	validator.RegisterFormatValidator("time", timeFormat)
-	validator.RegisterFormatValidator("uri", uriFormat)
-	validator.RegisterFormatValidator("uri-reference", uriReferenceFormat)
-	validator.RegisterFormatValidator("uuid", uuidFormat)
-}
-
 // RegisterFormatValidator registers a custom format validator.
 // If a schema uses format with the given keyword, this function
 // will be called to validate the schema. The function will be
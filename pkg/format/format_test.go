@@ -0,0 +1,43 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package format_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestFormats checks that importing the package
+// checks the formats of both subpackages.
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		ok     bool
+	}{
+		{"email", "joe@example.com", true},
+		{"email", "nope", false},
+		{"hostname", "a.com", true},
+		{"hostname", "-a.com", false},
+		{"date-time", "2025-02-28T12:30:00Z", true},
+		{"date-time", "x", false},
+		{"ipv6", "::1", true},
+		{"ipv6", "x", false},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		if err := json.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "format": "`+test.format+`"}`), &s); err != nil {
+			t.Fatal(err)
+		}
+		err := s.ValidateWithOpts(test.value, &schema.ValidateOpts{ValidateFormat: true})
+		if got := err == nil; got != test.ok {
+			t.Errorf("%s %q: got error %v, want ok %t", test.format, test.value, err, test.ok)
+		}
+	}
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package format
+package net
 
 import (
 	"fmt"
@@ -93,10 +93,8 @@ func isValidEmail(s string, idn bool) bool {
 	//                  ; zeros.  No more than 4 groups in addition to the
 	//                  ; "::" and IPv4-address-literal may be present.
 
-	// RFC5321 permits IPv6 iterals as "IPv6:literal" but net/mail
-	// doesn't parse that.
-	s = strings.Replace(s, "[IPv6:", "[", 1)
-
+	// net/mail parses an address literal as a domain literal,
+	// so it accepts the "IPv6:" prefix that RFC5321 requires.
 	addr, err := mail.ParseAddress(s)
 	if err != nil || addr.Name != "" {
 		return false
@@ -0,0 +1,21 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package net defines format checkers for the email, idn-email,
+// hostname and idn-hostname formats. They are kept out of package
+// [github.com/altshiftab/jsonschema/pkg/format/core] because they
+// depend on net/mail and golang.org/x/net/idna, which add much to
+// the size of a program that doesn't need them. Package
+// [github.com/altshiftab/jsonschema/pkg/format] imports this package.
+package net
+
+import "github.com/altshiftab/jsonschema/internal/validator"
+
+// init registers the defined formats.
+func init() {
+	validator.RegisterFormatValidator("email", emailFormat)
+	validator.RegisterFormatValidator("hostname", hostnameFormat)
+	validator.RegisterFormatValidator("idn-email", idnEmailFormat)
+	validator.RegisterFormatValidator("idn-hostname", idnHostnameFormat)
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format/net"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		ok     bool
+	}{
+		{"email", "joe@example.com", true},
+		{"email", "joe.bloggs@sub.example.com", true},
+		{"email", `"joe bloggs"@example.com`, true},
+		{"email", "joe@[127.0.0.1]", true},
+		{"email", "joe@[IPv6:::1]", true},
+		{"email", "joe@[::1]", false},
+		{"email", "joe", false},
+		{"email", "joe@", false},
+		{"email", "Joe <joe@example.com>", false},
+		{"email", "joe@exämple.com", false},
+		{"email", 1.0, true},
+		{"idn-email", "joe@exämple.com", true},
+		{"idn-email", "실례@실례.테스트", true},
+		{"idn-email", "joe", false},
+		{"hostname", "example.com", true},
+		{"hostname", "a-b.example.com", true},
+		{"hostname", "-ab.example.com", false},
+		{"hostname", "exämple.com", false},
+		{"hostname", "example..com", false},
+		{"hostname", true, true},
+		{"idn-hostname", "exämple.com", true},
+		{"idn-hostname", "실례.테스트", true},
+		{"idn-hostname", "-ab.example.com", false},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		if err := json.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "format": "`+test.format+`"}`), &s); err != nil {
+			t.Fatal(err)
+		}
+		err := s.ValidateWithOpts(test.value, &schema.ValidateOpts{ValidateFormat: true})
+		if got := err == nil; got != test.ok {
+			t.Errorf("%s %#v: got error %v, want ok %t", test.format, test.value, err, test.ok)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// coerceObject converts the string values in vals, as from a form,
+// to an object instance for the object schema s.
+// Each value is converted according to the type of its property.
+func coerceObject(s *schema.Schema, vals map[string][]string) map[string]any {
+	obj := make(map[string]any, len(vals))
+	for name, vs := range vals {
+		obj[name] = coerceValues(propertySchemas(s, name), vs)
+	}
+	return obj
+}
+
+// coerceValues converts the strings vs to a value for the schemas ss,
+// all of which apply to the value.
+// If the schemas permit an array, the result is an array of the
+// values, each converted according to the type of the items.
+// Otherwise a single value is converted according to the schema type,
+// and multiple values are left as an array of strings,
+// which will fail validation.
+func coerceValues(ss []*schema.Schema, vs []string) any {
+	types := typesOf(ss)
+	if slices.Contains(types, "array") && (len(vs) != 1 || !scalarAllowed(types)) {
+		var items []*schema.Schema
+		for _, s := range ss {
+			items = append(items, keywordSchemas(s, "items")...)
+		}
+		a := make([]any, len(vs))
+		for i, v := range vs {
+			a[i] = coerceScalar(typesOf(items), v)
+		}
+		return a
+	}
+	if len(vs) != 1 {
+		a := make([]any, len(vs))
+		for i, v := range vs {
+			a[i] = v
+		}
+		return a
+	}
+	return coerceScalar(types, vs[0])
+}
+
+// scalarAllowed reports whether types permits a value that is not an array.
+func scalarAllowed(types []string) bool {
+	return slices.ContainsFunc(types, func(t string) bool {
+		return t != "array"
+	})
+}
+
+// coerceScalar converts v to the first of types that it can be
+// converted to. If there is none, v is returned as a string,
+// which will fail validation if a string is not permitted.
+func coerceScalar(types []string, v string) any {
+	if len(types) == 0 || slices.Contains(types, "string") {
+		return v
+	}
+	for _, t := range types {
+		switch t {
+		case "integer", "number":
+			// A fraction for an integer is converted,
+			// so that validation reports the right problem.
+			if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				return f
+			}
+		case "boolean":
+			switch v {
+			case "true":
+				return true
+			case "false":
+				return false
+			}
+		case "null":
+			if v == "" {
+				return nil
+			}
+		}
+	}
+	return v
+}
+
+// typesOf returns the types permitted by all of the schemas ss.
+// It returns nil if they don't restrict the type.
+func typesOf(ss []*schema.Schema) []string {
+	var ret []string
+	restricted := false
+	for _, s := range ss {
+		for _, as := range applicators(s) {
+			v, ok := as.LookupKeyword("type")
+			if !ok {
+				continue
+			}
+			pv := v.(schema.PartStringOrStrings)
+			types := pv.Strings
+			if types == nil {
+				types = []string{pv.String}
+			}
+			if !restricted {
+				ret = slices.Clone(types)
+				restricted = true
+				continue
+			}
+			ret = intersectTypes(ret, types)
+		}
+	}
+	return ret
+}
+
+// intersectTypes returns the types that are permitted by both a and b.
+// An integer is a number, so "integer" is in the result if one has
+// "integer" and the other "number". The result is not nil.
+func intersectTypes(a, b []string) []string {
+	ret := make([]string, 0, len(a))
+	add := func(t string) {
+		if !slices.Contains(ret, t) {
+			ret = append(ret, t)
+		}
+	}
+	for _, t := range a {
+		switch {
+		case slices.Contains(b, t):
+			add(t)
+		case t == "integer" && slices.Contains(b, "number"),
+			t == "number" && slices.Contains(b, "integer"):
+			add("integer")
+		}
+	}
+	return ret
+}
+
+// propertySchemas returns the schemas that apply to
+// the property name of an instance of the object schema s.
+func propertySchemas(s *schema.Schema, name string) []*schema.Schema {
+	var ret []*schema.Schema
+	for _, as := range applicators(s) {
+		matched := false
+		if v, ok := as.LookupKeyword("properties"); ok {
+			if ps, ok := v.(schema.PartMapSchema)[name]; ok {
+				ret = append(ret, ps)
+				matched = true
+			}
+		}
+		if v, ok := as.LookupKeyword("patternProperties"); ok {
+			for pat, ps := range v.(schema.PartMapSchema) {
+				if re, err := schema.CompileRegexp(pat); err == nil && re.MatchString(name) {
+					ret = append(ret, ps)
+					matched = true
+				}
+			}
+		}
+		if v, ok := as.LookupKeyword("additionalProperties"); ok && !matched {
+			ret = append(ret, v.(schema.PartSchema).S)
+		}
+	}
+	return ret
+}
+
+// keywordSchemas returns the schema argument of the keyword name
+// in s, or in a schema that applies along with s.
+func keywordSchemas(s *schema.Schema, name string) []*schema.Schema {
+	var ret []*schema.Schema
+	for _, as := range applicators(s) {
+		if v, ok := as.LookupKeyword(name); ok {
+			if ps, ok := v.(schema.PartSchema); ok {
+				ret = append(ret, ps.S)
+			}
+		}
+	}
+	return ret
+}
+
+// applicators returns s and the schemas that always apply along
+// with s: the targets of references and the allOf subschemas.
+func applicators(s *schema.Schema) []*schema.Schema {
+	var ret []*schema.Schema
+	seen := make(map[*schema.Schema]bool)
+	var walk func(s *schema.Schema)
+	walk = func(s *schema.Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		ret = append(ret, s)
+		for _, part := range s.Parts {
+			switch {
+			case part.Keyword.Name == schema.ResolvedRefKeywordName:
+				walk(part.Value.(schema.PartSchema).S)
+			case part.Keyword.Name == "allOf" && part.Keyword.ArgType == arg_type.ArgTypeSchemas:
+				for _, sub := range part.Value.(schema.PartSchemas) {
+					walk(sub)
+				}
+			}
+		}
+	}
+	walk(s)
+	return ret
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Styles of parameter serialization, as in OpenAPI.
+const (
+	// StyleForm is the default for query parameters:
+	// a=1&a=2 if exploded, otherwise a=1,2.
+	StyleForm = "form"
+	// StyleSimple is the default for headers: 1,2.
+	// An object is k1,v1,k2,v2, or k1=v1,k2=v2 if exploded.
+	StyleSimple = "simple"
+	// StyleSpaceDelimited is a=1%202 for an array.
+	StyleSpaceDelimited = "spaceDelimited"
+	// StylePipeDelimited is a=1|2 for an array.
+	StylePipeDelimited = "pipeDelimited"
+	// StyleDeepObject is a[k1]=v1&a[k2]=v2 for an object.
+	StyleDeepObject = "deepObject"
+)
+
+// Param describes how a parameter is serialized.
+type Param struct {
+	// Style is one of the Style constants.
+	// If empty, the default style and explode
+	// for the location of the parameter are used.
+	Style string
+	// Explode is as for the OpenAPI explode field.
+	Explode bool
+}
+
+// ParamOpts describes options for [ValidateValues] and [ValidateHeader].
+type ParamOpts struct {
+	// Params describes the parameters that don't use the default
+	// style for their location, by parameter name.
+	Params map[string]Param
+}
+
+// ValidateValues validates the query parameters or form fields
+// vals against the object schema s, each property of which
+// describes a parameter. It returns the object that was validated.
+//
+// The values, which are strings, are converted to the types
+// of the properties: "3" is 3 for an integer property, and
+// an array property gets the values of a parameter that is
+// given more than once, or that is delimited according to
+// its style. A parameter given once for a property that is
+// not an array is a single value, not an array of one value.
+//
+// Objects can be given in the deepObject style, or in the form
+// style without explode. An object in the form style with explode,
+// whose properties are separate parameters, is not supported.
+func ValidateValues(vals url.Values, s *schema.Schema, opts *ParamOpts) (map[string]any, error) {
+	if opts == nil {
+		opts = &ParamOpts{}
+	}
+
+	obj := make(map[string]any, len(vals))
+	deep := make(map[string]map[string][]string)
+	for name, vs := range vals {
+		if base, key, ok := deepKey(name); ok && opts.param(base, StyleForm).Style == StyleDeepObject {
+			if deep[base] == nil {
+				deep[base] = make(map[string][]string)
+			}
+			deep[base][key] = append(deep[base][key], vs...)
+			continue
+		}
+		obj[name] = coerceParam(propertySchemas(s, name), vs, opts.param(name, StyleForm))
+	}
+	for name, props := range deep {
+		ss := propertySchemas(s, name)
+		pobj := make(map[string]any, len(props))
+		for key, vs := range props {
+			pobj[key] = coerceValues(objectPropertySchemas(ss, key), vs)
+		}
+		obj[name] = pobj
+	}
+
+	if err := s.Validate(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ValidateHeader validates the header h against the object
+// schema s, each property of which describes a header field.
+// Only the fields named by the properties of s are validated;
+// HTTP requests carry many fields that a schema doesn't describe.
+// Field names are not case sensitive, and the object that is
+// validated and returned uses the property names as keys.
+// The values are converted as for [ValidateValues],
+// with the simple style as the default.
+func ValidateHeader(h http.Header, s *schema.Schema, opts *ParamOpts) (map[string]any, error) {
+	if opts == nil {
+		opts = &ParamOpts{}
+	}
+
+	obj := make(map[string]any)
+	for _, name := range propertyNames(s) {
+		vs := h.Values(name)
+		if len(vs) == 0 {
+			continue
+		}
+		obj[name] = coerceParam(propertySchemas(s, name), vs, opts.param(name, StyleSimple))
+	}
+
+	if err := s.Validate(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// param returns the serialization of the parameter name,
+// whose default style is style.
+func (opts *ParamOpts) param(name, style string) Param {
+	if p, ok := opts.Params[name]; ok && p.Style != "" {
+		return p
+	}
+	return Param{Style: style, Explode: style == StyleForm}
+}
+
+// coerceParam converts the values vs of a parameter serialized
+// as described by p to a value for the schemas ss.
+func coerceParam(ss []*schema.Schema, vs []string, p Param) any {
+	types := typesOf(ss)
+	if slices.Contains(types, "object") && len(vs) == 1 {
+		if obj, ok := coerceParamObject(ss, vs[0], p); ok {
+			return obj
+		}
+	}
+
+	sep := ""
+	switch p.Style {
+	case StyleForm:
+		if !p.Explode {
+			sep = ","
+		}
+	case StyleSimple:
+		sep = ","
+	case StyleSpaceDelimited:
+		sep = " "
+	case StylePipeDelimited:
+		sep = "|"
+	}
+	if sep != "" && slices.Contains(types, "array") {
+		var split []string
+		for _, v := range vs {
+			for _, e := range strings.Split(v, sep) {
+				if p.Style == StyleSimple {
+					// Header lists may have spaces after commas.
+					e = strings.TrimSpace(e)
+				}
+				split = append(split, e)
+			}
+		}
+		vs = split
+	}
+	return coerceValues(ss, vs)
+}
+
+// coerceParamObject converts the value v of a parameter serialized
+// as described by p to an object for the schemas ss.
+// It reports false if v can't be converted.
+func coerceParamObject(ss []*schema.Schema, v string, p Param) (map[string]any, bool) {
+	parts := strings.Split(v, ",")
+	obj := make(map[string]any)
+	switch {
+	case p.Style == StyleSimple && p.Explode:
+		for _, part := range parts {
+			key, val, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, false
+			}
+			obj[key] = coerceValues(objectPropertySchemas(ss, key), []string{val})
+		}
+	case p.Style == StyleSimple || (p.Style == StyleForm && !p.Explode):
+		if len(parts)%2 != 0 {
+			return nil, false
+		}
+		for i := 0; i < len(parts); i += 2 {
+			key := parts[i]
+			obj[key] = coerceValues(objectPropertySchemas(ss, key), []string{parts[i+1]})
+		}
+	default:
+		return nil, false
+	}
+	return obj, true
+}
+
+// objectPropertySchemas returns the schemas that apply to the
+// property name of an object that is valid against all of ss.
+func objectPropertySchemas(ss []*schema.Schema, name string) []*schema.Schema {
+	var ret []*schema.Schema
+	for _, s := range ss {
+		ret = append(ret, propertySchemas(s, name)...)
+	}
+	return ret
+}
+
+// propertyNames returns the sorted names of the properties of s.
+func propertyNames(s *schema.Schema) []string {
+	var ret []string
+	for _, as := range applicators(s) {
+		if v, ok := as.LookupKeyword("properties"); ok {
+			for name := range v.(schema.PartMapSchema) {
+				if !slices.Contains(ret, name) {
+					ret = append(ret, name)
+				}
+			}
+		}
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// deepKey reports whether name has the form base[key],
+// as used by the deepObject style.
+func deepKey(name string) (base, key string, ok bool) {
+	i := strings.IndexByte(name, '[')
+	if i <= 0 || !strings.HasSuffix(name, "]") {
+		return "", "", false
+	}
+	return name[:i], name[i+1 : len(name)-1], true
+}
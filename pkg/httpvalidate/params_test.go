@@ -0,0 +1,121 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate_test
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/httpvalidate"
+)
+
+func TestValidateValues(t *testing.T) {
+	s := decode(t, `{
+		"properties": {
+			"n": {"type": "integer"},
+			"ids": {"type": "array", "items": {"type": "integer"}},
+			"names": {"type": "array", "items": {"type": "string"}},
+			"color": {"type": "object", "properties": {"r": {"type": "integer"}, "g": {"type": "integer"}}},
+			"filter": {"type": "object", "properties": {"min": {"type": "number"}, "q": {"type": "string"}}}
+		}
+	}`)
+	opts := &httpvalidate.ParamOpts{
+		Params: map[string]httpvalidate.Param{
+			"names":  {Style: httpvalidate.StylePipeDelimited},
+			"color":  {Style: httpvalidate.StyleForm, Explode: false},
+			"filter": {Style: httpvalidate.StyleDeepObject, Explode: true},
+		},
+	}
+	tests := []struct {
+		query string
+		want  map[string]any
+		ok    bool
+	}{
+		{"n=3", map[string]any{"n": 3.0}, true},
+		{"ids=1&ids=2", map[string]any{"ids": []any{1.0, 2.0}}, true},
+		{"ids=1", map[string]any{"ids": []any{1.0}}, true},
+		// Exploded form style doesn't split on commas.
+		{"ids=1,2", nil, false},
+		{"names=a|b", map[string]any{"names": []any{"a", "b"}}, true},
+		{"color=r,1,g,2", map[string]any{"color": map[string]any{"r": 1.0, "g": 2.0}}, true},
+		{"filter[min]=1.5&filter[q]=x", map[string]any{"filter": map[string]any{"min": 1.5, "q": "x"}}, true},
+		{"filter[min]=x", nil, false},
+		{"other=1", map[string]any{"other": "1"}, true},
+		{"n=x", nil, false},
+	}
+	for _, test := range tests {
+		vals, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := httpvalidate.ValidateValues(vals, s, opts)
+		if (err == nil) != test.ok {
+			t.Errorf("%s: got error %v, want ok %t", test.query, err, test.ok)
+			continue
+		}
+		if test.ok && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.query, got, test.want)
+		}
+	}
+
+	vals, _ := url.ParseQuery("ids=1,2&n=3")
+	got, err := httpvalidate.ValidateValues(vals, s, &httpvalidate.ParamOpts{
+		Params: map[string]httpvalidate.Param{"ids": {Style: httpvalidate.StyleForm}},
+	})
+	want := map[string]any{"ids": []any{1.0, 2.0}, "n": 3.0}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("form without explode: got %#v, %v, want %#v", got, err, want)
+	}
+}
+
+func TestValidateHeader(t *testing.T) {
+	s := decode(t, `{
+		"properties": {
+			"X-Count": {"type": "integer"},
+			"X-Tags": {"type": "array", "items": {"type": "string"}},
+			"X-Point": {"type": "object", "properties": {"x": {"type": "integer"}, "y": {"type": "integer"}}}
+		},
+		"required": ["X-Count"]
+	}`)
+	tests := []struct {
+		header http.Header
+		opts   *httpvalidate.ParamOpts
+		want   map[string]any
+		ok     bool
+	}{
+		{
+			http.Header{"X-Count": {"2"}, "X-Tags": {"a, b", "c"}, "X-Other": {"z"}},
+			nil,
+			map[string]any{"X-Count": 2.0, "X-Tags": []any{"a", "b", "c"}},
+			true,
+		},
+		{
+			http.Header{"X-Count": {"1"}, "X-Point": {"x,1,y,2"}},
+			nil,
+			map[string]any{"X-Count": 1.0, "X-Point": map[string]any{"x": 1.0, "y": 2.0}},
+			true,
+		},
+		{
+			http.Header{"X-Count": {"1"}, "X-Point": {"x=1,y=2"}},
+			&httpvalidate.ParamOpts{Params: map[string]httpvalidate.Param{"X-Point": {Style: httpvalidate.StyleSimple, Explode: true}}},
+			map[string]any{"X-Count": 1.0, "X-Point": map[string]any{"x": 1.0, "y": 2.0}},
+			true,
+		},
+		{http.Header{"X-Tags": {"a"}}, nil, nil, false},
+		{http.Header{"X-Count": {"many"}}, nil, nil, false},
+	}
+	for _, test := range tests {
+		got, err := httpvalidate.ValidateHeader(test.header, s, test.opts)
+		if (err == nil) != test.ok {
+			t.Errorf("%v: got error %v, want ok %t", test.header, err, test.ok)
+			continue
+		}
+		if test.ok && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: got %#v, want %#v", test.header, got, test.want)
+		}
+	}
+}
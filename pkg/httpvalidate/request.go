@@ -0,0 +1,125 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpvalidate validates the parts of HTTP requests
+// against schemas, for handlers that don't use a framework
+// that does so. Values that arrive as strings, such as form
+// fields, are converted to the types the schema expects
+// before validation.
+package httpvalidate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// ErrUnsupportedMediaType is returned by [ValidateHTTPRequest]
+// for a request body that is not in a supported media type.
+// A handler would normally respond with
+// [http.StatusUnsupportedMediaType].
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// MaxBodySize is the largest request body that
+// [ValidateHTTPRequest] will read.
+var MaxBodySize int64 = 10 << 20
+
+// ValidateHTTPRequest validates the body of r against s,
+// and returns the decoded body. The media type of the body
+// is taken from the Content-Type header:
+//
+//   - application/json, and types with a +json suffix,
+//     are decoded as JSON.
+//   - application/x-www-form-urlencoded and multipart/form-data
+//     are decoded into an object whose properties are the form
+//     fields. Each field is converted to the type of its property
+//     in s, so that "3" is 3 for an integer property, and a field
+//     given more than once is an array if the property is an array.
+//     Files in a multipart form are ignored.
+//
+// A request without a body is validated as null.
+// The body is restored after it is read,
+// so that the handler can read it again.
+// If the body is not valid, the error is a validation error.
+func ValidateHTTPRequest(r *http.Request, s *schema.Schema) (any, error) {
+	data, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, s.Validate(nil)
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		ct = "application/json"
+	}
+	mediaType, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+
+	var instance any
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if err := json.Unmarshal(data, &instance); err != nil {
+			return nil, fmt.Errorf("decoding request body: %v", err)
+		}
+
+	case mediaType == "application/x-www-form-urlencoded":
+		vals, err := url.ParseQuery(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding request body: %v", err)
+		}
+		instance = coerceObject(s, vals)
+
+	case mediaType == "multipart/form-data":
+		boundary := params["boundary"]
+		if boundary == "" {
+			return nil, errors.New("decoding request body: no multipart boundary")
+		}
+		mr := multipart.NewReader(bytes.NewReader(data), boundary)
+		form, err := mr.ReadForm(MaxBodySize)
+		if err != nil {
+			return nil, fmt.Errorf("decoding request body: %v", err)
+		}
+		defer form.RemoveAll()
+		instance = coerceObject(s, form.Value)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	if err := s.Validate(instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// readBody reads the body of r, and replaces it
+// so that it can be read again.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %v", err)
+	}
+	if int64(len(data)) > MaxBodySize {
+		return nil, fmt.Errorf("request body larger than %d bytes", MaxBodySize)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
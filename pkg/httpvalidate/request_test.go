@@ -0,0 +1,113 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/httpvalidate"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in draft 2020-12.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+func TestValidateHTTPRequestForm(t *testing.T) {
+	s := decode(t, `{
+		"properties": {
+			"n": {"type": "integer"},
+			"f": {"type": "number"},
+			"b": {"type": "boolean"},
+			"s": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "integer"}},
+			"both": {"allOf": [{"type": "number"}, {"type": "integer"}]},
+			"either": {"allOf": [{"type": ["integer", "string"]}, {"type": "number"}]}
+		}
+	}`)
+	tests := []struct {
+		body string
+		want map[string]any
+		// err is part of the error, or "" if valid.
+		err string
+	}{
+		{"n=3&f=1.5&b=true&s=4", map[string]any{"n": 3.0, "f": 1.5, "b": true, "s": "4"}, ""},
+		{"tags=1&tags=2", map[string]any{"tags": []any{1.0, 2.0}}, ""},
+		{"tags=1", map[string]any{"tags": []any{1.0}}, ""},
+		{"both=3", map[string]any{"both": 3.0}, ""},
+		{"either=3", map[string]any{"either": 3.0}, ""},
+		{"n=1.5", nil, "integer"},
+		{"n=x", nil, "integer"},
+		{"both=1.5", nil, "integer"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		got, err := httpvalidate.ValidateHTTPRequest(r, s)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.body, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.body, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.body, got, test.want)
+		}
+
+		// The body can be read again.
+		data, err := io.ReadAll(r.Body)
+		if err != nil || string(data) != test.body {
+			t.Errorf("%s: body read again is %q, %v", test.body, data, err)
+		}
+	}
+}
+
+func TestValidateHTTPRequestJSON(t *testing.T) {
+	s := decode(t, `{"type": "object", "required": ["id"]}`)
+	tests := []struct {
+		contentType string
+		body        string
+		ok          bool
+	}{
+		{"application/json", `{"id": 1}`, true},
+		{"application/merge-patch+json; charset=utf-8", `{"id": 1}`, true},
+		{"", `{"id": 1}`, true},
+		{"application/json", `{}`, false},
+		{"application/json", `{`, false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		if test.contentType != "" {
+			r.Header.Set("Content-Type", test.contentType)
+		}
+		if _, err := httpvalidate.ValidateHTTPRequest(r, s); (err == nil) != test.ok {
+			t.Errorf("%q %s: got error %v, want ok %t", test.contentType, test.body, err, test.ok)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader("id: 1"))
+	r.Header.Set("Content-Type", "application/yaml")
+	if _, err := httpvalidate.ValidateHTTPRequest(r, s); !errors.Is(err, httpvalidate.ErrUnsupportedMediaType) {
+		t.Errorf("YAML body: got error %v, want ErrUnsupportedMediaType", err)
+	}
+}
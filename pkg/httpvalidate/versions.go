@@ -0,0 +1,220 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// ErrUnknownVersion is returned by the methods of [Versions]
+// when the requested version has no schema, or when no version
+// is requested and there is no default.
+// A handler would normally respond with a 406 or 400 status.
+var ErrUnknownVersion = errors.New("unknown schema version")
+
+// Versions is a set of schemas for the versions of a payload,
+// such as the versions of an API. It selects the schema for the
+// version that a client asks for, by an Accept-like header or
+// by a field of the payload.
+//
+// Add the schemas with the Add method.
+// A Versions may be used by multiple goroutines simultaneously,
+// provided that none of them is calling Add.
+type Versions struct {
+	// Default is the version to use when none is requested.
+	// If empty, a version must be requested.
+	Default string
+	// Param is the media type parameter that names the version
+	// in a header, as in "application/json; version=v2".
+	// If empty, "version" is used.
+	Param string
+	// Field is the property of an object payload that names
+	// the version, such as "apiVersion". If empty, the version
+	// is never taken from the payload.
+	Field string
+
+	// names is the versions in the order they were added.
+	names []string
+	// schemas maps versions to their schemas.
+	schemas map[string]*schema.Schema
+}
+
+// Add adds the schema s for version, replacing any earlier one.
+func (vs *Versions) Add(version string, s *schema.Schema) *Versions {
+	if vs.schemas == nil {
+		vs.schemas = make(map[string]*schema.Schema)
+	}
+	if _, ok := vs.schemas[version]; !ok {
+		vs.names = append(vs.names, version)
+	}
+	vs.schemas[version] = s
+	return vs
+}
+
+// Lookup returns the schema for version.
+func (vs *Versions) Lookup(version string) (*schema.Schema, bool) {
+	s, ok := vs.schemas[version]
+	return s, ok
+}
+
+// Negotiate returns the version requested by header, which is
+// formatted like an HTTP Accept header: a comma-separated list of
+// entries with optional "q" weights. An entry names a version either
+// with the Param parameter, as in "application/json; version=v2",
+// or directly, as in "v2". The known version with the highest weight
+// is returned; of versions with the same weight, the first listed wins.
+// Entries for unknown versions are ignored, and entries that name no
+// version match the default. If header is empty, the default is used.
+func (vs *Versions) Negotiate(header string) (string, error) {
+	param := vs.Param
+	if param == "" {
+		param = "version"
+	}
+
+	best, bestQ := "", 0.0
+	found := false
+	for entry := range strings.SplitSeq(header, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(entry)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+
+		version, ok := params[param]
+		if !ok {
+			if _, known := vs.schemas[mediaType]; known {
+				version = mediaType
+			} else {
+				version = vs.Default
+			}
+		}
+		if _, known := vs.schemas[version]; !known || q <= 0 {
+			continue
+		}
+		if !found || q > bestQ {
+			best, bestQ, found = version, q, true
+		}
+	}
+
+	if found {
+		return best, nil
+	}
+	if strings.TrimSpace(header) != "" {
+		return "", fmt.Errorf("%w: none of %q", ErrUnknownVersion, header)
+	}
+	return vs.version(vs.Default)
+}
+
+// Validate validates instance against the schema for the version
+// requested by header, as for [Versions.Negotiate]. If header is
+// empty and Field is set, the version is the value of that property
+// of instance, if it has one. It returns the selected version.
+//
+// If instance is not valid, the error is a [*VersionError],
+// which records the versions that would have accepted it.
+func (vs *Versions) Validate(header string, instance any) (string, error) {
+	var version string
+	var err error
+	if v, ok := vs.fieldVersion(instance); ok && strings.TrimSpace(header) == "" {
+		version, err = vs.version(v)
+	} else {
+		version, err = vs.Negotiate(header)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := vs.schemas[version].Validate(instance); err != nil {
+		if !errors2.IsValidationError(err) {
+			return version, err
+		}
+		return version, &VersionError{
+			Version:  version,
+			Err:      err,
+			Accepted: vs.accepting(instance, version),
+		}
+	}
+	return version, nil
+}
+
+// fieldVersion returns the version named by the Field property
+// of instance.
+func (vs *Versions) fieldVersion(instance any) (string, bool) {
+	if vs.Field == "" {
+		return "", false
+	}
+	obj, ok := instance.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	v, ok := obj[vs.Field].(string)
+	return v, ok
+}
+
+// version checks that version has a schema.
+func (vs *Versions) version(version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("%w: no version requested", ErrUnknownVersion)
+	}
+	if _, ok := vs.schemas[version]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownVersion, version)
+	}
+	return version, nil
+}
+
+// accepting returns the versions other than skip
+// whose schemas accept instance, in the order they were added.
+func (vs *Versions) accepting(instance any, skip string) []string {
+	var ret []string
+	for _, name := range vs.names {
+		if name != skip && vs.schemas[name].Validate(instance) == nil {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// VersionError is returned by [Versions.Validate] for an instance
+// that is not valid for the selected version. It unwraps to the
+// validation error, so it matches ErrInvalidInstance in the
+// jsonschema errors package.
+type VersionError struct {
+	// Version is the version that was selected.
+	Version string
+	// Err is the validation error for that version.
+	Err error
+	// Accepted lists the other versions whose schemas
+	// accept the instance. It may be empty.
+	Accepted []string
+}
+
+// Error returns the error message that a user should see.
+func (ve *VersionError) Error() string {
+	if len(ve.Accepted) == 0 {
+		return fmt.Sprintf("not valid for version %s: %v", ve.Version, ve.Err)
+	}
+	return fmt.Sprintf("not valid for version %s (valid for %s): %v", ve.Version, strings.Join(ve.Accepted, ", "), ve.Err)
+}
+
+// Unwrap returns the validation error.
+func (ve *VersionError) Unwrap() error {
+	return ve.Err
+}
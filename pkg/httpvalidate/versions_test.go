@@ -0,0 +1,118 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpvalidate_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/httpvalidate"
+)
+
+// versions returns a Versions with schemas for v1, v2 and v3.
+func versions(t *testing.T) *httpvalidate.Versions {
+	vs := &httpvalidate.Versions{Default: "v1", Field: "apiVersion"}
+	vs.Add("v1", decode(t, `{"required": ["name"]}`))
+	vs.Add("v2", decode(t, `{"required": ["fullName"]}`))
+	vs.Add("v3", decode(t, `{"required": ["fullName", "email"]}`))
+	return vs
+}
+
+func TestNegotiate(t *testing.T) {
+	vs := versions(t)
+	tests := []struct {
+		header string
+		// want is the version, or "" for ErrUnknownVersion.
+		want string
+	}{
+		{"", "v1"},
+		{"application/json", "v1"},
+		{"*/*", "v1"},
+		{"application/json; version=v2", "v2"},
+		{"v3", "v3"},
+		{"application/json; version=v2, application/json; version=v3", "v2"},
+		{"application/json; version=v2; q=0.5, application/json; version=v3", "v3"},
+		{"application/json; version=v9, application/json; version=v2", "v2"},
+		{"application/json; version=v2; q=0", ""},
+		{"application/json; version=v9", ""},
+		{"application/json; version=v2; q=x, v3", "v3"},
+	}
+	for _, test := range tests {
+		got, err := vs.Negotiate(test.header)
+		if test.want == "" {
+			if !errors.Is(err, httpvalidate.ErrUnknownVersion) {
+				t.Errorf("%q: got %q, %v, want ErrUnknownVersion", test.header, got, err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%q: got %q, %v, want %q", test.header, got, err, test.want)
+		}
+	}
+
+	vs.Param = "v"
+	if got, err := vs.Negotiate("application/json; v=v3"); err != nil || got != "v3" {
+		t.Errorf("Param v: got %q, %v, want v3", got, err)
+	}
+	vs.Default = ""
+	if _, err := vs.Negotiate(""); !errors.Is(err, httpvalidate.ErrUnknownVersion) {
+		t.Errorf("no default: got %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestVersionsValidate(t *testing.T) {
+	vs := versions(t)
+	tests := []struct {
+		header   string
+		instance map[string]any
+		version  string
+		// accepted is the Accepted field of the
+		// VersionError, or nil if valid.
+		accepted []string
+	}{
+		{"", map[string]any{"name": "a"}, "v1", nil},
+		{"", map[string]any{"apiVersion": "v2", "fullName": "a"}, "v2", nil},
+		// The header wins over the field.
+		{"v1", map[string]any{"apiVersion": "v2", "name": "a"}, "v1", nil},
+		{"v1", map[string]any{"fullName": "a", "email": "b"}, "v1", []string{"v2", "v3"}},
+		{"v3", map[string]any{"fullName": "a"}, "v3", []string{"v2"}},
+		{"v2", map[string]any{}, "v2", []string{}},
+	}
+	for _, test := range tests {
+		version, err := vs.Validate(test.header, test.instance)
+		if version != test.version {
+			t.Errorf("%q %v: got version %q, want %q", test.header, test.instance, version, test.version)
+		}
+		if test.accepted == nil {
+			if err != nil {
+				t.Errorf("%q %v: %v", test.header, test.instance, err)
+			}
+			continue
+		}
+		var ve *httpvalidate.VersionError
+		if !errors.As(err, &ve) {
+			t.Errorf("%q %v: got %v, want a VersionError", test.header, test.instance, err)
+			continue
+		}
+		if ve.Version != test.version || !slices.Equal(ve.Accepted, test.accepted) {
+			t.Errorf("%q %v: got %+v, want accepted %q", test.header, test.instance, ve, test.accepted)
+		}
+		if !errors.Is(err, errors2.ErrInvalidInstance) {
+			t.Errorf("%q %v: error does not match ErrInvalidInstance", test.header, test.instance)
+		}
+	}
+
+	if _, err := vs.Validate("", map[string]any{"apiVersion": "v9"}); !errors.Is(err, httpvalidate.ErrUnknownVersion) {
+		t.Errorf("unknown field version: got %v, want ErrUnknownVersion", err)
+	}
+	if s, ok := vs.Lookup("v2"); !ok || s == nil {
+		t.Error("Lookup v2 failed")
+	}
+	if _, ok := vs.Lookup("v9"); ok {
+		t.Error("Lookup v9 succeeded")
+	}
+}
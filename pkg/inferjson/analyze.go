@@ -0,0 +1,240 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inferjson
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Kinds of [Suggestion].
+const (
+	// KindUnusedProperty is an optional property
+	// that no instance has. It might be removed.
+	KindUnusedProperty = "unused-property"
+	// KindRequire is an optional property that
+	// every instance has. It might be required.
+	KindRequire = "require"
+	// KindNarrowRange is a number whose values all fall in a
+	// narrower range than the schema permits. The Value is
+	// an object with the observed "minimum" and "maximum".
+	KindNarrowRange = "narrow-range"
+	// KindEnum is a string that takes only a few values,
+	// which are the Value. It might have an enum keyword.
+	KindEnum = "enum"
+	// KindLoosen is a keyword that instances often fail.
+	// It might be too strict.
+	KindLoosen = "loosen"
+)
+
+// Report is the result of [Analyze]. It is meant to be
+// encoded as JSON for other programs to read.
+type Report struct {
+	// Instances is the number of instances analyzed.
+	Instances int `json:"instances"`
+	// Invalid is the number of them that failed validation.
+	Invalid int `json:"invalid"`
+	// Suggestions are the suggested changes to the schema,
+	// ordered by schema location.
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Suggestion is a suggested change to a schema.
+type Suggestion struct {
+	// Kind is one of the Kind constants.
+	Kind string `json:"kind"`
+	// SchemaLocation is the location, as a JSON pointer fragment,
+	// of the schema or keyword that might change. For KindLoosen
+	// it is the absolute keyword location of the failures, which
+	// starts with the URI of the schema resource if there is one.
+	SchemaLocation string `json:"schemaLocation"`
+	// Message describes the suggestion.
+	Message string `json:"message"`
+	// Value is a value for the suggested keywords, if any.
+	Value any `json:"value,omitempty"`
+	// Rate is the fraction of instances that failed
+	// the keyword, for KindLoosen.
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// Analyze compares s with observed instances, as decoded by
+// [json.Unmarshal], and suggests ways to tighten or loosen it.
+//
+// Tightening suggestions come from the instances that are valid:
+// optional properties that none of them has, or that all of them
+// have, numbers with narrower ranges than the schema permits, and
+// strings that take only a few values. These follow the properties
+// and items keywords from the root of s; subschemas reached in other
+// ways are not analyzed. Loosening suggestions come from the
+// instances that are not valid: keywords that at least
+// opts.FailureThreshold of all the instances fail.
+//
+// The opts argument may be nil. Its RequiredThreshold
+// and Formats fields are not used.
+func Analyze(s *schema.Schema, instances []any, opts *Options) (*Report, error) {
+	in := New(opts)
+	report := &Report{Instances: len(instances)}
+
+	failures := make(map[string]int)
+	messages := make(map[string]string)
+	for _, inst := range instances {
+		err := s.Validate(inst)
+		if err == nil {
+			in.Add(inst)
+			continue
+		}
+		if !errors2.IsValidationError(err) {
+			return nil, err
+		}
+		report.Invalid++
+		// Count each keyword once per instance.
+		seen := make(map[string]bool)
+		for _, ve := range validationErrors(err) {
+			loc := ve.AbsoluteKeywordLocation
+			if loc == "" {
+				loc = ve.KeywordLocation
+			}
+			if seen[loc] {
+				continue
+			}
+			seen[loc] = true
+			failures[loc]++
+			if _, ok := messages[loc]; !ok {
+				messages[loc] = ve.Message
+			}
+		}
+	}
+
+	var sugs []Suggestion
+	if in.root.count > 0 {
+		sugs = in.tighten(sugs, s, in.root, "#")
+	}
+	for loc, n := range failures {
+		rate := float64(n) / float64(len(instances))
+		if rate < in.opts.FailureThreshold {
+			continue
+		}
+		sugs = append(sugs, Suggestion{
+			Kind:           KindLoosen,
+			SchemaLocation: loc,
+			Message:        fmt.Sprintf("%d of %d instances fail this keyword, such as with: %s", n, len(instances), messages[loc]),
+			Rate:           rate,
+		})
+	}
+	slices.SortStableFunc(sugs, func(a, b Suggestion) int {
+		return cmp.Or(strings.Compare(a.SchemaLocation, b.SchemaLocation), strings.Compare(a.Kind, b.Kind))
+	})
+	report.Suggestions = sugs
+	return report, nil
+}
+
+// validationErrors returns the validation errors in err.
+func validationErrors(err error) []*errors2.ValidationError {
+	var ves *errors2.ValidationErrors
+	if errors.As(err, &ves) {
+		return ves.Errs
+	}
+	var ve *errors2.ValidationError
+	if errors.As(err, &ve) {
+		return []*errors2.ValidationError{ve}
+	}
+	return nil
+}
+
+// tighten appends to sugs the suggestions for tightening s,
+// whose instances are recorded in n. The location of s is loc.
+func (in *Inferrer) tighten(sugs []Suggestion, s *schema.Schema, n *node, loc string) []Suggestion {
+	var required []string
+	if pv, ok := s.LookupKeyword("required"); ok {
+		required, _ = pv.(schema.PartStrings)
+	}
+	if pv, ok := s.LookupKeyword("properties"); ok && n.types["object"] > 0 {
+		props, _ := pv.(schema.PartMapSchema)
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			ploc := loc + "/properties/" + jsonpointer.EscapeToken(name)
+			pn := n.props[name]
+			optional := !slices.Contains(required, name)
+			switch {
+			case pn == nil && optional:
+				sugs = append(sugs, Suggestion{
+					Kind:           KindUnusedProperty,
+					SchemaLocation: ploc,
+					Message:        fmt.Sprintf("optional property %q is not in any instance", name),
+				})
+			case pn != nil && optional && pn.count == n.types["object"]:
+				sugs = append(sugs, Suggestion{
+					Kind:           KindRequire,
+					SchemaLocation: loc + "/required",
+					Message:        fmt.Sprintf("optional property %q is in every instance", name),
+					Value:          name,
+				})
+			}
+			if pn != nil {
+				sugs = in.tighten(sugs, props[name], pn, ploc)
+			}
+		}
+	}
+
+	if pv, ok := s.LookupKeyword("items"); ok && n.items != nil && n.items.count > 0 {
+		if ps, ok := pv.(schema.PartSchema); ok {
+			sugs = in.tighten(sugs, ps.S, n.items, loc+"/items")
+		}
+	}
+
+	if n.numbers > 0 && n.numbers == n.count {
+		min, hasMin := floatKeyword(s, "minimum")
+		max, hasMax := floatKeyword(s, "maximum")
+		if !hasMin || min < n.min || !hasMax || max > n.max {
+			sugs = append(sugs, Suggestion{
+				Kind:           KindNarrowRange,
+				SchemaLocation: loc,
+				Message:        fmt.Sprintf("values are between %v and %v", n.min, n.max),
+				Value:          map[string]any{"minimum": n.min, "maximum": n.max},
+			})
+		}
+	}
+
+	if _, ok := s.LookupKeyword("enum"); !ok {
+		if _, ok := s.LookupKeyword("const"); !ok {
+			if enum, ok := n.enum(); ok {
+				sugs = append(sugs, Suggestion{
+					Kind:           KindEnum,
+					SchemaLocation: loc,
+					Message:        fmt.Sprintf("values are one of %d strings", len(enum)),
+					Value:          enum,
+				})
+			}
+		}
+	}
+
+	return sugs
+}
+
+// floatKeyword returns the value of the numeric keyword name of s.
+func floatKeyword(s *schema.Schema, name string) (float64, bool) {
+	pv, ok := s.LookupKeyword(name)
+	if !ok {
+		return 0, false
+	}
+	switch v := pv.(type) {
+	case schema.PartFloat:
+		return float64(v), true
+	case schema.PartInt:
+		return float64(v), true
+	}
+	return 0, false
+}
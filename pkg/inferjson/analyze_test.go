@@ -0,0 +1,81 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inferjson_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/inferjson"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestAnalyze(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"kind": {"type": "string"},
+			"size": {"type": "number", "minimum": 0},
+			"legacy": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}}
+		},
+		"required": ["id"]
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	instances := decode(t,
+		`{"id": 1, "kind": "a", "size": 2, "tags": ["x"]}`,
+		`{"id": 2, "kind": "b", "size": 5, "tags": ["x", "y"]}`,
+		`{"id": 3, "kind": "a", "size": 3, "tags": []}`,
+		`{"id": 4, "kind": "a", "size": 4, "tags": ["long tag"]}`,
+		`{"kind": "b"}`,
+	)
+	report, err := inferjson.Analyze(&s, instances, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Instances != 5 || report.Invalid != 2 {
+		t.Errorf("got %d instances, %d invalid, want 5, 2", report.Instances, report.Invalid)
+	}
+
+	type sug struct {
+		kind, loc string
+		value     any
+	}
+	var got []sug
+	for _, s := range report.Suggestions {
+		got = append(got, sug{s.Kind, s.SchemaLocation, s.Value})
+	}
+	want := []sug{
+		{inferjson.KindNarrowRange, "#/properties/id", map[string]any{"minimum": 1.0, "maximum": 3.0}},
+		{inferjson.KindEnum, "#/properties/kind", []any{"a", "b"}},
+		{inferjson.KindUnusedProperty, "#/properties/legacy", nil},
+		{inferjson.KindNarrowRange, "#/properties/size", map[string]any{"minimum": 2.0, "maximum": 5.0}},
+		{inferjson.KindEnum, "#/properties/tags/items", []any{"x", "y"}},
+		{inferjson.KindLoosen, "#/properties/tags/items/maxLength", nil},
+		{inferjson.KindLoosen, "#/required", nil},
+		{inferjson.KindRequire, "#/required", "kind"},
+		{inferjson.KindRequire, "#/required", "size"},
+		{inferjson.KindRequire, "#/required", "tags"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+
+	// A higher threshold leaves out the loosening suggestions.
+	report, err = inferjson.Analyze(&s, instances, &inferjson.Options{FailureThreshold: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range report.Suggestions {
+		if s.Kind == inferjson.KindLoosen {
+			t.Errorf("FailureThreshold 0.5: got %+v", s)
+		}
+	}
+}
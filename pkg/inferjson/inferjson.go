@@ -0,0 +1,355 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package inferjson infers a draft 2020-12 schema from example
+// JSON instances, such as the requests and responses of an API
+// that has no schema. The result describes the types that were
+// seen at each location, the object properties that were present
+// often enough to be required, enumerations of strings that took
+// only a few values, and string formats that every value matched.
+//
+// An inferred schema is a starting point for writing a schema
+// by hand: it only knows about the examples it was given.
+// Once there is a schema, [Analyze] compares it with the
+// instances that are seen, and suggests changes to it.
+package inferjson
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Default values of the [Options] fields.
+const (
+	// DefaultRequiredThreshold requires the properties
+	// that are present in every object.
+	DefaultRequiredThreshold = 1.0
+	// DefaultMaxEnum is the largest number of distinct
+	// values for which a string becomes an enumeration.
+	DefaultMaxEnum = 10
+	// DefaultFailureThreshold is the fraction of instances
+	// that must fail a keyword for [Analyze] to suggest loosening it.
+	DefaultFailureThreshold = 0.05
+)
+
+// DefaultFormats is the list of formats that are detected
+// if [Options.Formats] is nil, in order of preference.
+var DefaultFormats = []string{"date-time", "date", "time", "uuid", "email", "ipv4", "ipv6", "uri"}
+
+// Options are options for an [Inferrer].
+type Options struct {
+	// RequiredThreshold is the fraction, between 0 and 1, of the
+	// objects at a location in which a property must be present
+	// for it to be required. If zero, DefaultRequiredThreshold is used.
+	RequiredThreshold float64
+	// MaxEnum is the largest number of distinct values that a
+	// string may take for it to be described by an enum keyword.
+	// A value must also be seen more than once. If zero,
+	// DefaultMaxEnum is used; if negative, no enums are inferred.
+	MaxEnum int
+	// Formats is the list of formats to detect, in order of
+	// preference. A string location gets a format keyword for the
+	// first format that all of its values match. If nil,
+	// DefaultFormats is used; if empty, no formats are detected.
+	Formats []string
+	// FailureThreshold is the fraction, between 0 and 1, of the
+	// instances that must fail a keyword for [Analyze] to suggest
+	// loosening it. If zero, DefaultFailureThreshold is used.
+	FailureThreshold float64
+}
+
+// Inferrer infers a schema from the instances given to it.
+// Use [New] to get an Inferrer.
+type Inferrer struct {
+	opts Options
+	root *node
+	// formats are schemas that check each of opts.Formats.
+	formats []*schema.Schema
+}
+
+// New returns a new [Inferrer]. The opts argument may be nil.
+func New(opts *Options) *Inferrer {
+	in := &Inferrer{root: &node{}}
+	if opts != nil {
+		in.opts = *opts
+	}
+	if in.opts.RequiredThreshold == 0 {
+		in.opts.RequiredThreshold = DefaultRequiredThreshold
+	}
+	if in.opts.MaxEnum == 0 {
+		in.opts.MaxEnum = DefaultMaxEnum
+	}
+	if in.opts.FailureThreshold == 0 {
+		in.opts.FailureThreshold = DefaultFailureThreshold
+	}
+	if in.opts.Formats == nil {
+		in.opts.Formats = DefaultFormats
+	}
+	for _, f := range in.opts.Formats {
+		in.formats = append(in.formats, draft202012.NewSubBuilder().AddFormat(f).Build())
+	}
+	return in
+}
+
+// Infer returns the schema inferred from instances,
+// which are as decoded by [json.Unmarshal].
+func Infer(instances []any, opts *Options) *schema.Schema {
+	in := New(opts)
+	for _, inst := range instances {
+		in.Add(inst)
+	}
+	return in.Schema()
+}
+
+// Add adds an example instance, as decoded by [json.Unmarshal].
+// Numbers may also be [json.Number] values.
+func (in *Inferrer) Add(instance any) {
+	in.add(in.root, instance)
+}
+
+// AddJSON adds an example instance given as JSON.
+func (in *Inferrer) AddJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	in.Add(v)
+	return nil
+}
+
+// Schema returns the schema inferred from the instances added so far.
+// If no instances have been added, the schema accepts everything.
+func (in *Inferrer) Schema() *schema.Schema {
+	b := draft202012.NewBuilder()
+	in.build(b, in.root)
+	return b.Build()
+}
+
+// node records what was seen at one location of the instances.
+type node struct {
+	// count is the number of values seen.
+	count int
+	// types counts the values of each JSON type.
+	// An integer is counted as "integer", not "number".
+	types map[string]int
+
+	// props records the values of object properties,
+	// and propOrder is the order in which they were first seen.
+	props     map[string]*node
+	propOrder []string
+
+	// items records the elements of arrays.
+	items *node
+
+	// strings counts the distinct string values, until there
+	// are too many for an enum, when it is set to nil
+	// and tooMany is set.
+	strings map[string]int
+	tooMany bool
+	// formats is the indexes of the formats that all the
+	// strings matched. It is nil before the first string.
+	formats []int
+
+	// numbers is the number of numeric values,
+	// and min and max are the smallest and largest.
+	numbers  int
+	min, max float64
+}
+
+// add records instance in n.
+func (in *Inferrer) add(n *node, instance any) {
+	n.count++
+	if n.types == nil {
+		n.types = make(map[string]int)
+	}
+	typ := jsonType(instance)
+	n.types[typ]++
+
+	switch v := instance.(type) {
+	case map[string]any:
+		if n.props == nil {
+			n.props = make(map[string]*node)
+		}
+		// Visit properties in a fixed order, so that
+		// propOrder doesn't depend on map iteration.
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			pn, ok := n.props[name]
+			if !ok {
+				pn = &node{}
+				n.props[name] = pn
+				n.propOrder = append(n.propOrder, name)
+			}
+			in.add(pn, v[name])
+		}
+
+	case []any:
+		if n.items == nil {
+			n.items = &node{}
+		}
+		for _, e := range v {
+			in.add(n.items, e)
+		}
+
+	case string:
+		in.addString(n, v)
+
+	case float64:
+		n.addNumber(v)
+
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			n.addNumber(f)
+		}
+	}
+}
+
+// addNumber records the number f in n.
+func (n *node) addNumber(f float64) {
+	if n.numbers == 0 || f < n.min {
+		n.min = f
+	}
+	if n.numbers == 0 || f > n.max {
+		n.max = f
+	}
+	n.numbers++
+}
+
+// addString records the string s in n.
+func (in *Inferrer) addString(n *node, s string) {
+	if !n.tooMany && in.opts.MaxEnum > 0 {
+		if n.strings == nil {
+			n.strings = make(map[string]int)
+		}
+		n.strings[s]++
+		if len(n.strings) > in.opts.MaxEnum {
+			n.strings = nil
+			n.tooMany = true
+		}
+	}
+
+	if n.formats == nil {
+		n.formats = make([]int, len(in.formats))
+		for i := range n.formats {
+			n.formats[i] = i
+		}
+	}
+	opts := &schema.ValidateOpts{ValidateFormat: true}
+	n.formats = slices.DeleteFunc(n.formats, func(i int) bool {
+		return in.formats[i].ValidateWithOpts(s, opts) != nil
+	})
+}
+
+// jsonType returns the JSON type of v.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return "null"
+	}
+}
+
+// enum returns the values of an enum keyword for n, and reports
+// whether n should have one: its values are all strings,
+// there are only a few of them, and some are repeated.
+func (n *node) enum() ([]any, bool) {
+	if n.strings == nil || n.types["string"] != n.count || len(n.strings) >= n.count {
+		return nil, false
+	}
+	values := make([]string, 0, len(n.strings))
+	for s := range n.strings {
+		values = append(values, s)
+	}
+	slices.Sort(values)
+	enum := make([]any, len(values))
+	for i, s := range values {
+		enum[i] = s
+	}
+	return enum, true
+}
+
+// typeOrder is the order in which types are listed in a schema.
+var typeOrder = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// build adds the keywords that describe n to b.
+func (in *Inferrer) build(b *draft202012.Builder, n *node) {
+	if n.count == 0 {
+		return
+	}
+
+	var types []string
+	for _, t := range typeOrder {
+		if n.types[t] == 0 {
+			continue
+		}
+		if t == "integer" && n.types["number"] > 0 {
+			// Every integer is a number.
+			continue
+		}
+		types = append(types, t)
+	}
+	b.AddType(types...)
+
+	if n.types["object"] > 0 {
+		props := make(map[string]*schema.Schema, len(n.props))
+		var required []string
+		for _, name := range n.propOrder {
+			pn := n.props[name]
+			pb := b.NewSubBuilder()
+			in.build(pb, pn)
+			props[name] = pb.Build()
+			if float64(pn.count)/float64(n.types["object"]) >= in.opts.RequiredThreshold {
+				required = append(required, name)
+			}
+		}
+		if len(props) > 0 {
+			b.AddProperties(props)
+		}
+		if len(required) > 0 {
+			b.AddRequired(required)
+		}
+	}
+
+	if n.items != nil && n.items.count > 0 {
+		ib := b.NewSubBuilder()
+		in.build(ib, n.items)
+		b.AddItems(ib.Build())
+	}
+
+	if n.types["string"] > 0 {
+		if enum, ok := n.enum(); ok {
+			b.AddEnum(enum)
+		} else if len(n.formats) > 0 {
+			b.AddFormat(in.opts.Formats[n.formats[0]])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package inferjson_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/inferjson"
+)
+
+// decode decodes the JSON values in srcs.
+func decode(t *testing.T, srcs ...string) []any {
+	t.Helper()
+	var ret []any
+	for _, src := range srcs {
+		var v any
+		if err := json.Unmarshal([]byte(src), &v); err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// marshal returns the JSON encoding of v, without the $schema keyword.
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+	s = strings.Replace(s, `"$schema":"https://json-schema.org/draft/2020-12/schema",`, "", 1)
+	return strings.Replace(s, `"$schema":"https://json-schema.org/draft/2020-12/schema"`, "", 1)
+}
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []string
+		opts      *inferjson.Options
+		want      string
+	}{
+		{
+			"none",
+			nil,
+			nil,
+			`{}`,
+		},
+		{
+			"objects",
+			[]string{
+				`{"id": 1, "kind": "a", "at": "2025-01-02T03:04:05Z", "tags": ["x"], "score": 1}`,
+				`{"id": 2, "kind": "b", "at": "2025-02-03T04:05:06Z", "tags": [], "score": 1.5, "note": null}`,
+				`{"id": 3, "kind": "a", "at": "2025-03-04T05:06:07Z", "tags": ["y", "z"], "score": 2}`,
+			},
+			nil,
+			`{"properties":{"at":{"format":"date-time","type":"string"},"id":{"type":"integer"},"kind":{"enum":["a","b"],"type":"string"},"note":{"type":"null"},"score":{"type":"number"},"tags":{"items":{"type":"string"},"type":"array"}},"required":["at","id","kind","score","tags"],"type":"object"}`,
+		},
+		{
+			"required threshold",
+			[]string{`{"a": 1, "b": 1}`, `{"a": 1, "b": 1}`, `{"a": 1}`, `{"c": 1}`},
+			&inferjson.Options{RequiredThreshold: 0.5},
+			`{"properties":{"a":{"type":"integer"},"b":{"type":"integer"},"c":{"type":"integer"}},"required":["a","b"],"type":"object"}`,
+		},
+		{
+			"mixed types",
+			[]string{`1`, `"x"`, `null`, `true`, `[1]`},
+			nil,
+			`{"items":{"type":"integer"},"type":["array","string","integer","boolean","null"]}`,
+		},
+		{
+			"all different",
+			[]string{`"a"`, `"b"`, `"c"`},
+			nil,
+			`{"type":"string"}`,
+		},
+		{
+			"too many values",
+			[]string{`"a"`, `"b"`, `"a"`, `"c"`},
+			&inferjson.Options{MaxEnum: 2},
+			`{"type":"string"}`,
+		},
+		{
+			"no enums",
+			[]string{`"a"`, `"a"`},
+			&inferjson.Options{MaxEnum: -1},
+			`{"type":"string"}`,
+		},
+		{
+			"formats",
+			[]string{`"2025-01-02"`, `"2025-01-03"`},
+			&inferjson.Options{MaxEnum: -1},
+			`{"format":"date","type":"string"}`,
+		},
+		{
+			"first format",
+			[]string{`"192.0.2.1"`, `"198.51.100.7"`},
+			&inferjson.Options{MaxEnum: -1, Formats: []string{"uuid", "ipv4"}},
+			`{"format":"ipv4","type":"string"}`,
+		},
+		{
+			"no formats",
+			[]string{`"2025-01-02"`, `"2025-01-03"`},
+			&inferjson.Options{MaxEnum: -1, Formats: []string{}},
+			`{"type":"string"}`,
+		},
+	}
+	for _, test := range tests {
+		s := inferjson.Infer(decode(t, test.instances...), test.opts)
+		if got := marshal(t, s); got != test.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", test.name, got, test.want)
+		}
+		if test.opts != nil && test.opts.RequiredThreshold != 0 {
+			// Some instances lack required properties.
+			continue
+		}
+		for _, inst := range decode(t, test.instances...) {
+			if err := s.Validate(inst); err != nil {
+				t.Errorf("%s: inferred schema rejects %v: %v", test.name, inst, err)
+			}
+		}
+	}
+}
+
+func TestAddJSON(t *testing.T) {
+	in := inferjson.New(nil)
+	if err := in.AddJSON([]byte(`{`)); err == nil {
+		t.Error("invalid JSON: no error")
+	}
+	in.Add(json.Number("1.5"))
+	in.Add(json.Number("2"))
+	if got, want := marshal(t, in.Schema()), `{"type":"number"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
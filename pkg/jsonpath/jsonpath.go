@@ -0,0 +1,379 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jsonpath converts between the JSON pointers used in
+// validation errors and JSONPath expressions, which many logging
+// and observability tools expect, and evaluates JSONPath
+// expressions over instances.
+//
+// The supported JSONPath syntax is the part of RFC 9535 without
+// filter expressions: the root $, member names as .name or ['name'],
+// array indices as [0] or [-1], wildcards as .* or [*], slices as
+// [start:end:step], unions as [0,'a'], and descendants as ..name.
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+)
+
+// FromPointer returns the JSONPath expression for a JSON pointer,
+// such as the InstanceLocation of a validation error.
+// The pointer may start with "#", as in a URI fragment.
+// A JSON pointer doesn't say whether a token that is a number
+// is an array index or a member name; FromPointer treats it as
+// an array index. Use [FromPointerIn] to look at the instance.
+func FromPointer(ptr string) (string, error) {
+	return fromPointer(ptr, nil, false)
+}
+
+// FromPointerIn is like [FromPointer], but uses instance
+// to decide whether a number token is an array index.
+// The pointer need not refer to a value in instance; tokens
+// below a missing value are converted as by FromPointer.
+func FromPointerIn(instance any, ptr string) (string, error) {
+	return fromPointer(ptr, instance, true)
+}
+
+// fromPointer implements FromPointer and FromPointerIn.
+func fromPointer(ptr string, v any, haveV bool) (string, error) {
+	toks, err := pointerTokens(ptr)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, tok := range toks {
+		if i, ok := arrayIndex(tok); ok && (!haveV || isArray(v)) {
+			fmt.Fprintf(&sb, "[%d]", i)
+			if a, ok := v.([]any); ok && i < len(a) {
+				v = a[i]
+			} else {
+				haveV = false
+			}
+			continue
+		}
+		writeName(&sb, tok)
+		if m, ok := v.(map[string]any); ok {
+			v, haveV = m[tok]
+		} else {
+			haveV = false
+		}
+	}
+	return sb.String(), nil
+}
+
+// ToPointer returns the JSON pointer for a JSONPath expression
+// that refers to at most one value, such as $.items[0].price.
+// Negative indices, wildcards, slices, unions and descendants
+// are errors, because they can't be written as a pointer.
+func ToPointer(path string) (string, error) {
+	segs, err := parse(path)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, seg := range segs {
+		if seg.descendant || len(seg.sels) != 1 {
+			return "", fmt.Errorf("JSONPath %q does not refer to a single value", path)
+		}
+		sel := seg.sels[0]
+		switch sel.kind {
+		case selName:
+			sb.WriteString("/" + jsonpointer.EscapeToken(sel.name))
+		case selIndex:
+			if sel.index < 0 {
+				return "", fmt.Errorf("JSONPath %q has a negative index", path)
+			}
+			fmt.Fprintf(&sb, "/%d", sel.index)
+		default:
+			return "", fmt.Errorf("JSONPath %q does not refer to a single value", path)
+		}
+	}
+	return sb.String(), nil
+}
+
+// Node is a value selected by a JSONPath expression.
+type Node struct {
+	// Pointer is the JSON pointer to the value in the instance.
+	Pointer string
+	// Value is the value.
+	Value any
+}
+
+// Select returns the values in instance selected by the
+// JSONPath expression path, in document order. Object members
+// are visited in sorted order, so that the result is stable.
+// Selecting nothing is not an error.
+func Select(instance any, path string) ([]Node, error) {
+	segs, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{{Pointer: "", Value: instance}}
+	for _, seg := range segs {
+		var next []Node
+		for _, n := range nodes {
+			if seg.descendant {
+				descendants(n, func(d Node) {
+					next = seg.apply(d, next)
+				})
+			} else {
+				next = seg.apply(n, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// ErrorPaths returns the JSONPath expressions for the instance
+// locations of the validation errors in err, in order.
+// The instance, if not nil, is used as by [FromPointerIn].
+func ErrorPaths(err error, instance any) []string {
+	var errs []*errors2.ValidationError
+	var ves *errors2.ValidationErrors
+	var ve *errors2.ValidationError
+	switch {
+	case errors.As(err, &ves):
+		errs = ves.Errs
+	case errors.As(err, &ve):
+		errs = []*errors2.ValidationError{ve}
+	}
+
+	ret := make([]string, 0, len(errs))
+	for _, ve := range errs {
+		p, err := fromPointer(ve.InstanceLocation, instance, instance != nil)
+		if err != nil {
+			// Not a pointer; leave it alone.
+			p = ve.InstanceLocation
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// selectorKind is the kind of a selector.
+type selectorKind int
+
+const (
+	selName     selectorKind = iota // ['name'] or .name
+	selIndex                        // [0]
+	selWildcard                     // [*] or .*
+	selSlice                        // [start:end:step]
+)
+
+// selector is a single selector within a segment.
+type selector struct {
+	kind  selectorKind
+	name  string
+	index int
+	// For a slice; hasStart and hasEnd are false if omitted.
+	start, end, step int
+	hasStart, hasEnd bool
+}
+
+// segment is a segment of a JSONPath expression,
+// such as .name, [0,1] or ..name.
+type segment struct {
+	sels       []selector
+	descendant bool
+}
+
+// apply appends the children of n selected by seg to nodes.
+func (seg *segment) apply(n Node, nodes []Node) []Node {
+	for _, sel := range seg.sels {
+		switch v := n.Value.(type) {
+		case map[string]any:
+			switch sel.kind {
+			case selName:
+				if e, ok := v[sel.name]; ok {
+					nodes = append(nodes, Node{n.Pointer + "/" + jsonpointer.EscapeToken(sel.name), e})
+				}
+			case selWildcard:
+				for _, k := range sortedKeys(v) {
+					nodes = append(nodes, Node{n.Pointer + "/" + jsonpointer.EscapeToken(k), v[k]})
+				}
+			}
+
+		case []any:
+			switch sel.kind {
+			case selIndex:
+				i := sel.index
+				if i < 0 {
+					i += len(v)
+				}
+				if i >= 0 && i < len(v) {
+					nodes = append(nodes, Node{n.Pointer + "/" + strconv.Itoa(i), v[i]})
+				}
+			case selWildcard:
+				for i, e := range v {
+					nodes = append(nodes, Node{n.Pointer + "/" + strconv.Itoa(i), e})
+				}
+			case selSlice:
+				for _, i := range sel.indices(len(v)) {
+					nodes = append(nodes, Node{n.Pointer + "/" + strconv.Itoa(i), v[i]})
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// indices returns the indices selected by a slice
+// of an array of length n, as defined by RFC 9535.
+func (sel *selector) indices(n int) []int {
+	if sel.step == 0 {
+		return nil
+	}
+	norm := func(i int) int {
+		if i < 0 {
+			return i + n
+		}
+		return i
+	}
+	var ret []int
+	if sel.step > 0 {
+		lo, hi := 0, n
+		if sel.hasStart {
+			lo = min(max(norm(sel.start), 0), n)
+		}
+		if sel.hasEnd {
+			hi = min(max(norm(sel.end), 0), n)
+		}
+		for i := lo; i < hi; i += sel.step {
+			ret = append(ret, i)
+		}
+	} else {
+		hi, lo := n-1, -1
+		if sel.hasStart {
+			hi = min(max(norm(sel.start), -1), n-1)
+		}
+		if sel.hasEnd {
+			lo = min(max(norm(sel.end), -1), n-1)
+		}
+		for i := hi; i > lo; i += sel.step {
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+// descendants calls f for n and each of its descendants,
+// in document order.
+func descendants(n Node, f func(Node)) {
+	f(n)
+	switch v := n.Value.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			descendants(Node{n.Pointer + "/" + jsonpointer.EscapeToken(k), v[k]}, f)
+		}
+	case []any:
+		for i, e := range v {
+			descendants(Node{n.Pointer + "/" + strconv.Itoa(i), e}, f)
+		}
+	}
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// pointerTokens returns the decoded tokens of a JSON pointer.
+func pointerTokens(ptr string) ([]string, error) {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+	toks := strings.Split(ptr[1:], "/")
+	for i, tok := range toks {
+		toks[i] = jsonpointer.UnescapeToken(tok)
+	}
+	return toks, nil
+}
+
+// arrayIndex reports whether the pointer token tok is an array index.
+func arrayIndex(tok string) (int, bool) {
+	if tok == "" || (len(tok) > 1 && tok[0] == '0') {
+		return 0, false
+	}
+	for _, c := range tok {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	i, err := strconv.Atoi(tok)
+	return i, err == nil
+}
+
+// isArray reports whether v is a JSON array.
+func isArray(v any) bool {
+	_, ok := v.([]any)
+	return ok
+}
+
+// writeName writes a member name selector for name:
+// .name if name is an identifier, otherwise ['name'].
+func writeName(sb *strings.Builder, name string) {
+	if isIdent(name) {
+		sb.WriteString("." + name)
+		return
+	}
+	sb.WriteString("['")
+	for _, c := range name {
+		switch c {
+		case '\'', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(c)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(sb, `\u%04x`, c)
+			} else {
+				sb.WriteRune(c)
+			}
+		}
+	}
+	sb.WriteString("']")
+}
+
+// isIdent reports whether name can be written in dot notation.
+func isIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= 0x80:
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
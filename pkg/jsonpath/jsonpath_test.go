@@ -0,0 +1,171 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/jsonpath"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode decodes a JSON value.
+func decode(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestFromPointer(t *testing.T) {
+	tests := []struct {
+		ptr  string
+		want string
+	}{
+		{"", "$"},
+		{"#", "$"},
+		{"/a/b", "$.a.b"},
+		{"#/items/0/price", "$.items[0].price"},
+		{"/a~1b/c~0d", "$['a/b']['c~d']"},
+		{"/it's", `$['it\'s']`},
+		{"/", "$['']"},
+		{"/01", "$['01']"},
+		{"/9a", "$['9a']"},
+	}
+	for _, test := range tests {
+		got, err := jsonpath.FromPointer(test.ptr)
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+		} else if got != test.want {
+			t.Errorf("%q: got %s, want %s", test.ptr, got, test.want)
+		}
+	}
+	if _, err := jsonpath.FromPointer("a/b"); err == nil {
+		t.Error("a/b: no error")
+	}
+}
+
+func TestFromPointerIn(t *testing.T) {
+	instance := decode(t, `{"0": {"1": [true, {"2": null}]}}`)
+	tests := []struct {
+		ptr  string
+		want string
+	}{
+		{"/0/1/1/2", "$['0']['1'][1]['2']"},
+		// Below a missing value, numbers are indices.
+		{"/0/x/3", "$['0'].x[3]"},
+	}
+	for _, test := range tests {
+		got, err := jsonpath.FromPointerIn(instance, test.ptr)
+		if err != nil {
+			t.Errorf("%q: %v", test.ptr, err)
+		} else if got != test.want {
+			t.Errorf("%q: got %s, want %s", test.ptr, got, test.want)
+		}
+	}
+}
+
+func TestToPointer(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$", ""},
+		{"$.items[0].price", "/items/0/price"},
+		{"$['a/b']['c~d']", "/a~1b/c~0d"},
+		{`$["x y"]`, "/x y"},
+	}
+	for _, test := range tests {
+		got, err := jsonpath.ToPointer(test.path)
+		if err != nil {
+			t.Errorf("%q: %v", test.path, err)
+		} else if got != test.want {
+			t.Errorf("%q: got %s, want %s", test.path, got, test.want)
+		}
+	}
+
+	for _, bad := range []string{"", "a", "$.a[-1]", "$.*", "$[0,1]", "$[1:2]", "$..a", "$[", "$['a"} {
+		if _, err := jsonpath.ToPointer(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	instance := decode(t, `{
+		"store": {
+			"book": [
+				{"title": "A", "price": 8},
+				{"title": "B", "price": 12},
+				{"title": "C", "price": 9}
+			],
+			"bicycle": {"price": 20}
+		}
+	}`)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"$", []string{""}},
+		{"$.store.book[0].title", []string{"/store/book/0/title"}},
+		{"$.store.book[-1].title", []string{"/store/book/2/title"}},
+		{"$.store.book[5]", nil},
+		{"$.store.*", []string{"/store/bicycle", "/store/book"}},
+		{"$.store.book[*].price", []string{"/store/book/0/price", "/store/book/1/price", "/store/book/2/price"}},
+		{"$.store.book[1:]", []string{"/store/book/1", "/store/book/2"}},
+		{"$.store.book[::-1]", []string{"/store/book/2", "/store/book/1", "/store/book/0"}},
+		{"$.store.book[0:3:2]", []string{"/store/book/0", "/store/book/2"}},
+		{"$.store.book[::0]", nil},
+		{"$.store.book[2,0]", []string{"/store/book/2", "/store/book/0"}},
+		{"$..price", []string{"/store/bicycle/price", "/store/book/0/price", "/store/book/1/price", "/store/book/2/price"}},
+		{"$.missing.x", nil},
+	}
+	for _, test := range tests {
+		nodes, err := jsonpath.Select(instance, test.path)
+		if err != nil {
+			t.Errorf("%q: %v", test.path, err)
+			continue
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, n.Pointer)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestErrorPaths(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {
+			"items": {"items": {"properties": {"price": {"type": "number"}}}},
+			"0": {"type": "string"}
+		}
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	instance := decode(t, `{"items": [{"price": "x"}], "0": 1}`)
+	err := s.Validate(instance)
+	if err == nil {
+		t.Fatal("no error")
+	}
+	got := jsonpath.ErrorPaths(err, instance)
+	slices.Sort(got)
+	want := []string{"$.items[0].price", "$['0']"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := jsonpath.ErrorPaths(nil, nil); len(got) != 0 {
+		t.Errorf("nil error: got %q", got)
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parse parses a JSONPath expression into segments.
+func parse(path string) ([]segment, error) {
+	p := &parser{path: path}
+	if !strings.HasPrefix(path, "$") {
+		return nil, p.errorf("must start with $")
+	}
+	p.pos = 1
+
+	var segs []segment
+	for p.pos < len(p.path) {
+		seg, err := p.segment()
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// parser holds the state used by parse.
+type parser struct {
+	path string
+	pos  int
+}
+
+// segment parses a single segment.
+func (p *parser) segment() (segment, error) {
+	var seg segment
+	switch {
+	case strings.HasPrefix(p.path[p.pos:], ".."):
+		p.pos += 2
+		seg.descendant = true
+		if p.peek() == '[' {
+			sels, err := p.bracket()
+			seg.sels = sels
+			return seg, err
+		}
+	case p.peek() == '.':
+		p.pos++
+	case p.peek() == '[':
+		sels, err := p.bracket()
+		seg.sels = sels
+		return seg, err
+	default:
+		return seg, p.errorf("unexpected %q", p.path[p.pos:p.pos+1])
+	}
+
+	// A shorthand: .name, .*, ..name or ..*.
+	if p.peek() == '*' {
+		p.pos++
+		seg.sels = []selector{{kind: selWildcard}}
+		return seg, nil
+	}
+	start := p.pos
+	for p.pos < len(p.path) {
+		c, size := utf8.DecodeRuneInString(p.path[p.pos:])
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 ||
+			c >= '0' && c <= '9' && p.pos > start) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return seg, p.errorf("expected member name")
+	}
+	seg.sels = []selector{{kind: selName, name: p.path[start:p.pos]}}
+	return seg, nil
+}
+
+// bracket parses a bracketed list of selectors.
+func (p *parser) bracket() ([]selector, error) {
+	p.pos++ // [
+	var sels []selector
+	for {
+		p.space()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+		p.space()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return sels, nil
+		default:
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+// selector parses a single selector within brackets.
+func (p *parser) selector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.quoted()
+		return selector{kind: selName, name: name}, err
+	case c == '*':
+		p.pos++
+		return selector{kind: selWildcard}, nil
+	case c == '?':
+		return selector{}, p.errorf("filter expressions are not supported")
+	}
+
+	// An index or a slice.
+	var nums [3]int
+	var have [3]bool
+	n := 0
+	for {
+		p.space()
+		if i, ok, err := p.integer(); err != nil {
+			return selector{}, err
+		} else if ok {
+			nums[n], have[n] = i, true
+		}
+		p.space()
+		if p.peek() != ':' || n == 2 {
+			break
+		}
+		p.pos++
+		n++
+	}
+	if n == 0 {
+		if !have[0] {
+			return selector{}, p.errorf("expected selector")
+		}
+		return selector{kind: selIndex, index: nums[0]}, nil
+	}
+	sel := selector{
+		kind:     selSlice,
+		start:    nums[0],
+		end:      nums[1],
+		step:     1,
+		hasStart: have[0],
+		hasEnd:   have[1],
+	}
+	if have[2] {
+		sel.step = nums[2]
+	}
+	return sel, nil
+}
+
+// integer parses an optional integer.
+func (p *parser) integer() (int, bool, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.path) && p.path[p.pos] >= '0' && p.path[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	text := p.path[start:p.pos]
+	i, err := strconv.Atoi(text)
+	if err != nil {
+		p.pos = start
+		return 0, false, p.errorf("invalid integer %q", text)
+	}
+	return i, true, nil
+}
+
+// quoted parses a quoted member name.
+func (p *parser) quoted() (string, error) {
+	quote := p.path[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.path) {
+		c := p.path[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.path) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.path[p.pos]
+			p.pos++
+			switch e {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.path) {
+					return "", p.errorf("invalid \\u escape")
+				}
+				r, err := strconv.ParseUint(p.path[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid \\u escape")
+				}
+				p.pos += 4
+				sb.WriteRune(rune(r))
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// space skips white space.
+func (p *parser) space() {
+	for p.pos < len(p.path) && strings.IndexByte(" \t\n\r", p.path[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// peek returns the next byte, or 0 at the end.
+func (p *parser) peek() byte {
+	if p.pos >= len(p.path) {
+		return 0
+	}
+	return p.path[p.pos]
+}
+
+// errorf returns an error at the current position.
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid JSONPath %q: offset %d: %s", p.path, p.pos, fmt.Sprintf(format, args...))
+}
@@ -11,7 +11,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/altshiftab/jsonschema/internal/argtype"
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
 	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
 	"github.com/altshiftab/jsonschema/pkg/types/schema"
 )
@@ -24,7 +24,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 	pointer = strings.TrimPrefix(pointer, "/")
 	toks := strings.Split(pointer, "/")
 	for i := 0; i < len(toks); i++ {
-		tok := decodeToken(toks[i])
+		tok := UnescapeToken(toks[i])
 		for _, part := range s.Parts {
 			if part.Keyword.Generated {
 				continue
@@ -42,7 +42,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 				if i >= len(toks) {
 					return nil, fmt.Errorf("when dereferencing pointer %q expected array index after %q", pointer, tok)
 				}
-				tok = decodeToken(toks[i])
+				tok = UnescapeToken(toks[i])
 				idx, err := strconv.Atoi(tok)
 				if err != nil {
 					return nil, fmt.Errorf("when dereferencing pointer %q got token %q, expected array index", pointer, tok)
@@ -58,7 +58,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 				if i >= len(toks) {
 					return nil, fmt.Errorf("when dereferencing pointer %q expected map key after %q", pointer, tok)
 				}
-				tok = decodeToken(toks[i])
+				tok = UnescapeToken(toks[i])
 				m := part.Value.(schema.PartMapSchema)
 				ms, ok := m[tok]
 				if !ok {
@@ -75,7 +75,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 					if i >= len(toks) {
 						return nil, fmt.Errorf("when dereferencing pointer %q expected array index after %q", pointer, tok)
 					}
-					tok = decodeToken(toks[i])
+					tok = UnescapeToken(toks[i])
 					idx, err := strconv.Atoi(tok)
 					if err != nil {
 						return nil, fmt.Errorf("when dereferencing pointer %q got token %q, expected array index", pointer, tok)
@@ -91,7 +91,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 				if i >= len(toks) {
 					return nil, fmt.Errorf("when dereferencing pointer %q expected map key after %q", pointer, tok)
 				}
-				tok = decodeToken(toks[i])
+				tok = UnescapeToken(toks[i])
 				m := part.Value.(schema.PartMapArrayOrSchema)
 				mv, ok := m[tok]
 				if !ok {
@@ -102,6 +102,28 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 				}
 				s = mv.Schema
 
+			case arg_type.ArgTypeMapMapSchema:
+				i++
+				if i >= len(toks) {
+					return nil, fmt.Errorf("when dereferencing pointer %q expected map key after %q", pointer, tok)
+				}
+				tok = UnescapeToken(toks[i])
+				m := part.Value.(schema.PartMapMapSchema)
+				mm, ok := m[tok]
+				if !ok {
+					return nil, fmt.Errorf("when dereferencing pointer %q map key %q not present", pointer, tok)
+				}
+				i++
+				if i >= len(toks) {
+					return nil, fmt.Errorf("when dereferencing pointer %q expected map key after %q", pointer, tok)
+				}
+				tok = UnescapeToken(toks[i])
+				ms, ok := mm[tok]
+				if !ok {
+					return nil, fmt.Errorf("when dereferencing pointer %q map key %q not present", pointer, tok)
+				}
+				s = ms
+
 			case arg_type.ArgTypeAny:
 				pv := part.Value.(schema.PartAny).V
 			resolveLoop:
@@ -120,7 +142,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 						if i >= len(toks) {
 							return nil, fmt.Errorf("when dereferencing pointer %q expected array index after %q", pointer, tok)
 						}
-						tok = decodeToken(toks[i])
+						tok = UnescapeToken(toks[i])
 						idx, err := strconv.Atoi(tok)
 						if err != nil {
 							return nil, fmt.Errorf("when dereferencing pointer %q for token %q, expected array index", pointer, tok)
@@ -136,7 +158,7 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 				}
 
 			default:
-				return nil, fmt.Errorf("when dereferencing pointer %q unexpected part type %s", pointer, argtype.Name(part.Keyword.ArgType))
+				return nil, fmt.Errorf("when dereferencing pointer %q unexpected part type %s", pointer, part.Keyword.ArgType)
 			}
 
 			break
@@ -146,8 +168,14 @@ func DerefSchema(schemaID string, root *schema.Schema, pointer string) (*schema.
 	return s, nil
 }
 
-// decodeToken unmangles a token in a JSON pointer.
-func decodeToken(tok string) string {
-	tok = strings.ReplaceAll(tok, "~1", "/")
-	return strings.ReplaceAll(tok, "~0", "~")
+// EscapeToken returns tok, such as a property name, escaped as a
+// token of a JSON pointer: "~" is written as "~0" and "/" as "~1".
+func EscapeToken(tok string) string {
+	return pointertoken.Escape(tok)
+}
+
+// UnescapeToken unmangles a token in a JSON pointer.
+// It is the inverse of [EscapeToken].
+func UnescapeToken(tok string) string {
+	return pointertoken.Unescape(tok)
 }
@@ -0,0 +1,32 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonpointer_test
+
+import (
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+)
+
+func TestEscapeToken(t *testing.T) {
+	tests := []struct {
+		tok, escaped string
+	}{
+		{"", ""},
+		{"name", "name"},
+		{"a/b", "a~1b"},
+		{"a~b", "a~0b"},
+		{"~1", "~01"},
+		{"/~", "~1~0"},
+	}
+	for _, test := range tests {
+		if got := jsonpointer.EscapeToken(test.tok); got != test.escaped {
+			t.Errorf("EscapeToken(%q) = %q, want %q", test.tok, got, test.escaped)
+		}
+		if got := jsonpointer.UnescapeToken(test.escaped); got != test.tok {
+			t.Errorf("UnescapeToken(%q) = %q, want %q", test.escaped, got, test.tok)
+		}
+	}
+}
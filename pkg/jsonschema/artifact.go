@@ -0,0 +1,250 @@
+package jsonschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	motmedelErrors "github.com/Motmedel/utils_go/pkg/errors"
+)
+
+// ArtifactKeyword is the keyword of the metadata block
+// that [GenerateArtifact] adds to a generated schema.
+// It is not a known keyword, so it does not affect validation.
+const ArtifactKeyword = "x-artifact"
+
+// modulePath is the path of this module,
+// whose version is recorded as the generator version.
+const modulePath = "github.com/altshiftab/jsonschema"
+
+// ErrStaleArtifact is returned by [VerifyArtifact] when a schema
+// artifact does not match the schema generated from its Go type.
+var ErrStaleArtifact = errors.New("schema artifact is stale")
+
+// ArtifactMetadata describes how a schema artifact was generated.
+type ArtifactMetadata struct {
+	// Generator is the module that generated the schema.
+	Generator string `json:"generator"`
+	// Version is the version of the generator module,
+	// or "(devel)" if it is not known.
+	Version string `json:"version"`
+	// Source is the Go type that the schema was generated from,
+	// as its package path and name.
+	Source string `json:"source"`
+	// Timestamp is when the schema was generated.
+	Timestamp time.Time `json:"timestamp"`
+	// Hash is the hex encoded SHA-256 hash of the schema without
+	// the metadata block, with its properties in sorted order.
+	Hash string `json:"hash"`
+}
+
+// GenerateArtifact returns the JSON encoding of the schema for the
+// Go type T, as [FromType] builds it, with an [ArtifactKeyword]
+// metadata block. The result is meant to be committed to version
+// control, and checked in tests by [VerifyArtifact].
+func GenerateArtifact[T any]() ([]byte, error) {
+	v, hash, err := generate[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := ArtifactMetadata{
+		Generator: modulePath,
+		Version:   generatorVersion(),
+		Source:    typeName(reflect.TypeFor[T]()),
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+		Hash:      hash,
+	}
+	v[ArtifactKeyword] = meta
+
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json marshal indent: %w", err))
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteArtifact writes the result of [GenerateArtifact] to the file path.
+func WriteArtifact[T any](path string) error {
+	data, err := GenerateArtifact[T]()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o666); err != nil {
+		return motmedelErrors.NewWithTrace(fmt.Errorf("os write file: %w", err))
+	}
+	return nil
+}
+
+// ReadArtifact returns the schema artifact data, such as a file
+// embedded with a go:embed directive, and its metadata.
+// It is an error if the schema does not match the hash
+// in the metadata, which means that it was edited by hand.
+func ReadArtifact(data []byte) (*Schema, *ArtifactMetadata, error) {
+	v, err := decodeObject(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, err := artifactMetadata(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(v, ArtifactKeyword)
+	hash, err := hashSchema(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hash != meta.Hash {
+		return nil, nil, motmedelErrors.New(fmt.Errorf("schema artifact for %s does not match its hash", meta.Source))
+	}
+
+	schema, err := New(data)
+	if err != nil {
+		return nil, nil, motmedelErrors.New(fmt.Errorf("new: %w", err))
+	}
+	return schema, meta, nil
+}
+
+// VerifyArtifact checks that the schema artifact data, as written by
+// [GenerateArtifact], is up to date with the Go type T. If the schema
+// generated from T has changed, it returns an error that wraps
+// [ErrStaleArtifact]. A test can call VerifyArtifact to fail
+// when a committed schema needs to be generated again.
+func VerifyArtifact[T any](data []byte) error {
+	_, meta, err := ReadArtifact(data)
+	if err != nil {
+		return err
+	}
+	if source := typeName(reflect.TypeFor[T]()); meta.Source != source {
+		return motmedelErrors.New(fmt.Errorf("%w: generated from %s, not %s", ErrStaleArtifact, meta.Source, source))
+	}
+
+	_, hash, err := generate[T]()
+	if err != nil {
+		return err
+	}
+	if hash != meta.Hash {
+		return motmedelErrors.New(fmt.Errorf("%w: schema for %s has changed since %s", ErrStaleArtifact, meta.Source, meta.Timestamp.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// VerifyArtifactFile is like [VerifyArtifact],
+// for the schema artifact in the file path.
+func VerifyArtifactFile[T any](path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return motmedelErrors.NewWithTrace(fmt.Errorf("os read file: %w", err))
+	}
+	if err := VerifyArtifact[T](data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// generate returns the schema for the Go type T,
+// decoded into a map, and its hash.
+func generate[T any]() (map[string]any, string, error) {
+	schema, err := FromType[T]()
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, "", motmedelErrors.NewWithTrace(fmt.Errorf("json marshal: %w", err))
+	}
+	v, err := decodeObject(data)
+	if err != nil {
+		return nil, "", err
+	}
+	hash, err := hashSchema(v)
+	if err != nil {
+		return nil, "", err
+	}
+	return v, hash, nil
+}
+
+// decodeObject decodes the JSON object data,
+// keeping the spelling of its numbers.
+func decodeObject(data []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v map[string]any
+	if err := dec.Decode(&v); err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json decode: %w", err))
+	}
+	if v == nil {
+		return nil, motmedelErrors.New(errors.New("schema artifact is not an object"))
+	}
+	return v, nil
+}
+
+// artifactMetadata returns the metadata block of the schema v.
+func artifactMetadata(v map[string]any) (*ArtifactMetadata, error) {
+	raw, ok := v[ArtifactKeyword]
+	if !ok {
+		return nil, motmedelErrors.New(fmt.Errorf("schema artifact has no %s metadata", ArtifactKeyword))
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json marshal: %w", err))
+	}
+	var meta ArtifactMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json unmarshal %s: %w", ArtifactKeyword, err))
+	}
+	return &meta, nil
+}
+
+// hashSchema returns the hash of the schema v,
+// as described by [ArtifactMetadata.Hash].
+func hashSchema(v map[string]any) (string, error) {
+	// json.Marshal sorts the keys of maps.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", motmedelErrors.NewWithTrace(fmt.Errorf("json marshal: %w", err))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// generatorVersion returns the version of this module
+// in the running program.
+func generatorVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	version := ""
+	if bi.Main.Path == modulePath {
+		version = bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == modulePath {
+			version = dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+		}
+	}
+	if version == "" {
+		return "(devel)"
+	}
+	return version
+}
+
+// typeName returns the package path and name of t,
+// or its string form for an unnamed type.
+func typeName(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
@@ -8,6 +8,7 @@ import (
 	motmedelReflect "github.com/Motmedel/utils_go/pkg/reflect"
 	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
 	_ "github.com/altshiftab/jsonschema/pkg/format"
+	"github.com/altshiftab/jsonschema/pkg/template"
 	schemaPkg "github.com/altshiftab/jsonschema/pkg/types/schema"
 	jsonschemaTypeGeneration "github.com/vphpersson/type_generation/pkg/producers/jsonschema"
 )
@@ -23,6 +24,52 @@ func New(data []byte) (*Schema, error) {
 	return &s, nil
 }
 
+// Opts describes options for NewWithOpts.
+type Opts struct {
+	// Params are the values of the template placeholders.
+	Params map[string]any
+	// Features are the enabled features. Subschemas that
+	// require other features are pruned.
+	Features []string
+}
+
+// NewWithParams is like New, but first expands the placeholders
+// in data using params, as described in the template package.
+func NewWithParams(data []byte, params map[string]any) (*Schema, error) {
+	return NewWithOpts(data, &Opts{Params: params})
+}
+
+// NewWithOpts is like New, but first prunes the subschemas for
+// disabled features and then expands the placeholders in data,
+// as described in the template package.
+func NewWithOpts(data []byte, opts *Opts) (*Schema, error) {
+	if opts == nil {
+		opts = &Opts{}
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json unmarshal: %w", err))
+	}
+
+	pruned, err := template.Prune(v, opts.Features)
+	if err != nil {
+		return nil, motmedelErrors.New(fmt.Errorf("template prune: %w", err))
+	}
+
+	expanded, err := template.Expand(pruned, opts.Params)
+	if err != nil {
+		return nil, motmedelErrors.New(fmt.Errorf("template expand: %w", err))
+	}
+
+	expandedData, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, motmedelErrors.NewWithTrace(fmt.Errorf("json marshal: %w", err))
+	}
+
+	return New(expandedData)
+}
+
 func FromType[T any]() (*Schema, error) {
 	schemaData, err := jsonschemaTypeGeneration.Convert(motmedelReflect.TypeOf[T]())
 	if err != nil {
@@ -0,0 +1,312 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package keywordspec defines the JSON format of the files that
+// describe the keywords of a JSON schema version, and of the file
+// that describes a whole version package, or dialect. The draft
+// packages of this module are generated from such files by the
+// keywordgen command in cmd/keywordgen.
+//
+// A [Group] file lists keywords: their names, argument types,
+// validator functions, documentation and sort order.
+// A [Dialect] file names the groups of a package, along with its
+// $schema URI, its meta-schema, and how it resolves references.
+// Other programs may use this package to read and check these
+// files, such as to generate packages for their own dialects.
+package keywordspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+// Group is the JSON format that describes a group of keywords.
+type Group struct {
+	// The name of this group of keywords; fairly arbitrary.
+	Name string `json:"name"`
+	// The description of this group of keywords, not currently used.
+	Description string `json:"description"`
+	// The number of characters to skip in the keyword name
+	// to form an identifier; 1 for a group that contains
+	// keywords that start with '$'.
+	Prefix int `json:"prefix,omitempty"`
+	// The actual keywords in this group.
+	Keywords []Keyword `json:"keywords"`
+}
+
+// Keyword is the JSON format that describes a single keyword.
+type Keyword struct {
+	// Keyword name: $id, allOf, ....
+	Name string `json:"name"`
+	// A short description of the keyword, for help text.
+	Description string `json:"description,omitempty"`
+	// A link to the section of the specification
+	// that defines the keyword.
+	Link string `json:"link,omitempty"`
+	// Argument type of keyword: string, int, schema, ....
+	ArgType string `json:"argType"`
+	// Type is ArgType parsed by [ReadGroup].
+	Type arg_type.ArgType `json:"-"`
+	// Whether the keyword is always valid,
+	// in which case it does not need a validation function.
+	AlwaysValid bool `json:"alwaysValid,omitempty"`
+	// Whether the validator function only looks at the argument
+	// and the instance. See the Leaf field of [schema.Keyword].
+	// Keywords that are always valid are always leaf keywords.
+	Leaf bool `json:"leaf,omitempty"`
+	// Whether the validator function reads the notes of
+	// in-place subschemas. See the UsesNotes field of [schema.Keyword].
+	UsesNotes bool `json:"usesNotes,omitempty"`
+	// Whether the value of the keyword is an annotation
+	// that is collected during validation.
+	// See the Annotation field of [schema.Keyword].
+	Annotation bool `json:"annotation,omitempty"`
+	// The name of the validator function. A function of this
+	// package takes the Part type of ArgType, such as
+	// [schema.PartString]. The default is validator.ValidateNAME,
+	// from the internal validator package of this module.
+	Validator string `json:"validator,omitempty"`
+	// Whether the validator function takes a [schema.PartValue],
+	// as the Validate field of [schema.Keyword] does, rather than
+	// the Part type of ArgType. Such a function is used as is,
+	// without a conversion from the validator package.
+	RawValidator bool `json:"rawValidator,omitempty"`
+	// Whether to skip adding a Builder method for this keyword.
+	SkipBuilder bool `json:"skipBuilder,omitempty"`
+	// An optional comment for the Builder method.
+	BuilderComment string `json:"builderComment,omitempty"`
+	// If present, a list of keywords that this keyword should follow.
+	// This is used to sort the keywords in an instance of a schema.
+	After []string `json:"after,omitempty"`
+}
+
+// Dialect is the JSON format that describes a JSON schema
+// version package. The generated package has a SchemaID constant,
+// a Vocabulary variable that is registered when the package is
+// imported, a Builder type, and a MetaSchema function.
+type Dialect struct {
+	// The Go package name, such as "draft06".
+	Package string `json:"package"`
+	// The name of the vocabulary, such as "draft-06".
+	// It is also used in doc comments.
+	Name string `json:"name"`
+	// The URI that is the value of the $schema keyword,
+	// without a trailing "#".
+	SchemaID string `json:"schemaID"`
+	// Base, if not empty, is the import path of a dialect package,
+	// such as "github.com/altshiftab/jsonschema/pkg/draft202012",
+	// whose keywords this dialect has along with those of its groups.
+	// The keywords of the groups are sorted after those of the base,
+	// and references are resolved as the base resolves them.
+	// The base package must have a Vocabulary variable, as every
+	// package generated by keywordgen does.
+	//
+	// A dialect with a base doesn't use the internal packages of
+	// this module, so it may be in another module. The keywords of
+	// its groups must be always valid, or name a validator function.
+	Base string `json:"base,omitempty"`
+	// The keyword that sets the base URI: "$id", or "id" for draft-04.
+	// A keyword other than "$id" requires IDAnchors.
+	// These are not used for a dialect with a base.
+	IDKeyword string `json:"idKeyword"`
+	// Whether a plain-name fragment in the IDKeyword defines
+	// an anchor, as before draft 2019-09.
+	IDAnchors bool `json:"idAnchors,omitempty"`
+	// Whether keywords next to a $ref keyword are ignored,
+	// as in draft-07 and earlier.
+	IgnoreRefSiblings bool `json:"ignoreRefSiblings,omitempty"`
+	// Whether the vocabulary is registered as the default.
+	Default bool `json:"default,omitempty"`
+	// The file, relative to the dialect file, that holds the
+	// meta-schema. It is embedded in the package, and served
+	// for references to SchemaID.
+	MetaSchema string `json:"metaSchema"`
+	// The files, relative to the dialect file, that hold the
+	// keyword groups, in order.
+	Groups []string `json:"groups"`
+	// The names of the generated files for the keywords
+	// and for the rest of the package.
+	KeywordsOutput string `json:"keywordsOutput"`
+	Output         string `json:"output"`
+}
+
+// ReadGroup reads and checks a JSON file describing a group of keywords.
+func ReadGroup(name string) (*Group, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err := ParseGroup(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return g, nil
+}
+
+// ParseGroup parses and checks the JSON description of a group of
+// keywords. It sets the Type field of each keyword from its ArgType.
+func ParseGroup(data []byte) (*Group, error) {
+	var g Group
+	if err := json.Unmarshal(data, &g); err != nil {
+		if se, ok := err.(*json.SyntaxError); ok {
+			return nil, fmt.Errorf("%d: %v", se.Offset, err)
+		}
+		return nil, err
+	}
+	for i := range g.Keywords {
+		k := &g.Keywords[i]
+		t, ok := arg_type.Parse(k.ArgType)
+		if !ok {
+			return nil, fmt.Errorf("%s: unrecognized keyword type %q", k.Name, k.ArgType)
+		}
+		k.Type = t
+		if len(k.Name) <= g.Prefix || strings.ContainsAny(k.Name[:g.Prefix], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			return nil, fmt.Errorf("%s: prefix %d would remove part of the keyword name", k.Name, g.Prefix)
+		}
+		if k.SkipBuilder && k.BuilderComment != "" {
+			return nil, fmt.Errorf("%s: has both skipBuilder and builderComment", k.Name)
+		}
+		if k.RawValidator && (k.Validator == "" || k.AlwaysValid) {
+			return nil, fmt.Errorf("%s: rawValidator needs a validator", k.Name)
+		}
+	}
+	return &g, nil
+}
+
+// ReadDialect reads and checks a JSON file describing a dialect,
+// and the keyword groups that it names. The returned groups are
+// in the order of the Groups field.
+func ReadDialect(name string) (*Dialect, []*Group, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	var d Dialect
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", name, err)
+	}
+	switch {
+	case d.Package == "", d.Name == "", d.SchemaID == "":
+		return nil, nil, fmt.Errorf("%s: package, name and schemaID are required", name)
+	case d.IDKeyword == "" && d.Base == "":
+		return nil, nil, fmt.Errorf("%s: idKeyword is required", name)
+	case d.IDKeyword != "$id" && !d.IDAnchors && d.Base == "":
+		return nil, nil, fmt.Errorf("%s: idKeyword %q needs idAnchors", name, d.IDKeyword)
+	case d.MetaSchema == "":
+		return nil, nil, fmt.Errorf("%s: metaSchema is required", name)
+	case len(d.Groups) == 0:
+		return nil, nil, fmt.Errorf("%s: no keyword groups", name)
+	case d.KeywordsOutput == "", d.Output == "":
+		return nil, nil, fmt.Errorf("%s: keywordsOutput and output are required", name)
+	}
+	var groups []*Group
+	for _, gname := range d.Groups {
+		g, err := ReadGroup(filepath.Join(filepath.Dir(name), gname))
+		if err != nil {
+			return nil, nil, err
+		}
+		groups = append(groups, g)
+	}
+	if d.Base == "" {
+		if err := Check(groups); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", name, err)
+		}
+	} else {
+		if err := checkDuplicates(groups); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", name, err)
+		}
+		for _, g := range groups {
+			for _, k := range g.Keywords {
+				if !k.AlwaysValid && k.Validator == "" {
+					return nil, nil, fmt.Errorf("%s: keyword %q needs a validator, as the dialect has a base", name, k.Name)
+				}
+				if strings.HasPrefix(k.Validator, "validator.") {
+					return nil, nil, fmt.Errorf("%s: keyword %q can't use %s, as the dialect has a base", name, k.Name, k.Validator)
+				}
+			}
+		}
+	}
+	return &d, groups, nil
+}
+
+// Check checks that the keywords of groups, which are those of one
+// package, have distinct names, and follow only keywords among them.
+func Check(groups []*Group) error {
+	if err := checkDuplicates(groups); err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	for _, g := range groups {
+		for _, k := range g.Keywords {
+			names[k.Name] = true
+		}
+	}
+	for _, g := range groups {
+		for _, k := range g.Keywords {
+			for _, a := range k.After {
+				// $schema is handled by package schema,
+				// and precedes every keyword.
+				if !names[a] && a != "$schema" {
+					return fmt.Errorf("keyword %q follows unknown keyword %q", k.Name, a)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicates checks that the keywords of groups have distinct names.
+func checkDuplicates(groups []*Group) error {
+	names := make(map[string]bool)
+	for _, g := range groups {
+		for _, k := range g.Keywords {
+			if names[k.Name] {
+				return fmt.Errorf("keyword %q is defined more than once", k.Name)
+			}
+			names[k.Name] = true
+		}
+	}
+	return nil
+}
+
+// Rank returns the names of keywords in the order in which
+// they are sorted: by name, except that each keyword comes
+// after the keywords in its After list.
+func Rank(keywords []Keyword) []string {
+	// Sort by name in descending order.
+	sorted := slices.Clone(keywords)
+	slices.SortFunc(sorted, func(a, b Keyword) int {
+		return -strings.Compare(a.Name, b.Name)
+	})
+
+	// Build a list of keywords in reverse order,
+	// putting each keyword that must follow other keywords in place.
+	ranked := make([]Keyword, 0, len(sorted))
+	for _, k := range sorted {
+		ins := len(ranked)
+		for _, a := range k.After {
+			for i, r := range ranked {
+				if r.Name == a {
+					ins = min(ins, i)
+				}
+			}
+		}
+		ranked = slices.Insert(ranked, ins, k)
+	}
+
+	// Reverse the list to get the expected order.
+	slices.Reverse(ranked)
+
+	names := make([]string, len(ranked))
+	for i, r := range ranked {
+		names[i] = r.Name
+	}
+	return names
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package keywordspec_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/keywordspec"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+func TestParseGroup(t *testing.T) {
+	g, err := keywordspec.ParseGroup([]byte(`{
+		"name": "core",
+		"prefix": 1,
+		"keywords": [
+			{"name": "$id", "argType": "string", "alwaysValid": true},
+			{"name": "$ref", "argType": "string", "validator": "validateRef", "after": ["$id"]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Keywords) != 2 || g.Keywords[1].Type != arg_type.ArgTypeString {
+		t.Errorf("got %+v", g)
+	}
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`{"keywords": [`, "unexpected end"},
+		{`{"keywords": [{"name": "a", "argType": "nonsense"}]}`, "unrecognized keyword type"},
+		{`{"prefix": 1, "keywords": [{"name": "ab", "argType": "string"}]}`, "prefix 1"},
+		{`{"prefix": 1, "keywords": [{"name": "$", "argType": "string"}]}`, "prefix 1"},
+		{`{"keywords": [{"name": "a", "argType": "string", "skipBuilder": true, "builderComment": "x"}]}`, "skipBuilder and builderComment"},
+		{`{"keywords": [{"name": "a", "argType": "string", "rawValidator": true}]}`, "rawValidator needs a validator"},
+	}
+	for _, test := range tests {
+		_, err := keywordspec.ParseGroup([]byte(test.src))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.src, err, test.err)
+		}
+	}
+}
+
+func TestReadDialect(t *testing.T) {
+	for _, pkg := range []string{"draft04", "draft06"} {
+		d, groups, err := keywordspec.ReadDialect(filepath.Join("..", pkg, "dialect.json"))
+		if err != nil {
+			t.Errorf("%s: %v", pkg, err)
+			continue
+		}
+		if d.Package != pkg || len(groups) != len(d.Groups) {
+			t.Errorf("%s: got package %s with %d groups", pkg, d.Package, len(groups))
+		}
+	}
+}
+
+func TestReadDialectErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("core.json", `{"keywords": [{"name": "a", "argType": "string", "after": ["b"]}]}`)
+	write("ok.json", `{"keywords": [{"name": "a", "argType": "string", "alwaysValid": true}]}`)
+	write("internal.json", `{"keywords": [{"name": "a", "argType": "string", "validator": "validator.ValidateTrue"}]}`)
+	write("novalidator.json", `{"keywords": [{"name": "a", "argType": "string"}]}`)
+
+	// dialect returns a dialect file with the given fields
+	// in place of the defaults; the last of duplicate fields wins.
+	dialect := func(fields string) string {
+		d := `"package": "p", "name": "n", "schemaID": "https://example.com/s", "idKeyword": "$id", "metaSchema": "m.json", "groups": ["ok.json"], "keywordsOutput": "k.go", "output": "d.go"`
+		if fields != "" {
+			d += ", " + fields
+		}
+		return "{" + d + "}"
+	}
+	tests := []struct {
+		dialect string
+		err     string
+	}{
+		{`{"package": "p"}`, "package, name and schemaID are required"},
+		{dialect(`"idKeyword": ""`), "idKeyword is required"},
+		{dialect(`"idKeyword": "id"`), "needs idAnchors"},
+		{dialect(`"metaSchema": ""`), "metaSchema is required"},
+		{dialect(`"groups": []`), "no keyword groups"},
+		{dialect(`"output": ""`), "keywordsOutput and output are required"},
+		{dialect(`"groups": ["missing.json"]`), "missing.json"},
+		{dialect(`"groups": ["core.json"]`), "unknown keyword"},
+		{dialect(`"groups": ["ok.json", "ok.json"]`), "more than once"},
+		{dialect(`"base": "example.com/base", "groups": ["novalidator.json"]`), "needs a validator"},
+		{dialect(`"base": "example.com/base", "groups": ["internal.json"]`), "can't use validator.ValidateTrue"},
+	}
+	for _, test := range tests {
+		write("dialect.json", test.dialect)
+		_, _, err := keywordspec.ReadDialect(filepath.Join(dir, "dialect.json"))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.dialect, err, test.err)
+		}
+	}
+
+	write("dialect.json", dialect(`"base": "example.com/base", "idKeyword": ""`))
+	if _, _, err := keywordspec.ReadDialect(filepath.Join(dir, "dialect.json")); err != nil {
+		t.Errorf("dialect with a base: %v", err)
+	}
+}
+
+func TestRank(t *testing.T) {
+	keywords := []keywordspec.Keyword{
+		{Name: "a"},
+		{Name: "then", After: []string{"if"}},
+		{Name: "if"},
+		{Name: "else", After: []string{"if", "then"}},
+		{Name: "z"},
+	}
+	got := keywordspec.Rank(keywords)
+	want := []string{"a", "if", "then", "else", "z"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// LoadDir is like [LoadFS], for the files in the directory dir.
+// The URI of each file is its file: URL.
+func LoadDir(dir string) (map[string]*schema.Schema, error) {
+	g, err := LoadDirGraph(dir)
+	if err != nil {
+		return nil, err
+	}
+	return g.Schemas, nil
+}
+
+// LoadDirGraph is like [LoadDir], but also returns
+// the references between the files.
+func LoadDirGraph(dir string) (*Graph, error) {
+	// Check dir here, as the errors of os.DirFS
+	// don't name it.
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		// A Windows path, such as C:/schemas.
+		p = "/" + p
+	}
+	return loadFS(os.DirFS(dir), &url.URL{Scheme: "file", Path: strings.TrimSuffix(p, "/") + "/"})
+}
+
+// LoadFS decodes and resolves the schemas in the files of fsys whose
+// names end in ".json". The result maps the name of each file,
+// such as "geo/point.json", to its schema.
+//
+// A $ref in one file may refer to another file by its name, relative
+// to the referring file, or by the $id of the other file or of one of
+// its subschemas. The URI of a file without a $id is "file:///"
+// followed by its name. No other schemas are loaded, except for the
+// meta-schemas of registered vocabularies.
+//
+// The files are resolved in dependency order, so that an error in
+// a file is reported for that file. LoadFS reports all the problems
+// it finds, each with the name of its file: a file that is not a
+// schema, a $id used in two files, a reference to a schema that is
+// not in fsys, and a cycle of references between files, which must
+// be broken by moving the schemas of the cycle into one file.
+func LoadFS(fsys fs.FS) (map[string]*schema.Schema, error) {
+	g, err := LoadFSGraph(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return g.Schemas, nil
+}
+
+// LoadFSGraph is like [LoadFS], but also returns
+// the references between the files.
+func LoadFSGraph(fsys fs.FS) (*Graph, error) {
+	return loadFS(fsys, &url.URL{Scheme: "file", Path: "/"})
+}
+
+// A Graph holds the schemas loaded by [LoadDirGraph] or [LoadFSGraph],
+// and the references between their files. A build system can use it
+// to find what to rebuild when a schema changes: the artifacts
+// generated from the [Graph.Dependents] of the file.
+// All the names are file names, as in the keys of Schemas.
+type Graph struct {
+	// Schemas maps the name of each file to its resolved schema.
+	Schemas map[string]*schema.Schema
+	// URIs maps the name of each file to its URI:
+	// the $id of its schema, or the URI of the file.
+	URIs map[string]string
+
+	// deps maps the name of each file
+	// to the files that it refers to.
+	deps map[string][]string
+	// order is the names of the files in dependency order.
+	order []string
+}
+
+// Order returns the names of the files in the order in which they
+// were resolved: each file comes after the files that it refers to.
+func (g *Graph) Order() []string {
+	return slices.Clone(g.order)
+}
+
+// Deps returns the names of the files that
+// the file name refers to directly, in sorted order.
+func (g *Graph) Deps(name string) []string {
+	return slices.Clone(g.deps[name])
+}
+
+// AllDeps returns the names of the files that the file name refers
+// to, directly or through other files, in dependency order.
+func (g *Graph) AllDeps(name string) []string {
+	seen := make(map[string]bool)
+	var walk func(string)
+	walk = func(n string) {
+		for _, dep := range g.deps[n] {
+			if !seen[dep] {
+				seen[dep] = true
+				walk(dep)
+			}
+		}
+	}
+	walk(name)
+	return g.inOrder(seen)
+}
+
+// Dependents returns the names of the files that refer to the file
+// name, directly or through other files, in dependency order.
+// These are the files that are affected by a change to name.
+func (g *Graph) Dependents(name string) []string {
+	seen := map[string]bool{name: true}
+	for _, n := range g.order {
+		if !seen[n] && slices.ContainsFunc(g.deps[n], func(dep string) bool { return seen[dep] }) {
+			seen[n] = true
+		}
+	}
+	delete(seen, name)
+	return g.inOrder(seen)
+}
+
+// inOrder returns the names in set in dependency order.
+func (g *Graph) inOrder(set map[string]bool) []string {
+	var ret []string
+	for _, n := range g.order {
+		if set[n] {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// dirFile is a schema file read by loadFS.
+type dirFile struct {
+	uri  *url.URL
+	data []byte
+	// s is the unresolved schema.
+	s *schema.Schema
+	// deps are the names of the files that the schema refers to.
+	deps []string
+}
+
+// loadFS implements LoadDirGraph and LoadFSGraph.
+// The URI of a file is its name resolved against base.
+func loadFS(fsys fs.FS, base *url.URL) (*Graph, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(name) == ".json" {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	files := make(map[string]*dirFile)
+	// ids maps the URI of each file, and each $id,
+	// without a fragment, to the name of its file.
+	ids := make(map[string]string)
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		uri := base.ResolveReference(&url.URL{Path: name})
+		s, err := schema.SchemaFromJSONWithSource("", uri, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		files[name] = &dirFile{uri: uri, data: data, s: s}
+
+		ids[withoutFragment(uri)] = name
+		walkIDs(s, uri, func(_ *schema.Schema, id *url.URL) {
+			us := withoutFragment(id)
+			if other, ok := ids[us]; ok && other != name {
+				errs = append(errs, fmt.Errorf("%s: $id %q is also in %s", name, us, other))
+				return
+			}
+			ids[us] = name
+		})
+	}
+
+	// Find the references between files.
+	for _, name := range names {
+		f, ok := files[name]
+		if !ok {
+			continue
+		}
+		walkRefs(f.s, f.uri, func(ref *url.URL) {
+			us := withoutFragment(ref)
+			if dep, ok := ids[us]; ok {
+				if dep != name && !slices.Contains(f.deps, dep) {
+					f.deps = append(f.deps, dep)
+				}
+				return
+			}
+			if schema.LookupVocabulary(strings.TrimSuffix(us, "#")) != nil {
+				// A reference to a meta-schema.
+				return
+			}
+			errs = append(errs, fmt.Errorf("%s: unresolved reference to %q", name, us))
+		})
+		slices.Sort(f.deps)
+	}
+
+	// Sort the files so that each comes after the files it refers to.
+	var order []string
+	state := make(map[string]int) // 1 while visiting, 2 when done
+	var stack []string
+	var visit func(name string)
+	visit = func(name string) {
+		switch state[name] {
+		case 1:
+			i := slices.Index(stack, name)
+			cycle := append(slices.Clone(stack[i:]), name)
+			errs = append(errs, fmt.Errorf("%s: reference cycle %s", name, strings.Join(cycle, " -> ")))
+			return
+		case 2:
+			return
+		}
+		state[name] = 1
+		stack = append(stack, name)
+		for _, dep := range files[name].deps {
+			visit(dep)
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = 2
+		order = append(order, name)
+	}
+	for _, name := range names {
+		if _, ok := files[name]; ok {
+			visit(name)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// Resolve the files. A file that is referred to is decoded
+	// again for each file that refers to it, as a Loader must
+	// return a schema that has not been resolved.
+	load := schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		us := withoutFragment(uri)
+		name, ok := ids[us]
+		if !ok {
+			return nil, fmt.Errorf("no schema for %q", us)
+		}
+		f := files[name]
+		s, err := schema.SchemaFromJSONContext(ctx, schemaID, f.uri, f.data)
+		if err != nil {
+			return nil, err
+		}
+		if us == withoutFragment(f.uri) {
+			return s, nil
+		}
+		// The URI is the $id of the file or of one of its subschemas.
+		var found *schema.Schema
+		walkIDs(s, f.uri, func(sub *schema.Schema, id *url.URL) {
+			if found == nil && withoutFragment(id) == us {
+				found = sub
+			}
+		})
+		if found == nil {
+			return nil, fmt.Errorf("no schema for %q in %s", us, name)
+		}
+		return found, nil
+	})
+
+	g := &Graph{
+		Schemas: make(map[string]*schema.Schema, len(files)),
+		URIs:    make(map[string]string, len(files)),
+		deps:    make(map[string][]string, len(files)),
+		order:   order,
+	}
+	for _, name := range order {
+		f := files[name]
+		if err := f.s.Resolve(&schema.ResolveOpts{URI: f.uri, Loader: load}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		g.Schemas[name] = f.s
+		g.URIs[name] = withoutFragment(f.uri)
+		if id, ok := schemaID(f.s); ok {
+			if u, err := url.Parse(id); err == nil && u.Path+u.Host != "" {
+				g.URIs[name] = withoutFragment(f.uri.ResolveReference(u))
+			}
+		}
+		g.deps[name] = f.deps
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return g, nil
+}
+
+// walkIDs calls fn for s and each of its subschemas that has a $id,
+// or an id as in draft-04, with the absolute URI of the subschema.
+// The URI of s, if it has no $id, is base.
+func walkIDs(s *schema.Schema, base *url.URL, fn func(*schema.Schema, *url.URL)) {
+	if id, ok := schemaID(s); ok {
+		if u, err := url.Parse(id); err == nil && u.Path+u.Host != "" {
+			base = base.ResolveReference(u)
+			fn(s, base)
+		}
+	}
+	for _, sub := range s.Children() {
+		walkIDs(sub, base, fn)
+	}
+}
+
+// walkRefs calls fn for the absolute URI of each $ref
+// in s and its subschemas. The base URI of s is base.
+func walkRefs(s *schema.Schema, base *url.URL, fn func(*url.URL)) {
+	if id, ok := schemaID(s); ok {
+		if u, err := url.Parse(id); err == nil {
+			base = base.ResolveReference(u)
+		}
+	}
+	if pv, ok := s.LookupKeyword("$ref"); ok {
+		if ref, ok := pv.(schema.PartString); ok {
+			if u, err := url.Parse(string(ref)); err == nil {
+				fn(base.ResolveReference(u))
+			}
+		}
+	}
+	for _, sub := range s.Children() {
+		walkRefs(sub, base, fn)
+	}
+}
+
+// schemaID returns the $id of s, or its id as in draft-04.
+func schemaID(s *schema.Schema) (string, bool) {
+	for _, kw := range []string{"$id", "id"} {
+		if pv, ok := s.LookupKeyword(kw); ok {
+			if id, ok := pv.(schema.PartString); ok {
+				return string(id), true
+			}
+		}
+	}
+	return "", false
+}
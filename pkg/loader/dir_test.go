@@ -0,0 +1,134 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader_test
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/loader"
+)
+
+func TestLoadDir(t *testing.T) {
+	schemas, err := loader.LoadDir(filepath.Join("testdata", "dir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// README.md and broken.json.txt are skipped.
+	names := slices.Sorted(maps.Keys(schemas))
+	want := []string{"geo/address.json", "geo/zip.json", "person.json", "pet.json", "tagged.json"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("got files %q, want %q", names, want)
+	}
+
+	tests := []struct {
+		name string
+		// valid and invalid are instances the schema
+		// accepts and rejects.
+		valid, invalid any
+	}{
+		// A reference by file name, relative to the referring file.
+		{
+			"person.json",
+			map[string]any{"address": map[string]any{"zip": "12345"}},
+			map[string]any{"address": map[string]any{"zip": "1234"}},
+		},
+		{"geo/address.json", map[string]any{"zip": "12345"}, map[string]any{}},
+		// A reference by the $id of a file.
+		{
+			"person.json",
+			map[string]any{"address": map[string]any{"zip": "12345"}, "pet": map[string]any{"name": "Rex"}},
+			map[string]any{"address": map[string]any{"zip": "12345"}, "pet": map[string]any{"name": 1.0}},
+		},
+		// A reference by the $id of a subschema, relative to the $id of its file.
+		{"pet.json", map[string]any{"tag": "abc"}, map[string]any{"tag": "abcd"}},
+		{"tagged.json", []any{"abc"}, []any{"abc", "abcd"}},
+	}
+	for _, test := range tests {
+		s := schemas[test.name]
+		if err := s.Validate(test.valid); err != nil {
+			t.Errorf("%s: %v: %v", test.name, test.valid, err)
+		}
+		if err := s.Validate(test.invalid); err == nil {
+			t.Errorf("%s: %v: no error", test.name, test.invalid)
+		}
+	}
+}
+
+func TestLoadDirGraph(t *testing.T) {
+	dir := filepath.Join("testdata", "dir")
+	g, err := loader.LoadDirGraph(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := g.URIs["pet.json"], "https://example.com/pet"; got != want {
+		t.Errorf("pet.json: got URI %q, want %q", got, want)
+	}
+	abs, err := filepath.Abs(filepath.Join(dir, "person.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.URIs["person.json"]; !strings.HasPrefix(got, "file:///") || !strings.HasSuffix(got, filepath.ToSlash(abs)) {
+		t.Errorf("person.json: got URI %q, want file: URL of %s", got, abs)
+	}
+
+	depTests := []struct {
+		name       string
+		deps       []string
+		dependents []string
+	}{
+		{"person.json", []string{"geo/address.json", "pet.json"}, nil},
+		{"geo/address.json", []string{"geo/zip.json"}, []string{"person.json"}},
+		{"geo/zip.json", nil, []string{"geo/address.json", "person.json"}},
+		{"pet.json", nil, []string{"person.json", "tagged.json"}},
+		{"tagged.json", []string{"pet.json"}, nil},
+	}
+	for _, test := range depTests {
+		if got := g.Deps(test.name); !slices.Equal(got, test.deps) {
+			t.Errorf("Deps(%q) = %q, want %q", test.name, got, test.deps)
+		}
+		if got := g.Dependents(test.name); !slices.Equal(got, test.dependents) {
+			t.Errorf("Dependents(%q) = %q, want %q", test.name, got, test.dependents)
+		}
+	}
+
+	// Each file comes after the files it refers to.
+	order := g.Order()
+	for _, name := range order {
+		for _, dep := range g.Deps(name) {
+			if slices.Index(order, dep) > slices.Index(order, name) {
+				t.Errorf("Order() = %q: %s is after %s, which refers to it", order, dep, name)
+			}
+		}
+	}
+}
+
+func TestLoadDirErrors(t *testing.T) {
+	tests := []struct {
+		dir string
+		// errs are the parts of the error message.
+		errs []string
+	}{
+		{"dupid", []string{`b.json: $id "https://example.com/same" is also in a.json`}},
+		{"missing", []string{"missing"}},
+	}
+	for _, test := range tests {
+		_, err := loader.LoadDir(filepath.Join("testdata", test.dir))
+		if err == nil {
+			t.Errorf("%s: no error", test.dir)
+			continue
+		}
+		for _, want := range test.errs {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: got error %q, want %q", test.dir, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/schemadiff"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Drift describes a recorded schema that differs from
+// the live copy, as reported by [CheckDrift].
+type Drift struct {
+	// URI is the URI of the schema.
+	URI string
+	// RecordedHash and LiveHash are hashes of the canonical
+	// JSON encodings of the recorded and live schemas.
+	// LiveHash is empty if Err is not nil.
+	RecordedHash, LiveHash string
+	// Changes are the changes from the recorded schema
+	// to the live one.
+	Changes []schemadiff.Change
+	// Err is the error, if the live schema could not be loaded.
+	Err error
+}
+
+// CheckDrift compares the schemas recorded in dir by [Record]
+// with the live copies returned by fetch, which is normally
+// the loader that was passed to Record.
+// It returns the schemas that differ, or that could not be
+// fetched, sorted by URI. The ctx is passed to fetch, and
+// the schemaID is passed to fetch as the default schema ID.
+//
+// Running this periodically tells users of third-party schemas
+// about upstream changes before the changes break anything.
+// To accept the changes, run again with Record.
+func CheckDrift(ctx context.Context, dir, schemaID string, fetch schema.Loader) ([]Drift, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.uri"))
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	for _, name := range names {
+		udata, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		uri, err := url.Parse(strings.TrimSpace(string(udata)))
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(strings.TrimSuffix(name, ".uri") + ".json")
+		if err != nil {
+			return nil, err
+		}
+		recorded, recordedHash, err := canonical(data)
+		if err != nil {
+			return nil, err
+		}
+
+		d := Drift{
+			URI:          uri.String(),
+			RecordedHash: recordedHash,
+		}
+		live, err := fetch.Load(ctx, schemaID, uri)
+		if err == nil {
+			data, err = json.Marshal(live)
+		}
+		var liveVal any
+		if err == nil {
+			liveVal, d.LiveHash, err = canonical(data)
+		}
+		if err != nil {
+			d.Err = err
+			d.LiveHash = ""
+			drifts = append(drifts, d)
+			continue
+		}
+		if d.LiveHash != d.RecordedHash {
+			d.Changes = schemadiff.JSON(recorded, liveVal)
+			drifts = append(drifts, d)
+		}
+	}
+
+	slices.SortFunc(drifts, func(a, b Drift) int {
+		return strings.Compare(a.URI, b.URI)
+	})
+	return drifts, nil
+}
+
+// canonical decodes the JSON encoding data, and returns the
+// value and a hash of its canonical encoding, in which object
+// keys are sorted and there is no white space.
+func canonical(data []byte) (any, string, error) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, "", err
+	}
+	cdata, err := json.Marshal(v)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(cdata)
+	return v, hex.EncodeToString(sum[:]), nil
+}
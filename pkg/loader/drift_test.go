@@ -0,0 +1,64 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/loader"
+)
+
+func TestCheckDrift(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	rec := loader.Record(dir, loader.Map(map[string][]byte{
+		"https://example.com/same.json":    []byte(`{"type": "string", "maxLength": 3}`),
+		"https://example.com/changed.json": []byte(`{"type": "string", "maxLength": 3}`),
+		"https://example.com/gone.json":    []byte(`{"type": "string"}`),
+	}))
+	for _, u := range []string{"https://example.com/same.json", "https://example.com/changed.json", "https://example.com/gone.json"} {
+		if _, err := rec.Load(ctx, draft202012, mustParse(t, u)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	live := loader.Map(map[string][]byte{
+		// The order of keywords doesn't matter.
+		"https://example.com/same.json":    []byte(`{"maxLength": 3, "type": "string"}`),
+		"https://example.com/changed.json": []byte(`{"type": "string", "maxLength": 2}`),
+	})
+	drifts, err := loader.CheckDrift(ctx, dir, draft202012, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 2 {
+		t.Fatalf("got %d drifts, want 2: %+v", len(drifts), drifts)
+	}
+
+	changed := drifts[0]
+	if changed.URI != "https://example.com/changed.json" {
+		t.Errorf("first drift is for %s", changed.URI)
+	}
+	if changed.Err != nil || changed.LiveHash == "" || changed.LiveHash == changed.RecordedHash {
+		t.Errorf("changed: got %+v", changed)
+	}
+	if len(changed.Changes) != 1 || changed.Changes[0].Pointer != "/maxLength" || !changed.Changes[0].Breaking {
+		t.Errorf("changed: got changes %v", changed.Changes)
+	}
+
+	gone := drifts[1]
+	if gone.URI != "https://example.com/gone.json" {
+		t.Errorf("second drift is for %s", gone.URI)
+	}
+	if gone.Err == nil || gone.LiveHash != "" || gone.RecordedHash == "" {
+		t.Errorf("gone: got %+v", gone)
+	}
+
+	drifts, err = loader.CheckDrift(ctx, t.TempDir(), draft202012, live)
+	if err != nil || len(drifts) != 0 {
+		t.Errorf("empty directory: got %v, %v", drifts, err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// FS returns a loader that loads schemas from files in fsys,
+// such as an [embed.FS], so that an application can carry the
+// schemas it refers to and resolve references offline.
+//
+// The keys of dirs are base URIs, and the values are the directories
+// of fsys that hold the schemas under those URIs. For example, with
+//
+//	loader.FS(schemas, map[string]string{
+//		"https://example.com/schemas/": "testdata/example",
+//	})
+//
+// the URI https://example.com/schemas/geo/point.json is loaded from
+// the file testdata/example/geo/point.json. A directory of "."
+// is the root of fsys. If several base URIs match, the longest is
+// used. If there is no file for the URI, and its last element has
+// no extension, FS also tries the name with ".json" appended,
+// since schema $id values often omit it.
+// It is an error to load a URI that matches no base URI,
+// so a loader from FS never fetches a remote schema.
+func FS(fsys fs.FS, dirs map[string]string) schema.Loader {
+	return schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		us := withoutFragment(uri)
+		base, ok := longestPrefix(dirs, us)
+		if !ok {
+			return nil, fmt.Errorf("no directory for %q", us)
+		}
+
+		rest, err := url.PathUnescape(strings.TrimPrefix(us, base))
+		if err != nil {
+			return nil, fmt.Errorf("loading %q: %v", us, err)
+		}
+		rest = strings.TrimPrefix(rest, "/")
+		if !fs.ValidPath(rest) {
+			// This rejects a URI that leads out of the directory.
+			return nil, fmt.Errorf("loading %q: invalid file name %q", us, rest)
+		}
+		name := path.Join(dirs[base], rest)
+
+		data, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+			data, err = fs.ReadFile(fsys, name+".json")
+		}
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("no schema for %q in %s", us, dirs[base])
+			}
+			return nil, err
+		}
+		return schema.SchemaFromJSONContext(ctx, schemaID, uri, data)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/altshiftab/jsonschema/pkg/loader"
+)
+
+func TestFS(t *testing.T) {
+	ctx := context.Background()
+	fsys := fstest.MapFS{
+		"schemas/geo/point.json":    {Data: []byte(`{"type": "object", "required": ["x", "y"]}`)},
+		"schemas/geo/line.json":     {Data: []byte(`{"type": "array"}`)},
+		"schemas/geo/v2/point.json": {Data: []byte(`{"type": "object", "required": ["x", "y", "z"]}`)},
+		"schemas/name with space":   {Data: []byte(`{"type": "string"}`)},
+		"secret.json":               {Data: []byte(`{}`)},
+	}
+	l := loader.FS(fsys, map[string]string{
+		"https://example.com/schemas/":    "schemas",
+		"https://example.com/schemas/v2/": "schemas/geo/v2",
+	})
+
+	tests := []struct {
+		uri string
+		// valid and invalid are instances the schema
+		// accepts and rejects.
+		valid, invalid any
+	}{
+		{"https://example.com/schemas/geo/point.json", map[string]any{"x": 1.0, "y": 1.0}, map[string]any{"x": 1.0}},
+		{"https://example.com/schemas/geo/point#/required", map[string]any{"x": 1.0, "y": 1.0}, map[string]any{"x": 1.0}},
+		{"https://example.com/schemas/geo/line", []any{}, "line"},
+		// The longest base URI is used.
+		{"https://example.com/schemas/v2/point.json", map[string]any{"x": 1.0, "y": 1.0, "z": 1.0}, map[string]any{"x": 1.0, "y": 1.0}},
+		{"https://example.com/schemas/name%20with%20space", "a", 1.0},
+	}
+	for _, test := range tests {
+		s, err := l.Load(ctx, draft202012, mustParse(t, test.uri))
+		if err != nil {
+			t.Errorf("%s: %v", test.uri, err)
+			continue
+		}
+		if err := s.Validate(test.valid); err != nil {
+			t.Errorf("%s: %v: %v", test.uri, test.valid, err)
+		}
+		if err := s.Validate(test.invalid); err == nil {
+			t.Errorf("%s: %v: no error", test.uri, test.invalid)
+		}
+	}
+
+	errTests := []struct {
+		uri string
+		err string
+	}{
+		{"https://example.com/other/x.json", "no directory"},
+		{"https://example.com/schemas/geo/circle.json", "no schema"},
+		{"https://example.com/schemas/../secret.json", "invalid file name"},
+		{"https://example.com/schemas/%2E%2E/secret.json", "invalid file name"},
+	}
+	for _, test := range errTests {
+		_, err := l.Load(ctx, draft202012, mustParse(t, test.uri))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.uri, err, test.err)
+		}
+	}
+}
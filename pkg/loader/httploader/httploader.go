@@ -0,0 +1,254 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httploader provides a [schema.Loader] that fetches
+// referenced schemas over HTTP, with the safeguards that a loader
+// for user-written schemas needs: only allowed hosts are contacted,
+// redirects are limited and checked, responses are limited in size,
+// and each request has a timeout. Temporary failures are retried,
+// and responses are cached and revalidated with ETag and
+// Last-Modified headers.
+//
+// For example:
+//
+//	d := &schema.Decoder{
+//		Loader: httploader.New(&httploader.Options{
+//			AllowedHosts: []string{"json.schemastore.org", "*.example.com"},
+//		}),
+//	}
+package httploader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Default values of the fields of [Options].
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxRedirects = 5
+	DefaultRetries      = 2
+	DefaultRetryDelay   = 500 * time.Millisecond
+	DefaultMaxSize      = 8 << 20
+)
+
+// Options are options for [New].
+// The zero value of a field selects its default.
+type Options struct {
+	// AllowedHosts are the hosts that schemas may be loaded from.
+	// An entry "*.example.com" allows any subdomain of example.com,
+	// but not example.com itself. If empty, no host is allowed,
+	// so every load fails; there is no way to allow every host.
+	AllowedHosts []string
+	// AllowHTTP permits loading from http URLs.
+	// By default only https URLs are loaded.
+	AllowHTTP bool
+	// Client is the HTTP client to use. It is copied, and its
+	// CheckRedirect field is replaced. The default is a client
+	// like [http.DefaultClient].
+	Client *http.Client
+	// Timeout is the time limit for each attempt to fetch a schema.
+	Timeout time.Duration
+	// MaxRedirects is the number of redirects to follow.
+	// A negative value means no redirects are followed.
+	MaxRedirects int
+	// Retries is the number of times to retry a fetch that fails
+	// with a network error or with a status of 429 or 5xx.
+	// A negative value means no retries.
+	Retries int
+	// RetryDelay is the delay before the first retry.
+	// The delay doubles for each retry.
+	RetryDelay time.Duration
+	// MaxSize is the largest schema, in bytes, that is loaded.
+	MaxSize int64
+}
+
+// Loader is a [schema.Loader] that loads schemas over HTTP.
+// A Loader may be used concurrently.
+type Loader struct {
+	opts   Options
+	client http.Client
+
+	mu    sync.Mutex
+	cache map[string]*cached
+}
+
+// cached is a cached response.
+type cached struct {
+	data         []byte
+	etag         string
+	lastModified string
+}
+
+// New returns a new [Loader]. A nil opts is the same as
+// a pointer to a zero Options, which allows no hosts.
+func New(opts *Options) *Loader {
+	l := &Loader{
+		cache: make(map[string]*cached),
+	}
+	if opts != nil {
+		l.opts = *opts
+	}
+	if l.opts.Timeout == 0 {
+		l.opts.Timeout = DefaultTimeout
+	}
+	if l.opts.MaxRedirects == 0 {
+		l.opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if l.opts.Retries == 0 {
+		l.opts.Retries = DefaultRetries
+	}
+	if l.opts.RetryDelay == 0 {
+		l.opts.RetryDelay = DefaultRetryDelay
+	}
+	if l.opts.MaxSize == 0 {
+		l.opts.MaxSize = DefaultMaxSize
+	}
+	if l.opts.Client != nil {
+		l.client = *l.opts.Client
+	}
+	l.client.CheckRedirect = l.checkRedirect
+	return l
+}
+
+// Load fetches the schema at uri. This implements [schema.Loader].
+func (l *Loader) Load(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+	if err := l.checkURL(uri); err != nil {
+		return nil, err
+	}
+	key := uri.String()
+
+	var err error
+	delay := l.opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		var data []byte
+		var retry bool
+		data, retry, err = l.fetch(ctx, key)
+		if err == nil {
+			return schema.SchemaFromJSONContext(ctx, schemaID, uri, data)
+		}
+		if !retry || attempt >= l.opts.Retries || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return nil, err
+}
+
+// fetch makes one attempt to fetch the schema at key.
+// It reports whether a failure may be temporary.
+func (l *Loader) fetch(ctx context.Context, key string) (data []byte, retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, l.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/schema+json, application/json")
+	l.mu.Lock()
+	c := l.cache[key]
+	l.mu.Unlock()
+	if c != nil {
+		if c.etag != "" {
+			req.Header.Set("If-None-Match", c.etag)
+		}
+		if c.lastModified != "" {
+			req.Header.Set("If-Modified-Since", c.lastModified)
+		}
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		var ne net.Error
+		return nil, errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded), fmt.Errorf("loading %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && c != nil:
+		return c.data, false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("loading %s: %s", key, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("loading %s: %s", key, resp.Status)
+	}
+
+	data, err = io.ReadAll(io.LimitReader(resp.Body, l.opts.MaxSize+1))
+	if err != nil {
+		return nil, true, fmt.Errorf("loading %s: %w", key, err)
+	}
+	if int64(len(data)) > l.opts.MaxSize {
+		return nil, false, fmt.Errorf("loading %s: schema larger than %d bytes", key, l.opts.MaxSize)
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		l.mu.Lock()
+		l.cache[key] = &cached{
+			data:         data,
+			etag:         etag,
+			lastModified: lastModified,
+		}
+		l.mu.Unlock()
+	}
+	return data, false, nil
+}
+
+// checkRedirect is the CheckRedirect function of the client.
+// The target of a redirect must also be allowed.
+func (l *Loader) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > l.opts.MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", l.opts.MaxRedirects)
+	}
+	return l.checkURL(req.URL)
+}
+
+// checkURL returns an error if schemas may not be loaded from u.
+func (l *Loader) checkURL(u *url.URL) error {
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !l.opts.AllowHTTP {
+			return fmt.Errorf("loading %s: http URLs are not allowed", u)
+		}
+	default:
+		return fmt.Errorf("loading %s: unsupported URL scheme %q", u, u.Scheme)
+	}
+	if !l.allowedHost(u.Hostname()) {
+		return fmt.Errorf("loading %s: host %q is not allowed", u, u.Hostname())
+	}
+	return nil
+}
+
+// allowedHost reports whether host matches an entry of AllowedHosts.
+func (l *Loader) allowedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range l.opts.AllowedHosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httploader_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/loader/httploader"
+)
+
+const draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// server is a test server for the loader.
+type server struct {
+	*httptest.Server
+	// requests counts the requests for each path.
+	requests map[string]*atomic.Int32
+	// notModified counts the responses with status 304.
+	notModified atomic.Int32
+}
+
+// newServer starts a server with these paths:
+//
+//	/schema.json    a schema, with an ETag
+//	/flaky.json     a schema after two failures
+//	/big.json       a large schema
+//	/redirect       a redirect to /schema.json
+//	/loop           a redirect to itself
+//	/away           a redirect to another host
+func newServer(t *testing.T) *server {
+	s := &server{requests: make(map[string]*atomic.Int32)}
+	for _, p := range []string{"/schema.json", "/flaky.json", "/big.json", "/redirect", "/loop", "/away"} {
+		s.requests[p] = new(atomic.Int32)
+	}
+	const body = `{"type": "integer"}`
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, ok := s.requests[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		count := n.Add(1)
+		switch r.URL.Path {
+		case "/schema.json":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				s.notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(body))
+		case "/flaky.json":
+			if count <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(body))
+		case "/big.json":
+			w.Write([]byte(`{"description": "` + strings.Repeat("x", 1000) + `"}`))
+		case "/redirect":
+			http.Redirect(w, r, "/schema.json", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, "http://elsewhere.example/schema.json", http.StatusFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// options returns loader options that allow the server.
+func (s *server) options() *httploader.Options {
+	return &httploader.Options{
+		AllowedHosts: []string{"127.0.0.1"},
+		AllowHTTP:    true,
+		Client:       s.Client(),
+		RetryDelay:   time.Millisecond,
+	}
+}
+
+// url returns the URL of path on the server.
+func (s *server) url(t *testing.T, path string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	l := httploader.New(srv.options())
+
+	for range 2 {
+		s, err := l.Load(ctx, draft202012, srv.url(t, "/schema.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate("x"); err == nil {
+			t.Error(`"x": no error`)
+		}
+	}
+	// The second load revalidates the cached copy.
+	if n := srv.notModified.Load(); n != 1 {
+		t.Errorf("got %d responses with status 304, want 1", n)
+	}
+
+	if _, err := l.Load(ctx, draft202012, srv.url(t, "/flaky.json")); err != nil {
+		t.Errorf("flaky: %v", err)
+	}
+	if n := srv.requests["/flaky.json"].Load(); n != 3 {
+		t.Errorf("flaky: got %d requests, want 3", n)
+	}
+
+	if _, err := l.Load(ctx, draft202012, srv.url(t, "/redirect")); err != nil {
+		t.Errorf("redirect: %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+
+	tests := []struct {
+		name string
+		opts func(*httploader.Options)
+		path string
+		// err is part of the error.
+		err string
+	}{
+		{"no hosts", func(o *httploader.Options) { o.AllowedHosts = nil }, "/schema.json", "not allowed"},
+		{"other host", func(o *httploader.Options) { o.AllowedHosts = []string{"*.example.com", "127.0.0.2"} }, "/schema.json", "not allowed"},
+		{"http", func(o *httploader.Options) { o.AllowHTTP = false }, "/schema.json", "http URLs are not allowed"},
+		{"not found", nil, "/missing.json", "404"},
+		{"no retries", func(o *httploader.Options) { o.Retries = -1 }, "/flaky.json", "503"},
+		{"too big", func(o *httploader.Options) { o.MaxSize = 100 }, "/big.json", "larger than 100 bytes"},
+		{"no redirects", func(o *httploader.Options) { o.MaxRedirects = -1 }, "/redirect", "redirects"},
+		{"redirect loop", nil, "/loop", "redirects"},
+		{"redirect away", nil, "/away", "not allowed"},
+	}
+	for _, test := range tests {
+		opts := srv.options()
+		if test.opts != nil {
+			test.opts(opts)
+		}
+		_, err := httploader.New(opts).Load(ctx, draft202012, srv.url(t, test.path))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.err)
+		}
+	}
+
+	if _, err := httploader.New(nil).Load(ctx, draft202012, srv.url(t, "/schema.json")); err == nil {
+		t.Error("nil options: no error")
+	}
+	u, _ := url.Parse("file:///etc/passwd")
+	if _, err := httploader.New(srv.options()).Load(ctx, draft202012, u); err == nil {
+		t.Error("file URL: no error")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Mux returns a loader that passes each URI to the loader in routes
+// whose key is the longest prefix of the URI, without its fragment.
+// A key may be a scheme, such as "file:", or a base URI, such as
+// "https://example.com/schemas/". The empty key matches every URI,
+// and so is a fallback. It is an error to load a URI that matches
+// no key. For example, this loads the schemas of example.com from
+// the binary, and fetches the rest from a few trusted hosts:
+//
+//	loader.Mux(map[string]schema.Loader{
+//		"https://example.com/": loader.FS(schemas, map[string]string{"https://example.com/": "."}),
+//		"https://":             httploader.New(&httploader.Options{AllowedHosts: hosts}),
+//	})
+func Mux(routes map[string]schema.Loader) schema.Loader {
+	return schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		us := withoutFragment(uri)
+		prefix, ok := longestPrefix(routes, us)
+		if !ok {
+			return nil, fmt.Errorf("no loader for %q", us)
+		}
+		return routes[prefix].Load(ctx, schemaID, uri)
+	})
+}
+
+// Map returns a loader that loads schemas from memory.
+// The keys of schemas are URIs, without fragments,
+// and the values are the JSON encodings of the schemas.
+// It is an error to load a URI that is not in schemas.
+func Map(schemas map[string][]byte) schema.Loader {
+	return schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		us := withoutFragment(uri)
+		data, ok := schemas[us]
+		if !ok {
+			return nil, fmt.Errorf("no schema for %q", us)
+		}
+		return schema.SchemaFromJSONContext(ctx, schemaID, uri, data)
+	})
+}
+
+// withoutFragment returns uri as a string, without its fragment.
+func withoutFragment(uri *url.URL) string {
+	u := *uri
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+// longestPrefix returns the longest key of m that is a prefix of s.
+// It reports false if there is none.
+func longestPrefix[V any](m map[string]V, s string) (string, bool) {
+	var ret string
+	found := false
+	for k := range m {
+		if strings.HasPrefix(s, k) && (!found || len(k) > len(ret)) {
+			ret = k
+			found = true
+		}
+	}
+	return ret, found
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/loader"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestMux(t *testing.T) {
+	ctx := context.Background()
+	l := loader.Mux(map[string]schema.Loader{
+		"https://example.com/": loader.Map(map[string][]byte{
+			"https://example.com/a.json":     []byte(`{"type": "string"}`),
+			"https://example.com/sub/a.json": []byte(`{"type": "boolean"}`),
+		}),
+		"https://example.com/sub/": loader.Map(map[string][]byte{
+			"https://example.com/sub/a.json": []byte(`{"type": "integer"}`),
+		}),
+		"urn:": loader.Map(map[string][]byte{
+			"urn:example:a": []byte(`{"type": "null"}`),
+		}),
+	})
+	tests := []struct {
+		uri            string
+		valid, invalid any
+	}{
+		{"https://example.com/a.json", "a", 1.0},
+		{"https://example.com/a.json#/type", "a", 1.0},
+		// The longest prefix is used.
+		{"https://example.com/sub/a.json", 1.0, true},
+		{"urn:example:a", nil, "a"},
+	}
+	for _, test := range tests {
+		s, err := l.Load(ctx, draft202012, mustParse(t, test.uri))
+		if err != nil {
+			t.Errorf("%s: %v", test.uri, err)
+			continue
+		}
+		if err := s.Validate(test.valid); err != nil {
+			t.Errorf("%s: %v: %v", test.uri, test.valid, err)
+		}
+		if err := s.Validate(test.invalid); err == nil {
+			t.Errorf("%s: %v: no error", test.uri, test.invalid)
+		}
+	}
+
+	for _, uri := range []string{"https://other.example/a.json", "https://example.com/b.json", "urn:example:b"} {
+		if _, err := l.Load(ctx, draft202012, mustParse(t, uri)); err == nil {
+			t.Errorf("%s: no error", uri)
+		}
+	}
+
+	// The empty key is a fallback.
+	l = loader.Mux(map[string]schema.Loader{
+		"": loader.Map(map[string][]byte{"https://other.example/a.json": []byte(`{}`)}),
+	})
+	if _, err := l.Load(ctx, draft202012, mustParse(t, "https://other.example/a.json")); err != nil {
+		t.Errorf("fallback: %v", err)
+	}
+}
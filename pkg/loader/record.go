@@ -0,0 +1,118 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package loader provides [schema.Loader] implementations for loading
+// schemas referenced by a $ref to another document.
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Record returns a loader that calls next, and saves each schema
+// that next returns in files in dir, so that a later run can use
+// [Replay] to load the same schemas without fetching them.
+// The directory is created if necessary.
+// Loading the same URI again overwrites the recording.
+func Record(dir string, next schema.Loader) schema.Loader {
+	return schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		s, err := next.Load(ctx, schemaID, uri)
+		if err != nil || s == nil {
+			return s, err
+		}
+		data, err := json.MarshalIndent(s, "", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("recording %q: %v", uri, err)
+		}
+		if err := os.MkdirAll(dir, 0o777); err != nil {
+			return nil, fmt.Errorf("recording %q: %v", uri, err)
+		}
+		base := filepath.Join(dir, recordingName(uri))
+		if err := writeFile(base+".uri", []byte(uri.String()+"\n")); err != nil {
+			return nil, fmt.Errorf("recording %q: %v", uri, err)
+		}
+		if err := writeFile(base+".json", data); err != nil {
+			return nil, fmt.Errorf("recording %q: %v", uri, err)
+		}
+		return s, nil
+	})
+}
+
+// Replay returns a loader that loads the schemas saved in dir by
+// [Record]. It is an error to load a URI that was not recorded,
+// so a run that uses Replay never fetches a remote schema.
+func Replay(dir string) schema.Loader {
+	return schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		data, err := os.ReadFile(filepath.Join(dir, recordingName(uri)+".json"))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("no recording of %q in %s", uri, dir)
+			}
+			return nil, err
+		}
+		return schema.SchemaFromJSONContext(ctx, schemaID, uri, data)
+	})
+}
+
+// recordingName returns the base name of the files that hold the
+// recording of uri: the schema is in a ".json" file, and the URI
+// in a ".uri" file. The name is readable where possible,
+// and a hash of the full URI makes it unique.
+func recordingName(uri *url.URL) string {
+	u := uri.String()
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://"))
+	const maxName = 80
+	if len(name) > maxName {
+		name = name[len(name)-maxName:]
+	}
+	sum := sha256.Sum256([]byte(u))
+	return name + "-" + hex.EncodeToString(sum[:6])
+}
+
+// writeFile writes data to the file name, replacing it atomically,
+// so that concurrent loads of the same URI don't see partial files.
+func writeFile(name string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), ".record-*")
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
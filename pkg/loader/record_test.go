@@ -0,0 +1,111 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package loader_test
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/loader"
+)
+
+const draft202012 = "https://json-schema.org/draft/2020-12/schema"
+
+// mustParse parses a URI.
+func mustParse(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "recordings")
+	live := loader.Map(map[string][]byte{
+		"https://example.com/a.json": []byte(`{"type": "integer"}`),
+		"https://example.com/b.json": []byte(`{"type": "string"}`),
+	})
+
+	rec := loader.Record(dir, live)
+	for _, u := range []string{"https://example.com/a.json", "https://example.com/b.json#/x"} {
+		if _, err := rec.Load(ctx, draft202012, mustParse(t, u)); err != nil {
+			t.Fatalf("recording %s: %v", u, err)
+		}
+	}
+	if _, err := rec.Load(ctx, draft202012, mustParse(t, "https://example.com/c.json")); err == nil {
+		t.Error("recording a missing schema: no error")
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*.uri"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Errorf("got %d recordings, want 2", len(names))
+	}
+	leftovers, err := filepath.Glob(filepath.Join(dir, ".record-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+
+	replay := loader.Replay(dir)
+	s, err := replay.Load(ctx, draft202012, mustParse(t, "https://example.com/a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(1.0); err != nil {
+		t.Errorf("1: %v", err)
+	}
+	if err := s.Validate("x"); err == nil {
+		t.Error(`"x": no error`)
+	}
+	if _, err := replay.Load(ctx, draft202012, mustParse(t, "https://example.com/c.json")); err == nil {
+		t.Error("replaying an unrecorded schema: no error")
+	}
+}
+
+func TestRecordingName(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	uris := []string{
+		"https://example.com/very/long/path/that/goes/on/and/on/and/on/until/it/passes/the/limit/schema.json",
+		"http://example.com/x.json",
+		"https://example.com/x.json",
+		"https://example.com/x%20y.json",
+	}
+	schemas := make(map[string][]byte)
+	for _, u := range uris {
+		schemas[u] = []byte(`{}`)
+	}
+	rec := loader.Record(dir, loader.Map(schemas))
+	for _, u := range uris {
+		if _, err := rec.Load(ctx, draft202012, mustParse(t, u)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each URI has its own pair of files, even where the
+	// readable parts of the names are the same.
+	if len(entries) != 2*len(uris) {
+		t.Errorf("got %d files, want %d", len(entries), 2*len(uris))
+	}
+	for _, e := range entries {
+		if len(e.Name()) > 100 {
+			t.Errorf("file name %q is too long", e.Name())
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package metrics aggregates reports of validations, so that the
+// health of the schemas used by a program can be monitored:
+// how many instances each schema validates, how many of them fail
+// and at which keywords, and how long validation takes.
+//
+// Set an [*Aggregator] as the Collector of the validation options:
+//
+//	agg := metrics.New(nil)
+//	err := s.ValidateWithOpts(instance, &schema.ValidateOpts{Collector: agg})
+//
+// Package prometheus serves the aggregated metrics
+// in the Prometheus text format.
+package metrics
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets
+// of the duration histogram if [New] is passed nil.
+var DefaultBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Aggregator is a [schema.Collector] that counts validations
+// and failures for each schema, and records a histogram of
+// their durations. An Aggregator may be used concurrently.
+type Aggregator struct {
+	buckets []float64
+
+	mu sync.Mutex
+	// schemas maps the schema name to its metrics.
+	schemas map[string]*SchemaMetrics
+}
+
+// New returns a new [Aggregator] whose duration histograms have
+// buckets with the given upper bounds, in seconds, in increasing
+// order. If buckets is nil, [DefaultBuckets] is used.
+func New(buckets []float64) *Aggregator {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	return &Aggregator{
+		buckets: slices.Clone(buckets),
+		schemas: make(map[string]*SchemaMetrics),
+	}
+}
+
+// SchemaMetrics are the metrics of the validations against one schema.
+type SchemaMetrics struct {
+	// Schema is the $id of the schema, or the empty string
+	// for the schemas that have no $id.
+	Schema string
+	// Validations is the number of validations.
+	Validations uint64
+	// Failures is the number of validations that
+	// returned an error.
+	Failures uint64
+	// KeywordFailures counts the failures of each keyword,
+	// keyed by keyword location, such as "#/properties/a/type".
+	KeywordFailures map[string]uint64
+	// Duration is the histogram of the durations of the validations.
+	Duration Histogram
+}
+
+// Histogram is a histogram of durations, in seconds.
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets.
+	Buckets []float64
+	// Counts are the number of durations that were at most the
+	// upper bound of each bucket. Each count includes those of
+	// the buckets before it, as in Prometheus.
+	Counts []uint64
+	// Count is the number of durations.
+	Count uint64
+	// Sum is the sum of the durations.
+	Sum float64
+}
+
+// Collect implements [schema.Collector].
+func (a *Aggregator) Collect(r *schema.ValidationReport) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	m, ok := a.schemas[r.SchemaID]
+	if !ok {
+		m = &SchemaMetrics{
+			Schema:          r.SchemaID,
+			KeywordFailures: make(map[string]uint64),
+			Duration: Histogram{
+				Buckets: a.buckets,
+				Counts:  make([]uint64, len(a.buckets)),
+			},
+		}
+		a.schemas[r.SchemaID] = m
+	}
+
+	m.Validations++
+	if r.Err != nil {
+		m.Failures++
+	}
+	for _, f := range r.Failures {
+		m.KeywordFailures[f.KeywordLocation]++
+	}
+
+	secs := r.Duration.Seconds()
+	for i, b := range a.buckets {
+		if secs <= b {
+			m.Duration.Counts[i]++
+		}
+	}
+	m.Duration.Count++
+	m.Duration.Sum += secs
+}
+
+// Snapshot returns a copy of the metrics collected so far,
+// sorted by schema.
+func (a *Aggregator) Snapshot() []SchemaMetrics {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r := make([]SchemaMetrics, 0, len(a.schemas))
+	for _, name := range slices.Sorted(maps.Keys(a.schemas)) {
+		m := *a.schemas[name]
+		m.KeywordFailures = maps.Clone(m.KeywordFailures)
+		m.Duration.Counts = slices.Clone(m.Duration.Counts)
+		r = append(r, m)
+	}
+	return r
+}
+
+// Reset discards the metrics collected so far.
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	clear(a.schemas)
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/metrics"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestAggregator(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/point",
+		"type": "object",
+		"properties": {"x": {"type": "number"}, "y": {"type": "number"}},
+		"required": ["x", "y"]
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	agg := metrics.New(nil)
+	opts := &schema.ValidateOpts{Collector: agg}
+	for _, inst := range []any{
+		map[string]any{"x": 1.0, "y": 2.0},
+		map[string]any{"x": "a", "y": "b"},
+		map[string]any{"x": "a"},
+		"point",
+	} {
+		s.ValidateWithOpts(inst, opts)
+	}
+
+	ms := agg.Snapshot()
+	if len(ms) != 1 {
+		t.Fatalf("got %d schemas, want 1", len(ms))
+	}
+	m := ms[0]
+	if m.Schema != "https://example.com/point" || m.Validations != 4 || m.Failures != 3 {
+		t.Errorf("got %s with %d validations and %d failures, want 4 and 3", m.Schema, m.Validations, m.Failures)
+	}
+	want := map[string]uint64{
+		"#/properties/x/type": 2,
+		"#/properties/y/type": 1,
+		"#/required":          1,
+		"#/type":              1,
+	}
+	if !maps.Equal(m.KeywordFailures, want) {
+		t.Errorf("got keyword failures %v, want %v", m.KeywordFailures, want)
+	}
+	if m.Duration.Count != 4 || !slices.Equal(m.Duration.Buckets, metrics.DefaultBuckets) {
+		t.Errorf("got duration %+v", m.Duration)
+	}
+
+	// The snapshot is a copy.
+	m.KeywordFailures["#/type"] = 100
+	if got := agg.Snapshot()[0].KeywordFailures["#/type"]; got != 1 {
+		t.Errorf("snapshot shares keyword failures: got %d", got)
+	}
+
+	agg.Reset()
+	if ms := agg.Snapshot(); len(ms) != 0 {
+		t.Errorf("after Reset: got %v", ms)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	agg := metrics.New([]float64{0.001, 0.01, 0.1})
+	for _, d := range []time.Duration{500 * time.Microsecond, time.Millisecond, 5 * time.Millisecond, time.Second} {
+		agg.Collect(&schema.ValidationReport{Duration: d})
+	}
+	agg.Collect(&schema.ValidationReport{SchemaID: "b", Err: errors.New("not a validation error")})
+
+	ms := agg.Snapshot()
+	if len(ms) != 2 || ms[0].Schema != "" || ms[1].Schema != "b" {
+		t.Fatalf("got %+v, want schemas \"\" and \"b\"", ms)
+	}
+	h := ms[0].Duration
+	if want := []uint64{2, 3, 3}; !slices.Equal(h.Counts, want) {
+		t.Errorf("got bucket counts %v, want %v", h.Counts, want)
+	}
+	if h.Count != 4 || h.Sum != 1.0065 {
+		t.Errorf("got count %d, sum %g, want 4, 1.0065", h.Count, h.Sum)
+	}
+	if ms[1].Validations != 1 || ms[1].Failures != 1 || len(ms[1].KeywordFailures) != 0 {
+		t.Errorf("got %+v", ms[1])
+	}
+}
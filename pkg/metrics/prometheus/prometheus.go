@@ -0,0 +1,126 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prometheus exposes the validation metrics of a
+// [metrics.Aggregator] in the Prometheus text exposition format,
+// so that a Prometheus server can scrape them:
+//
+//	agg := metrics.New(nil)
+//	http.Handle("/metrics/jsonschema", prometheus.Handler(agg))
+//
+// The metrics are labeled by the $id of the schema:
+//
+//	jsonschema_validations_total{schema}
+//	jsonschema_validation_failures_total{schema}
+//	jsonschema_keyword_failures_total{schema,keyword,location}
+//	jsonschema_validation_duration_seconds{schema} (a histogram)
+//
+// This package writes the format itself, and doesn't depend on the
+// Prometheus client library. A program that uses the client library
+// can serve these metrics from another path of its server.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/metrics"
+)
+
+// ContentType is the media type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler returns an HTTP handler that serves the metrics of a.
+func Handler(a *metrics.Aggregator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := Write(w, a.Snapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Write writes ms to w in the Prometheus text format.
+func Write(w io.Writer, ms []metrics.SchemaMetrics) error {
+	bw := bufio.NewWriter(w)
+
+	header(bw, "jsonschema_validations_total", "counter", "Number of instances validated.")
+	for _, m := range ms {
+		sample(bw, "jsonschema_validations_total", labels("schema", m.Schema), float64(m.Validations))
+	}
+
+	header(bw, "jsonschema_validation_failures_total", "counter", "Number of instances that failed validation.")
+	for _, m := range ms {
+		sample(bw, "jsonschema_validation_failures_total", labels("schema", m.Schema), float64(m.Failures))
+	}
+
+	header(bw, "jsonschema_keyword_failures_total", "counter", "Number of failures of each schema keyword.")
+	for _, m := range ms {
+		for _, loc := range slices.Sorted(maps.Keys(m.KeywordFailures)) {
+			l := labels("schema", m.Schema, "keyword", keyword(loc), "location", loc)
+			sample(bw, "jsonschema_keyword_failures_total", l, float64(m.KeywordFailures[loc]))
+		}
+	}
+
+	header(bw, "jsonschema_validation_duration_seconds", "histogram", "Time taken to validate an instance.")
+	for _, m := range ms {
+		h := m.Duration
+		for i, b := range h.Buckets {
+			l := labels("schema", m.Schema, "le", formatFloat(b))
+			sample(bw, "jsonschema_validation_duration_seconds_bucket", l, float64(h.Counts[i]))
+		}
+		l := labels("schema", m.Schema, "le", "+Inf")
+		sample(bw, "jsonschema_validation_duration_seconds_bucket", l, float64(h.Count))
+		sample(bw, "jsonschema_validation_duration_seconds_sum", labels("schema", m.Schema), h.Sum)
+		sample(bw, "jsonschema_validation_duration_seconds_count", labels("schema", m.Schema), float64(h.Count))
+	}
+
+	return bw.Flush()
+}
+
+// header writes the HELP and TYPE lines of a metric.
+func header(w *bufio.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one sample of a metric.
+func sample(w *bufio.Writer, name, labels string, v float64) {
+	fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatFloat(v))
+}
+
+// labels formats pairs of label names and values.
+func labels(kv ...string) string {
+	var sb strings.Builder
+	for i := 0; i < len(kv); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(kv[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(kv[i+1]))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+// labelEscaper escapes a label value.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// keyword returns the name of the keyword at the keyword location loc.
+func keyword(loc string) string {
+	kw := loc[strings.LastIndex(loc, "/")+1:]
+	return jsonpointer.UnescapeToken(kw)
+}
+
+// formatFloat formats a sample value or bucket bound.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prometheus_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/altshiftab/jsonschema/pkg/metrics"
+	"github.com/altshiftab/jsonschema/pkg/metrics/prometheus"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestWrite(t *testing.T) {
+	ms := []metrics.SchemaMetrics{{
+		Schema:      `https://example.com/"a"`,
+		Validations: 3,
+		Failures:    1,
+		KeywordFailures: map[string]uint64{
+			"#/required":             1,
+			"#/properties/a~1b/type": 2,
+		},
+		Duration: metrics.Histogram{
+			Buckets: []float64{0.001, 0.5},
+			Counts:  []uint64{1, 2},
+			Count:   3,
+			Sum:     1.25,
+		},
+	}}
+	var sb strings.Builder
+	if err := prometheus.Write(&sb, ms); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP jsonschema_validations_total Number of instances validated.
+# TYPE jsonschema_validations_total counter
+jsonschema_validations_total{schema="https://example.com/\"a\""} 3
+# HELP jsonschema_validation_failures_total Number of instances that failed validation.
+# TYPE jsonschema_validation_failures_total counter
+jsonschema_validation_failures_total{schema="https://example.com/\"a\""} 1
+# HELP jsonschema_keyword_failures_total Number of failures of each schema keyword.
+# TYPE jsonschema_keyword_failures_total counter
+jsonschema_keyword_failures_total{schema="https://example.com/\"a\"",keyword="type",location="#/properties/a~1b/type"} 2
+jsonschema_keyword_failures_total{schema="https://example.com/\"a\"",keyword="required",location="#/required"} 1
+# HELP jsonschema_validation_duration_seconds Time taken to validate an instance.
+# TYPE jsonschema_validation_duration_seconds histogram
+jsonschema_validation_duration_seconds_bucket{schema="https://example.com/\"a\"",le="0.001"} 1
+jsonschema_validation_duration_seconds_bucket{schema="https://example.com/\"a\"",le="0.5"} 2
+jsonschema_validation_duration_seconds_bucket{schema="https://example.com/\"a\"",le="+Inf"} 3
+jsonschema_validation_duration_seconds_sum{schema="https://example.com/\"a\""} 1.25
+jsonschema_validation_duration_seconds_count{schema="https://example.com/\"a\""} 3
+`
+	if got := sb.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	agg := metrics.New(nil)
+	agg.Collect(&schema.ValidationReport{SchemaID: "s", Duration: time.Millisecond})
+
+	w := httptest.NewRecorder()
+	prometheus.Handler(agg).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	resp := w.Result()
+	if ct := resp.Header.Get("Content-Type"); ct != prometheus.ContentType {
+		t.Errorf("got Content-Type %q, want %q", ct, prometheus.ContentType)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `jsonschema_validations_total{schema="s"} 1`+"\n") {
+		t.Errorf("body does not count the validation:\n%s", body)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi30
+
+import (
+	"maps"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/draft04"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// nullableKeyword is the "nullable" keyword. It is checked
+// by the "type" keyword, so it always validates.
+var nullableKeyword = schema.Keyword{
+	Name:     "nullable",
+	ArgType:  arg_type.ArgTypeBool,
+	Validate: validator.ValidateTrue,
+}
+
+// typeKeyword replaces the draft-04 "type" keyword,
+// to permit null if "nullable" is true.
+// It is not a leaf keyword, as it looks at the schema.
+var typeKeyword = schema.Keyword{
+	Name:     "type",
+	ArgType:  arg_type.ArgTypeStringOrStrings,
+	Validate: validator.ArgTypeStringOrStrings(validator.ValidateTypeNullable),
+}
+
+// oneOfKeyword and anyOfKeyword replace the draft-04 keywords,
+// so that a discriminator can select the branch.
+var (
+	oneOfKeyword = schema.Keyword{
+		Name:     "oneOf",
+		ArgType:  arg_type.ArgTypeSchemas,
+		Validate: validator.ArgTypeSchemas(validator.ValidateOneOfDiscriminator),
+	}
+	anyOfKeyword = schema.Keyword{
+		Name:     "anyOf",
+		ArgType:  arg_type.ArgTypeSchemas,
+		Validate: validator.ArgTypeSchemas(validator.ValidateAnyOfDiscriminator),
+	}
+)
+
+// annotationKeyword returns an annotation keyword
+// of OpenAPI 3.0 whose argument has type argType.
+func annotationKeyword(name string, argType arg_type.ArgType) *schema.Keyword {
+	return &schema.Keyword{
+		Name:       name,
+		ArgType:    argType,
+		Validate:   validator.ValidateTrue,
+		Leaf:       true,
+		Annotation: true,
+	}
+}
+
+// newKeywords are the keywords that OpenAPI 3.0
+// adds to draft-04, or changes.
+var newKeywords = []*schema.Keyword{
+	&nullableKeyword,
+	&typeKeyword,
+	&oneOfKeyword,
+	&anyOfKeyword,
+	annotationKeyword("discriminator", arg_type.ArgTypeAny),
+	annotationKeyword("readOnly", arg_type.ArgTypeBool),
+	annotationKeyword("writeOnly", arg_type.ArgTypeBool),
+	annotationKeyword("xml", arg_type.ArgTypeAny),
+	annotationKeyword("externalDocs", arg_type.ArgTypeAny),
+	annotationKeyword("example", arg_type.ArgTypeAny),
+	annotationKeyword("deprecated", arg_type.ArgTypeBool),
+}
+
+// keywordMap returns the keywords of the vocabulary:
+// the draft-04 keywords plus the OpenAPI ones.
+func keywordMap() map[string]*schema.Keyword {
+	m := maps.Clone(draft04.Vocabulary.Keywords)
+	for _, k := range newKeywords {
+		m[k.Name] = k
+	}
+	return m
+}
+
+// keywordDocs returns the documentation of the keywords.
+func keywordDocs() map[string]schema.KeywordDoc {
+	m := maps.Clone(draft04.Vocabulary.Docs)
+	const link = "https://spec.openapis.org/oas/v3.0.3#fixed-fields-19"
+	for name, desc := range map[string]string{
+		"nullable":      "Whether null is permitted in addition to the type.",
+		"discriminator": "Names the property that tells which of the oneOf or anyOf schemas an object is for.",
+		"readOnly":      "Whether the property is only sent in responses.",
+		"writeOnly":     "Whether the property is only sent in requests.",
+		"xml":           "Describes the XML representation of a property.",
+		"externalDocs":  "Additional external documentation for the schema.",
+		"example":       "An example of an instance.",
+		"deprecated":    "Whether the schema is deprecated.",
+	} {
+		m[name] = schema.KeywordDoc{Description: desc, Link: link}
+	}
+	return m
+}
+
+// sortAs maps a new keyword to the draft-04 keyword
+// that it sorts with.
+var sortAs = map[string]string{
+	"nullable":      "type",
+	"discriminator": "title",
+	"readOnly":      "title",
+	"writeOnly":     "title",
+	"xml":           "title",
+	"externalDocs":  "title",
+	"example":       "title",
+	"deprecated":    "title",
+}
+
+// keywordCmp is the keyword comparison routine.
+func keywordCmp(a, b string) int {
+	if s, ok := sortAs[a]; ok {
+		a = s
+	}
+	if s, ok := sortAs[b]; ok {
+		b = s
+	}
+	return draft04.Vocabulary.Cmp(a, b)
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openapi30 defines the keywords used by the schema objects
+// of OpenAPI 3.0 documents, so that they can be validated without
+// converting them to a JSON schema draft first.
+//
+// OpenAPI 3.0 schema objects are a variant of JSON schema draft-04:
+//   - They have no "$schema" keyword. Use [Unmarshal], or name
+//     [SchemaID] as the default schema ID when decoding.
+//   - A "nullable" keyword that is true permits null as well as
+//     the types named by the "type" keyword.
+//   - "exclusiveMaximum" and "exclusiveMinimum" are booleans that
+//     make "maximum" and "minimum" exclusive.
+//   - Keywords next to "$ref" are ignored.
+//   - "discriminator", "readOnly", "writeOnly", "xml", "externalDocs",
+//     "example" and "deprecated" are annotations. If
+//     [schema.ValidateOpts.Discriminator] is set, "discriminator"
+//     selects the branch of "oneOf" or "anyOf", as described there.
+//
+// OpenAPI 3.0 has no URI for its dialect of JSON schema.
+// This package names it with [SchemaID], which is never fetched.
+//
+// Importing this package registers the vocabulary, but does not
+// make it the default.
+package openapi30
+
+import (
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+const SchemaID = "https://spec.openapis.org/oas/3.0/dialect"
+
+var Vocabulary = &schema.Vocabulary{
+	Name:     "openapi-3.0",
+	Schema:   SchemaID,
+	Keywords: keywordMap(),
+	Cmp:      keywordCmp,
+	Resolve:  resolveSchema,
+	Docs:     keywordDocs(),
+	// Keywords next to $ref are ignored.
+	IgnoreRefSiblings: true,
+}
+
+func init() {
+	schema.RegisterVocabulary(Vocabulary, false)
+}
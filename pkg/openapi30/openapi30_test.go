@@ -0,0 +1,126 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi30_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/loader"
+	"github.com/altshiftab/jsonschema/pkg/openapi30"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance string
+		valid    bool
+	}{
+		{`{"type": "string"}`, `null`, false},
+		{`{"type": "string", "nullable": true}`, `null`, true},
+		{`{"type": "string", "nullable": true}`, `1`, false},
+		{`{"type": "string", "nullable": false}`, `null`, false},
+		{`{"maximum": 3, "exclusiveMaximum": true}`, `3`, false},
+		{`{"minimum": 3, "exclusiveMinimum": true}`, `3.5`, true},
+		// Keywords next to $ref are ignored.
+		{`{"definitions": {"n": {"type": "number"}}, "$ref": "#/definitions/n", "maximum": 1}`, `5`, true},
+		{`{"readOnly": true, "writeOnly": false, "deprecated": true, "example": 1, "xml": {"name": "n"}, "externalDocs": {}}`, `1`, true},
+		// Keywords from later drafts are not known.
+		{`{"const": 1}`, `2`, true},
+	}
+	for _, test := range tests {
+		s, err := openapi30.Unmarshal([]byte(test.schema), nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.schema, err)
+			continue
+		}
+		var v any
+		if err := json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: %s: got error %v, want valid %t", test.schema, test.instance, err, test.valid)
+		}
+	}
+
+	if _, err := openapi30.Unmarshal([]byte(`{"nullable": "yes"}`), nil); err == nil {
+		t.Error(`nullable "yes": no error`)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	const src = `{"nullable":true,"type":"integer"}`
+	s, err := openapi30.Unmarshal([]byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// There is no $schema to add.
+	if strings.Contains(string(data), "$schema") {
+		t.Errorf("got %s, want no $schema", data)
+	}
+}
+
+func TestDiscriminator(t *testing.T) {
+	var v any
+	if err := json.Unmarshal([]byte(`{
+		"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}],
+		"discriminator": {"propertyName": "petType"},
+		"components": {
+			"schemas": {
+				"Cat": {"properties": {"lives": {"type": "integer"}}},
+				"Dog": {"required": ["bark"]}
+			}
+		}
+	}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	s, err := openapi30.UnmarshalValue(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &schema.ValidateOpts{Discriminator: true}
+	if err := s.ValidateWithOpts(map[string]any{"petType": "Cat", "lives": 9.0}, opts); err != nil {
+		t.Error(err)
+	}
+	err = s.ValidateWithOpts(map[string]any{"petType": "Dog"}, opts)
+	if err == nil || !strings.Contains(err.Error(), "oneOf/1") {
+		t.Errorf("got error %v, want one for oneOf/1", err)
+	}
+}
+
+func TestRemoteRef(t *testing.T) {
+	uri, err := url.Parse("https://example.com/api/pet.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ropts := &schema.ResolveOpts{
+		URI: uri,
+		Loader: loader.Map(map[string][]byte{
+			// The referenced schema has no $schema either.
+			"https://example.com/api/name.json": []byte(`{"type": "string", "nullable": true}`),
+		}),
+	}
+	s, err := openapi30.Unmarshal([]byte(`{"properties": {"name": {"$ref": "name.json"}}}`), ropts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(map[string]any{"name": nil}); err != nil {
+		t.Errorf("null name: %v", err)
+	}
+	if err := s.Validate(map[string]any{"name": 1.0}); err == nil {
+		t.Error("numeric name: no error")
+	}
+
+	if _, err := openapi30.Unmarshal([]byte(`{"$ref": "https://example.com/other.json"}`), nil); err == nil {
+		t.Error("remote reference without a loader: no error")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi30
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Unmarshal decodes the JSON encoding of an OpenAPI 3.0 schema
+// object, which has no "$schema" keyword, and resolves it.
+// The ropts argument may be nil; its Vocabulary field is ignored.
+// The result does not have a "$schema" keyword either,
+// so it marshals as it was.
+func Unmarshal(data []byte, ropts *schema.ResolveOpts) (*schema.Schema, error) {
+	var uri *url.URL
+	if ropts != nil {
+		uri = ropts.URI
+	}
+	s, err := schema.SchemaFromJSONWithSource(SchemaID, uri, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var nopts schema.ResolveOpts
+	if ropts != nil {
+		nopts = *ropts
+	}
+	nopts.Vocabulary = Vocabulary
+	if err := s.Resolve(&nopts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalValue is like [Unmarshal], but takes the schema object
+// as decoded by [json.Unmarshal], as when it has been taken from
+// a larger OpenAPI document.
+func UnmarshalValue(v any, ropts *schema.ResolveOpts) (*schema.Schema, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(data, ropts)
+}
+
+// resolveSchema resolves references in a schema.
+// OpenAPI 3.0 has no id keyword, and $ref works as in
+// draft 2020-12, so we use the draft 2020-12 resolver.
+// We wrap the loader so that remote schemas that don't have
+// a "$schema" keyword, which is all of them, default to OpenAPI 3.0.
+func resolveSchema(s *schema.Schema, ropts *schema.ResolveOpts) error {
+	var nopts schema.ResolveOpts
+	if ropts != nil {
+		nopts = *ropts
+	}
+	next := nopts.Loader
+	nopts.Loader = schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		if next == nil {
+			return nil, errors.New("remote loading not permitted")
+		}
+		return next.Load(ctx, SchemaID, uri)
+	})
+	return draft202012.Vocabulary.Resolve(s, &nopts)
+}
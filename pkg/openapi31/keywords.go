@@ -0,0 +1,102 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi31
+
+import (
+	"maps"
+
+	"github.com/altshiftab/jsonschema/internal/validator"
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// annotationKeyword returns an annotation keyword
+// of the OpenAPI base vocabulary.
+func annotationKeyword(name string) *schema.Keyword {
+	return &schema.Keyword{
+		Name:       name,
+		ArgType:    arg_type.ArgTypeAny,
+		Validate:   validator.ValidateTrue,
+		Leaf:       true,
+		Annotation: true,
+	}
+}
+
+// The keywords of the OpenAPI base vocabulary.
+var (
+	discriminatorKeyword = annotationKeyword("discriminator")
+	exampleKeyword       = annotationKeyword("example")
+	externalDocsKeyword  = annotationKeyword("externalDocs")
+	xmlKeyword           = annotationKeyword("xml")
+)
+
+// oneOfKeyword and anyOfKeyword replace the draft 2020-12 keywords,
+// so that a discriminator can select the branch.
+var (
+	oneOfKeyword = schema.Keyword{
+		Name:     "oneOf",
+		ArgType:  arg_type.ArgTypeSchemas,
+		Validate: validator.ArgTypeSchemas(validator.ValidateOneOfDiscriminator),
+	}
+	anyOfKeyword = schema.Keyword{
+		Name:     "anyOf",
+		ArgType:  arg_type.ArgTypeSchemas,
+		Validate: validator.ArgTypeSchemas(validator.ValidateAnyOfDiscriminator),
+	}
+)
+
+// keywordMap returns the keywords of the vocabulary:
+// the draft 2020-12 keywords plus the OpenAPI ones.
+func keywordMap() map[string]*schema.Keyword {
+	m := maps.Clone(draft202012.Vocabulary.Keywords)
+	for _, k := range []*schema.Keyword{discriminatorKeyword, exampleKeyword, externalDocsKeyword, xmlKeyword, &oneOfKeyword, &anyOfKeyword} {
+		m[k.Name] = k
+	}
+	return m
+}
+
+// keywordDocs returns the documentation of the keywords.
+func keywordDocs() map[string]schema.KeywordDoc {
+	m := maps.Clone(draft202012.Vocabulary.Docs)
+	const link = "https://spec.openapis.org/oas/v3.1.0#fixed-fields-19"
+	m["discriminator"] = schema.KeywordDoc{
+		Description: "Names the property that tells which of the oneOf or anyOf schemas an object is for.",
+		Link:        link,
+	}
+	m["example"] = schema.KeywordDoc{
+		Description: "An example of an instance. Deprecated in favor of examples.",
+		Link:        link,
+	}
+	m["externalDocs"] = schema.KeywordDoc{
+		Description: "Additional external documentation for the schema.",
+		Link:        link,
+	}
+	m["xml"] = schema.KeywordDoc{
+		Description: "Describes the XML representation of a property.",
+		Link:        link,
+	}
+	return m
+}
+
+// sortAs maps a new keyword to the draft 2020-12 keyword
+// that it sorts with.
+var sortAs = map[string]string{
+	"discriminator": "examples",
+	"example":       "examples",
+	"externalDocs":  "examples",
+	"xml":           "examples",
+}
+
+// keywordCmp is the keyword comparison routine.
+func keywordCmp(a, b string) int {
+	if s, ok := sortAs[a]; ok {
+		a = s
+	}
+	if s, ok := sortAs[b]; ok {
+		b = s
+	}
+	return draft202012.Vocabulary.Cmp(a, b)
+}
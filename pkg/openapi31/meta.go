@@ -0,0 +1,79 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi31
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+//go:embed metaschema/*/*.json
+var metaFS embed.FS
+
+// loadMetaSchema checks whether uri refers to one of the OpenAPI 3.1
+// meta-schemas, the dialect or the base vocabulary meta-schema,
+// and loads the schema if it does. If uri is not a meta-schema,
+// this returns nil, nil.
+//
+// The result is not resolved, as it is returned by a loader;
+// see [schema.ResolveOpts].
+func loadMetaSchema(uri *url.URL) (*schema.Schema, error) {
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, nil
+	}
+	if uri.Host != "spec.openapis.org" {
+		return nil, nil
+	}
+	path, ok := strings.CutPrefix(uri.Path, "/oas/3.1/")
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := metaFS.ReadFile("metaschema/" + path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("can't find meta-schema URI %q: %v", uri, err)
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("can't parse meta-schema URI %q: %v", uri, err)
+	}
+	return schema.SchemaFromJSON(SchemaID, uri, v)
+}
+
+// MetaSchema returns the OpenAPI 3.1 dialect meta-schema, which
+// describes valid OpenAPI 3.1 schemas. It can be used to check a
+// schema before using it. The meta-schemas are loaded from copies
+// embedded in this package, so this does not access the network.
+//
+// The result is shared and must not be modified.
+func MetaSchema() *schema.Schema {
+	return metaSchema()
+}
+
+// metaSchema loads the meta-schema once.
+var metaSchema = sync.OnceValue(func() *schema.Schema {
+	uri, err := url.Parse(SchemaID)
+	if err != nil {
+		panic(err)
+	}
+	s, err := loadMetaSchema(uri)
+	if err == nil {
+		err = s.Resolve(&schema.ResolveOpts{
+			Vocabulary: Vocabulary,
+			URI:        uri,
+		})
+	}
+	if err != nil {
+		panic(fmt.Sprintf("loading embedded meta-schema: %v", err))
+	}
+	return s
+})
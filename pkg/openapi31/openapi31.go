@@ -0,0 +1,39 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openapi31 defines the keywords used by the schemas in
+// OpenAPI 3.1 documents, so that they can be decoded by $schema.
+//
+// The OpenAPI 3.1 dialect is draft 2020-12 plus the keywords of the
+// OpenAPI base vocabulary: "discriminator", "example", "externalDocs"
+// and "xml". These are annotations. If [schema.ValidateOpts.Discriminator]
+// is set, a "discriminator" keyword also selects the branch of the
+// "oneOf" or "anyOf" keyword next to it that an instance is validated
+// against, by the value of the named property.
+//
+// Importing this package registers the vocabulary, but does not
+// make it the default; a schema must name [SchemaID] in its
+// "$schema" keyword to use it.
+package openapi31
+
+import (
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+const SchemaID = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+var Vocabulary = &schema.Vocabulary{
+	Name:     "openapi-3.1",
+	Schema:   SchemaID,
+	Keywords: keywordMap(),
+	Cmp:      keywordCmp,
+	Resolve:  resolveSchema,
+	Docs:     keywordDocs(),
+	// Keywords next to $ref apply along with the referenced schema.
+	IgnoreRefSiblings: false,
+}
+
+func init() {
+	schema.RegisterVocabulary(Vocabulary, false)
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi31_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/openapi31"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in the OpenAPI 3.1 dialect.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "` + openapi31.SchemaID + `", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+// pets is a schema with a discriminator.
+const pets = `{
+	"$defs": {
+		"Cat": {"type": "object", "properties": {"petType": {"const": "Cat"}, "lives": {"type": "integer"}}, "required": ["lives"]},
+		"Dog": {"type": "object", "properties": {"petType": {"const": "dog"}, "bark": {"type": "string"}}, "required": ["bark"]}
+	},
+	"oneOf": [{"$ref": "#/$defs/Cat"}, {"$ref": "#/$defs/Dog"}],
+	"discriminator": {"propertyName": "petType", "mapping": {"dog": "#/$defs/Dog"}},
+	"example": {"petType": "Cat", "lives": 9},
+	"externalDocs": {"url": "https://example.com/pets"},
+	"xml": {"name": "pet"}
+}`
+
+func TestDiscriminator(t *testing.T) {
+	s := decode(t, pets)
+	tests := []struct {
+		instance string
+		valid    bool
+		// err is part of the error with Discriminator set.
+		err string
+	}{
+		{`{"petType": "Cat", "lives": 9}`, true, ""},
+		{`{"petType": "dog", "bark": "woof"}`, true, ""},
+		{`{"petType": "Cat", "lives": "many"}`, false, "oneOf/0"},
+		{`{"petType": "dog"}`, false, "oneOf/1"},
+		{`{"petType": "Bird"}`, false, `selects no "oneOf" schema`},
+		{`{"lives": 9}`, true, ""},
+	}
+	for _, test := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(test.instance), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		// By default the discriminator is only an annotation.
+		if err := s.Validate(v); (err == nil) != test.valid {
+			t.Errorf("%s: got error %v, want valid %t", test.instance, err, test.valid)
+		}
+
+		err := s.ValidateWithOpts(v, &schema.ValidateOpts{Discriminator: true})
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: with Discriminator: %v", test.instance, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: with Discriminator: got error %v, want %q", test.instance, err, test.err)
+		}
+	}
+}
+
+func TestAnyOfDiscriminator(t *testing.T) {
+	s := decode(t, strings.Replace(pets, `"oneOf"`, `"anyOf"`, 1))
+	opts := &schema.ValidateOpts{Discriminator: true}
+	if err := s.ValidateWithOpts(map[string]any{"petType": "dog", "bark": "woof"}, opts); err != nil {
+		t.Error(err)
+	}
+	err := s.ValidateWithOpts(map[string]any{"petType": "dog"}, opts)
+	if err == nil || !strings.Contains(err.Error(), "anyOf/1") {
+		t.Errorf("got error %v, want one for anyOf/1", err)
+	}
+}
+
+func TestMetaSchema(t *testing.T) {
+	meta := openapi31.MetaSchema()
+	var v any
+	if err := json.Unmarshal([]byte(pets), &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Validate(v); err != nil {
+		t.Errorf("valid schema: %v", err)
+	}
+	v.(map[string]any)["discriminator"] = map[string]any{"mapping": map[string]any{}}
+	if err := meta.Validate(v); err == nil {
+		t.Error("discriminator without propertyName: no error")
+	}
+}
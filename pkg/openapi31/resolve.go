@@ -0,0 +1,39 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi31
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// resolveSchema resolves references in a schema.
+// The OpenAPI 3.1 core keywords are the draft 2020-12 ones,
+// so we use the draft 2020-12 resolver. We wrap the loader
+// so that references to the OpenAPI meta-schemas are served
+// from the embedded copies, and so that remote schemas that don't
+// have a "$schema" keyword default to the OpenAPI dialect.
+func resolveSchema(s *schema.Schema, ropts *schema.ResolveOpts) error {
+	var nopts schema.ResolveOpts
+	if ropts != nil {
+		nopts = *ropts
+	}
+	next := nopts.Loader
+	nopts.Loader = schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+		ms, err := loadMetaSchema(uri)
+		if ms != nil || err != nil {
+			return ms, err
+		}
+		if next == nil {
+			return nil, errors.New("remote loading not permitted")
+		}
+		return next.Load(ctx, SchemaID, uri)
+	})
+	return draft202012.Vocabulary.Resolve(s, &nopts)
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package replay records failed validations in a replay file,
+// so that they can be reproduced elsewhere, such as when
+// a validation fails in production but not in a test.
+//
+// A [*Writer] is a [schema.Collector]; set it as the Collector of
+// the validation options to record each validation that fails:
+//
+//	w := replay.NewWriter(f)
+//	err := s.ValidateWithOpts(instance, &schema.ValidateOpts{Collector: w})
+//
+// Later, [Read] reads the file, and [Entry.Validate] validates
+// each recorded instance again with the recorded options.
+//
+// A replay file is a sequence of JSON objects, one per line, each of
+// which is the JSON encoding of an [Entry]. A schema is identified by
+// the SHA-256 hash of its JSON encoding, and is only written in full
+// the first time that it appears in the file. References to other
+// documents are not recorded; the schema must be read with a
+// loader that can load them.
+//
+// A replay file holds the instances that failed, which may be
+// sensitive: handle it as carefully as the instances themselves.
+package replay
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Entry is one recorded validation.
+type Entry struct {
+	// SchemaHash identifies the schema, as "sha256:"
+	// followed by the hexadecimal hash of its JSON encoding.
+	SchemaHash string `json:"schemaHash"`
+	// SchemaJSON is the JSON encoding of the schema, if this is the
+	// first entry for it in the file. [Read] sets it in every entry.
+	SchemaJSON json.RawMessage `json:"schema,omitempty"`
+	// Instance is the JSON encoding of the instance.
+	Instance json.RawMessage `json:"instance"`
+	// Options are the validation options.
+	Options Options `json:"options"`
+	// Errors are the validation errors that were reported.
+	Errors []*errors2.ValidationError `json:"errors,omitempty"`
+	// Time is when the validation happened.
+	Time time.Time `json:"time"`
+
+	// s is the decoded schema, set by Read.
+	s *schema.Schema
+}
+
+// Options are the validation options that are recorded:
+// those of [schema.ValidateOpts] that are flags.
+type Options struct {
+	ApplyDefaults    bool `json:"applyDefaults,omitempty"`
+	ValidateFormat   bool `json:"validateFormat,omitempty"`
+	ObjectContains   bool `json:"objectContains,omitempty"`
+	OneOfAnnotations bool `json:"oneOfAnnotations,omitempty"`
+	Discriminator    bool `json:"discriminator,omitempty"`
+	ValidateContent  bool `json:"validateContent,omitempty"`
+	DeferRequired    bool `json:"deferRequired,omitempty"`
+}
+
+// Writer writes the failed validations reported
+// to it to a replay file. Use [NewWriter] to get a Writer.
+// A Writer may be used concurrently.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+	// hashes maps the schemas written so far to their hashes.
+	hashes map[*schema.Schema]string
+	// seen records the hashes of the schemas written so far.
+	seen map[string]bool
+}
+
+// NewWriter returns a [Writer] that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		w:      w,
+		hashes: make(map[*schema.Schema]string),
+		seen:   make(map[string]bool),
+	}
+}
+
+// Collect implements [schema.Collector]. It records r if the
+// validation failed with a validation error; other reports are
+// ignored. An error writing the entry is reported by [Writer.Err].
+func (w *Writer) Collect(r *schema.ValidationReport) {
+	if r.Err == nil || !schema.IsValidationError(r.Err) {
+		return
+	}
+	var opts Options
+	if r.Opts != nil {
+		opts = Options{
+			ApplyDefaults:    r.Opts.ApplyDefaults,
+			ValidateFormat:   r.Opts.ValidateFormat,
+			ObjectContains:   r.Opts.ObjectContains,
+			OneOfAnnotations: r.Opts.OneOfAnnotations,
+			Discriminator:    r.Opts.Discriminator,
+			ValidateContent:  r.Opts.ValidateContent,
+			DeferRequired:    r.Opts.DeferRequired,
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return
+	}
+	w.err = w.write(r.Schema, r.Instance, opts, validationErrors(r.Err), time.Now())
+}
+
+// Record records a validation of instance against s that
+// failed with err, with the options opts, which may be nil.
+// It is for validations that don't use a Collector.
+func (w *Writer) Record(s *schema.Schema, instance any, opts *schema.ValidateOpts, err error) error {
+	w.Collect(&schema.ValidationReport{
+		Schema:   s,
+		Instance: instance,
+		Opts:     opts,
+		Err:      err,
+	})
+	return w.Err()
+}
+
+// Err returns the first error that occurred writing an entry.
+// Once there is an error, nothing more is written.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// write writes an entry. w.mu is held.
+func (w *Writer) write(s *schema.Schema, instance any, opts Options, errs []*errors2.ValidationError, t time.Time) error {
+	inst, err := json.Marshal(instance)
+	if err != nil {
+		return fmt.Errorf("recording instance: %v", err)
+	}
+	e := &Entry{
+		SchemaHash: w.hashes[s],
+		Instance:   inst,
+		Options:    opts,
+		Errors:     errs,
+		Time:       t,
+	}
+	if e.SchemaHash == "" {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("recording schema: %v", err)
+		}
+		e.SchemaHash = hash(data)
+		w.hashes[s] = e.SchemaHash
+		if !w.seen[e.SchemaHash] {
+			w.seen[e.SchemaHash] = true
+			e.SchemaJSON = data
+		}
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(line, '\n'))
+	return err
+}
+
+// hash returns the hash of the JSON encoding of a schema.
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// validationErrors returns the validation errors in err.
+func validationErrors(err error) []*errors2.ValidationError {
+	var ves *errors2.ValidationErrors
+	if errors.As(err, &ves) {
+		return ves.Errs
+	}
+	var ve *errors2.ValidationError
+	if errors.As(err, &ve) {
+		return []*errors2.ValidationError{ve}
+	}
+	return nil
+}
+
+// Read reads the entries of a replay file, and decodes their
+// schemas with d. If d is nil, a [schema.Decoder] with no
+// loader is used, so the schemas may not refer to other documents.
+func Read(r io.Reader, d *schema.Decoder) ([]*Entry, error) {
+	if d == nil {
+		d = &schema.Decoder{}
+	}
+	schemas := make(map[string]*Entry)
+	var entries []*Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 64<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		e := new(Entry)
+		if err := json.Unmarshal(sc.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("replay line %d: %v", line, err)
+		}
+		if first, ok := schemas[e.SchemaHash]; ok {
+			e.SchemaJSON = first.SchemaJSON
+			e.s = first.s
+		} else {
+			if e.SchemaJSON == nil {
+				return nil, fmt.Errorf("replay line %d: unknown schema %s", line, e.SchemaHash)
+			}
+			s, err := d.Decode(context.Background(), e.SchemaJSON, nil)
+			if err != nil {
+				return nil, fmt.Errorf("replay line %d: %v", line, err)
+			}
+			e.s = s
+			schemas[e.SchemaHash] = e
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Schema returns the decoded schema of an entry returned by [Read].
+func (e *Entry) Schema() *schema.Schema {
+	return e.s
+}
+
+// Validate validates the instance of an entry returned by [Read]
+// against its schema again, with the recorded options.
+// The error may be compared with e.Errors.
+func (e *Entry) Validate() error {
+	if e.s == nil {
+		return errors.New("replay entry has no decoded schema")
+	}
+	var instance any
+	if err := json.Unmarshal(e.Instance, &instance); err != nil {
+		return fmt.Errorf("decoding replayed instance: %v", err)
+	}
+	opts := &schema.ValidateOpts{
+		ApplyDefaults:    e.Options.ApplyDefaults,
+		ValidateFormat:   e.Options.ValidateFormat,
+		ObjectContains:   e.Options.ObjectContains,
+		OneOfAnnotations: e.Options.OneOfAnnotations,
+		Discriminator:    e.Options.Discriminator,
+		ValidateContent:  e.Options.ValidateContent,
+		DeferRequired:    e.Options.DeferRequired,
+	}
+	return e.s.ValidateWithOpts(instance, opts)
+}
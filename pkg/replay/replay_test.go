@@ -0,0 +1,99 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package replay_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	_ "github.com/altshiftab/jsonschema/pkg/format/net"
+	"github.com/altshiftab/jsonschema/pkg/replay"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode returns the schema src, in draft 2020-12.
+func decode(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	src = `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + strings.TrimPrefix(src, "{")
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return &s
+}
+
+func TestWriteRead(t *testing.T) {
+	s1 := decode(t, `{"type": "object", "required": ["id"]}`)
+	s2 := decode(t, `{"type": "string", "format": "email"}`)
+	// s3 has the same encoding as s1.
+	s3 := decode(t, `{"required": ["id"], "type": "object"}`)
+
+	var buf bytes.Buffer
+	w := replay.NewWriter(&buf)
+	formats := &schema.ValidateOpts{Collector: w, ValidateFormat: true}
+	s1.ValidateWithOpts(map[string]any{"id": 1.0}, formats)
+	s1.ValidateWithOpts(map[string]any{}, formats)
+	s1.ValidateWithOpts([]any{}, formats)
+	s2.ValidateWithOpts("not an email", formats)
+	// Without ValidateFormat, the instance is valid, so not recorded.
+	s2.ValidateWithOpts("not an email", &schema.ValidateOpts{Collector: w})
+	if err := w.Record(s3, map[string]any{"name": "a"}, nil, s3.Validate(map[string]any{"name": "a"})); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	// Only the first entry for each schema has the schema.
+	for i, want := range []bool{true, false, true, false} {
+		if got := strings.Contains(lines[i], `"schema":`); got != want {
+			t.Errorf("line %d has schema %t, want %t: %s", i+1, got, want, lines[i])
+		}
+	}
+
+	entries, err := replay.Read(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+	if entries[0].Schema() != entries[1].Schema() || entries[0].Schema() != entries[3].Schema() {
+		t.Error("entries for the same schema have different schemas")
+	}
+	for i, e := range entries {
+		if e.SchemaJSON == nil {
+			t.Errorf("entry %d has no schema", i)
+		}
+		if len(e.Errors) == 0 {
+			t.Errorf("entry %d has no errors", i)
+		}
+		if err := e.Validate(); err == nil {
+			t.Errorf("entry %d: replayed validation succeeded", i)
+		}
+	}
+	if !entries[2].Options.ValidateFormat || entries[3].Options.ValidateFormat {
+		t.Errorf("got options %+v and %+v", entries[2].Options, entries[3].Options)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, src := range []string{
+		`{`,
+		`{"schemaHash": "sha256:00", "instance": 1}`,
+		`{"schemaHash": "sha256:00", "schema": {"type": 1}, "instance": 1}`,
+	} {
+		if _, err := replay.Read(strings.NewReader(src+"\n"), nil); err == nil {
+			t.Errorf("%s: no error", src)
+		}
+	}
+	if err := new(replay.Entry).Validate(); err == nil {
+		t.Error("Validate of an entry that was not read: no error")
+	}
+}
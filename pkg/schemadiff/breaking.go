@@ -0,0 +1,418 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schemadiff
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+)
+
+// Keywords whose value is a map from names to subschemas.
+var schemaMapKeywords = map[string]bool{
+	"properties":        true,
+	"patternProperties": true,
+	"dependentSchemas":  true,
+	"$defs":             true,
+	"definitions":       true,
+}
+
+// Keywords whose value is a list of subschemas.
+var schemaListKeywords = map[string]bool{
+	"allOf":       true,
+	"anyOf":       true,
+	"oneOf":       true,
+	"prefixItems": true,
+}
+
+// Keywords whose value is a subschema. Before draft 2020-12,
+// items may also be a list of subschemas.
+var schemaKeywords = map[string]bool{
+	"not":                   true,
+	"if":                    true,
+	"then":                  true,
+	"else":                  true,
+	"items":                 true,
+	"additionalItems":       true,
+	"contains":              true,
+	"additionalProperties":  true,
+	"propertyNames":         true,
+	"unevaluatedItems":      true,
+	"unevaluatedProperties": true,
+	"contentSchema":         true,
+}
+
+// Keywords that constrain instances, other than those above.
+// A keyword that is not listed, such as title, or an unknown
+// keyword, is taken to be an annotation.
+var constraintKeywords = map[string]bool{
+	"type":              true,
+	"enum":              true,
+	"const":             true,
+	"multipleOf":        true,
+	"maximum":           true,
+	"exclusiveMaximum":  true,
+	"minimum":           true,
+	"exclusiveMinimum":  true,
+	"maxLength":         true,
+	"minLength":         true,
+	"pattern":           true,
+	"format":            true,
+	"contentEncoding":   true,
+	"contentMediaType":  true,
+	"maxItems":          true,
+	"minItems":          true,
+	"uniqueItems":       true,
+	"maxContains":       true,
+	"minContains":       true,
+	"maxProperties":     true,
+	"minProperties":     true,
+	"required":          true,
+	"dependentRequired": true,
+	"dependencies":      true,
+	"$ref":              true,
+	"$dynamicRef":       true,
+	"$recursiveRef":     true,
+}
+
+// Keywords that change how references are resolved,
+// any change to which may change what is valid.
+var coreKeywords = map[string]bool{
+	"$schema":          true,
+	"$id":              true,
+	"id":               true,
+	"$anchor":          true,
+	"$dynamicAnchor":   true,
+	"$recursiveAnchor": true,
+}
+
+// Keywords whose limit is raised by a larger value,
+// and lowered by a smaller one.
+var maxKeywords = map[string]bool{
+	"maximum":          true,
+	"exclusiveMaximum": true,
+	"maxLength":        true,
+	"maxItems":         true,
+	"maxContains":      true,
+	"maxProperties":    true,
+}
+
+// Keywords whose limit is raised by a smaller value,
+// and lowered by a larger one.
+var minKeywords = map[string]bool{
+	"minimum":          true,
+	"exclusiveMinimum": true,
+	"minLength":        true,
+	"minItems":         true,
+	"minContains":      true,
+	"minProperties":    true,
+}
+
+// location is where a change is in a schema,
+// as found by locate.
+type location struct {
+	// keyword is the keyword that holds the change,
+	// or "" if the change is to the root schema.
+	keyword string
+	// keywordPtr is the JSON pointer to the keyword.
+	keywordPtr string
+	// rest are the tokens after the keyword, within its value.
+	// For a keyword that holds subschemas, these are the name
+	// or index of the subschema.
+	rest []string
+	// schema is set if the change is to a whole subschema.
+	schema bool
+	// inverted is set if the change is within the subschema of not,
+	// if or oneOf, where accepting more may also reject instances.
+	inverted bool
+}
+
+// breaking reports whether the change c from the schema old to the
+// schema new may make an instance that old accepts invalid.
+// The answer is a conservative guess when the effect of the change
+// depends on the rest of the schema.
+func breaking(old, new any, c Change) bool {
+	loc := locate(old, new, c.Pointer)
+	kw := loc.keyword
+
+	if coreKeywords[kw] {
+		return true
+	}
+	if kw != "" && !constraintKeywords[kw] && !schemaKeywords[kw] && !schemaListKeywords[kw] && !schemaMapKeywords[kw] {
+		// An annotation.
+		return false
+	}
+	if loc.inverted {
+		return true
+	}
+
+	if loc.schema {
+		switch c.Kind {
+		case Added:
+			switch kw {
+			case "anyOf", "$defs", "definitions":
+				// Another branch of anyOf only adds instances,
+				// and a definition is unused until referred to.
+				return false
+			case "not":
+				return !restrictive(c.New)
+			case "oneOf", "if":
+				return true
+			}
+			return !permissive(c.New)
+		case Removed:
+			return removesSchemas(kw)
+		default:
+			switch kw {
+			case "not", "if", "oneOf":
+				return true
+			}
+			return !restrictive(c.Old)
+		}
+	}
+
+	if len(loc.rest) > 0 {
+		// The change is within the value of the keyword.
+		switch kw {
+		case "required":
+			return addsElement(lookup(old, loc.keywordPtr), lookup(new, loc.keywordPtr))
+		case "dependentRequired", "dependencies":
+			p := loc.keywordPtr + "/" + jsonpointer.EscapeToken(loc.rest[0])
+			return addsElement(lookup(old, p), lookup(new, p))
+		case "enum":
+			return addsElement(lookup(new, loc.keywordPtr), lookup(old, loc.keywordPtr))
+		case "type":
+			return !coversTypes(lookup(new, loc.keywordPtr), lookup(old, loc.keywordPtr))
+		}
+		return true
+	}
+
+	switch c.Kind {
+	case Added:
+		switch {
+		case kw == "uniqueItems":
+			return c.New == true
+		case minKeywords[kw] && kw != "minimum" && kw != "exclusiveMinimum":
+			n, ok := number(c.New)
+			return !ok || n > 0
+		case kw == "$defs" || kw == "definitions":
+			return false
+		}
+		return true
+
+	case Removed:
+		if kw == "minContains" {
+			// The default minContains is 1.
+			n, ok := number(c.Old)
+			return !ok || n < 1
+		}
+		return removesSchemas(kw)
+
+	default:
+		switch kw {
+		case "type":
+			return !coversTypes(c.New, c.Old)
+		case "uniqueItems":
+			return c.New == true
+		case "exclusiveMaximum", "exclusiveMinimum":
+			// In draft-04 these are booleans.
+			if _, ok := c.New.(bool); ok {
+				return c.New == true
+			}
+		case "multipleOf":
+			o, ok1 := number(c.Old)
+			n, ok2 := number(c.New)
+			if ok1 && ok2 && n != 0 {
+				// Every multiple of o is a multiple of n
+				// if o is a multiple of n.
+				q := o / n
+				return q != math.Trunc(q)
+			}
+			return true
+		}
+		o, ok1 := number(c.Old)
+		n, ok2 := number(c.New)
+		if ok1 && ok2 {
+			switch {
+			case maxKeywords[kw]:
+				return n < o
+			case minKeywords[kw]:
+				return n > o
+			}
+		}
+		return true
+	}
+}
+
+// removesSchemas reports whether removing subschemas of the keyword
+// kw may reject instances. Removing a branch of anyOf or oneOf may,
+// and so may removing a property schema or a prefixItems schema,
+// as the property or item may be left to additionalProperties
+// or items. Removing any other subschema only accepts more.
+func removesSchemas(kw string) bool {
+	switch kw {
+	case "anyOf", "oneOf", "properties", "patternProperties", "prefixItems":
+		return true
+	}
+	return false
+}
+
+// locate returns the location in the schemas old and new
+// of the JSON pointer ptr.
+func locate(old, new any, ptr string) location {
+	var toks []string
+	if ptr != "" {
+		toks = strings.Split(ptr[1:], "/")
+	}
+	for i, tok := range toks {
+		toks[i] = jsonpointer.UnescapeToken(tok)
+	}
+
+	loc := location{schema: true}
+	cur := ""
+	inverted := false
+	for i := 0; i < len(toks); {
+		// toks[i] is a keyword of a schema.
+		kw := toks[i]
+		kwPtr := cur + "/" + jsonpointer.EscapeToken(kw)
+		loc = location{keyword: kw, keywordPtr: kwPtr, rest: toks[i+1:], inverted: inverted}
+		if kw == "not" || kw == "if" || kw == "oneOf" {
+			inverted = true
+		}
+		i++
+		switch {
+		case schemaMapKeywords[kw], schemaListKeywords[kw]:
+			if i == len(toks) {
+				return loc
+			}
+			cur = kwPtr + "/" + jsonpointer.EscapeToken(toks[i])
+			i++
+		case kw == "items" || kw == "dependencies":
+			// items may be a list of subschemas, and the values
+			// of dependencies may be subschemas or lists of names.
+			if i == len(toks) {
+				return loc
+			}
+			if kw == "dependencies" || isList(old, new, kwPtr) {
+				p := kwPtr + "/" + jsonpointer.EscapeToken(toks[i])
+				if kw == "dependencies" && isList(old, new, p) {
+					return loc
+				}
+				cur = p
+				i++
+			} else {
+				cur = kwPtr
+			}
+		case schemaKeywords[kw]:
+			cur = kwPtr
+		default:
+			return loc
+		}
+		if i == len(toks) {
+			loc.schema = true
+		}
+	}
+	return loc
+}
+
+// isList reports whether the value at ptr
+// in either old or new is a list.
+func isList(old, new any, ptr string) bool {
+	_, ok1 := lookup(old, ptr).([]any)
+	_, ok2 := lookup(new, ptr).([]any)
+	return ok1 || ok2
+}
+
+// lookup returns the value at the JSON pointer ptr in v,
+// or nil if there is none.
+func lookup(v any, ptr string) any {
+	if ptr == "" {
+		return v
+	}
+	for tok := range strings.SplitSeq(ptr[1:], "/") {
+		tok = jsonpointer.UnescapeToken(tok)
+		switch x := v.(type) {
+		case map[string]any:
+			v = x[tok]
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil
+			}
+			v = x[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// permissive reports whether the schema v accepts everything.
+func permissive(v any) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	m, ok := v.(map[string]any)
+	return ok && len(m) == 0
+}
+
+// restrictive reports whether the schema v accepts nothing.
+func restrictive(v any) bool {
+	return v == false
+}
+
+// addsElement reports whether the list to
+// has an element that the list from does not.
+func addsElement(from, to any) bool {
+	fl, _ := from.([]any)
+	tl, _ := to.([]any)
+	have := make(map[string]bool, len(fl))
+	for _, e := range fl {
+		have[key(e)] = true
+	}
+	return slices.ContainsFunc(tl, func(e any) bool {
+		return !have[key(e)]
+	})
+}
+
+// key returns a string that identifies the JSON value v.
+func key(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// coversTypes reports whether the type keyword value to
+// allows every type that the value from allows.
+func coversTypes(to, from any) bool {
+	types := func(v any) []any {
+		if l, ok := v.([]any); ok {
+			return l
+		}
+		return []any{v}
+	}
+	tt := types(to)
+	for _, t := range types(from) {
+		if !slices.Contains(tt, t) && !(t == "integer" && slices.Contains(tt, any("number"))) {
+			return false
+		}
+	}
+	return true
+}
+
+// number returns v as a number, if it is one.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
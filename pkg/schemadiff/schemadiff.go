@@ -0,0 +1,198 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schemadiff reports the differences between two versions
+// of a schema, as a list of changes at JSON pointers.
+// The comparison is structural: it compares the JSON encodings of
+// the schemas, so it is not affected by the order of keywords,
+// but it does not know that, for example, two different
+// patterns may match the same strings.
+package schemadiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Kind is the kind of a [Change].
+type Kind int
+
+const (
+	// Added means the value is only in the new schema.
+	Added Kind = iota
+	// Removed means the value is only in the old schema.
+	Removed
+	// Changed means the value differs between the schemas.
+	Changed
+)
+
+// String returns a name for the kind.
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Change is a single difference between two schemas.
+type Change struct {
+	// Pointer is the JSON pointer to the value that differs.
+	Pointer string
+	// Kind is the kind of the change.
+	Kind Kind
+	// Old and New are the old and new values.
+	// Old is nil if Kind is Added, and New is nil if it is Removed.
+	Old, New any
+	// Breaking reports whether the change may make an instance
+	// that the old schema accepts invalid, such as a new required
+	// property or a lower maximum. This is a guess, from the
+	// keyword that changed: a change whose effect depends on the
+	// rest of the schema, such as one within a not keyword or
+	// to a $ref, is counted as breaking. A change to an annotation,
+	// such as a description, or to an unknown keyword, is not.
+	Breaking bool
+}
+
+// String returns a one-line description of the change.
+func (c Change) String() string {
+	ptr := "#" + c.Pointer
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", ptr, encode(c.New))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", ptr, encode(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", ptr, encode(c.Old), encode(c.New))
+	}
+}
+
+// Schemas returns the changes from old to new.
+// The result is sorted by pointer, and is empty if
+// the schemas have the same JSON encoding.
+func Schemas(old, new *schema.Schema) ([]Change, error) {
+	ov, err := jsonValue(old)
+	if err != nil {
+		return nil, err
+	}
+	nv, err := jsonValue(new)
+	if err != nil {
+		return nil, err
+	}
+	return JSON(ov, nv), nil
+}
+
+// JSON is like [Schemas] for two JSON values, such as schema
+// documents parsed by [encoding/json.Unmarshal] into an empty
+// interface value.
+func JSON(old, new any) []Change {
+	var changes []Change
+	diff(old, new, "", &changes)
+	for i := range changes {
+		changes[i].Breaking = breaking(old, new, changes[i])
+	}
+	return changes
+}
+
+// Breaking reports whether any of changes is breaking.
+func Breaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool {
+		return c.Breaking
+	})
+}
+
+// diff appends the changes from old to new at ptr to changes.
+func diff(old, new any, ptr string, changes *[]Change) {
+	switch ov := old.(type) {
+	case map[string]any:
+		nv, ok := new.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := slices.Sorted(maps.Keys(ov))
+		for k := range nv {
+			if _, ok := ov[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			kptr := ptr + "/" + jsonpointer.EscapeToken(k)
+			oe, inOld := ov[k]
+			ne, inNew := nv[k]
+			switch {
+			case !inNew:
+				*changes = append(*changes, Change{Pointer: kptr, Kind: Removed, Old: oe})
+			case !inOld:
+				*changes = append(*changes, Change{Pointer: kptr, Kind: Added, New: ne})
+			default:
+				diff(oe, ne, kptr, changes)
+			}
+		}
+		return
+
+	case []any:
+		nv, ok := new.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(ov), len(nv)) {
+			iptr := ptr + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(nv):
+				*changes = append(*changes, Change{Pointer: iptr, Kind: Removed, Old: ov[i]})
+			case i >= len(ov):
+				*changes = append(*changes, Change{Pointer: iptr, Kind: Added, New: nv[i]})
+			default:
+				diff(ov[i], nv[i], iptr, changes)
+			}
+		}
+		return
+
+	default:
+		if old == new {
+			return
+		}
+	}
+
+	*changes = append(*changes, Change{Pointer: ptr, Kind: Changed, Old: old, New: new})
+}
+
+// jsonValue returns s as a JSON value.
+func jsonValue(s *schema.Schema) (any, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// encode returns the JSON encoding of v for a description,
+// shortened if it is long.
+func encode(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	const maxLen = 60
+	if len(data) > maxLen {
+		return string(data[:maxLen-3]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schemadiff_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/schemadiff"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decode decodes a JSON value.
+func decode(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		old, new string
+		// want are the String forms of the changes.
+		want     []string
+		breaking bool
+	}{
+		{`{"type": "string"}`, `{"type": "string"}`, nil, false},
+		{
+			`{"title": "a"}`, `{"title": "b", "description": "d"}`,
+			[]string{`+ #/description: "d"`, `~ #/title: "a" -> "b"`},
+			false,
+		},
+		{`{"maximum": 10}`, `{"maximum": 20}`, []string{`~ #/maximum: 10 -> 20`}, false},
+		{`{"maximum": 10}`, `{"maximum": 5}`, []string{`~ #/maximum: 10 -> 5`}, true},
+		{`{"minLength": 2}`, `{"minLength": 1}`, []string{`~ #/minLength: 2 -> 1`}, false},
+		{`{"minLength": 2}`, `{"minLength": 3}`, []string{`~ #/minLength: 2 -> 3`}, true},
+		{`{}`, `{"minItems": 0}`, []string{`+ #/minItems: 0`}, false},
+		{`{}`, `{"minItems": 1}`, []string{`+ #/minItems: 1`}, true},
+		{`{"multipleOf": 4}`, `{"multipleOf": 2}`, []string{`~ #/multipleOf: 4 -> 2`}, false},
+		{`{"multipleOf": 2}`, `{"multipleOf": 4}`, []string{`~ #/multipleOf: 2 -> 4`}, true},
+		{`{"required": ["a"]}`, `{"required": ["a", "b"]}`, []string{`+ #/required/1: "b"`}, true},
+		{`{"required": ["a", "b"]}`, `{"required": ["a"]}`, []string{`- #/required/1: "b"`}, false},
+		{`{"enum": [1]}`, `{"enum": [1, 2]}`, []string{`+ #/enum/1: 2`}, false},
+		{`{"enum": [1, 2]}`, `{"enum": [1]}`, []string{`- #/enum/1: 2`}, true},
+		{`{"type": "integer"}`, `{"type": "number"}`, []string{`~ #/type: "integer" -> "number"`}, false},
+		{`{"type": "number"}`, `{"type": "integer"}`, []string{`~ #/type: "number" -> "integer"`}, true},
+		{`{"type": ["string"]}`, `{"type": ["string", "null"]}`, []string{`+ #/type/1: "null"`}, false},
+		{
+			`{"properties": {"a": {"type": "string"}}}`, `{"properties": {"a": {"type": "string", "maxLength": 3}}}`,
+			[]string{`+ #/properties/a/maxLength: 3`},
+			true,
+		},
+		{
+			`{"not": {"maximum": 3}}`, `{"not": {"maximum": 5}}`,
+			[]string{`~ #/not/maximum: 3 -> 5`},
+			true,
+		},
+		{`{"anyOf": [{"type": "string"}]}`, `{"anyOf": [{"type": "string"}, {"type": "null"}]}`, []string{`+ #/anyOf/1: {"type":"null"}`}, false},
+		{`{"$defs": {}}`, `{"$defs": {"a": {"type": "string"}}}`, []string{`+ #/$defs/a: {"type":"string"}`}, false},
+		{`{"$id": "https://a.example/"}`, `{"$id": "https://b.example/"}`, []string{`~ #/$id: "https://a.example/" -> "https://b.example/"`}, true},
+		{`{"x-a/b": 1}`, `{"x-a/b": 2}`, []string{`~ #/x-a~1b: 1 -> 2`}, false},
+		{`{"items": {}}`, `{"items": []}`, []string{`~ #/items: {} -> []`}, true},
+	}
+	for _, test := range tests {
+		changes := schemadiff.JSON(decode(t, test.old), decode(t, test.new))
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s -> %s: got %q, want %q", test.old, test.new, got, test.want)
+		}
+		if b := schemadiff.Breaking(changes); b != test.breaking {
+			t.Errorf("%s -> %s: breaking %t, want %t", test.old, test.new, b, test.breaking)
+		}
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	unmarshal := func(src string) *schema.Schema {
+		var s schema.Schema
+		if err := json.Unmarshal([]byte(src), &s); err != nil {
+			t.Fatal(err)
+		}
+		return &s
+	}
+	old := unmarshal(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "required": ["a"]}`)
+	// The order of the keywords doesn't matter.
+	same := unmarshal(`{"required": ["a"], "type": "object", "$schema": "https://json-schema.org/draft/2020-12/schema"}`)
+	changes, err := schemadiff.Schemas(old, same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("got %v, want no changes", changes)
+	}
+
+	new := unmarshal(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object"}`)
+	changes, err = schemadiff.Schemas(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Pointer != "/required" || changes[0].Kind != schemadiff.Removed || changes[0].Breaking {
+		t.Errorf("got %v, want the removal of required", changes)
+	}
+}
+
+func TestKindString(t *testing.T) {
+	for k, want := range map[schemadiff.Kind]string{
+		schemadiff.Added:   "added",
+		schemadiff.Removed: "removed",
+		schemadiff.Changed: "changed",
+		schemadiff.Kind(7): "Kind(7)",
+	} {
+		if got := k.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schemaregistry fetches JSON schemas from a schema registry
+// that implements the Confluent Schema Registry HTTP API, such as
+// the registries used with Kafka. Schemas are cached, and references
+// to other registered schemas are resolved through the registry,
+// so that messages can be validated against the schema they were
+// produced with.
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// Latest is the version that refers to the latest version of a subject.
+const Latest = "latest"
+
+// Client is a client of a schema registry.
+// A Client may be used by multiple goroutines simultaneously.
+type Client struct {
+	// BaseURL is the URL of the registry, such as
+	// "https://registry.example.com" or "http://localhost:8081".
+	BaseURL string
+	// HTTPClient is the client used to make requests.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+	// Username and Password, if not empty, are sent using
+	// HTTP basic authentication, as for an API key and secret.
+	Username, Password string
+	// SchemaID is the default schema ID, such as
+	// [draft202012.SchemaID], for registered schemas
+	// without a $schema keyword. If empty, the default
+	// vocabulary is used.
+	SchemaID string
+	// Environment, if not nil, holds the vocabularies that
+	// registered schemas are decoded with. If nil, the globally
+	// registered vocabularies are used.
+	Environment *schema.Environment
+
+	mu sync.Mutex
+	// byID caches resolved schemas by registry ID.
+	// IDs are never reused, so entries are never stale.
+	byID map[int]*schema.Schema
+	// versions caches registry IDs by subject and version.
+	// The latest version is not cached, as it can change.
+	versions map[subjectVersion]int
+	// raw caches registry responses by registry ID.
+	raw map[int]*registered
+}
+
+// subjectVersion is a key of Client.versions.
+type subjectVersion struct {
+	subject, version string
+}
+
+// registered is a schema as returned by the registry.
+type registered struct {
+	ID         int         `json:"id"`
+	Version    int         `json:"version"`
+	SchemaType string      `json:"schemaType"`
+	Schema     string      `json:"schema"`
+	References []reference `json:"references"`
+}
+
+// reference is a reference from a registered schema to another one.
+// The Name is the URI used by $ref in the referencing schema.
+type reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Error is an error returned by the registry.
+type Error struct {
+	// StatusCode is the HTTP status code.
+	StatusCode int
+	// Code is the registry error code, such as 40401
+	// for a subject that is not found. It may be 0.
+	Code int `json:"error_code"`
+	// Message is the registry error message.
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("schema registry: HTTP status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("schema registry: error %d: %s", e.Code, e.Message)
+}
+
+// Schema returns the resolved schema registered under subject
+// with the given version, which is a version number or [Latest].
+// It also returns the registry ID of the schema.
+func (c *Client) Schema(ctx context.Context, subject, version string) (*schema.Schema, int, error) {
+	key := subjectVersion{subject, version}
+	c.mu.Lock()
+	id, ok := c.versions[key]
+	c.mu.Unlock()
+	if !ok {
+		r, err := c.fetchVersion(ctx, subject, version)
+		if err != nil {
+			return nil, 0, err
+		}
+		id = r.ID
+		if version != Latest {
+			c.mu.Lock()
+			if c.versions == nil {
+				c.versions = make(map[subjectVersion]int)
+			}
+			c.versions[key] = id
+			c.mu.Unlock()
+		}
+	}
+	s, err := c.SchemaByID(ctx, id)
+	return s, id, err
+}
+
+// SchemaByID returns the resolved schema with the given registry ID.
+func (c *Client) SchemaByID(ctx context.Context, id int) (*schema.Schema, error) {
+	c.mu.Lock()
+	s, ok := c.byID[id]
+	c.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	r, err := c.fetchID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s, err = c.resolve(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("schema registry: schema %d: %v", id, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cs, ok := c.byID[id]; ok {
+		// Another goroutine got there first.
+		return cs, nil
+	}
+	if c.byID == nil {
+		c.byID = make(map[int]*schema.Schema)
+	}
+	c.byID[id] = s
+	return s, nil
+}
+
+// ValidateMessage validates a message in the Confluent wire format,
+// which is a zero byte, a 4-byte big-endian schema registry ID,
+// and the JSON encoding of the value. It returns the decoded value.
+// If the value is not valid, the error is a validation error.
+func (c *Client) ValidateMessage(ctx context.Context, msg []byte) (any, error) {
+	if len(msg) < 5 || msg[0] != 0 {
+		return nil, errors.New("schema registry: message is not in the wire format")
+	}
+	id := int(binary.BigEndian.Uint32(msg[1:5]))
+	s, err := c.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(msg[5:], &v); err != nil {
+		return nil, fmt.Errorf("schema registry: decoding message: %v", err)
+	}
+	if err := s.Validate(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// resolve builds and resolves the schema in r.
+// References to other registered schemas are loaded
+// from the registry.
+func (c *Client) resolve(ctx context.Context, r *registered) (*schema.Schema, error) {
+	// The registry doesn't give schemas a URI,
+	// so we make one up for resolving references.
+	uri, err := url.Parse(c.baseURL() + "/schemas/ids/" + strconv.Itoa(r.ID))
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.build(ctx, uri, r)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]reference)
+	addRefs := func(base *url.URL, rs []reference) {
+		for _, ref := range rs {
+			refs[ref.Name] = ref
+			if u, err := base.Parse(ref.Name); err == nil {
+				refs[u.String()] = ref
+			}
+		}
+	}
+	addRefs(uri, r.References)
+
+	ropts := &schema.ResolveOpts{
+		URI: uri,
+		Loader: schema.LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*schema.Schema, error) {
+			ref, ok := refs[uri.String()]
+			if !ok {
+				// A schema with an $id changes the base URI of
+				// its references, so also try the last element.
+				ref, ok = refs[uri.Path[strings.LastIndex(uri.Path, "/")+1:]]
+			}
+			if !ok {
+				return nil, fmt.Errorf("%q is not a reference of the registered schema", uri)
+			}
+			rr, err := c.fetchVersion(ctx, ref.Subject, strconv.Itoa(ref.Version))
+			if err != nil {
+				return nil, err
+			}
+			addRefs(uri, rr.References)
+			return c.build(ctx, uri, rr)
+		}),
+		Context: ctx,
+	}
+	if c.SchemaID != "" {
+		if c.Environment != nil {
+			ropts.Vocabulary = c.Environment.LookupVocabulary(c.SchemaID)
+		} else {
+			ropts.Vocabulary = schema.LookupVocabulary(c.SchemaID)
+		}
+	}
+	if err := s.Resolve(ropts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// build builds the unresolved schema in r.
+func (c *Client) build(ctx context.Context, uri *url.URL, r *registered) (*schema.Schema, error) {
+	if r.SchemaType != "JSON" {
+		typ := r.SchemaType
+		if typ == "" {
+			typ = "AVRO"
+		}
+		return nil, fmt.Errorf("registered schema %d has type %s, not JSON", r.ID, typ)
+	}
+	if c.Environment != nil {
+		return c.Environment.SchemaFromJSONWithSource(c.SchemaID, uri, []byte(r.Schema))
+	}
+	return schema.SchemaFromJSONContext(ctx, c.SchemaID, uri, []byte(r.Schema))
+}
+
+// fetchVersion fetches the schema for a subject and version.
+func (c *Client) fetchVersion(ctx context.Context, subject, version string) (*registered, error) {
+	var r registered
+	path := "/subjects/" + url.PathEscape(subject) + "/versions/" + url.PathEscape(version)
+	if err := c.get(ctx, path, &r); err != nil {
+		return nil, err
+	}
+	if r.ID != 0 {
+		c.mu.Lock()
+		if c.raw == nil {
+			c.raw = make(map[int]*registered)
+		}
+		c.raw[r.ID] = &r
+		c.mu.Unlock()
+	}
+	return &r, nil
+}
+
+// fetchID fetches the schema with a registry ID.
+func (c *Client) fetchID(ctx context.Context, id int) (*registered, error) {
+	c.mu.Lock()
+	r, ok := c.raw[id]
+	c.mu.Unlock()
+	if ok {
+		return r, nil
+	}
+
+	r = new(registered)
+	if err := c.get(ctx, "/schemas/ids/"+strconv.Itoa(id), r); err != nil {
+		return nil, err
+	}
+	// This endpoint doesn't return the ID.
+	r.ID = id
+	c.mu.Lock()
+	if c.raw == nil {
+		c.raw = make(map[int]*registered)
+	}
+	c.raw[id] = r
+	c.mu.Unlock()
+	return r, nil
+}
+
+// get makes a GET request to path, and decodes the response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("schema registry: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		e := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, e) != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(data))
+		}
+		return e
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("schema registry: decoding response: %v", err)
+	}
+	return nil
+}
+
+// baseURL returns the base URL without a trailing slash.
+func (c *Client) baseURL() string {
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
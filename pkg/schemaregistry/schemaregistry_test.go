@@ -0,0 +1,191 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schemaregistry_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/schemaregistry"
+)
+
+// registry is a fake schema registry.
+type registry struct {
+	mu sync.Mutex
+	// responses maps paths to response bodies.
+	responses map[string]any
+	// requests counts the requests for each path.
+	requests map[string]int
+}
+
+func (r *registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, pass, _ := req.BasicAuth(); user != "key" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error_code": 401, "message": "Unauthorized"}`))
+		return
+	}
+	r.requests[req.URL.Path]++
+	resp, ok := r.responses[req.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code": 40401, "message": "Subject not found"}`))
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// newRegistry starts a fake registry, and returns a client of it.
+func newRegistry(t *testing.T) (*registry, *schemaregistry.Client) {
+	r := &registry{
+		responses: map[string]any{
+			"/subjects/address/versions/1": map[string]any{
+				"id":         1,
+				"version":    1,
+				"schemaType": "JSON",
+				"schema":     `{"type": "object", "properties": {"zip": {"type": "string"}}, "required": ["zip"]}`,
+			},
+			"/subjects/person/versions/latest": map[string]any{
+				"id":         2,
+				"version":    3,
+				"schemaType": "JSON",
+				"schema":     `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "properties": {"name": {"type": "string"}, "address": {"$ref": "address.json"}}}`,
+				"references": []any{
+					map[string]any{"name": "address.json", "subject": "address", "version": 1},
+				},
+			},
+			"/schemas/ids/2": map[string]any{
+				"schemaType": "JSON",
+				"schema":     `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "properties": {"name": {"type": "string"}, "address": {"$ref": "address.json"}}}`,
+				"references": []any{
+					map[string]any{"name": "address.json", "subject": "address", "version": 1},
+				},
+			},
+			"/schemas/ids/3": map[string]any{
+				"schema": `{"type": "record", "name": "x", "fields": []}`,
+			},
+		},
+		requests: make(map[string]int),
+	}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return r, &schemaregistry.Client{
+		BaseURL:    srv.URL + "/",
+		HTTPClient: srv.Client(),
+		Username:   "key",
+		Password:   "secret",
+	}
+}
+
+// message returns a message in the wire format.
+func message(id uint32, value string) []byte {
+	msg := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(msg[1:], id)
+	return append(msg, value...)
+}
+
+func TestSchema(t *testing.T) {
+	ctx := context.Background()
+	r, c := newRegistry(t)
+
+	s, id, err := c.Schema(ctx, "person", schemaregistry.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 {
+		t.Errorf("got ID %d, want 2", id)
+	}
+	if err := s.Validate(map[string]any{"name": "a", "address": map[string]any{"zip": "1"}}); err != nil {
+		t.Errorf("valid person: %v", err)
+	}
+	if err := s.Validate(map[string]any{"name": "a", "address": map[string]any{}}); err == nil {
+		t.Error("person with an invalid address: no error")
+	}
+
+	// The schema is cached by ID, but the latest
+	// version is looked up each time.
+	if _, _, err := c.Schema(ctx, "person", schemaregistry.Latest); err != nil {
+		t.Fatal(err)
+	}
+	if n := r.requests["/subjects/person/versions/latest"]; n != 2 {
+		t.Errorf("latest version fetched %d times, want 2", n)
+	}
+	if n := r.requests["/schemas/ids/2"]; n != 0 {
+		t.Errorf("schema 2 fetched by ID %d times, want 0", n)
+	}
+
+	// A referenced schema without $schema uses the default vocabulary.
+	s, id, err = c.Schema(ctx, "address", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 {
+		t.Errorf("got ID %d, want 1", id)
+	}
+	if err := s.Validate(map[string]any{}); err == nil {
+		t.Error("address without zip: no error")
+	}
+	if _, _, err := c.Schema(ctx, "address", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := r.requests["/subjects/address/versions/1"]; n != 2 {
+		// Once to resolve person, and once for Schema;
+		// after that the version is cached.
+		t.Errorf("address version 1 fetched %d times, want 2", n)
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	ctx := context.Background()
+	_, c := newRegistry(t)
+
+	_, _, err := c.Schema(ctx, "nobody", "1")
+	var re *schemaregistry.Error
+	if !errors.As(err, &re) || re.StatusCode != http.StatusNotFound || re.Code != 40401 {
+		t.Errorf("missing subject: got %v", err)
+	}
+
+	if _, err := c.SchemaByID(ctx, 3); err == nil {
+		t.Error("Avro schema: no error")
+	}
+
+	c.Password = "wrong"
+	if _, err := c.SchemaByID(ctx, 2); !errors.As(err, &re) || re.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong password: got %v", err)
+	}
+}
+
+func TestValidateMessage(t *testing.T) {
+	ctx := context.Background()
+	r, c := newRegistry(t)
+
+	v, err := c.ValidateMessage(ctx, message(2, `{"name": "a", "address": {"zip": "1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := v.(map[string]any); !ok || m["name"] != "a" {
+		t.Errorf("got %v", v)
+	}
+	if _, err := c.ValidateMessage(ctx, message(2, `{"name": 1}`)); err == nil {
+		t.Error("invalid value: no error")
+	}
+	if n := r.requests["/schemas/ids/2"]; n != 1 {
+		t.Errorf("schema 2 fetched %d times, want 1", n)
+	}
+
+	for _, msg := range [][]byte{nil, {0, 0, 0}, append([]byte{1}, message(2, `{}`)[1:]...), message(2, `{`)} {
+		if _, err := c.ValidateMessage(ctx, msg); err == nil {
+			t.Errorf("%q: no error", msg)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+)
+
+// FeatureKeyword is the extension keyword that makes a subschema
+// conditional on a feature. Its value is a feature name,
+// or an array of feature names that must all be enabled:
+//
+//	"properties": {
+//		"beta": {"x-if-feature": "beta", "type": "string"}
+//	}
+const FeatureKeyword = "x-if-feature"
+
+// Prune returns a copy of the JSON value v, as produced by
+// [encoding/json.Unmarshal] into an empty interface value,
+// with every object whose [FeatureKeyword] names a feature
+// that is not in features removed from its parent object or array.
+// When an object is removed from "properties", the property name
+// is also removed from a "required" keyword next to "properties".
+// The [FeatureKeyword] keyword is removed from the objects that remain.
+// It is an error for the root schema to be removed.
+func Prune(v any, features []string) (any, error) {
+	p := &pruner{features: features}
+	nv, keep, err := p.prune(v, "")
+	if err != nil {
+		return nil, err
+	}
+	if !keep {
+		return nil, fmt.Errorf("%s: root schema requires a disabled feature", pointer(""))
+	}
+	return nv, nil
+}
+
+// pruner holds the state used by Prune.
+type pruner struct {
+	features []string
+}
+
+// prune returns a copy of v with disabled objects removed.
+// The bool result is false if v itself should be removed.
+// ptr is the JSON pointer to v, used in error messages.
+func (p *pruner) prune(v any, ptr string) (any, bool, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if f, ok := v[FeatureKeyword]; ok {
+			enabled, err := p.enabled(f, ptr)
+			if err != nil {
+				return nil, false, err
+			}
+			if !enabled {
+				return nil, false, nil
+			}
+		}
+
+		m := make(map[string]any, len(v))
+		for k, ev := range v {
+			if k == FeatureKeyword {
+				continue
+			}
+			nv, keep, err := p.prune(ev, ptr+"/"+jsonpointer.EscapeToken(k))
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				m[k] = nv
+			}
+		}
+
+		// A property that was pruned is no longer required.
+		oldProps, _ := v["properties"].(map[string]any)
+		newProps, _ := m["properties"].(map[string]any)
+		if req, ok := m["required"].([]any); ok && len(newProps) < len(oldProps) {
+			m["required"] = slices.DeleteFunc(req, func(e any) bool {
+				name, _ := e.(string)
+				_, was := oldProps[name]
+				_, is := newProps[name]
+				return was && !is
+			})
+		}
+
+		return m, true, nil
+
+	case []any:
+		a := make([]any, 0, len(v))
+		for i, ev := range v {
+			nv, keep, err := p.prune(ev, fmt.Sprintf("%s/%d", ptr, i))
+			if err != nil {
+				return nil, false, err
+			}
+			if keep {
+				a = append(a, nv)
+			}
+		}
+		return a, true, nil
+
+	default:
+		return v, true, nil
+	}
+}
+
+// enabled reports whether the features named by the
+// [FeatureKeyword] value f are all enabled.
+func (p *pruner) enabled(f any, ptr string) (bool, error) {
+	switch f := f.(type) {
+	case string:
+		return slices.Contains(p.features, f), nil
+	case []any:
+		for _, e := range f {
+			name, ok := e.(string)
+			if !ok {
+				return false, fmt.Errorf("%s: %s array contains %T, want string", pointer(ptr), FeatureKeyword, e)
+			}
+			if !slices.Contains(p.features, name) {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("%s: %s value is %T, want string or array of strings", pointer(ptr), FeatureKeyword, f)
+	}
+}
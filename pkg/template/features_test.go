@@ -0,0 +1,87 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/template"
+)
+
+func TestPrune(t *testing.T) {
+	src := `{
+		"type": "object",
+		"required": ["id", "beta", "audit"],
+		"properties": {
+			"id": {"type": "string"},
+			"beta": {"x-if-feature": "beta", "type": "string"},
+			"audit": {"x-if-feature": ["beta", "audit"], "type": "object"}
+		},
+		"anyOf": [
+			{"x-if-feature": "beta", "required": ["beta"]},
+			{"required": ["id"]}
+		]
+	}`
+	tests := []struct {
+		features []string
+		want     string
+	}{
+		{nil, `{
+			"type": "object",
+			"required": ["id"],
+			"properties": {"id": {"type": "string"}},
+			"anyOf": [{"required": ["id"]}]
+		}`},
+		{[]string{"beta"}, `{
+			"type": "object",
+			"required": ["id", "beta"],
+			"properties": {"id": {"type": "string"}, "beta": {"type": "string"}},
+			"anyOf": [{"required": ["beta"]}, {"required": ["id"]}]
+		}`},
+		{[]string{"audit", "beta"}, `{
+			"type": "object",
+			"required": ["id", "beta", "audit"],
+			"properties": {
+				"id": {"type": "string"},
+				"beta": {"type": "string"},
+				"audit": {"type": "object"}
+			},
+			"anyOf": [{"required": ["beta"]}, {"required": ["id"]}]
+		}`},
+	}
+	for _, test := range tests {
+		v := parse(t, src)
+		got, err := template.Prune(v, test.features)
+		if err != nil {
+			t.Errorf("%q: %v", test.features, err)
+			continue
+		}
+		if want := parse(t, test.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", test.features, got, want)
+		}
+		if !reflect.DeepEqual(v, parse(t, src)) {
+			t.Errorf("%q: Prune modified its argument", test.features)
+		}
+	}
+}
+
+func TestPruneErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`{"x-if-feature": "beta"}`, "root schema requires a disabled feature"},
+		{`{"properties": {"a": {"x-if-feature": 1}}}`, "/properties/a: x-if-feature value is float64"},
+		{`{"properties": {"a": {"x-if-feature": [1, "beta"]}}}`, "x-if-feature array contains float64"},
+	}
+	for _, test := range tests {
+		_, err := template.Prune(parse(t, test.src), nil)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.src, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package template implements parameterized schemas.
+// A schema template is a JSON schema in which some values are
+// placeholders of the form
+//
+//	{"$param": "limit"}
+//
+// Expand replaces each placeholder with the value of the named
+// parameter, so that a single schema source can produce variants
+// for different tenants or environments.
+//
+// Prune removes the subschemas that are marked with [FeatureKeyword]
+// for features that are not enabled, so that a single schema source
+// can cover several product variants.
+//
+// Placeholders and feature markers are processed wherever they appear,
+// including within the values of keywords such as "const" and "default".
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/altshiftab/jsonschema/pkg/jsonpointer"
+)
+
+// ParamKeyword is the name of the placeholder key.
+const ParamKeyword = "$param"
+
+// Expand returns a copy of the JSON value v, as produced by
+// [encoding/json.Unmarshal] into an empty interface value,
+// with every placeholder replaced by the named value in params.
+// The parameter values must be encodable as JSON;
+// they are converted to the types that json.Unmarshal produces.
+// It is an error for a placeholder to name a missing parameter.
+func Expand(v any, params map[string]any) (any, error) {
+	e := &expander{
+		params: params,
+		values: make(map[string]any),
+	}
+	return e.expand(v, "")
+}
+
+// Params returns the sorted names of the parameters used by the
+// JSON value v. This can be used to check a template before
+// calling Expand.
+func Params(v any) []string {
+	seen := make(map[string]bool)
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if name, ok := placeholder(v); ok {
+				seen[name] = true
+				return
+			}
+			for _, e := range v {
+				walk(e)
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+
+	ret := make([]string, 0, len(seen))
+	for name := range seen {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// expander holds the state used by Expand.
+type expander struct {
+	params map[string]any
+	// values caches parameter values converted to JSON values.
+	values map[string]any
+}
+
+// expand returns a copy of v with placeholders replaced.
+// ptr is the JSON pointer to v, used in error messages.
+func (e *expander) expand(v any, ptr string) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if name, ok := placeholder(v); ok {
+			return e.value(name, ptr)
+		}
+		if p, ok := v[ParamKeyword]; ok {
+			if _, ok := p.(string); !ok {
+				return nil, fmt.Errorf("%s: %s value is %T, want string", pointer(ptr), ParamKeyword, p)
+			}
+			return nil, fmt.Errorf("%s: %s placeholder has other keys", pointer(ptr), ParamKeyword)
+		}
+		m := make(map[string]any, len(v))
+		for k, ev := range v {
+			nv, err := e.expand(ev, ptr+"/"+jsonpointer.EscapeToken(k))
+			if err != nil {
+				return nil, err
+			}
+			m[k] = nv
+		}
+		return m, nil
+
+	case []any:
+		a := make([]any, len(v))
+		for i, ev := range v {
+			nv, err := e.expand(ev, fmt.Sprintf("%s/%d", ptr, i))
+			if err != nil {
+				return nil, err
+			}
+			a[i] = nv
+		}
+		return a, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// value returns the JSON value of the named parameter.
+func (e *expander) value(name, ptr string) (any, error) {
+	if v, ok := e.values[name]; ok {
+		return copyValue(v), nil
+	}
+	p, ok := e.params[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: no value for parameter %q", pointer(ptr), name)
+	}
+
+	// Convert the value to the types json.Unmarshal produces,
+	// which are the types the schema package expects.
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q: %v", name, err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("parameter %q: %v", name, err)
+	}
+	e.values[name] = v
+	return copyValue(v), nil
+}
+
+// placeholder reports whether m is a placeholder,
+// and returns the parameter name.
+func placeholder(m map[string]any) (string, bool) {
+	if len(m) != 1 {
+		return "", false
+	}
+	name, ok := m[ParamKeyword].(string)
+	return name, ok
+}
+
+// copyValue returns a deep copy of a JSON value,
+// so that parameters used more than once don't share storage.
+func copyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = copyValue(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// pointer returns ptr for use in an error message.
+func pointer(ptr string) string {
+	if ptr == "" {
+		return "schema root"
+	}
+	return ptr
+}
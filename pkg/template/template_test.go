@@ -0,0 +1,95 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package template_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/template"
+)
+
+// parse returns the JSON value src.
+func parse(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return v
+}
+
+func TestExpand(t *testing.T) {
+	src := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "maxLength": {"$param": "maxName"}},
+			"tier": {"enum": {"$param": "tiers"}, "default": {"$param": "tier"}},
+			"tags": {"items": {"$param": "tag"}, "prefixItems": [{"$param": "tag"}]}
+		}
+	}`
+	v := parse(t, src)
+	if got, want := template.Params(v), []string{"maxName", "tag", "tier", "tiers"}; !slices.Equal(got, want) {
+		t.Errorf("Params = %q, want %q", got, want)
+	}
+
+	type limit struct {
+		Max int `json:"maximum"`
+	}
+	got, err := template.Expand(v, map[string]any{
+		"maxName": 40,
+		"tiers":   []string{"free", "pro"},
+		"tier":    "free",
+		"tag":     limit{Max: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := parse(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "maxLength": 40},
+			"tier": {"enum": ["free", "pro"], "default": "free"},
+			"tags": {"items": {"maximum": 3}, "prefixItems": [{"maximum": 3}]}
+		}
+	}`)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expand:\ngot  %v\nwant %v", got, want)
+	}
+
+	// A parameter used twice doesn't share storage.
+	tags := got.(map[string]any)["properties"].(map[string]any)["tags"].(map[string]any)
+	tags["items"].(map[string]any)["maximum"] = 4.0
+	if m := tags["prefixItems"].([]any)[0].(map[string]any)["maximum"]; m != 3.0 {
+		t.Errorf("changing one use of a parameter changed another to %v", m)
+	}
+	// The template is not modified.
+	if !reflect.DeepEqual(v, parse(t, src)) {
+		t.Error("Expand modified the template")
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		params map[string]any
+		err    string
+	}{
+		{`{"maximum": {"$param": "max"}}`, nil, `/maximum: no value for parameter "max"`},
+		{`{"maximum": {"$param": 1}}`, nil, "$param value is float64, want string"},
+		{`{"maximum": {"$param": "max", "x": 1}}`, map[string]any{"max": 1}, "placeholder has other keys"},
+		{`{"const": {"$param": "c"}}`, map[string]any{"c": func() {}}, `parameter "c"`},
+		{`{"$param": "x"}`, nil, `schema root: no value for parameter "x"`},
+	}
+	for _, test := range tests {
+		_, err := template.Expand(parse(t, test.src), test.params)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.src, err, test.err)
+		}
+	}
+}
@@ -1,5 +1,19 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package arg_type defines the types of the arguments of
+// schema keywords. This is the single definition used by the
+// schema package, the Builder, and the code generators.
 package arg_type
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
 // ArgType is an enumeration of the possible schema part types.
 type ArgType int
 
@@ -16,5 +30,77 @@ const (
 	ArgTypeSchemaOrSchemas
 	ArgTypeMapArrayOrSchema
 	ArgTypeAny
+	ArgTypeMapMapSchema
 )
 
+// info describes an ArgType.
+type info struct {
+	name   string // the name, as in the constant ArgType<name>
+	goType string // the Go type of the argument, for generated code
+	schema bool   // whether the argument holds subschemas
+}
+
+// infos maps each ArgType to its description.
+var infos = map[ArgType]info{
+	ArgTypeBool:             {"Bool", "bool", false},
+	ArgTypeString:           {"String", "string", false},
+	ArgTypeStrings:          {"Strings", "[]string", false},
+	ArgTypeStringOrStrings:  {"StringOrStrings", "schema.PartStringOrStrings", false},
+	ArgTypeInt:              {"Int", "int64", false},
+	ArgTypeFloat:            {"Float", "float64", false},
+	ArgTypeSchema:           {"Schema", "*schema.Schema", true},
+	ArgTypeSchemas:          {"Schemas", "[]*schema.Schema", true},
+	ArgTypeMapSchema:        {"MapSchema", "map[string]*schema.Schema", true},
+	ArgTypeSchemaOrSchemas:  {"SchemaOrSchemas", "schema.PartSchemaOrSchemas", true},
+	ArgTypeMapArrayOrSchema: {"MapArrayOrSchema", "map[string]schema.ArrayOrSchema", true},
+	ArgTypeAny:              {"Any", "any", false},
+	ArgTypeMapMapSchema:     {"MapMapSchema", "map[string]map[string]*schema.Schema", true},
+}
+
+// Values returns the valid ArgType values, in increasing order.
+func Values() []ArgType {
+	return slices.Sorted(maps.Keys(infos))
+}
+
+// String returns the name of t, such as "MapSchema" for
+// ArgTypeMapSchema. This is the name used in generated
+// function and type names, such as schema.PartMapSchema.
+func (t ArgType) String() string {
+	if i, ok := infos[t]; ok {
+		return i.name
+	}
+	return fmt.Sprintf("ArgType(%d)", int(t))
+}
+
+// GoType returns the Go type of an argument of type t, as a string,
+// for use in generated code that imports the schema package.
+// It panics if t is not a valid ArgType.
+func (t ArgType) GoType() string {
+	if i, ok := infos[t]; ok {
+		return i.goType
+	}
+	panic(fmt.Sprintf("unexpected ArgType %d", int(t)))
+}
+
+// IsSchemaBearing reports whether an argument of type t holds
+// subschemas, as the arguments of properties and allOf do.
+// These are the types whose subschemas are reported
+// by the Children method of a schema.
+func (t ArgType) IsSchemaBearing() bool {
+	return infos[t].schema
+}
+
+// Parse returns the ArgType whose String method returns name.
+// The first letter of name may be lower case, as in "mapSchema".
+func Parse(name string) (ArgType, bool) {
+	if name == "" {
+		return 0, false
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+	for t, i := range infos {
+		if i.name == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
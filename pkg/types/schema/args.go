@@ -0,0 +1,88 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"fmt"
+	"math"
+)
+
+// nonNegativeKeywords are the keywords whose integer
+// argument must not be negative.
+var nonNegativeKeywords = map[string]bool{
+	"maxLength":     true,
+	"minLength":     true,
+	"maxItems":      true,
+	"minItems":      true,
+	"maxContains":   true,
+	"minContains":   true,
+	"maxProperties": true,
+	"minProperties": true,
+}
+
+// CheckArguments reports whether the arguments of the keywords of s
+// are in the range that the specification permits: a negative
+// minLength, a multipleOf of zero, or a minContains greater than
+// maxContains is an error. The arguments of keywords with a
+// [Keyword.CheckArg] function are checked by it.
+// Subschemas are not checked.
+//
+// Schemas decoded from JSON are checked as they are decoded.
+// A Builder checks the schemas it builds in strict mode.
+func (s *Schema) CheckArguments() error {
+	var (
+		minContains, maxContains int64
+		hasMin, hasMax           bool
+	)
+	for _, part := range s.Parts {
+		if part.Keyword.Generated {
+			continue
+		}
+		name := part.Keyword.Name
+		if check := part.Keyword.CheckArg; check != nil {
+			if err := check(part.Value); err != nil {
+				return &ParseError{
+					Pointer: "/" + name,
+					Err:     fmt.Errorf("%q argument: %w", name, err),
+				}
+			}
+		}
+		switch v := part.Value.(type) {
+		case PartInt:
+			if nonNegativeKeywords[name] && v < 0 {
+				return &ParseError{
+					Pointer: "/" + name,
+					Err:     fmt.Errorf("%q argument %d is negative", name, v),
+				}
+			}
+			switch name {
+			case "minContains":
+				minContains, hasMin = int64(v), true
+			case "maxContains":
+				maxContains, hasMax = int64(v), true
+			}
+		case PartFloat:
+			if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+				return &ParseError{
+					Pointer: "/" + name,
+					Err:     fmt.Errorf("%q argument %v is not a finite number", name, float64(v)),
+				}
+			}
+			if name == "multipleOf" && v <= 0 {
+				return &ParseError{
+					Pointer: "/" + name,
+					Err:     fmt.Errorf("%q argument %v is not greater than 0", name, float64(v)),
+				}
+			}
+		}
+	}
+	if hasMin && hasMax && minContains > maxContains {
+		return &ParseError{
+			Pointer: "/minContains",
+			Err:     fmt.Errorf(`"minContains" argument %d is greater than "maxContains" argument %d`, minContains, maxContains),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+)
+
+// instanceFrame is a map, slice or pointer of the instance that is
+// being validated, with the number of tokens of its location.
+type instanceFrame struct {
+	node  sharedNode
+	depth int
+}
+
+// enterInstance records that instance is being validated at the
+// current instance location, so that a value that contains itself
+// is found rather than being followed until the validation is too
+// deep. It reports whether it recorded instance, in which case the
+// caller must call leaveInstance when it is done.
+//
+// If instance is already being validated at an enclosing location,
+// enterInstance reports done, and the caller must return err:
+// a validation error, or nil if [ValidateOpts.AllowInstanceCycles]
+// is set.
+func (vs *ValidationState) enterInstance(instance any) (entered, done bool, err error) {
+	root := vs.RootState
+	if root == nil {
+		return false, false, nil
+	}
+	// Only maps, slices and pointers can contain themselves.
+	// Check the kind first, as this is called for every value.
+	switch instance.(type) {
+	case nil, string, float64, bool, json.Number, int, int64:
+		return false, false, nil
+	}
+	switch reflect.TypeOf(instance).Kind() {
+	case reflect.Map, reflect.Pointer, reflect.Slice:
+	default:
+		return false, false, nil
+	}
+
+	v := reflect.ValueOf(instance)
+	var node sharedNode
+	if v.Kind() == reflect.Slice {
+		if v.Len() == 0 {
+			return false, false, nil
+		}
+		node = sharedNode{typ: v.Type(), ptr: v.UnsafePointer(), len: v.Len()}
+	} else {
+		if v.IsNil() {
+			return false, false, nil
+		}
+		node = sharedNode{typ: v.Type(), ptr: v.UnsafePointer()}
+	}
+
+	depth := len(vs.InstancePath)
+	for _, f := range root.instances {
+		if f.node == node && f.depth < depth {
+			if vs.Opts != nil && vs.Opts.AllowInstanceCycles {
+				return false, true, nil
+			}
+			return false, true, &errors2.ValidationError{
+				Message: fmt.Sprintf("circular instance reference at %s to %s", vs.InstancePointer(), instancePointer(vs.InstancePath[:f.depth])),
+			}
+		}
+	}
+	root.instances = append(root.instances, instanceFrame{node: node, depth: depth})
+	return true, false, nil
+}
+
+// leaveInstance removes the value recorded by enterInstance.
+func (vs *ValidationState) leaveInstance() {
+	root := vs.RootState
+	root.instances = root.instances[:len(root.instances)-1]
+}
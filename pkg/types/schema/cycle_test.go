@@ -0,0 +1,45 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestInstanceCycle(t *testing.T) {
+	var s schema.Schema
+	src := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {"node": {"type": "object", "properties": {
+			"self": {"$ref": "#/$defs/node"},
+			"up": {"$ref": "#/$defs/node"}
+		}}},
+		"$ref": "#/$defs/node"
+	}`
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	cyclic := map[string]any{}
+	cyclic["self"] = map[string]any{"up": cyclic}
+	err := s.Validate(cyclic)
+	if err == nil || !strings.Contains(err.Error(), "circular instance reference at #/self/up to #") {
+		t.Errorf("cyclic map: got %v, want a circular reference error", err)
+	}
+	if err := s.ValidateWithOpts(cyclic, &schema.ValidateOpts{AllowInstanceCycles: true}); err != nil {
+		t.Errorf("cyclic map with AllowInstanceCycles: %v", err)
+	}
+
+	// The same value twice, but not inside itself, is not a cycle.
+	shared := map[string]any{"self": map[string]any{}}
+	if err := s.Validate(map[string]any{"self": shared, "up": shared}); err != nil {
+		t.Errorf("shared map: %v", err)
+	}
+}
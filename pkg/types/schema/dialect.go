@@ -0,0 +1,393 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// vocabularyURIs is the registry of vocabulary URIs,
+// as used in the $vocabulary keyword of a meta-schema.
+var vocabularyURIs struct {
+	mu sync.Mutex
+	m  map[string]vocabularyURI
+}
+
+// vocabularyURI is the set of keywords of a vocabulary URI.
+type vocabularyURI struct {
+	// base is the vocabulary that defines the keywords.
+	base *Vocabulary
+	// keywords are the names of the keywords.
+	keywords []string
+}
+
+// RegisterVocabularyURI registers a vocabulary URI, such as
+// "https://json-schema.org/draft/2020-12/vocab/applicator",
+// which may be named in the $vocabulary keyword of a meta-schema.
+// The vocabulary URI stands for the keywords of base with the
+// given names.
+//
+// When the $schema keyword of a schema names a meta-schema that
+// is not a registered vocabulary, and the schema is decoded by a
+// [Decoder] with [DecoderOpts.LoadMetaSchemas] set, the meta-schema
+// is loaded, and the schema uses the keywords of the vocabulary URIs
+// that its $vocabulary keyword names. All of those vocabulary URIs must
+// have the same base vocabulary, which supplies the rest of the
+// behavior, such as how references are resolved.
+//
+// It's normally not necessary to call this;
+// importing a JSON schema version package will register its
+// vocabulary URIs.
+func RegisterVocabularyURI(uri string, base *Vocabulary, keywords []string) {
+	vocabularyURIs.mu.Lock()
+	defer vocabularyURIs.mu.Unlock()
+	if vocabularyURIs.m == nil {
+		vocabularyURIs.m = make(map[string]vocabularyURI)
+	}
+	if _, found := vocabularyURIs.m[uri]; found {
+		panic(fmt.Sprintf("multiple attempts to register vocabulary URI %q", uri))
+	}
+	vocabularyURIs.m[uri] = vocabularyURI{base, keywords}
+}
+
+// lookupVocabularyURI returns the keywords of a vocabulary URI.
+func lookupVocabularyURI(uri string) (vocabularyURI, bool) {
+	vocabularyURIs.mu.Lock()
+	defer vocabularyURIs.mu.Unlock()
+	vu, ok := vocabularyURIs.m[uri]
+	return vu, ok
+}
+
+// decodeConfig is the configuration used when decoding a schema
+// to find the vocabulary named by its $schema keyword.
+type decodeConfig struct {
+	// reg is the registry of vocabularies.
+	reg *registry
+	// loader, if not nil, is used to load a meta-schema
+	// that is not a registered vocabulary.
+	// It is only set if [DecoderOpts.LoadMetaSchemas] is.
+	loader Loader
+	// dialects holds the vocabularies built from loaded
+	// meta-schemas. It is the registry of the Environment,
+	// or else one that belongs to the Decoder, so that loading
+	// a meta-schema does not change the global registry.
+	dialects *registry
+	// ctx is passed to loader. If nil, [context.Background] is used.
+	ctx context.Context
+}
+
+// lookup returns the vocabulary registered for id,
+// or built by dc from the meta-schema id.
+func (dc *decodeConfig) lookup(id string) *Vocabulary {
+	if v := dc.reg.lookup(id); v != nil {
+		return v
+	}
+	if dc.dialects != nil {
+		return dc.dialects.lookup(id)
+	}
+	return nil
+}
+
+// vocabulary returns the vocabulary for the $schema keyword value
+// version. If there is no registered vocabulary, and dc has a loader,
+// this builds one from the $vocabulary keyword of the meta-schema,
+// and records it in dc.dialects.
+//
+// Meta-schemas are loaded with a configuration that has no loader,
+// so the $schema keyword of a meta-schema must name a
+// registered vocabulary.
+func (dc *decodeConfig) vocabulary(version string) (*Vocabulary, error) {
+	id := strings.TrimSuffix(version, "#")
+	if v := dc.lookup(id); v != nil {
+		return v, nil
+	}
+	if dc.loader == nil || dc.dialects == nil {
+		return nil, fmt.Errorf("JSON schema version %q not recognized", version)
+	}
+
+	uri, err := url.Parse(id)
+	if err != nil || !uri.IsAbs() {
+		return nil, fmt.Errorf("JSON schema version %q not recognized", version)
+	}
+	ctx := dc.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withDecodeConfig(ctx, &decodeConfig{reg: dc.reg, dialects: dc.dialects})
+	meta, err := dc.loader.Load(ctx, "", uri)
+	if err != nil {
+		return nil, fmt.Errorf("loading meta-schema %q: %v", version, err)
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("JSON schema version %q not recognized", version)
+	}
+	v, err := dialect(id, meta, dc.reg)
+	if err != nil {
+		return nil, err
+	}
+	return dc.dialects.addDialect(id, v), nil
+}
+
+// dialect returns the vocabulary described by meta, the meta-schema
+// whose URI is id. The vocabularies of the meta-schema's own
+// $schema keyword are looked up in r.
+func dialect(id string, meta *Schema, r *registry) (*Vocabulary, error) {
+	var metaVocab *Vocabulary
+	if pv, ok := meta.LookupKeyword(SchemaKeyword.Name); ok {
+		metaVocab = r.lookup(strings.TrimSuffix(string(pv.(PartString)), "#"))
+	}
+
+	pv, ok := meta.LookupKeyword("$vocabulary")
+	if !ok {
+		// Without $vocabulary, the meta-schema describes
+		// schemas of the same vocabulary as itself.
+		if metaVocab == nil {
+			return nil, fmt.Errorf("meta-schema %q has no $vocabulary keyword and no known $schema", id)
+		}
+		return metaVocab, nil
+	}
+	declared, ok := pv.(PartAny).V.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("meta-schema %q: $vocabulary is not an object", id)
+	}
+
+	var base *Vocabulary
+	keywords := make(map[string]*Keyword)
+	for vocabURI, req := range declared {
+		required, ok := req.(bool)
+		if !ok {
+			return nil, fmt.Errorf("meta-schema %q: $vocabulary value for %q is not a boolean", id, vocabURI)
+		}
+		vu, ok := lookupVocabularyURI(vocabURI)
+		if !ok {
+			if required {
+				return nil, fmt.Errorf("meta-schema %q requires unknown vocabulary %q", id, vocabURI)
+			}
+			// An optional vocabulary that we don't know is ignored.
+			continue
+		}
+		if base == nil {
+			base = vu.base
+		} else if base != vu.base {
+			return nil, fmt.Errorf("meta-schema %q mixes vocabularies of %s and %s", id, base.Name, vu.base.Name)
+		}
+		for _, name := range vu.keywords {
+			if k, ok := vu.base.Keywords[name]; ok {
+				keywords[name] = k
+			}
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("meta-schema %q declares no known vocabulary", id)
+	}
+
+	return &Vocabulary{
+		Name:              id,
+		Schema:            id,
+		Keywords:          keywords,
+		Cmp:               base.Cmp,
+		Resolve:           base.Resolve,
+		Docs:              base.Docs,
+		IgnoreRefSiblings: base.IgnoreRefSiblings,
+	}, nil
+}
+
+// KeywordSet is a set of keywords for [NewDialect].
+type KeywordSet struct {
+	// Vocabulary is the vocabulary that defines the keywords.
+	// It may be nil if all the keywords are in Keywords.
+	Vocabulary *Vocabulary
+	// Names are the names of the keywords of Vocabulary
+	// that are in the set. If nil, all of them are.
+	Names []string
+	// Keywords are keywords that are not in Vocabulary,
+	// such as those of a private vocabulary.
+	Keywords []*Keyword
+	// Docs is the documentation of Keywords.
+	Docs map[string]KeywordDoc
+}
+
+// NewDialect returns a new vocabulary whose name is name, and
+// whose $schema keyword value is schemaURI, with the keywords of the
+// given sets. The result may be registered with [RegisterVocabulary],
+// or with [Environment.RegisterVocabulary].
+//
+// Keywords are sorted, and so validated, in the order of their sets.
+// Within a set, the keywords of the Vocabulary are sorted as that
+// vocabulary sorts them, and come before the other Keywords,
+// which are sorted in order. So keywords that use the notes of
+// other keywords, such as unevaluatedProperties, should be in a
+// later set than those keywords.
+//
+// The vocabulary of the first set that has one supplies the rest of
+// the behavior, such as how references are resolved. It is an error
+// if there is no such set, if a name is not a keyword of its set's
+// vocabulary, or if a keyword is in more than one set.
+func NewDialect(name, schemaURI string, sets ...KeywordSet) (*Vocabulary, error) {
+	v := &Vocabulary{
+		Name:     name,
+		Schema:   schemaURI,
+		Keywords: make(map[string]*Keyword),
+		Docs:     make(map[string]KeywordDoc),
+	}
+	// rank records the set of each keyword, and for
+	// the Keywords of a set, their position in it.
+	type rank struct {
+		set   int
+		vocab *Vocabulary
+		pos   int
+	}
+	ranks := make(map[string]rank)
+	add := func(k *Keyword, doc KeywordDoc, hasDoc bool, r rank) error {
+		if _, ok := v.Keywords[k.Name]; ok {
+			return fmt.Errorf("dialect %s: keyword %q is in more than one set", name, k.Name)
+		}
+		v.Keywords[k.Name] = k
+		if hasDoc {
+			v.Docs[k.Name] = doc
+		}
+		ranks[k.Name] = r
+		return nil
+	}
+
+	var base *Vocabulary
+	for i, set := range sets {
+		if vocab := set.Vocabulary; vocab != nil {
+			if base == nil {
+				base = vocab
+			}
+			names := set.Names
+			if names == nil {
+				for name := range vocab.Keywords {
+					names = append(names, name)
+				}
+			}
+			for _, kname := range names {
+				k, ok := vocab.Keywords[kname]
+				if !ok {
+					return nil, fmt.Errorf("dialect %s: %s has no keyword %q", name, vocab.Name, kname)
+				}
+				doc, hasDoc := vocab.Docs[kname]
+				if err := add(k, doc, hasDoc, rank{set: i, vocab: vocab}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for j, k := range set.Keywords {
+			doc, hasDoc := set.Docs[k.Name]
+			if err := add(k, doc, hasDoc, rank{set: i, pos: j + 1}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if base == nil {
+		return nil, fmt.Errorf("dialect %s: no keyword set has a vocabulary", name)
+	}
+
+	v.Resolve = base.Resolve
+	v.IgnoreRefSiblings = base.IgnoreRefSiblings
+	v.Cmp = func(a, b string) int {
+		ra, rb := ranks[a], ranks[b]
+		if c := cmp.Compare(ra.set, rb.set); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(ra.pos, rb.pos); c != 0 {
+			return c
+		}
+		if ra.vocab != nil {
+			return ra.vocab.Cmp(a, b)
+		}
+		return 0
+	}
+	return v, nil
+}
+
+// RegisterMetaSchema registers a vocabulary for schemas whose
+// $schema keyword is uri, a proprietary dialect URI that has
+// no $vocabulary keyword that this package can use.
+// The vocabulary has the keywords and behavior of base,
+// and meta is the JSON encoding of its meta-schema, which a $ref
+// to uri loads. The meta-schema may use the keywords of base,
+// or name another registered vocabulary in its $schema keyword.
+// It is an error if meta is not a valid schema, or if its
+// $id is not uri.
+//
+// RegisterMetaSchema returns the registered vocabulary.
+// Like [RegisterVocabulary], it panics if uri is already registered.
+func RegisterMetaSchema(uri string, base *Vocabulary, meta []byte) (*Vocabulary, error) {
+	v, err := metaSchemaVocabulary(&reg, uri, base, meta)
+	if err != nil {
+		return nil, err
+	}
+	reg.add(v.Schema, v, false)
+	return v, nil
+}
+
+// RegisterMetaSchema is like the [RegisterMetaSchema] function,
+// but registers the vocabulary in e.
+func (e *Environment) RegisterMetaSchema(uri string, base *Vocabulary, meta []byte) (*Vocabulary, error) {
+	v, err := metaSchemaVocabulary(&e.vocabs, uri, base, meta)
+	if err != nil {
+		return nil, err
+	}
+	e.vocabs.add(v.Schema, v, false)
+	return v, nil
+}
+
+// metaSchemaVocabulary returns the vocabulary for [RegisterMetaSchema].
+// The $schema keyword of meta is looked up in r.
+func metaSchemaVocabulary(r *registry, uri string, base *Vocabulary, meta []byte) (*Vocabulary, error) {
+	id := strings.TrimSuffix(uri, "#")
+	u, err := url.Parse(id)
+	if err != nil || !u.IsAbs() {
+		return nil, fmt.Errorf("meta-schema URI %q is not an absolute URI", uri)
+	}
+	decode := func() (*Schema, error) {
+		return schemaFromJSONWithSource(&decodeConfig{reg: r}, base.Schema, u, meta)
+	}
+	ms, err := decode()
+	if err != nil {
+		return nil, fmt.Errorf("meta-schema %q: %v", uri, err)
+	}
+	for _, kw := range []string{"$id", "id"} {
+		if pv, ok := ms.LookupKeyword(kw); ok {
+			if s, ok := pv.(PartString); ok && strings.TrimSuffix(string(s), "#") != id {
+				return nil, fmt.Errorf("meta-schema %q has %s %q", uri, kw, s)
+			}
+		}
+	}
+	if err := ms.Resolve(&ResolveOpts{URI: u}); err != nil {
+		return nil, fmt.Errorf("meta-schema %q: %v", uri, err)
+	}
+
+	v := *base
+	v.Name = id
+	v.Schema = id
+	// References to the meta-schema are served from meta,
+	// and other references are loaded as usual.
+	v.Resolve = func(s *Schema, ropts *ResolveOpts) error {
+		var nopts ResolveOpts
+		if ropts != nil {
+			nopts = *ropts
+		}
+		next := nopts.Loader
+		nopts.Loader = LoaderFunc(func(ctx context.Context, schemaID string, ref *url.URL) (*Schema, error) {
+			if ref.String() == id {
+				return decode()
+			}
+			if next == nil {
+				return nil, fmt.Errorf("remote loading of URI %q not permitted", ref)
+			}
+			return next.Load(ctx, schemaID, ref)
+		})
+		return base.Resolve(s, &nopts)
+	}
+	return &v, nil
+}
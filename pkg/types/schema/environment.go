@@ -0,0 +1,121 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Environment is a set of vocabularies, format validators and a loader
+// that is separate from the package-global ones. A library that uses
+// JSON schemas can use its own Environment, so that its configuration
+// doesn't affect, and isn't affected by, other packages in the same
+// program. An Environment may be used concurrently.
+//
+// Schemas decoded by [Schema.UnmarshalJSON] and the other package
+// functions use the global configuration. Use the methods of an
+// Environment to decode schemas with its configuration, and set
+// [ValidateOpts.Environment] to validate with its format validators.
+type Environment struct {
+	// Loader loads schemas referenced from other documents.
+	// If nil, a reference to another document is an error;
+	// the loader set by [SetLoader] is not used.
+	// A Loader used with an Environment should build schemas with
+	// [SchemaFromJSONContext], so that they use the same
+	// vocabularies.
+	Loader Loader
+	// LoadMetaSchemas is used as [DecoderOpts.LoadMetaSchemas]
+	// by [Environment.Decode]. Vocabularies built from loaded
+	// meta-schemas are kept in the Environment.
+	LoadMetaSchemas bool
+
+	// vocabs is the vocabulary registry.
+	vocabs registry
+
+	mu sync.Mutex
+	// formats maps format names to validators.
+	formats map[string]FormatValidator
+}
+
+// FormatValidator is a function that validates the format keyword.
+// It returns an error if instance does not match the format.
+type FormatValidator func(instance any, state *ValidationState) error
+
+// NewEnvironment returns a new [Environment]. It starts with the
+// vocabularies that are registered globally, which are normally
+// those of the imported schema version packages; registering
+// a vocabulary later, either globally or in the Environment,
+// does not affect the other. It has no loader and no format
+// validators of its own.
+func NewEnvironment() *Environment {
+	e := &Environment{}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	e.vocabs.mapping = maps.Clone(reg.mapping)
+	e.vocabs.defval = reg.defval
+	return e
+}
+
+// RegisterVocabulary registers a vocabulary in e.
+// The def argument is true for the default vocabulary.
+// Like the global [RegisterVocabulary], this panics if the
+// vocabulary or a default vocabulary is already registered.
+func (e *Environment) RegisterVocabulary(v *Vocabulary, def bool) {
+	e.vocabs.add(v.Schema, v, def)
+}
+
+// LookupVocabulary returns a vocabulary registered in e,
+// or nil if no vocabulary was registered under that name.
+func (e *Environment) LookupVocabulary(s string) *Vocabulary {
+	return e.vocabs.lookup(strings.TrimSuffix(s, "#"))
+}
+
+// DefaultVocabulary returns the default vocabulary of e,
+// or nil if there isn't one.
+func (e *Environment) DefaultVocabulary() *Vocabulary {
+	return e.vocabs.def()
+}
+
+// RegisterFormat registers a validator for the format keyword
+// with the argument format. When validating with e, it is used
+// instead of any validator registered globally for that format.
+func (e *Environment) RegisterFormat(format string, fv FormatValidator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.formats == nil {
+		e.formats = make(map[string]FormatValidator)
+	}
+	e.formats[format] = fv
+}
+
+// LookupFormat returns the validator registered in e for format.
+func (e *Environment) LookupFormat(format string) (FormatValidator, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fv, ok := e.formats[format]
+	return fv, ok
+}
+
+// SchemaFromJSON is like the [SchemaFromJSON] function,
+// but uses the vocabularies of e.
+func (e *Environment) SchemaFromJSON(schemaID string, uri *url.URL, v any) (*Schema, error) {
+	return schemaFromJSON(&decodeConfig{reg: &e.vocabs}, schemaID, uri, v)
+}
+
+// SchemaFromJSONWithSource is like the [SchemaFromJSONWithSource]
+// function, but uses the vocabularies of e.
+func (e *Environment) SchemaFromJSONWithSource(schemaID string, uri *url.URL, data []byte) (*Schema, error) {
+	return schemaFromJSONWithSource(&decodeConfig{reg: &e.vocabs}, schemaID, uri, data)
+}
+
+// Decode decodes and resolves the schema whose JSON encoding is data,
+// using the configuration of e. It is like [Decoder.Decode].
+func (e *Environment) Decode(ctx context.Context, data []byte, uri *url.URL) (*Schema, error) {
+	return NewDecoder(DecoderOpts{Loader: e.Loader, Registry: e, LoadMetaSchemas: e.LoadMetaSchemas}).Decode(ctx, data, uri)
+}
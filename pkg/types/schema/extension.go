@@ -0,0 +1,67 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"fmt"
+	"sync"
+)
+
+// An extensionRegistry is a mapping from name to extension keyword.
+type extensionRegistry struct {
+	mu       sync.Mutex
+	keywords map[string]*Keyword
+	docs     map[string]KeywordDoc
+}
+
+// extensions is the global extension keyword registry.
+var extensions extensionRegistry
+
+// RegisterExtension registers an extension keyword, along with its
+// documentation. Extension keywords are recognized in every vocabulary,
+// but only when the vocabulary does not define a keyword of the same name.
+// Without registration, such keywords are unknown and are ignored.
+// Extension keywords should normally have names starting with "x-".
+//
+// It's normally not necessary to call this;
+// importing an extension package will register its keywords.
+func RegisterExtension(k *Keyword, doc KeywordDoc) {
+	extensions.mu.Lock()
+	defer extensions.mu.Unlock()
+	if extensions.keywords == nil {
+		extensions.keywords = make(map[string]*Keyword)
+		extensions.docs = make(map[string]KeywordDoc)
+	}
+	if _, found := extensions.keywords[k.Name]; found {
+		panic(fmt.Sprintf("multiple attempts to register extension keyword %q", k.Name))
+	}
+	extensions.keywords[k.Name] = k
+	extensions.docs[k.Name] = doc
+}
+
+// LookupExtension returns a registered extension keyword
+// and its documentation.
+func LookupExtension(name string) (*Keyword, KeywordDoc, bool) {
+	extensions.mu.Lock()
+	defer extensions.mu.Unlock()
+	k, ok := extensions.keywords[name]
+	return k, extensions.docs[name], ok
+}
+
+// ArgValidator converts a validator function that accepts a
+// particular type of argument, such as [PartString], to one that
+// can be stored in the Validate field of a [Keyword]. The argument
+// of the keyword must have that type, which it does if the ArgType
+// of the keyword is the corresponding one.
+func ArgValidator[T PartValue](fn func(arg T, instance any, state *ValidationState) error) func(PartValue, any, *ValidationState) error {
+	return func(arg PartValue, instance any, state *ValidationState) error {
+		v, ok := arg.(T)
+		if !ok {
+			var zero T
+			return fmt.Errorf("got %T, expect %T", arg, zero)
+		}
+		return fn(v, instance, state)
+	}
+}
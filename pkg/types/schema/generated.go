@@ -0,0 +1,94 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import "iter"
+
+// Names of the generated keywords that this module adds to schemas.
+// A generated keyword has [Keyword.Generated] set. It does not
+// appear in JSON, and records information derived from the schema,
+// such as the schema that a $ref refers to.
+//
+// The names are stable: a generated keyword keeps its name and the
+// type of its argument. New generated keywords may be added, and one
+// that is no longer needed may stop being added to schemas, so code
+// that walks [Schema.Parts] should skip generated keywords it does
+// not know about, using [IsGenerated].
+// All names start with "$$", which is not used by any JSON keyword.
+const (
+	// ResolvedRefKeywordName records the schema that a $ref
+	// refers to. The argument is a [PartSchema].
+	ResolvedRefKeywordName = "$$resolvedRef"
+	// ResolvedDynamicRefKeywordName records the schema that a
+	// $dynamicRef refers to, if it refers to a $dynamicAnchor.
+	// The argument is a [PartSchema].
+	ResolvedDynamicRefKeywordName = "$$resolvedDynamicRef"
+	// DetachedDynamicRefKeywordName records the schema that a
+	// $dynamicRef refers to if no $dynamicAnchor is in scope.
+	// The argument is a [PartSchema].
+	DetachedDynamicRefKeywordName = "$$detachedDynamicRef"
+	// RecordDynamicAnchorKeywordName and ClearDynamicAnchorKeywordName
+	// mark a schema resource with a $dynamicAnchor.
+	// The argument is a [PartString].
+	RecordDynamicAnchorKeywordName = "$$recordDynamicAnchorKeyword"
+	ClearDynamicAnchorKeywordName  = "$$clearDynamicAnchorKeyword"
+	// AnyOfTypesKeywordName and OneOfTypesKeywordName record
+	// the JSON types that each branch of an anyOf or oneOf
+	// keyword can match. The argument is a [PartAny].
+	AnyOfTypesKeywordName = "$$anyOfTypes"
+	OneOfTypesKeywordName = "$$oneOfTypes"
+	// AllOfMergedKeywordName records the branches of an allOf
+	// keyword merged into one, if they are simple object schemas.
+	// The argument is a [PartAny].
+	AllOfMergedKeywordName = "$$allOfMerged"
+	// PatternPropertiesRegexpsKeywordName records the compiled
+	// regexps of patternProperties. The argument is a [PartAny].
+	PatternPropertiesRegexpsKeywordName = "$$patternPropertiesRegexps"
+	// SourceKeywordName records the [Source] of a schema.
+	// The argument is a [PartAny].
+	SourceKeywordName = "$$source"
+	// LeafKeywordName marks a schema all of whose keywords
+	// are leaf keywords. The argument is a [PartBool].
+	LeafKeywordName = "$$leaf"
+	// UsesNotesKeywordName marks a schema that has a keyword
+	// with [Keyword.UsesNotes] set. The argument is a [PartBool].
+	UsesNotesKeywordName = "$$usesNotes"
+	// NumberLiteralsKeywordName records the JSON text of number
+	// arguments that would not marshal as they were written, such
+	// as "1.0", keyed by keyword name. The argument is a [PartAny].
+	NumberLiteralsKeywordName = "$$numberLiterals"
+)
+
+// IsGenerated reports whether keyword is a generated keyword,
+// which is not represented in JSON. This is keyword.Generated.
+func IsGenerated(keyword *Keyword) bool {
+	return keyword.Generated
+}
+
+// restoresState reports whether k undoes a change that an earlier
+// keyword of the schema made to the validation state, as when the
+// clear keyword of a dynamic anchor removes it from the dynamic
+// scope. Such a keyword is validated even after an earlier keyword
+// failed with [ValidateOpts.FailFast], so that FailFast does not
+// change the result of the rest of the validation.
+func (k *Keyword) restoresState() bool {
+	return k.Name == ClearDynamicAnchorKeywordName
+}
+
+// JSONParts returns the parts of s that are represented in JSON,
+// skipping generated keywords. The parts are in the order of
+// s.Parts. For a bool schema this yields the part for [BoolKeyword].
+func (s *Schema) JSONParts() iter.Seq[Part] {
+	return func(yield func(Part) bool) {
+		for _, part := range s.Parts {
+			if IsGenerated(part.Keyword) {
+				continue
+			}
+			if !yield(part) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Loader loads a schema referenced by a $ref to another document.
+//
+// Load returns the schema at uri, which has no fragment.
+// The schemaID is the default schema ID, such as
+// [draft202012.SchemaID], for a schema that has no $schema keyword.
+// The schema will be resolved by the resolver of the schema that
+// references it, so Load should not call [Schema.Resolve];
+// to fully support cross references, it should call
+// [SchemaFromJSONContext] with ctx, which decodes the schema with
+// the vocabularies of the [Decoder] or [Environment] that is loading
+// it, or else [SchemaFromJSON] or [SchemaFromJSONWithSource].
+//
+// When decoding user-written schemas, Load can be called with
+// arbitrary URIs. It's probably unwise to simply call
+// [net/http.Get] in all cases.
+//
+// [draft202012.SchemaID]: https://pkg.go.dev/github.com/altshiftab/jsonschema/pkg/draft202012#SchemaID
+type Loader interface {
+	Load(ctx context.Context, schemaID string, uri *url.URL) (*Schema, error)
+}
+
+// LoaderFunc is an adapter that lets an ordinary function be used
+// as a [Loader].
+type LoaderFunc func(ctx context.Context, schemaID string, uri *url.URL) (*Schema, error)
+
+// Load calls f(ctx, schemaID, uri).
+func (f LoaderFunc) Load(ctx context.Context, schemaID string, uri *url.URL) (*Schema, error) {
+	return f(ctx, schemaID, uri)
+}
+
+// Decoder decodes schemas from JSON using its own configuration,
+// rather than the package-global configuration used by
+// [Schema.UnmarshalJSON]. A Decoder may be used concurrently.
+//
+// A zero Decoder uses the globally registered vocabularies and
+// default vocabulary, and has no loader. Use [NewDecoder] for
+// a Decoder that doesn't depend on any global configuration.
+type Decoder struct {
+	// Loader loads schemas referenced from other documents.
+	// If nil, a reference to another document is an error;
+	// the loader set by [SetLoader] is not used.
+	Loader Loader
+
+	// defaultDraft is the $schema of a schema without one,
+	// or "" for the default vocabulary of the registry.
+	defaultDraft string
+	// env holds the vocabularies, or is nil
+	// to use the global vocabularies.
+	env *Environment
+	// loadMeta is DecoderOpts.LoadMetaSchemas.
+	loadMeta bool
+	// dialects holds the vocabularies built from loaded
+	// meta-schemas if env is nil.
+	dialects registry
+}
+
+// DecoderOpts describes the configuration of a [Decoder].
+type DecoderOpts struct {
+	// DefaultDraft is the schema ID, such as [draft202012.SchemaID],
+	// of a schema that has no $schema keyword. If empty, the default
+	// vocabulary of Registry is used, as set by [SetDefaultSchema]
+	// for the global registry.
+	//
+	// [draft202012.SchemaID]: https://pkg.go.dev/github.com/altshiftab/jsonschema/pkg/draft202012#SchemaID
+	DefaultDraft string
+	// Loader loads schemas referenced from other documents.
+	// If nil, a reference to another document is an error.
+	Loader Loader
+	// Registry holds the vocabularies that schemas may use.
+	// If nil, the globally registered vocabularies are used.
+	Registry *Environment
+	// LoadMetaSchemas reports whether to use Loader to load the
+	// meta-schema named by a $schema keyword that is not a
+	// registered vocabulary, and build a vocabulary from its
+	// $vocabulary keyword, as described by [RegisterVocabularyURI].
+	// The vocabulary is kept in Registry, or if that is nil,
+	// in the Decoder; it is never added to the global registry.
+	// If false, such a $schema keyword is an error.
+	LoadMetaSchemas bool
+}
+
+// NewDecoder returns a [Decoder] with the configuration opts.
+// Libraries should decode schemas with their own Decoder, rather
+// than call [SetDefaultSchema] or [SetLoader], which affect every
+// package in the program.
+func NewDecoder(opts DecoderOpts) *Decoder {
+	return &Decoder{
+		Loader:       opts.Loader,
+		defaultDraft: opts.DefaultDraft,
+		env:          opts.Registry,
+		loadMeta:     opts.LoadMetaSchemas,
+	}
+}
+
+// Decode decodes and resolves the schema whose JSON encoding is
+// data, as [UnmarshalWithSource] does. The uri is where data was
+// loaded from; it may be nil. The ctx is passed to the Loader,
+// which should decode schemas with [SchemaFromJSONContext].
+func (d *Decoder) Decode(ctx context.Context, data []byte, uri *url.URL) (*Schema, error) {
+	dc := d.decodeConfig()
+	ctx = withDecodeConfig(ctx, dc)
+	dc.ctx = ctx
+	s, err := schemaFromJSONWithSource(dc, d.defaultDraft, uri, data)
+	if err != nil {
+		return nil, err
+	}
+
+	ropts := &ResolveOpts{
+		Vocabulary: d.vocabulary(dc, s),
+		URI:        uri,
+		Loader:     d.Loader,
+		Context:    ctx,
+	}
+	if err := s.Resolve(ropts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// registry returns the vocabulary registry of d.
+func (d *Decoder) registry() *registry {
+	if d.env != nil {
+		return &d.env.vocabs
+	}
+	return &reg
+}
+
+// decodeConfig returns the configuration for decoding with d.
+func (d *Decoder) decodeConfig() *decodeConfig {
+	dc := &decodeConfig{reg: d.registry()}
+	if d.loadMeta {
+		dc.loader = d.Loader
+		if d.env != nil {
+			dc.dialects = &d.env.vocabs
+		} else {
+			dc.dialects = &d.dialects
+		}
+	}
+	return dc
+}
+
+// vocabulary returns the vocabulary of the schema s decoded with dc:
+// the one named by its $schema keyword, or else by d.defaultDraft.
+// It returns nil if there is neither, in which case the default is used.
+func (d *Decoder) vocabulary(dc *decodeConfig, s *Schema) *Vocabulary {
+	for _, part := range s.Parts {
+		if part.Keyword == &SchemaKeyword {
+			return dc.lookup(strings.TrimSuffix(string(part.Value.(PartString)), "#"))
+		}
+	}
+	if d.defaultDraft != "" {
+		return dc.lookup(strings.TrimSuffix(d.defaultDraft, "#"))
+	}
+	return nil
+}
+
+// decodeConfigKey is the context key of the decodeConfig
+// of the Decoder that passed the context to a [Loader].
+type decodeConfigKey struct{}
+
+// withDecodeConfig returns a context that carries dc.
+func withDecodeConfig(ctx context.Context, dc *decodeConfig) context.Context {
+	return context.WithValue(ctx, decodeConfigKey{}, dc)
+}
+
+// SchemaFromJSONContext is like [SchemaFromJSONWithSource], but if
+// ctx is, or is derived from, a context that a [Decoder] or
+// [Environment] passed to [Loader.Load], it uses their vocabularies
+// rather than the global ones. Loaders should call this, so that a
+// schema they load is decoded in the same way as the schema that
+// refers to it.
+//
+// It is normally necessary to call Resolve on the result.
+func SchemaFromJSONContext(ctx context.Context, schemaID string, uri *url.URL, data []byte) (*Schema, error) {
+	dc, ok := ctx.Value(decodeConfigKey{}).(*decodeConfig)
+	if !ok {
+		return SchemaFromJSONWithSource(schemaID, uri, data)
+	}
+	return schemaFromJSONWithSource(dc, schemaID, uri, data)
+}
+
+// globalLoader returns the loader set by [SetLoader],
+// or nil if there is none.
+func globalLoader() Loader {
+	fn := loader
+	if fn == nil {
+		return nil
+	}
+	return LoaderFunc(func(ctx context.Context, schemaID string, uri *url.URL) (*Schema, error) {
+		return fn(schemaID, uri)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/loader"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestDecoderRemoteRef checks that a schema loaded through a $ref
+// by a Decoder with its own registry is decoded with that registry,
+// and that decoding doesn't change the global registry.
+func TestDecoderRemoteRef(t *testing.T) {
+	const private = "https://example.com/private-vocabulary"
+	vocab := *draft202012.Vocabulary
+	vocab.Schema = private
+	env := schema.NewEnvironment()
+	env.RegisterVocabulary(&vocab, false)
+
+	d := schema.NewDecoder(schema.DecoderOpts{
+		Loader: loader.Map(map[string][]byte{
+			"https://example.com/remote": []byte(`{"$schema": "` + private + `", "type": "integer"}`),
+		}),
+		Registry: env,
+	})
+	s, err := d.Decode(context.Background(), []byte(`{"$schema": "`+private+`", "$ref": "https://example.com/remote"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(1.0); err != nil {
+		t.Errorf("1: %v", err)
+	}
+	if err := s.Validate("x"); err == nil {
+		t.Error(`"x": no error`)
+	}
+
+	if v := schema.LookupVocabulary(private); v != nil {
+		t.Errorf("global registry has %s after decoding", private)
+	}
+	if _, err := schema.UnmarshalWithSource([]byte(`{"$schema": "`+private+`"}`), nil); err == nil {
+		t.Errorf("global decoding accepts %s", private)
+	}
+}
+
+// TestLoadMetaSchemas checks that a Decoder loads an unknown
+// meta-schema only if asked to, and keeps the vocabulary
+// built from it to itself.
+func TestLoadMetaSchemas(t *testing.T) {
+	const meta = "https://example.com/meta"
+	load := loader.Map(map[string][]byte{
+		meta: []byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "` + meta + `",
+			"$vocabulary": {
+				"https://json-schema.org/draft/2020-12/vocab/core": true,
+				"https://json-schema.org/draft/2020-12/vocab/validation": true
+			}
+		}`),
+	})
+	src := []byte(`{"$schema": "` + meta + `", "type": "integer"}`)
+
+	d := schema.NewDecoder(schema.DecoderOpts{Loader: load})
+	if _, err := d.Decode(context.Background(), src, nil); err == nil {
+		t.Error("decoded without LoadMetaSchemas")
+	}
+
+	d = schema.NewDecoder(schema.DecoderOpts{Loader: load, LoadMetaSchemas: true})
+	s, err := d.Decode(context.Background(), src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate("x"); err == nil {
+		t.Error(`"x": no error`)
+	}
+	if v := schema.LookupVocabulary(meta); v != nil {
+		t.Errorf("global registry has %s after decoding", meta)
+	}
+
+	env := schema.NewEnvironment()
+	env.Loader = load
+	env.LoadMetaSchemas = true
+	if _, err := env.Decode(context.Background(), src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v := env.LookupVocabulary(meta); v == nil {
+		t.Errorf("environment does not have %s after decoding", meta)
+	}
+	if v := schema.NewEnvironment().LookupVocabulary(meta); v != nil {
+		t.Errorf("new environment has %s", meta)
+	}
+}
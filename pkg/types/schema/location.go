@@ -0,0 +1,202 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+)
+
+// locateErrors sets the EvaluationPath and AbsoluteKeywordLocation
+// of the validation errors in err that don't have them yet, which
+// are the errors reported by the keyword kw of s. The keyword of vs
+// is the one that applied s, as for [Schema.ValidateSubSchema].
+// This is only done when there is an error, so the locations
+// are computed rather than tracked during validation.
+func (vs *ValidationState) locateErrors(err error, s *Schema, kw string) {
+	var evalPath, absLoc string
+	for _, ve := range validationErrorList(err) {
+		if ve.EvaluationPath != "" {
+			continue
+		}
+		if evalPath == "" {
+			toks := append(vs.evaluationPath(s), pointertoken.Escape(kw))
+			evalPath = "#/" + strings.Join(toks, "/")
+			if root := vs.RootState; root != nil {
+				if loc, ok := root.schemaLocation(s); ok {
+					absLoc = loc + "/" + pointertoken.Escape(kw)
+				}
+			}
+		}
+		ve.EvaluationPath = evalPath
+		ve.AbsoluteKeywordLocation = absLoc
+	}
+}
+
+// evaluationPath returns the tokens of the evaluation path of s,
+// which is applied by the keyword of vs. The states between vs and
+// the root state record the keyword being evaluated at each level.
+func (vs *ValidationState) evaluationPath(s *Schema) []string {
+	var toks []string
+	for st := vs; st != nil && st.Schema != nil; st = st.parent {
+		if p := st.parent; p != nil && p.Schema == st.Schema && p.Index == st.Index {
+			// A copy of the state made by a keyword
+			// to validate subschemas.
+			continue
+		}
+		part := st.Schema.Parts[st.Index]
+		toks = append(toks, childName(part, s))
+		s = st.Schema
+	}
+	slices.Reverse(toks)
+	return toks
+}
+
+// childName returns the name of the subschema s of the keyword part,
+// such as "properties/name", or just the keyword name if s is not
+// its argument, as for a $ref.
+func childName(part Part, s *Schema) string {
+	one := Schema{Parts: []Part{part}}
+	for name, sub := range one.Children() {
+		if sub == s {
+			return name
+		}
+	}
+	return pointertoken.Escape(part.Keyword.Name)
+}
+
+// schemaLocation returns the absolute location of s, as a URI
+// whose fragment is a JSON pointer. vs must be the root state.
+// The locations of all the schemas that the root can reach
+// are computed the first time.
+func (vs *ValidationState) schemaLocation(s *Schema) (string, bool) {
+	if vs.locations == nil {
+		vs.locations = schemaLocations(vs.Root)
+	}
+	loc, ok := vs.locations[s]
+	return loc, ok
+}
+
+// schemaLocations returns the absolute locations of root and of the
+// schemas that it can reach, through subschemas and references.
+// A schema is located in the schema resource that holds it, whose
+// URI is its $id; the URI of the root, if it has no $id, is that of
+// its [Source], if known. A schema reached only through a reference
+// is located by the reference. If that ends in an anchor rather than
+// a JSON pointer, the anchor is used as if it were the pointer.
+func schemaLocations(root *Schema) map[*Schema]string {
+	locs := make(map[*Schema]string)
+
+	// refTarget is a schema reached by a reference,
+	// to be located if it is not part of a resource
+	// that is located otherwise.
+	type refTarget struct {
+		s    *Schema
+		base *url.URL
+		ref  string
+	}
+	var refs []refTarget
+
+	var walk func(s *Schema, base *url.URL, ptr string)
+	walk = func(s *Schema, base *url.URL, ptr string) {
+		if _, ok := locs[s]; ok {
+			return
+		}
+		if id, ok := idKeyword(s); ok {
+			if u, err := url.Parse(id); err == nil && u.Path+u.Host != "" {
+				if base != nil {
+					u = base.ResolveReference(u)
+				}
+				u.Fragment = ""
+				base, ptr = u, ""
+			}
+		}
+		loc := "#" + ptr
+		if base != nil {
+			loc = base.String() + loc
+		}
+		locs[s] = loc
+
+		var ref, dynamicRef string
+		for _, part := range s.Parts {
+			v, ok := part.Value.(PartString)
+			if !ok {
+				continue
+			}
+			switch part.Keyword.Name {
+			case "$ref":
+				ref = string(v)
+			case "$dynamicRef", "$recursiveRef":
+				dynamicRef = string(v)
+			}
+		}
+		for _, part := range s.Parts {
+			v, ok := part.Value.(PartSchema)
+			if !ok {
+				continue
+			}
+			switch part.Keyword.Name {
+			case ResolvedRefKeywordName:
+				refs = append(refs, refTarget{v.S, base, ref})
+			case ResolvedDynamicRefKeywordName, DetachedDynamicRefKeywordName:
+				refs = append(refs, refTarget{v.S, base, dynamicRef})
+			}
+		}
+
+		for name, sub := range s.Children() {
+			walk(sub, base, ptr+"/"+name)
+		}
+	}
+
+	var base *url.URL
+	if src, ok := root.Source(); ok && src.URI != "" {
+		base, _ = url.Parse(src.URI)
+	}
+	walk(root, base, "")
+
+	// Locate the schemas reached by references after the
+	// resources that hold them, so that a schema that is
+	// part of a located resource is located by its place there.
+	for len(refs) > 0 {
+		r := refs[0]
+		refs = refs[1:]
+		if _, ok := locs[r.s]; ok {
+			continue
+		}
+		u, err := url.Parse(r.ref)
+		if err != nil {
+			continue
+		}
+		if r.base != nil {
+			u = r.base.ResolveReference(u)
+		}
+		ptr := u.Fragment
+		if ptr != "" && !strings.HasPrefix(ptr, "/") {
+			ptr = "/" + ptr
+		}
+		u.Fragment = ""
+		var tbase *url.URL
+		if u.String() != "" {
+			tbase = u
+		}
+		walk(r.s, tbase, ptr)
+	}
+	return locs
+}
+
+// idKeyword returns the $id of s, or its id as in draft-04.
+func idKeyword(s *Schema) (string, bool) {
+	for _, part := range s.Parts {
+		if part.Keyword.Name == "$id" || part.Keyword.Name == "id" {
+			if v, ok := part.Value.(PartString); ok {
+				return string(v), true
+			}
+		}
+	}
+	return "", false
+}
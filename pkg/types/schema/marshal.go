@@ -0,0 +1,576 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+)
+
+// MarshalOpts describes options for [Schema.MarshalWithOpts].
+type MarshalOpts struct {
+	// HoistShared moves each anonymous subschema that appears
+	// more than once in the schema (the same *Schema pointer)
+	// into the "$defs" keyword of the root schema.
+	// Each appearance is replaced by a "$ref" to the definition.
+	// The name of the definition is derived from the location
+	// of the first appearance.
+	// Subschemas that are already in the root "$defs" keep their name.
+	//
+	// A subschema is only moved if that does not change what it
+	// means: it may not contain an "$id", a dynamic anchor, or a
+	// reference that is not an absolute URI. An appearance within a
+	// nested schema resource, one with its own "$id", is replaced
+	// by a "$ref" to the absolute URI of the definition if the root
+	// has an absolute "$id", and is otherwise written in full.
+	HoistShared bool
+
+	// Minify produces smaller output, for embedding schemas
+	// where space is limited. It drops annotation keywords
+	// such as "title" and "description" (but not "default",
+	// which can change validation), drops keywords that have
+	// no effect such as "minLength": 0, and replaces a schema
+	// that is just a "$ref" to a root "$defs" entry that is
+	// referenced nowhere else with the entry itself.
+	// Inlining a definition requires a resolved schema.
+	Minify bool
+
+	// FormatFloat, if not nil, returns the JSON text of each
+	// number argument of a keyword, such as the 0.5 of
+	// "multipleOf": 0.5. By default a number is written as it was
+	// in the JSON the schema was decoded from, or, if that is not
+	// known, without a fraction if it is an integer, and otherwise
+	// in the shortest form, which may use an exponent, as in 1e-07.
+	// For tools that reject exponents, FormatFloat could be
+	//
+	//	func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+	//
+	// The result must be a JSON number.
+	FormatFloat func(float64) string
+}
+
+// MarshalWithOpts is like MarshalJSON, but takes options.
+// A nil opts is the same as MarshalJSON.
+func (s *Schema) MarshalWithOpts(opts *MarshalOpts) ([]byte, error) {
+	var ms *marshalState
+	if opts != nil && (opts.HoistShared || opts.Minify || opts.FormatFloat != nil) {
+		ms = newMarshalState(s, opts)
+	}
+
+	var buf bytes.Buffer
+	if err := s.marshalSchema(&buf, ms); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalState is the state used when marshaling with options.
+// A nil *marshalState means plain JSON output.
+type marshalState struct {
+	root *Schema
+	// hoisted maps a schema to its name in the root "$defs".
+	hoisted map[*Schema]string
+	// names is the sorted list of newly hoisted definitions.
+	names []string
+	// defs maps the names in names to their schemas.
+	defs map[string]*Schema
+	// wroteDefs is set once the root "$defs" has been written.
+	wroteDefs bool
+	// rootID is the absolute URI of the root, without a fragment,
+	// or "" if it does not have one.
+	rootID string
+	// nested counts the schema resources, other than the root,
+	// that enclose the schema being written.
+	nested int
+
+	// minify is set if we are dropping unneeded keywords.
+	minify bool
+	// inline maps a schema that is just a "$ref" to the
+	// definition that should be written in its place.
+	inline map[*Schema]*Schema
+	// collapsed records the root "$defs" entries
+	// that are written inline.
+	collapsed map[string]bool
+
+	// formatFloat is MarshalOpts.FormatFloat.
+	formatFloat func(float64) string
+}
+
+// newMarshalState returns a marshalState for marshaling root.
+func newMarshalState(root *Schema, opts *MarshalOpts) *marshalState {
+	ms := &marshalState{
+		root:      root,
+		hoisted:   make(map[*Schema]string),
+		defs:      make(map[string]*Schema),
+		minify:    opts.Minify,
+		inline:    make(map[*Schema]*Schema),
+		collapsed: make(map[string]bool),
+
+		formatFloat: opts.FormatFloat,
+	}
+	if opts.HoistShared {
+		ms.hoist()
+	}
+	if opts.Minify {
+		ms.collapse()
+	}
+	return ms
+}
+
+// hoist finds the shared subschemas of the root schema,
+// and assigns them names in the root "$defs".
+func (ms *marshalState) hoist() {
+	root := ms.root
+	if id, ok := idKeyword(root); ok {
+		if u, err := url.Parse(id); err == nil && u.IsAbs() {
+			u.Fragment = ""
+			ms.rootID = u.String()
+		}
+	}
+
+	// Existing definitions keep their names,
+	// and all of them are taken.
+	taken := make(map[string]bool)
+	if v, ok := root.LookupKeyword("$defs"); ok {
+		for name, sub := range v.(PartMapSchema) {
+			taken[name] = true
+			if _, ok := ms.hoisted[sub]; !ok {
+				ms.hoisted[sub] = name
+			}
+		}
+	}
+
+	// Count the appearances of each subschema that can be replaced
+	// by a "$ref", remembering the location of the first one.
+	// We don't look inside a schema we have already seen,
+	// which also stops us on cycles.
+	counts := make(map[*Schema]int)
+	seen := make(map[*Schema]bool)
+	var order []*Schema
+	first := make(map[*Schema]string)
+	var walk func(s *Schema, nested bool)
+	walk = func(s *Schema, nested bool) {
+		for name, sub := range s.Children() {
+			if sub == root {
+				continue
+			}
+			if isBool, _ := sub.isBoolSchema(); isBool {
+				continue
+			}
+			if !nested || ms.rootID != "" {
+				counts[sub]++
+			}
+			if seen[sub] {
+				continue
+			}
+			seen[sub] = true
+			order = append(order, sub)
+			first[sub] = name
+			_, isResource := idKeyword(sub)
+			walk(sub, nested || isResource)
+		}
+	}
+	walk(root, false)
+
+	for _, sub := range order {
+		if counts[sub] < 2 || !movable(sub) {
+			continue
+		}
+		if _, ok := ms.hoisted[sub]; ok {
+			continue
+		}
+		name := hoistName(first[sub], taken)
+		taken[name] = true
+		ms.hoisted[sub] = name
+		ms.defs[name] = sub
+		ms.names = append(ms.names, name)
+	}
+	slices.Sort(ms.names)
+}
+
+// movable reports whether s means the same thing in the root
+// "$defs" as where it is. It may not contain an "$id", which may be
+// relative to where s is, or a dynamic anchor, which is in scope
+// only in the schema resource that holds it. A reference must be
+// an absolute URI, as s may be moved out of a nested resource.
+func movable(s *Schema) bool {
+	seen := make(map[*Schema]bool)
+	var check func(s *Schema) bool
+	check = func(s *Schema) bool {
+		if seen[s] {
+			return true
+		}
+		seen[s] = true
+		if _, ok := idKeyword(s); ok {
+			return false
+		}
+		for _, part := range s.Parts {
+			switch part.Keyword.Name {
+			case "$dynamicAnchor", "$recursiveAnchor", "$recursiveRef":
+				return false
+			case "$ref", "$dynamicRef":
+				v, ok := part.Value.(PartString)
+				if !ok {
+					return false
+				}
+				if u, err := url.Parse(string(v)); err != nil || !u.IsAbs() {
+					return false
+				}
+			}
+		}
+		for _, sub := range s.Children() {
+			if !check(sub) {
+				return false
+			}
+		}
+		return true
+	}
+	return check(s)
+}
+
+// collapse finds the root "$defs" entries that are the target
+// of a single "$ref", and arranges to write them in place of
+// the "$ref". It does nothing if the schema is not resolved.
+func (ms *marshalState) collapse() {
+	root := ms.root
+	v, ok := root.LookupKeyword("$defs")
+	if !ok {
+		return
+	}
+	defs := v.(PartMapSchema)
+
+	// owner maps every schema within a definition
+	// to the name of the definition.
+	owner := make(map[*Schema]string)
+	var own func(name string, s *Schema)
+	own = func(name string, s *Schema) {
+		if _, ok := owner[s]; ok {
+			return
+		}
+		owner[s] = name
+		for _, sub := range s.Children() {
+			own(name, sub)
+		}
+	}
+	for name, def := range defs {
+		own(name, def)
+	}
+
+	// Count the references to each schema. Generated keywords
+	// with a schema argument record resolved references.
+	refs := make(map[*Schema]int)
+	// single maps a schema that is just a "$ref" to its target.
+	single := make(map[*Schema]*Schema)
+	seen := make(map[*Schema]bool)
+	resolved := true
+	var walk func(s *Schema)
+	walk = func(s *Schema) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+
+		var (
+			hasRef  bool
+			target  *Schema
+			targets int
+			others  int
+		)
+		for _, part := range s.Parts {
+			switch {
+			case part.Keyword.Generated:
+				if ps, ok := part.Value.(PartSchema); ok {
+					refs[ps.S]++
+					target = ps.S
+					targets++
+				}
+			case part.Keyword.Name == "$ref":
+				hasRef = true
+			case ms.skipKeyword(part):
+			default:
+				others++
+			}
+		}
+		if hasRef && targets == 0 {
+			resolved = false
+		}
+		if hasRef && targets == 1 && others == 0 {
+			single[s] = target
+		}
+
+		for _, sub := range s.Children() {
+			walk(sub)
+		}
+	}
+	walk(root)
+	if !resolved {
+		return
+	}
+
+	for name, def := range defs {
+		if refs[def] != 1 || def == root {
+			continue
+		}
+		if _, ok := def.LookupKeyword("$id"); ok {
+			continue
+		}
+		ms.collapsed[name] = true
+	}
+
+	for s, target := range single {
+		name, ok := owner[target]
+		if !ok || !ms.collapsed[name] || defs[name] != target {
+			continue
+		}
+		// Don't inline a definition into itself.
+		if owner[s] == name {
+			delete(ms.collapsed, name)
+			continue
+		}
+		ms.inline[s] = target
+	}
+
+	// A definition is only collapsed if its single reference
+	// is inlined, and if nothing refers to the schemas within it.
+	inlined := make(map[*Schema]bool)
+	for _, target := range ms.inline {
+		inlined[target] = true
+	}
+	for s, n := range refs {
+		if name, ok := owner[s]; ok && n > 0 && s != defs[name] {
+			delete(ms.collapsed, name)
+		}
+	}
+	for name := range ms.collapsed {
+		if !inlined[defs[name]] {
+			delete(ms.collapsed, name)
+		}
+	}
+
+	// Inlining definitions that refer to each other
+	// in a cycle would never finish.
+	parent := make(map[string]string)
+	for s, target := range ms.inline {
+		if p, ok := owner[s]; ok {
+			parent[owner[target]] = p
+		}
+	}
+	for name := range ms.collapsed {
+		p, ok := parent[name]
+		for range len(parent) {
+			if !ok {
+				break
+			}
+			if p == name {
+				delete(ms.collapsed, name)
+				break
+			}
+			p, ok = parent[p]
+		}
+	}
+
+	for s, target := range ms.inline {
+		if !ms.collapsed[owner[target]] {
+			delete(ms.inline, s)
+		}
+	}
+	for name := range ms.collapsed {
+		delete(ms.hoisted, defs[name])
+	}
+}
+
+// noEffect lists keyword values that are the same as
+// omitting the keyword.
+var noEffect = map[string]func(PartValue) bool{
+	"minLength":     isZeroInt,
+	"minItems":      isZeroInt,
+	"minProperties": isZeroInt,
+	"uniqueItems": func(v PartValue) bool {
+		b, ok := v.(PartBool)
+		return ok && !bool(b)
+	},
+	"required": func(v PartValue) bool {
+		s, ok := v.(PartStrings)
+		return ok && len(s) == 0
+	},
+}
+
+// isZeroInt reports whether v is the integer 0.
+func isZeroInt(v PartValue) bool {
+	i, ok := v.(PartInt)
+	return ok && i == 0
+}
+
+// annotationKeywords are the annotation keywords dropped by Minify.
+var annotationKeywords = map[string]bool{
+	"title":       true,
+	"description": true,
+	"$comment":    true,
+	"examples":    true,
+	"deprecated":  true,
+	"readOnly":    true,
+	"writeOnly":   true,
+}
+
+// skipKeyword reports whether part is dropped by Minify.
+func (ms *marshalState) skipKeyword(part Part) bool {
+	if !ms.minify {
+		return false
+	}
+	if annotationKeywords[part.Keyword.Name] {
+		return true
+	}
+	if fn, ok := noEffect[part.Keyword.Name]; ok && fn(part.Value) {
+		return true
+	}
+	return false
+}
+
+// skipPart reports whether part of s should not be written.
+func (ms *marshalState) skipPart(s *Schema, part Part) bool {
+	if ms == nil {
+		return false
+	}
+	if ms.skipKeyword(part) {
+		return true
+	}
+	if part.Keyword.Name == "$defs" && s == ms.root && len(ms.names) == 0 {
+		// Skip the root "$defs" if every entry is collapsed.
+		for name := range part.Value.(PartMapSchema) {
+			if !ms.collapsed[name] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// hoistName returns a definition name based on the last
+// element of the JSON pointer path, that is not in taken.
+func hoistName(path string, taken map[string]bool) string {
+	elems := strings.Split(path, "/")
+	last := elems[len(elems)-1]
+	if _, err := strconv.Atoi(last); err == nil && len(elems) > 1 {
+		// An array element: use the keyword too.
+		last = elems[len(elems)-2] + last
+	}
+
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, last)
+	if base == "" {
+		base = "def"
+	}
+
+	name := base
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	return name
+}
+
+// isRoot reports whether s is the root schema being hoisted into.
+func (ms *marshalState) isRoot(s *Schema) bool {
+	return ms != nil && s == ms.root
+}
+
+// hoistedRef returns the reference to the definition of s,
+// if s should be written as a reference.
+func (ms *marshalState) hoistedRef(s *Schema) (string, bool) {
+	if ms == nil || s == ms.root {
+		return "", false
+	}
+	name, ok := ms.hoisted[s]
+	if !ok {
+		return "", false
+	}
+	ref := "#/$defs/" + pointertoken.Escape(name)
+	if ms.nested > 0 {
+		// The reference would be resolved against
+		// the nested resource, not the root.
+		if ms.rootID == "" {
+			return "", false
+		}
+		ref = ms.rootID + ref
+	}
+	return ref, true
+}
+
+// enter records that s is being written, and returns
+// a function to call when it has been written.
+func (ms *marshalState) enter(s *Schema) func() {
+	if ms == nil || s == ms.root {
+		return func() {}
+	}
+	if _, ok := idKeyword(s); !ok {
+		return func() {}
+	}
+	ms.nested++
+	return func() { ms.nested-- }
+}
+
+// inlined returns the definition to write in place of s, if any.
+func (ms *marshalState) inlined(s *Schema) (*Schema, bool) {
+	if ms == nil {
+		return nil, false
+	}
+	target, ok := ms.inline[s]
+	return target, ok
+}
+
+// marshalDefs writes the root "$defs" value, which is the existing
+// definitions in defs followed by the hoisted definitions.
+func (ms *marshalState) marshalDefs(buf *bytes.Buffer, defs PartMapSchema) error {
+	ms.wroteDefs = true
+
+	keys := make([]string, 0, len(defs))
+	for k := range defs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	buf.WriteByte('{')
+	first := true
+	write := func(name string, sub *Schema) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		fmt.Fprintf(buf, "%s:", encodeString(name))
+		// A definition is written in full, not as a reference
+		// to itself, unless it is an alias of another definition.
+		if other, ok := ms.hoisted[sub]; ok && other != name {
+			return sub.marshalSchema(buf, ms)
+		}
+		return sub.marshalParts(buf, ms)
+	}
+	for _, k := range keys {
+		if ms.collapsed[k] {
+			continue
+		}
+		if err := write(k, defs[k]); err != nil {
+			return err
+		}
+	}
+	for _, name := range ms.names {
+		if err := write(name, ms.defs[name]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
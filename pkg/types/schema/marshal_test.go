@@ -0,0 +1,158 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// share decodes src and makes the property q of its child
+// called name the same *Schema as its property p.
+func share(t *testing.T, src, name string) *schema.Schema {
+	t.Helper()
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatal(err)
+	}
+	parent := &s
+	if name != "" {
+		for n, sub := range s.Children() {
+			if n == name {
+				parent = sub
+			}
+		}
+	}
+	v, ok := parent.LookupKeyword("properties")
+	if !ok {
+		t.Fatalf("no properties in %q", name)
+	}
+	props := v.(schema.PartMapSchema)
+	props["q"] = props["p"]
+	return &s
+}
+
+func TestHoistShared(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// child is the name of the child with the properties p and q,
+		// or "" for the root.
+		child string
+		// want and notWant are in the output.
+		want, notWant string
+		valid         []string
+		invalid       []string
+	}{
+		{
+			name:    "root",
+			src:     `{"$schema": "https://json-schema.org/draft/2020-12/schema", "properties": {"p": {"type": "string"}}}`,
+			want:    `"p":{"$ref":"#/$defs/p"}`,
+			valid:   []string{`{"p": "a", "q": "b"}`},
+			invalid: []string{`{"q": 1}`},
+		},
+		{
+			name: "nested relative ref",
+			src: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "https://example.com/root",
+				"properties": {
+					"inner": {
+						"$id": "https://example.com/inner",
+						"$defs": {"loc": {"type": "integer"}},
+						"properties": {"p": {"$ref": "#/$defs/loc"}}
+					}
+				}
+			}`,
+			child:   "properties/inner",
+			notWant: `"$defs/p"`,
+			valid:   []string{`{"inner": {"p": 1, "q": 2}}`},
+			invalid: []string{`{"inner": {"q": "x"}}`},
+		},
+		{
+			name: "nested absolute root",
+			src: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$id": "https://example.com/root",
+				"properties": {
+					"inner": {
+						"$id": "https://example.com/inner",
+						"properties": {"p": {"type": "integer"}}
+					}
+				}
+			}`,
+			child:   "properties/inner",
+			want:    `"p":{"$ref":"https://example.com/root#/$defs/p"}`,
+			valid:   []string{`{"inner": {"p": 1, "q": 2}}`},
+			invalid: []string{`{"inner": {"q": "x"}}`},
+		},
+		{
+			name: "nested no root id",
+			src: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"properties": {
+					"inner": {
+						"$id": "https://example.com/inner",
+						"properties": {"p": {"type": "integer"}}
+					}
+				}
+			}`,
+			child:   "properties/inner",
+			notWant: `"$ref"`,
+			valid:   []string{`{"inner": {"p": 1, "q": 2}}`},
+			invalid: []string{`{"inner": {"q": "x"}}`},
+		},
+		{
+			name: "relative ref",
+			src: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"$defs": {"int": {"type": "integer"}},
+				"properties": {"p": {"$ref": "#/$defs/int"}}
+			}`,
+			notWant: `"$defs/p"`,
+			valid:   []string{`{"p": 1, "q": 2}`},
+			invalid: []string{`{"q": "x"}`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := share(t, test.src, test.child)
+			data, err := s.MarshalWithOpts(&schema.MarshalOpts{HoistShared: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := string(data)
+			if test.want != "" && !strings.Contains(out, test.want) {
+				t.Errorf("output does not contain %s:\n%s", test.want, out)
+			}
+			if test.notWant != "" && strings.Contains(out, test.notWant) {
+				t.Errorf("output contains %s:\n%s", test.notWant, out)
+			}
+
+			// The output must resolve, and mean the same.
+			var got schema.Schema
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("%v\n%s", err, out)
+			}
+			check := func(instances []string, valid bool) {
+				for _, inst := range instances {
+					var v any
+					if err := json.Unmarshal([]byte(inst), &v); err != nil {
+						t.Fatal(err)
+					}
+					if err := got.Validate(v); (err == nil) != valid {
+						t.Errorf("%s: got error %v, want valid %t\n%s", inst, err, valid, out)
+					}
+				}
+			}
+			check(test.valid, true)
+			check(test.invalid, false)
+		})
+	}
+}
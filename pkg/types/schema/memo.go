@@ -0,0 +1,134 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+)
+
+// memoKey identifies the evaluation of a schema
+// against a value of the instance, for [ValidateOpts.Memoize].
+type memoKey struct {
+	s       *Schema
+	inPlace bool
+	// path is the instance location, as errors report it.
+	path string
+	// node identifies the value; see nodeIdentity.
+	node any
+}
+
+// memoResult is the result of an evaluation recorded in the memo.
+type memoResult struct {
+	err error
+	// evalPath is the evaluation path of the schema when err was
+	// found, which is the start of the EvaluationPath of its errors.
+	evalPath string
+}
+
+// sharedNode identifies a map, slice or pointer by its memory.
+type sharedNode struct {
+	typ reflect.Type
+	ptr unsafe.Pointer
+	len int
+}
+
+// memoKey returns the key of the evaluation of s against instance,
+// and reports whether its result may be memoized. notesNeeded is
+// whether the evaluation must leave notes for the keywords in scope,
+// which the memo does not record.
+//
+// A result is not memoized once the validation has entered a schema
+// resource with a dynamic anchor, as a $dynamicRef may then resolve
+// differently at different points of the validation.
+func (vs *ValidationState) memoKey(s *Schema, instance any, inPlace, notesNeeded bool) (memoKey, bool) {
+	if vs.memo == nil || notesNeeded {
+		return memoKey{}, false
+	}
+	if vs.VersionData != nil && *vs.VersionData != nil {
+		return memoKey{}, false
+	}
+	node, ok := nodeIdentity(instance)
+	if !ok {
+		return memoKey{}, false
+	}
+	return memoKey{s: s, inPlace: inPlace, path: vs.InstancePointer(), node: node}, true
+}
+
+// memoized returns the recorded result of the evaluation key,
+// and reports whether there is one. The same schema can be reached
+// along different evaluation paths, so the errors are copied with
+// the EvaluationPath of the evaluation of s by vs, as
+// [ValidationState.locateErrors] would set it.
+func (vs *ValidationState) memoized(key memoKey, s *Schema) (error, bool) {
+	r, ok := vs.memo[key]
+	if !ok || r.err == nil {
+		return nil, ok
+	}
+	evalPath := vs.memoEvalPath(s)
+	if evalPath == r.evalPath {
+		return r.err, true
+	}
+	var errs []*ValidationError
+	for _, ve := range validationErrorList(r.err) {
+		c := *ve
+		if rest, ok := strings.CutPrefix(c.EvaluationPath, r.evalPath); ok {
+			c.EvaluationPath = evalPath + rest
+		}
+		errs = append(errs, &c)
+	}
+	if _, ok := r.err.(*ValidationError); ok {
+		return errs[0], true
+	}
+	return &errors2.ValidationErrors{Errs: errs}, true
+}
+
+// memoize records err as the result of the evaluation key of s,
+// unless it is a problem with the schema or the validation,
+// such as a cancelled context, rather than with the instance.
+func (vs *ValidationState) memoize(key memoKey, s *Schema, err error) {
+	switch {
+	case err == nil:
+		vs.memo[key] = memoResult{}
+	case IsValidationError(err):
+		vs.memo[key] = memoResult{err: err, evalPath: vs.memoEvalPath(s)}
+	}
+}
+
+// memoEvalPath returns the evaluation path of s, which is applied
+// by the keyword of vs, without a trailing slash.
+func (vs *ValidationState) memoEvalPath(s *Schema) string {
+	toks := vs.evaluationPath(s)
+	if len(toks) == 0 {
+		return "#"
+	}
+	return "#/" + strings.Join(toks, "/")
+}
+
+// nodeIdentity returns a comparable value that identifies instance
+// within an instance that is not modified: the address of a map,
+// slice or pointer, or the value of a boolean, number or string.
+// It reports false for other values, such as structs.
+func nodeIdentity(instance any) (any, bool) {
+	if instance == nil {
+		return nil, true
+	}
+	v := reflect.ValueOf(instance)
+	switch v.Kind() {
+	case reflect.Map, reflect.Pointer:
+		return sharedNode{typ: v.Type(), ptr: v.UnsafePointer()}, true
+	case reflect.Slice:
+		return sharedNode{typ: v.Type(), ptr: v.UnsafePointer(), len: v.Len()}, true
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return instance, true
+	}
+	return nil, false
+}
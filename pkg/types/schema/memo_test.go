@@ -0,0 +1,69 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestMemoizeLocations checks that an error found again through the
+// memo has the evaluation path of where it was found again.
+func TestMemoizeLocations(t *testing.T) {
+	const src = `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/memo",
+		"$defs": {"base": {"type": "string"}},
+		"properties": {
+			"a": {"allOf": [
+				{"$ref": "#/$defs/base", "minLength": 2},
+				{"$ref": "#/$defs/base"}
+			]}
+		}
+	}`
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(src), &s); err != nil {
+		t.Fatal(err)
+	}
+	instance := map[string]any{"a": 1.0}
+
+	locations := func(memoize bool) []string {
+		err := s.ValidateWithOpts(instance, &schema.ValidateOpts{Memoize: memoize})
+		var ves *schema.ValidationErrors
+		var ve *schema.ValidationError
+		var list []*schema.ValidationError
+		switch {
+		case errors.As(err, &ves):
+			list = ves.Errs
+		case errors.As(err, &ve):
+			list = []*schema.ValidationError{ve}
+		default:
+			t.Fatalf("Memoize %t: got %v, want validation errors", memoize, err)
+		}
+		var locs []string
+		for _, ve := range list {
+			locs = append(locs, ve.EvaluationPath+" "+ve.AbsoluteKeywordLocation)
+		}
+		slices.Sort(locs)
+		return locs
+	}
+
+	want := locations(false)
+	if !slices.Contains(want, "#/properties/a/allOf/1/$ref/type https://example.com/memo#/$defs/base/type") {
+		t.Fatalf("without Memoize, got %q", want)
+	}
+	// Validate twice, so that the second validation
+	// can't change the errors of the first.
+	for range 2 {
+		if got := locations(true); !slices.Equal(got, want) {
+			t.Errorf("with Memoize, got %q, want %q", got, want)
+		}
+	}
+}
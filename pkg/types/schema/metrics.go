@@ -0,0 +1,96 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+)
+
+// Collector receives a report of each validation, for monitoring.
+// It is set in the Collector field of [ValidateOpts].
+// Collect may be called concurrently by validations
+// running in different goroutines.
+// Package metrics has a Collector that aggregates the reports.
+type Collector interface {
+	Collect(*ValidationReport)
+}
+
+// ValidationReport describes one validation of an instance,
+// as reported to a [Collector].
+type ValidationReport struct {
+	// Schema is the schema that was validated against.
+	Schema *Schema
+	// SchemaID is the $id of Schema, if it has one.
+	SchemaID string
+	// Instance is the instance that was validated.
+	Instance any
+	// Opts are the validation options.
+	Opts *ValidateOpts
+	// Duration is the time that validation took.
+	Duration time.Duration
+	// Err is the error returned by the validation, if any.
+	Err error
+	// Failures are the keywords that failed, if Err is a
+	// validation error. A keyword that failed at several
+	// locations of the instance is listed once for each.
+	Failures []KeywordFailure
+}
+
+// KeywordFailure is a keyword that failed during a validation.
+type KeywordFailure struct {
+	// Keyword is the name of the keyword, such as "type".
+	Keyword string
+	// KeywordLocation is the location of the keyword,
+	// as a URI fragment such as "#/properties/a/type".
+	KeywordLocation string
+}
+
+// collect reports a validation of instance against s to c.
+func collect(c Collector, s *Schema, instance any, opts *ValidateOpts, start time.Time, err error) {
+	r := &ValidationReport{
+		Schema:   s,
+		Instance: instance,
+		Opts:     opts,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if pv, ok := s.LookupKeyword("$id"); ok {
+		if id, ok := pv.(PartString); ok {
+			r.SchemaID = string(id)
+		}
+	}
+	if err != nil && IsValidationError(err) {
+		var ves *errors2.ValidationErrors
+		var ve *errors2.ValidationError
+		switch {
+		case errors.As(err, &ves):
+			for _, ve := range ves.Errs {
+				r.Failures = append(r.Failures, keywordFailure(ve))
+			}
+		case errors.As(err, &ve):
+			r.Failures = append(r.Failures, keywordFailure(ve))
+		}
+	}
+	c.Collect(r)
+}
+
+// keywordFailure returns the failed keyword of ve.
+func keywordFailure(ve *errors2.ValidationError) KeywordFailure {
+	loc := ve.KeywordLocation
+	if loc == "" {
+		loc = "#"
+	}
+	kw := loc[strings.LastIndex(loc, "/")+1:]
+	kw = pointertoken.Unescape(kw)
+	return KeywordFailure{
+		Keyword:         kw,
+		KeywordLocation: loc,
+	}
+}
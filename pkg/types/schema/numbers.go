@@ -0,0 +1,134 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+// numberLiteralsKeyword is a generated keyword that records the JSON
+// text of the number arguments of a schema decoded from JSON, where
+// it differs from the text that [formatFloat] produces, such as
+// "1.0" or "1e6". The argument is a map from keyword name to text.
+var numberLiteralsKeyword = Keyword{
+	Name:      NumberLiteralsKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Generated: true,
+	Leaf:      true,
+}
+
+// recordNumberLiteral records that the argument of keyword,
+// whose value is f, was written as lit.
+func (s *Schema) recordNumberLiteral(keyword string, f float64, lit json.Number) {
+	if string(lit) == formatFloat(f) {
+		return
+	}
+	for _, part := range s.Parts {
+		if part.Keyword == &numberLiteralsKeyword {
+			part.Value.(PartAny).V.(map[string]string)[keyword] = string(lit)
+			return
+		}
+	}
+	s.Parts = append(s.Parts, Part{
+		Keyword: &numberLiteralsKeyword,
+		Value:   PartAny{map[string]string{keyword: string(lit)}},
+	})
+}
+
+// numberLiteral returns the JSON text of the argument f of keyword,
+// as it was written when s was decoded, if it is known and if the
+// argument has not been changed since then.
+func (s *Schema) numberLiteral(keyword string, f float64) (string, bool) {
+	for _, part := range s.Parts {
+		if part.Keyword == &numberLiteralsKeyword {
+			lit, ok := part.Value.(PartAny).V.(map[string]string)[keyword]
+			if !ok {
+				return "", false
+			}
+			if v, err := strconv.ParseFloat(lit, 64); err != nil || v != f {
+				return "", false
+			}
+			return lit, true
+		}
+	}
+	return "", false
+}
+
+// ExactNumber returns the exact value of the number argument f
+// of keyword, if it was written in the JSON that s was decoded from
+// as an integer that f only approximates, such as
+// 18446744073709551615, and the argument has not changed since then.
+// Validation compares instances with the exact value rather than f.
+func (s *Schema) ExactNumber(keyword string, f float64) (*big.Int, bool) {
+	lit, ok := s.numberLiteral(keyword, f)
+	if !ok || !isIntegerLiteral(lit) {
+		return nil, false
+	}
+	i, ok := new(big.Int).SetString(lit, 10)
+	if !ok {
+		return nil, false
+	}
+	if _, acc := new(big.Float).SetInt(i).Float64(); acc == big.Exact {
+		return nil, false
+	}
+	return i, true
+}
+
+// hasExactNumbers reports whether s has a number argument
+// for which ExactNumber returns an exact value.
+func (s *Schema) hasExactNumbers() bool {
+	for _, part := range s.Parts {
+		if f, ok := part.Value.(PartFloat); ok {
+			if _, ok := s.ExactNumber(part.Keyword.Name, float64(f)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// marshalFloat writes the number argument f of keyword to buf.
+// The text is that of [MarshalOpts.FormatFloat], if set; otherwise
+// it is the text the number was decoded from, if known, or the
+// text that formatFloat produces.
+func (s *Schema) marshalFloat(buf *bytes.Buffer, ms *marshalState, keyword string, f float64) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("%q argument %v is not a finite number", keyword, f)
+	}
+	if ms != nil && ms.formatFloat != nil {
+		text := ms.formatFloat(f)
+		var n json.Number
+		if err := json.Unmarshal([]byte(text), &n); err != nil {
+			return fmt.Errorf("%q argument %v formatted as %q, which is not a JSON number", keyword, f, text)
+		}
+		buf.WriteString(text)
+		return nil
+	}
+	if lit, ok := s.numberLiteral(keyword, f); ok {
+		buf.WriteString(lit)
+		return nil
+	}
+	buf.WriteString(formatFloat(f))
+	return nil
+}
+
+// formatFloat returns the default JSON text of the number f:
+// without a fraction if it is an integer, and otherwise in
+// the shortest form of the %g verb, which may use an exponent.
+func formatFloat(f float64) string {
+	if float64(int64(f)) == f {
+		return strconv.FormatInt(int64(f), 10)
+	} else if float64(uint64(f)) == f {
+		return strconv.FormatUint(uint64(f), 10)
+	}
+	return fmt.Sprintf("%g", f)
+}
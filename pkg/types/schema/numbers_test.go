@@ -0,0 +1,162 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft04"
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+func TestExactConstEnum(t *testing.T) {
+	// 2^53+1 is the first integer that a float64 can't represent.
+	tests := []struct {
+		schema   string
+		instance any
+		valid    bool
+	}{
+		{`{"const": 9007199254740993}`, json.Number("9007199254740993"), true},
+		{`{"const": 9007199254740993}`, uint64(9007199254740993), true},
+		{`{"const": 9007199254740993}`, json.Number("9007199254740992"), false},
+		{`{"const": 9007199254740993}`, float64(9007199254740992), false},
+		{`{"const": 9007199254740992}`, float64(9007199254740992), true},
+		{`{"const": 12345678901234567890123}`, json.Number("12345678901234567890123"), true},
+		{`{"const": 12345678901234567890123}`, json.Number("12345678901234567890124"), false},
+		{`{"enum": [1, 9007199254740993]}`, json.Number("9007199254740993"), true},
+		{`{"enum": [1, 9007199254740993]}`, json.Number("9007199254740994"), false},
+		{`{"enum": [{"id": 9007199254740993}]}`, map[string]any{"id": json.Number("9007199254740993")}, true},
+		{`{"enum": [{"id": 9007199254740993}]}`, map[string]any{"id": json.Number("9007199254740992")}, false},
+		{`{"const": 0.1}`, 0.1, true},
+		{`{"const": 1.0}`, 1, true},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		src := `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + test.schema[1:]
+		if err := json.Unmarshal([]byte(src), &s); err != nil {
+			t.Fatalf("%s: %v", test.schema, err)
+		}
+		if err := s.Validate(test.instance); (err == nil) != test.valid {
+			t.Errorf("%s with %#v: got error %v, want valid %t", test.schema, test.instance, err, test.valid)
+		}
+	}
+
+	// The exact value is kept when marshaling.
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "const": 9007199254740993}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","const":9007199254740993}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+// TestDuplicateNumberKeyword checks that a repeated keyword replaces
+// the earlier one when the earlier one also recorded its literal.
+func TestDuplicateNumberKeyword(t *testing.T) {
+	tests := []struct {
+		src      string
+		instance float64
+		valid    bool
+		want     string
+	}{
+		{`{"minimum": 1.0, "maximum": 5, "minimum": 2}`, 1.5, false, `{"maximum":5,"minimum":2}`},
+		{`{"minimum": 1.0, "maximum": 5, "minimum": 2}`, 2, true, `{"maximum":5,"minimum":2}`},
+		{`{"minimum": 1, "maximum": 5, "minimum": 2.0}`, 1.5, false, `{"maximum":5,"minimum":2.0}`},
+		{`{"minimum": 1.0, "minimum": 2.0}`, 1.5, false, `{"minimum":2.0}`},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		if err := json.Unmarshal([]byte(test.src), &s); err != nil {
+			t.Fatalf("%s: %v", test.src, err)
+		}
+		if err := s.Validate(test.instance); (err == nil) != test.valid {
+			t.Errorf("%s with %v: got error %v, want valid %t", test.src, test.instance, err, test.valid)
+		}
+		data, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"$schema":"https://json-schema.org/draft/2020-12/schema",` + test.want[1:]; string(data) != want {
+			t.Errorf("%s: marshaled as %s, want %s", test.src, data, want)
+		}
+	}
+}
+
+// TestExactLimits checks that a limit that a float64 can't represent
+// is compared exactly, as it was written.
+func TestExactLimits(t *testing.T) {
+	const max64 = "18446744073709551615" // 2^64-1
+	tests := []struct {
+		schema   string
+		instance any
+		valid    bool
+	}{
+		{`{"maximum": ` + max64 + `}`, json.Number(max64), true},
+		{`{"maximum": ` + max64 + `}`, uint64(math.MaxUint64), true},
+		{`{"maximum": ` + max64 + `}`, json.Number("18446744073709551616"), false},
+		{`{"maximum": ` + max64 + `}`, float64(1 << 64), false},
+		{`{"exclusiveMaximum": ` + max64 + `}`, json.Number("18446744073709551614"), true},
+		{`{"exclusiveMaximum": ` + max64 + `}`, uint64(math.MaxUint64), false},
+		{`{"minimum": -` + max64 + `}`, json.Number("-" + max64), true},
+		{`{"minimum": -` + max64 + `}`, float64(-(1 << 64)), false},
+		{`{"exclusiveMinimum": 9007199254740993}`, json.Number("9007199254740993"), false},
+		{`{"exclusiveMinimum": 9007199254740993}`, json.Number("9007199254740994"), true},
+		{`{"multipleOf": 9007199254740993}`, json.Number("18014398509481986"), true},
+		{`{"multipleOf": 9007199254740993}`, json.Number("18014398509481984"), false},
+		{`{"multipleOf": 9007199254740993}`, float64(1 << 54), false},
+		// The exact limit applies in a subschema too.
+		{`{"items": {"maximum": ` + max64 + `}}`, []any{json.Number("18446744073709551616")}, false},
+		{`{"items": {"maximum": ` + max64 + `}}`, []any{json.Number(max64)}, true},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		src := `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + test.schema[1:]
+		if err := json.Unmarshal([]byte(src), &s); err != nil {
+			t.Fatalf("%s: %v", test.schema, err)
+		}
+		if err := s.Validate(test.instance); (err == nil) != test.valid {
+			t.Errorf("%s with %#v: got error %v, want valid %t", test.schema, test.instance, err, test.valid)
+		}
+	}
+
+	// Draft-04 has boolean exclusive limits.
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(`{"$schema": "http://json-schema.org/draft-04/schema#", "maximum": `+max64+`, "exclusiveMaximum": true}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(json.Number("18446744073709551614")); err != nil {
+		t.Errorf("draft-04: %v", err)
+	}
+	if err := s.Validate(json.Number(max64)); err == nil {
+		t.Error("draft-04: no error at the exclusive limit")
+	}
+
+	// The literal is kept when marshaling.
+	s = schema.Schema{}
+	if err := json.Unmarshal([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "maximum": `+max64+`}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","maximum":` + max64 + `}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+
+	// A number beyond the range of a float64 is still an error.
+	if err := json.Unmarshal([]byte(`{"maximum": 1`+strings.Repeat("0", 400)+`}`), &s); err == nil {
+		t.Error("out of range maximum: no error")
+	}
+}
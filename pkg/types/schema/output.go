@@ -0,0 +1,491 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+)
+
+// OutputFormat is one of the output formats defined by
+// the JSON schema specification, for [Schema.ValidateDetailed].
+type OutputFormat string
+
+const (
+	// OutputFlag reports only whether the instance is valid.
+	OutputFlag OutputFormat = "flag"
+	// OutputBasic reports the errors as a flat list.
+	OutputBasic OutputFormat = "basic"
+	// OutputDetailed reports the errors as a tree that follows
+	// the structure of the schema, omitting the parts of the
+	// schema that matched, and condensing units with one child.
+	OutputDetailed OutputFormat = "detailed"
+	// OutputVerbose reports the result of every keyword
+	// that was evaluated, as a tree that follows the
+	// structure of the schema.
+	OutputVerbose OutputFormat = "verbose"
+)
+
+// OutputUnit is a unit of validation output, as defined by the
+// JSON schema specification. Locations are JSON pointers:
+// the root of the schema or instance is the empty string.
+type OutputUnit struct {
+	// Valid reports whether the instance location
+	// is valid against the keyword location.
+	Valid bool `json:"valid"`
+	// KeywordLocation is the location of the keyword or
+	// subschema, following references from the root schema.
+	KeywordLocation string `json:"keywordLocation"`
+	// InstanceLocation is the location in the instance.
+	InstanceLocation string `json:"instanceLocation"`
+	// Error is the error message for the keyword, if any.
+	Error string `json:"error,omitempty"`
+	// Errors are the nested units of a unit that is not valid.
+	Errors []*OutputUnit `json:"errors,omitempty"`
+	// Annotation is the value of an annotation keyword,
+	// such as title or default, if the unit is for one.
+	Annotation any `json:"annotation,omitempty"`
+	// Annotations are the nested units of a valid unit:
+	// those that hold annotations in the detailed format,
+	// and all of them in the verbose format.
+	Annotations []*OutputUnit `json:"annotations,omitempty"`
+	// Patch is a JSON patch that would fix the failure of the
+	// keyword, if [ValidateOpts.SuggestPatches] is set and there
+	// is an obvious fix. Its paths are relative to the root of the
+	// instance. It is not part of the specified output formats.
+	Patch []PatchOperation `json:"patch,omitempty"`
+
+	// flagOnly is set for the flag format,
+	// which is marshaled with only the valid field.
+	flagOnly bool
+	// keyword is the name of the keyword, for a keyword unit.
+	keyword string
+	// part is the keyword of a keyword unit, in the schema parent,
+	// and instance is the value at InstanceLocation, for tracing.
+	part     *Part
+	parent   *Schema
+	instance any
+	// children are the nested units during validation.
+	children []*OutputUnit
+}
+
+// MarshalJSON marshals u. This implements [json.Marshaler].
+func (u *OutputUnit) MarshalJSON() ([]byte, error) {
+	if u.flagOnly {
+		return json.Marshal(struct {
+			Valid bool `json:"valid"`
+		}{u.Valid})
+	}
+	type plain OutputUnit
+	return json.Marshal((*plain)(u))
+}
+
+// ValidateDetailed is like ValidateWithOpts, but returns the result
+// in the output format opts.OutputFormat, which defaults to
+// [OutputBasic]. The error result is only for a problem with the
+// schema; a validation failure is reported by the output unit.
+//
+// Locations in the output follow references, so the
+// absoluteKeywordLocation field is not reported here; it is
+// set in the errors returned by [Schema.ValidateWithOpts].
+// The annotations of a schema that the instance does not
+// match are dropped, as the specification requires.
+func (s *Schema) ValidateDetailed(instance any, opts *ValidateOpts) (*OutputUnit, error) {
+	format := OutputBasic
+	if opts != nil && opts.OutputFormat != "" {
+		format = opts.OutputFormat
+	}
+
+	root, err := s.validateUnits(context.Background(), instance, opts)
+	if err != nil && !IsValidationError(err) {
+		return nil, err
+	}
+
+	switch format {
+	case OutputFlag:
+		return &OutputUnit{Valid: root.Valid, flagOnly: true}, nil
+	case OutputBasic:
+		ret := &OutputUnit{Valid: root.Valid}
+		if !root.Valid {
+			ret.Errors = root.flatten(nil)
+		}
+		return ret, nil
+	case OutputDetailed:
+		return root.detailed(true), nil
+	case OutputVerbose:
+		return root.verbose(true), nil
+	default:
+		return nil, errors2.AsSchemaError(&unknownOutputFormatError{format})
+	}
+}
+
+// Annotation is an annotation collected by [Schema.Annotations].
+type Annotation struct {
+	// Keyword is the name of the annotation keyword.
+	Keyword string
+	// KeywordLocation is the location of the keyword,
+	// following references from the root schema.
+	KeywordLocation string
+	// InstanceLocation is the location in the instance
+	// that the annotation applies to.
+	InstanceLocation string
+	// Value is the value of the keyword,
+	// such as a string for title or a bool for readOnly.
+	Value any
+}
+
+// Annotations validates instance against s, and returns the
+// annotations, such as title, description and default, that apply
+// to the locations in the instance, in the order they were found.
+// Annotations of schemas that don't match, such as a branch of
+// anyOf that failed, are not included.
+// If the instance is not valid, Annotations returns the
+// validation error, as for [Schema.ValidateWithOpts],
+// and no annotations.
+//
+// The annotation keywords are those with the Annotation
+// field of [Keyword] set, and those that call
+// [ValidationState.Annotate], such as oneOf if
+// opts.OneOfAnnotations is set.
+func (s *Schema) Annotations(instance any, opts *ValidateOpts) ([]Annotation, error) {
+	root, err := s.validateUnits(context.Background(), instance, opts)
+	if err != nil {
+		return nil, err
+	}
+	return root.annotations(nil), nil
+}
+
+// validateUnits validates instance against s,
+// and returns the output unit for s.
+// The error is as for ValidateContextWithOpts,
+// except that it does not check ctx.Err.
+func (s *Schema) validateUnits(ctx context.Context, instance any, opts *ValidateOpts) (*OutputUnit, error) {
+	var versionData any
+	top := &OutputUnit{}
+	state := &ValidationState{
+		Root:         s,
+		RootInstance: instance,
+		VersionData:  &versionData,
+		Opts:         opts,
+		ctx:          ctx,
+		out:          &outputNode{unit: top},
+	}
+	state.RootState = state
+	err := s.ValidateSubSchema(instance, state)
+	if err != nil && !IsValidationError(err) {
+		return nil, errors2.AsSchemaError(err)
+	}
+	if len(top.children) == 0 {
+		// Validation stopped before it started,
+		// as when ctx is already done.
+		return &OutputUnit{Valid: err == nil}, err
+	}
+	return top.children[0], err
+}
+
+// unknownOutputFormatError is returned by ValidateDetailed
+// for an output format it doesn't know.
+type unknownOutputFormatError struct {
+	format OutputFormat
+}
+
+func (e *unknownOutputFormatError) Error() string {
+	return "unknown output format " + string(e.format)
+}
+
+// outputNode records where output units are being added:
+// unit is the keyword unit, or the top unit for the root schema.
+// The keyword is part, in the schema at schemaLoc.
+type outputNode struct {
+	unit      *OutputUnit
+	schemaLoc string
+	part      *Part
+}
+
+// childLocation returns the keyword location of the subschema s,
+// which is being evaluated by the keyword of n.
+func (n *outputNode) childLocation(s *Schema) string {
+	if n.part == nil {
+		return n.schemaLoc
+	}
+	one := Schema{Parts: []Part{*n.part}}
+	for name, sub := range one.Children() {
+		if sub == s {
+			return n.schemaLoc + "/" + name
+		}
+	}
+	// A referenced schema is reported at the
+	// location of the reference.
+	return n.unit.KeywordLocation
+}
+
+// validateOutput is ValidateSubSchema or, if inPlace is set,
+// ValidateInPlaceSchema, when collecting output units.
+// It records a unit for s and for each of its keywords.
+func (s *Schema) validateOutput(instance any, state *ValidationState, inPlace bool) error {
+	subState, err := state.Child()
+	if err != nil {
+		return err
+	}
+
+	u := &OutputUnit{
+		Valid:            true,
+		KeywordLocation:  state.out.childLocation(s),
+		InstanceLocation: strings.TrimPrefix(state.InstancePointer(), "#"),
+	}
+	state.out.unit.children = append(state.out.unit.children, u)
+
+	subState.Schema = s
+	if !inPlace {
+		subState.cache = new(instanceCache)
+	}
+	// Evaluate every subschema, as for an unevaluated keyword,
+	// so that the output is complete.
+	subState.notesNeeded = true
+
+	var topErr error
+	for i, p := range s.Parts {
+		if p.Keyword.Validate == nil {
+			continue
+		}
+		subState.Index = i
+
+		// A boolean schema, and generated keywords,
+		// are reported as part of the schema unit.
+		ku := u
+		if !p.Keyword.Generated && p.Keyword != &BoolKeyword {
+			ku = &OutputUnit{
+				Valid:            true,
+				KeywordLocation:  u.KeywordLocation + "/" + pointertoken.Escape(p.Keyword.Name),
+				InstanceLocation: u.InstanceLocation,
+				keyword:          p.Keyword.Name,
+				part:             &s.Parts[i],
+				parent:           s,
+				instance:         instance,
+			}
+			u.children = append(u.children, ku)
+			if p.Keyword.Annotation {
+				ku.Annotation = annotationValue(p.Value)
+			}
+		}
+		subState.out = &outputNode{
+			unit:      ku,
+			schemaLoc: u.KeywordLocation,
+			part:      &s.Parts[i],
+		}
+
+		err := p.Keyword.Validate(p.Value, instance, subState)
+		if err == nil {
+			continue
+		}
+		state.locateErrors(err, s, p.Keyword.Name)
+		addKeywordError(&topErr, err, p.Keyword)
+		if IsValidationError(err) {
+			ku.Valid = false
+			ku.addError(err, p.Keyword.Name)
+			if ku != u && state.Opts != nil && state.Opts.SuggestPatches {
+				ku.Patch = suggestPatch(&s.Parts[i], s, instance, ku.InstanceLocation)
+			}
+		}
+	}
+
+	if inPlace {
+		state.Notes.AddNotes(subState.Notes)
+	}
+	if topErr != nil {
+		u.Valid = false
+		if src, ok := s.Source(); ok {
+			addErrorSource(topErr, src)
+		}
+	}
+	return topErr
+}
+
+// addError records the messages of the validation error err,
+// reported by the keyword name, that are not already
+// reported by the nested units of u.
+func (u *OutputUnit) addError(err error, name string) {
+	var own, all []string
+	for _, ve := range validationErrorList(err) {
+		all = append(all, ve.Message)
+		switch ve.KeywordLocation {
+		case "", "#", "#/" + pointertoken.Escape(name):
+			own = append(own, ve.Message)
+		}
+	}
+	if len(own) == 0 && !u.hasInvalidChild() {
+		own = all
+	}
+	if len(own) > 0 {
+		if u.Error != "" {
+			own = append([]string{u.Error}, own...)
+		}
+		u.Error = strings.Join(own, "; ")
+	}
+}
+
+// validationErrorList returns the validation errors in err.
+func validationErrorList(err error) []*errors2.ValidationError {
+	switch e := err.(type) {
+	case *errors2.ValidationError:
+		return []*errors2.ValidationError{e}
+	case *errors2.ValidationErrors:
+		return e.Errs
+	}
+	return nil
+}
+
+// hasInvalidChild reports whether any nested unit of u is not valid.
+func (u *OutputUnit) hasInvalidChild() bool {
+	for _, c := range u.children {
+		if !c.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten appends to list the units under u that are not valid
+// and have an error message, without their nested units,
+// for the basic format.
+func (u *OutputUnit) flatten(list []*OutputUnit) []*OutputUnit {
+	if u.Valid {
+		return list
+	}
+	if u.Error != "" {
+		list = append(list, &OutputUnit{
+			KeywordLocation:  u.KeywordLocation,
+			InstanceLocation: u.InstanceLocation,
+			Error:            u.Error,
+			Patch:            u.Patch,
+		})
+	}
+	for _, c := range u.children {
+		list = c.flatten(list)
+	}
+	return list
+}
+
+// detailed returns the unit u in the detailed format.
+// A valid unit keeps the nested units that hold annotations,
+// and a unit that is not valid keeps those that are not valid.
+// Units other than the root that have no error message or
+// annotation, and a single nested unit, are replaced by that unit.
+func (u *OutputUnit) detailed(root bool) *OutputUnit {
+	ret := &OutputUnit{
+		Valid:            u.Valid,
+		KeywordLocation:  u.KeywordLocation,
+		InstanceLocation: u.InstanceLocation,
+		Error:            u.Error,
+		Patch:            u.Patch,
+	}
+	if u.Valid {
+		ret.Annotation = u.Annotation
+		for _, c := range u.children {
+			if c.Valid && c.hasAnnotations() {
+				ret.Annotations = append(ret.Annotations, c.detailed(false))
+			}
+		}
+		if !root && ret.Annotation == nil && len(ret.Annotations) == 1 {
+			return ret.Annotations[0]
+		}
+		return ret
+	}
+	for _, c := range u.children {
+		if !c.Valid {
+			ret.Errors = append(ret.Errors, c.detailed(false))
+		}
+	}
+	if !root && ret.Error == "" && len(ret.Errors) == 1 {
+		return ret.Errors[0]
+	}
+	return ret
+}
+
+// verbose returns the unit u in the verbose format.
+// If keep is false, u is within a unit that is not valid,
+// and its annotations are dropped.
+func (u *OutputUnit) verbose(keep bool) *OutputUnit {
+	keep = keep && u.Valid
+	ret := &OutputUnit{
+		Valid:            u.Valid,
+		KeywordLocation:  u.KeywordLocation,
+		InstanceLocation: u.InstanceLocation,
+		Error:            u.Error,
+		Patch:            u.Patch,
+	}
+	if keep {
+		ret.Annotation = u.Annotation
+	}
+	for _, c := range u.children {
+		if u.Valid {
+			ret.Annotations = append(ret.Annotations, c.verbose(keep))
+		} else {
+			ret.Errors = append(ret.Errors, c.verbose(keep))
+		}
+	}
+	return ret
+}
+
+// hasAnnotations reports whether u, or a valid unit nested in it,
+// holds an annotation that is kept.
+func (u *OutputUnit) hasAnnotations() bool {
+	if !u.Valid {
+		return false
+	}
+	if u.Annotation != nil {
+		return true
+	}
+	for _, c := range u.children {
+		if c.hasAnnotations() {
+			return true
+		}
+	}
+	return false
+}
+
+// annotations appends to list the annotations held by u
+// and the valid units nested in it.
+func (u *OutputUnit) annotations(list []Annotation) []Annotation {
+	if !u.Valid {
+		return list
+	}
+	if u.Annotation != nil {
+		list = append(list, Annotation{
+			Keyword:          u.keyword,
+			KeywordLocation:  u.KeywordLocation,
+			InstanceLocation: u.InstanceLocation,
+			Value:            u.Annotation,
+		})
+	}
+	for _, c := range u.children {
+		list = c.annotations(list)
+	}
+	return list
+}
+
+// annotationValue returns the value of an annotation keyword
+// whose argument is v, as the Go value that JSON decodes to.
+func annotationValue(v PartValue) any {
+	switch v := v.(type) {
+	case PartBool:
+		return bool(v)
+	case PartString:
+		return string(v)
+	case PartStrings:
+		return []string(v)
+	case PartInt:
+		return int64(v)
+	case PartFloat:
+		return float64(v)
+	case PartAny:
+		return v.V
+	}
+	return v
+}
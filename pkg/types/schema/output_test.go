@@ -0,0 +1,242 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// outputSchema has nested anyOf, oneOf and $ref applicators.
+const outputSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {"pos": {"type": "integer", "minimum": 0}},
+	"properties": {
+		"a": {"anyOf": [{"type": "string"}, {"$ref": "#/$defs/pos"}]},
+		"b": {"oneOf": [{"type": "integer"}, {"type": "number"}]},
+		"c/d": {"items": {"$ref": "#/$defs/pos"}}
+	}
+}`
+
+func TestValidateDetailed(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(outputSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		instance string
+		format   schema.OutputFormat
+		// want is the output, as written by writeUnit.
+		want string
+	}{
+		{
+			"valid basic",
+			`{"a": 1, "b": 1.5, "c/d": [0]}`,
+			schema.OutputBasic,
+			`valid "" ""`,
+		},
+		{
+			"default format",
+			`{"a": -1}`,
+			"",
+			`
+invalid "" ""
+  invalid "/properties/a/anyOf" "/a" error
+  invalid "/properties/a/anyOf/0/type" "/a" error
+  invalid "/properties/a/anyOf/1/$ref/minimum" "/a" error`,
+		},
+		{
+			"anyOf basic",
+			`{"a": -1}`,
+			schema.OutputBasic,
+			`
+invalid "" ""
+  invalid "/properties/a/anyOf" "/a" error
+  invalid "/properties/a/anyOf/0/type" "/a" error
+  invalid "/properties/a/anyOf/1/$ref/minimum" "/a" error`,
+		},
+		{
+			"anyOf detailed",
+			`{"a": -1}`,
+			schema.OutputDetailed,
+			`
+invalid "" ""
+  invalid "/properties/a/anyOf" "/a" error
+    invalid "/properties/a/anyOf/0/type" "/a" error
+    invalid "/properties/a/anyOf/1/$ref/minimum" "/a" error`,
+		},
+		{
+			"anyOf and oneOf detailed",
+			`{"a": -1, "b": 1}`,
+			schema.OutputDetailed,
+			`
+invalid "" ""
+  invalid "/properties" ""
+    invalid "/properties/a/anyOf" "/a" error
+      invalid "/properties/a/anyOf/0/type" "/a" error
+      invalid "/properties/a/anyOf/1/$ref/minimum" "/a" error
+    invalid "/properties/b/oneOf" "/b" error`,
+		},
+		{
+			"oneOf no match detailed",
+			`{"b": "x"}`,
+			schema.OutputDetailed,
+			`
+invalid "" ""
+  invalid "/properties/b/oneOf" "/b" error
+    invalid "/properties/b/oneOf/0/type" "/b" error
+    invalid "/properties/b/oneOf/1/type" "/b" error`,
+		},
+		{
+			"oneOf verbose",
+			`{"b": 1}`,
+			schema.OutputVerbose,
+			`
+invalid "" ""
+  valid "/$defs" ""
+  valid "/$schema" ""
+  invalid "/properties" ""
+    invalid "/properties/b" "/b"
+      invalid "/properties/b/oneOf" "/b" error
+        valid "/properties/b/oneOf/0" "/b"
+          valid "/properties/b/oneOf/0/type" "/b"
+        valid "/properties/b/oneOf/1" "/b"
+          valid "/properties/b/oneOf/1/type" "/b"`,
+		},
+		{
+			"ref in items detailed",
+			`{"c/d": [0, -1]}`,
+			schema.OutputDetailed,
+			`
+invalid "" ""
+  invalid "/properties/c~1d/items/$ref/minimum" "/c~1d/1" error`,
+		},
+		{
+			"ref verbose",
+			`{"c/d": [-1]}`,
+			schema.OutputVerbose,
+			`
+invalid "" ""
+  valid "/$defs" ""
+  valid "/$schema" ""
+  invalid "/properties" ""
+    invalid "/properties/c~1d" "/c~1d"
+      invalid "/properties/c~1d/items" "/c~1d"
+        invalid "/properties/c~1d/items" "/c~1d/0"
+          invalid "/properties/c~1d/items/$ref" "/c~1d/0"
+            invalid "/properties/c~1d/items/$ref" "/c~1d/0"
+              invalid "/properties/c~1d/items/$ref/minimum" "/c~1d/0" error
+              valid "/properties/c~1d/items/$ref/type" "/c~1d/0"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var instance any
+			if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			u, err := s.ValidateDetailed(instance, &schema.ValidateOpts{OutputFormat: test.format})
+			if err != nil {
+				t.Fatalf("ValidateDetailed failed: %v", err)
+			}
+			var sb strings.Builder
+			writeUnit(&sb, u, 0)
+			want := strings.TrimPrefix(test.want, "\n") + "\n"
+			if got := sb.String(); got != want {
+				t.Errorf("got output\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// writeUnit writes u and its nested units to sb, one per line,
+// indented by depth. Error messages are not written, only
+// whether there is one. The nested units are sorted by location,
+// as the properties of an instance are validated in no set order.
+func writeUnit(sb *strings.Builder, u *schema.OutputUnit, depth int) {
+	valid := "invalid"
+	if u.Valid {
+		valid = "valid"
+	}
+	fmt.Fprintf(sb, "%s%s %q %q", strings.Repeat("  ", depth), valid, u.KeywordLocation, u.InstanceLocation)
+	if u.Error != "" {
+		sb.WriteString(" error")
+	}
+	sb.WriteString("\n")
+	for _, units := range [][]*schema.OutputUnit{u.Errors, u.Annotations} {
+		units = slices.Clone(units)
+		slices.SortStableFunc(units, func(a, b *schema.OutputUnit) int {
+			return cmp.Or(strings.Compare(a.KeywordLocation, b.KeywordLocation),
+				strings.Compare(a.InstanceLocation, b.InstanceLocation))
+		})
+		for _, c := range units {
+			writeUnit(sb, c, depth+1)
+		}
+	}
+}
+
+func TestValidateDetailedJSON(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(outputSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		instance string
+		format   schema.OutputFormat
+		want     string
+	}{
+		{`{"a": "x"}`, schema.OutputFlag, `{"valid":true}`},
+		{`{"a": -1}`, schema.OutputFlag, `{"valid":false}`},
+		{`{"a": "x"}`, schema.OutputBasic, `{"valid":true,"keywordLocation":"","instanceLocation":""}`},
+		{
+			`{"b": null}`,
+			schema.OutputBasic,
+			`{"valid":false,"keywordLocation":"","instanceLocation":"","errors":[{"valid":false,"keywordLocation":"/properties/b/oneOf","instanceLocation":"/b","error":"no match for \"oneof\" schema"},{"valid":false,"keywordLocation":"/properties/b/oneOf/0/type","instanceLocation":"/b","error":"instance has type \"null\", want \"integer\""},{"valid":false,"keywordLocation":"/properties/b/oneOf/1/type","instanceLocation":"/b","error":"instance has type \"null\", want \"number\""}]}`,
+		},
+	}
+	for _, test := range tests {
+		var instance any
+		if err := json.Unmarshal([]byte(test.instance), &instance); err != nil {
+			t.Fatal(err)
+		}
+		u, err := s.ValidateDetailed(instance, &schema.ValidateOpts{OutputFormat: test.format})
+		if err != nil {
+			t.Errorf("%s %s: ValidateDetailed failed: %v", test.instance, test.format, err)
+			continue
+		}
+		got, err := json.Marshal(u)
+		if err != nil {
+			t.Errorf("%s %s: Marshal failed: %v", test.instance, test.format, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s %s: got %s, want %s", test.instance, test.format, got, test.want)
+		}
+	}
+}
+
+func TestValidateDetailedUnknownFormat(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(outputSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.ValidateDetailed(map[string]any{}, &schema.ValidateOpts{OutputFormat: "list"})
+	if err == nil {
+		t.Fatalf("ValidateDetailed with unknown format succeeded with %v", u)
+	}
+	if !strings.Contains(err.Error(), "unknown output format list") {
+		t.Errorf("got error %q, want unknown output format", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+// incompleteKeywords are the keywords whose failures mean that
+// an instance is incomplete, rather than wrong.
+var incompleteKeywords = map[string]bool{
+	"required":          true,
+	"dependentRequired": true,
+	"minProperties":     true,
+}
+
+// ValidatePartial validates an instance that may be incomplete,
+// such as a form that is saved before it is done. It tells what is
+// missing from the instance apart from what is wrong with it.
+//
+// The error result is for what is wrong: it is the result of
+// validating instance as though the required, dependentRequired and
+// minProperties keywords always matched; see
+// [ValidateOpts.DeferRequired]. The incomplete result lists the
+// failures of those keywords, which are met by adding properties.
+// Both are reported if the instance is wrong and incomplete.
+//
+// A keyword that requires properties within a branch of anyOf or
+// oneOf, or within not, may change which branches match; such a
+// failure is reported as a failure of the branching keyword,
+// if at all, rather than as an incomplete finding.
+func (s *Schema) ValidatePartial(instance any, opts *ValidateOpts) (incomplete []*ValidationError, err error) {
+	var popts ValidateOpts
+	if opts != nil {
+		popts = *opts
+	}
+	popts.DeferRequired = true
+	verr := s.ValidateWithOpts(instance, &popts)
+	if verr != nil && !IsValidationError(verr) {
+		return nil, verr
+	}
+
+	// Validate again, reporting every failure,
+	// to find those of the deferred keywords.
+	popts.DeferRequired = false
+	popts.Collector = nil
+	popts.Trace = nil
+	full := s.ValidateWithOpts(instance, &popts)
+	if full != nil && !IsValidationError(full) {
+		return nil, full
+	}
+	for _, ve := range validationErrorList(full) {
+		if incompleteKeywords[keywordFailure(ve).Keyword] {
+			incomplete = append(incomplete, ve)
+		}
+	}
+	return incomplete, verr
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+)
+
+// PatchOperation is an operation of a JSON patch, as defined by
+// RFC 6902. It is used to suggest a change to an instance that
+// fixes a validation failure; see [ValidateOpts.SuggestPatches].
+type PatchOperation struct {
+	// Op is the operation: "add" or "replace".
+	Op string `json:"op"`
+	// Path is the JSON pointer of the value to add or replace.
+	Path string `json:"path"`
+	// Value is the new value.
+	Value any `json:"value"`
+}
+
+// suggestPatch returns a JSON patch that would fix the failure of
+// the keyword p, of schema s, at instance, which is at the instance
+// location loc. It returns nil if it has no suggestion.
+//
+// The suggestions are for common failures that have one obvious fix:
+// a required property that is missing and has a default value;
+// a value of the wrong type that can be converted to the right type,
+// such as the string "12" where an integer is wanted; and a number
+// beyond a limit, which is replaced by the nearest permitted number.
+// Only the failing keyword is considered, so the patched
+// instance may still fail other keywords.
+func suggestPatch(p *Part, s *Schema, instance any, loc string) []PatchOperation {
+	replace := func(v any) []PatchOperation {
+		return []PatchOperation{{Op: "replace", Path: loc, Value: v}}
+	}
+
+	switch p.Keyword.Name {
+	case "required":
+		return suggestRequired(p.Value, s, instance, loc)
+
+	case "type":
+		tv, ok := p.Value.(PartStringOrStrings)
+		if !ok {
+			return nil
+		}
+		types := tv.Strings
+		if tv.String != "" {
+			types = []string{tv.String}
+		}
+		for _, typ := range types {
+			if v, ok := coerce(instance, typ); ok {
+				return replace(v)
+			}
+		}
+
+	case "const":
+		if v, ok := p.Value.(PartAny); ok {
+			return replace(v.V)
+		}
+
+	case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+		limit, ok := p.Value.(PartFloat)
+		if !ok {
+			return nil
+		}
+		f, ok := instanceNumber(instance)
+		if !ok {
+			return nil
+		}
+		lower := p.Keyword.Name == "minimum" || p.Keyword.Name == "exclusiveMinimum"
+		exclusive := p.Keyword.Name == "exclusiveMinimum" || p.Keyword.Name == "exclusiveMaximum"
+		if !exclusive {
+			// In draft-04 minimum and maximum are exclusive
+			// if their exclusive keyword is true.
+			other := "exclusiveMaximum"
+			if lower {
+				other = "exclusiveMinimum"
+			}
+			if pv, ok := s.LookupKeyword(other); ok {
+				b, ok := pv.(PartBool)
+				exclusive = ok && bool(b)
+			}
+		}
+		if v, ok := nearest(float64(limit), lower, exclusive, f == math.Trunc(f)); ok {
+			return replace(v)
+		}
+	}
+	return nil
+}
+
+// suggestRequired returns a JSON patch that adds the properties
+// in the argument arg of a required keyword that are missing from
+// instance, if every one of them has a default in the properties
+// keyword of s.
+func suggestRequired(arg PartValue, s *Schema, instance any, loc string) []PatchOperation {
+	names, ok := arg.(PartStrings)
+	if !ok {
+		return nil
+	}
+	obj, ok := instance.(map[string]any)
+	if !ok {
+		return nil
+	}
+	pv, ok := s.LookupKeyword("properties")
+	if !ok {
+		return nil
+	}
+	props, ok := pv.(PartMapSchema)
+	if !ok {
+		return nil
+	}
+	var ops []PatchOperation
+	for _, name := range names {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		sub := props[name]
+		if sub == nil {
+			return nil
+		}
+		dv, ok := sub.LookupKeyword("default")
+		if !ok {
+			return nil
+		}
+		ops = append(ops, PatchOperation{
+			Op:    "add",
+			Path:  loc + "/" + pointertoken.Escape(name),
+			Value: annotationValue(dv),
+		})
+	}
+	return ops
+}
+
+// coerce converts the instance v to the JSON type typ,
+// if there is an obvious conversion that loses nothing.
+func coerce(v any, typ string) (any, bool) {
+	if s, ok := v.(string); ok {
+		switch typ {
+		case "integer", "number":
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil || math.IsInf(f, 0) || (typ == "integer" && f != math.Trunc(f)) {
+				return nil, false
+			}
+			return f, true
+		case "boolean":
+			if s == "true" || s == "false" {
+				return s == "true", true
+			}
+		case "null":
+			if s == "null" {
+				return nil, true
+			}
+		case "array":
+			return []any{v}, true
+		}
+		return nil, false
+	}
+	if b, ok := v.(bool); ok {
+		switch typ {
+		case "string":
+			return strconv.FormatBool(b), true
+		case "array":
+			return []any{v}, true
+		}
+		return nil, false
+	}
+	if f, ok := instanceNumber(v); ok {
+		switch typ {
+		case "string":
+			return strconv.FormatFloat(f, 'f', -1, 64), true
+		case "array":
+			return []any{v}, true
+		}
+		return nil, false
+	}
+	if typ == "array" {
+		if _, ok := v.(map[string]any); ok {
+			return []any{v}, true
+		}
+	}
+	return nil, false
+}
+
+// nearest returns the number nearest to limit that a minimum
+// keyword, if lower is set, or a maximum keyword permits.
+// An exclusive limit is only suggested for an integer,
+// as there is no nearest number that is not.
+func nearest(limit float64, lower, exclusive, integer bool) (float64, bool) {
+	if integer {
+		switch {
+		case lower && exclusive:
+			return math.Floor(limit) + 1, true
+		case lower:
+			return math.Ceil(limit), true
+		case exclusive:
+			return math.Ceil(limit) - 1, true
+		default:
+			return math.Floor(limit), true
+		}
+	}
+	return limit, !exclusive
+}
+
+// instanceNumber returns the value of an instance that is a number.
+func instanceNumber(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
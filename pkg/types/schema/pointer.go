@@ -0,0 +1,146 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+// ForInstancePointer returns the subschemas of s that apply to
+// the location ptr of an instance, such as "/user/address/zip",
+// so that a form generator or an editor can tell what constrains
+// the value there. The pointer may also be written as a URI
+// fragment, as in "#/user/address/zip"; "" is the instance itself.
+//
+// The subschemas are found by following the properties,
+// patternProperties, additionalProperties, prefixItems and items
+// keywords, and the schemas that those apply in place, through
+// $ref and allOf. The schema must be resolved.
+// As there is no instance, a token that is a number is taken to be
+// both a property name and an array index, and the branches of
+// anyOf, oneOf and if are not followed, as they depend on the
+// instance. An empty result means that nothing constrains
+// the location.
+func (s *Schema) ForInstancePointer(ptr string) ([]*Schema, error) {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr != "" && !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("instance pointer %q does not start with /", ptr)
+	}
+	cur := inPlaceSchemas([]*Schema{s})
+	if ptr == "" {
+		return cur, nil
+	}
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = pointertoken.Unescape(tok)
+		var next []*Schema
+		for _, as := range cur {
+			next = append(next, propertySubschemas(as, tok)...)
+			if i, err := strconv.Atoi(tok); err == nil && i >= 0 {
+				next = append(next, itemSubschemas(as, i)...)
+			}
+		}
+		cur = inPlaceSchemas(next)
+	}
+	return cur, nil
+}
+
+// inPlaceSchemas returns the schemas ss, and the schemas
+// that they apply in place through $ref and allOf.
+func inPlaceSchemas(ss []*Schema) []*Schema {
+	var ret []*Schema
+	seen := make(map[*Schema]bool)
+	var walk func(s *Schema)
+	walk = func(s *Schema) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		ret = append(ret, s)
+		for _, part := range s.Parts {
+			switch {
+			case part.Keyword.Name == ResolvedRefKeywordName:
+				walk(part.Value.(PartSchema).S)
+			case part.Keyword.Name == "allOf" && part.Keyword.ArgType == arg_type.ArgTypeSchemas:
+				for _, sub := range part.Value.(PartSchemas) {
+					walk(sub)
+				}
+			}
+		}
+	}
+	for _, s := range ss {
+		walk(s)
+	}
+	return ret
+}
+
+// propertySubschemas returns the subschemas of s
+// that apply to the property name of an object.
+// Those of patternProperties are in the order of the patterns.
+func propertySubschemas(s *Schema, name string) []*Schema {
+	var ret []*Schema
+	matched := false
+	if pv, ok := s.LookupKeyword("properties"); ok {
+		if sub, ok := pv.(PartMapSchema)[name]; ok {
+			ret = append(ret, sub)
+			matched = true
+		}
+	}
+	if pv, ok := s.LookupKeyword("patternProperties"); ok {
+		pm := pv.(PartMapSchema)
+		for _, pat := range slices.Sorted(maps.Keys(pm)) {
+			sub := pm[pat]
+			if re, err := CompileRegexp(pat); err == nil && re.MatchString(name) {
+				ret = append(ret, sub)
+				matched = true
+			}
+		}
+	}
+	if pv, ok := s.LookupKeyword("additionalProperties"); ok && !matched {
+		if sub, ok := pv.(PartSchema); ok {
+			ret = append(ret, sub.S)
+		}
+	}
+	return ret
+}
+
+// itemSubschemas returns the subschemas of s
+// that apply to the element i of an array.
+func itemSubschemas(s *Schema, i int) []*Schema {
+	if pv, ok := s.LookupKeyword("prefixItems"); ok {
+		if subs, ok := pv.(PartSchemas); ok && i < len(subs) {
+			return []*Schema{subs[i]}
+		}
+	}
+	pv, ok := s.LookupKeyword("items")
+	if !ok {
+		return nil
+	}
+	switch items := pv.(type) {
+	case PartSchema:
+		return []*Schema{items.S}
+	case PartSchemaOrSchemas:
+		// Before draft 2020-12, items may be a list,
+		// followed by additionalItems.
+		if items.Schema != nil {
+			return []*Schema{items.Schema}
+		}
+		if i < len(items.Schemas) {
+			return []*Schema{items.Schemas[i]}
+		}
+		if pv, ok := s.LookupKeyword("additionalItems"); ok {
+			if sub, ok := pv.(PartSchema); ok {
+				return []*Schema{sub.S}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// pointerSchema has a title on each subschema,
+// so that the test can tell which ones are found.
+const pointerSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "root",
+	"$defs": {
+		"addr": {
+			"title": "addr",
+			"properties": {"zip": {"title": "zip"}},
+			"additionalProperties": {"title": "addr-extra"}
+		}
+	},
+	"properties": {
+		"user": {
+			"title": "user",
+			"properties": {"address": {"title": "address", "$ref": "#/$defs/addr"}}
+		},
+		"a/b": {"title": "slash"},
+		"c~d": {"title": "tilde"},
+		"~1": {"title": "tilde-one"},
+		"0": {"title": "zero"},
+		"list": {
+			"title": "list",
+			"prefixItems": [{"title": "first"}],
+			"items": {"title": "rest"}
+		}
+	},
+	"patternProperties": {"^x-a": {"title": "xa"}, "^x-": {"title": "x"}},
+	"additionalProperties": {"title": "extra"},
+	"allOf": [{"title": "all", "properties": {"user": {"title": "user2"}}}]
+}`
+
+func TestForInstancePointer(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(pointerSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ptr string
+		// want is the titles of the schemas, in order.
+		want []string
+	}{
+		{"", []string{"root", "all"}},
+		{"#", []string{"root", "all"}},
+		{"/user", []string{"user", "user2"}},
+		{"#/user", []string{"user", "user2"}},
+		{"/user/address", []string{"address", "addr"}},
+		{"/user/address/zip", []string{"zip"}},
+		{"/user/address/street", []string{"addr-extra"}},
+		{"/a~1b", []string{"slash"}},
+		{"/c~0d", []string{"tilde"}},
+		{"/~01", []string{"tilde-one"}},
+		{"/a/b", nil},
+		{"/x-b", []string{"x"}},
+		{"/x-ab", []string{"x", "xa"}},
+		{"/other", []string{"extra"}},
+		{"/0", []string{"zero"}},
+		{"/list/0", []string{"first"}},
+		{"/list/1", []string{"rest"}},
+		{"/list/-1", nil},
+		{"/list/name", nil},
+		{"/user/address/zip/code", nil},
+		{"/slash", []string{"extra"}},
+	}
+	for _, test := range tests {
+		got, err := s.ForInstancePointer(test.ptr)
+		if err != nil {
+			t.Errorf("ForInstancePointer(%q) failed: %v", test.ptr, err)
+			continue
+		}
+		var titles []string
+		for _, sub := range got {
+			title, _ := sub.LookupKeyword("title")
+			titles = append(titles, string(title.(schema.PartString)))
+		}
+		if !slices.Equal(titles, test.want) {
+			t.Errorf("ForInstancePointer(%q) got %q, want %q", test.ptr, titles, test.want)
+		}
+	}
+}
+
+func TestForInstancePointerError(t *testing.T) {
+	var s schema.Schema
+	if err := json.Unmarshal([]byte(pointerSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+	for _, ptr := range []string{"user", "#user"} {
+		if got, err := s.ForInstancePointer(ptr); err == nil {
+			t.Errorf("ForInstancePointer(%q) = %v, want error", ptr, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/draft04"
+	"github.com/altshiftab/jsonschema/pkg/draft06"
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestIgnoreRefSiblings checks that keywords next to $ref are
+// ignored by the vocabularies of draft-07 and earlier, and apply
+// from draft 2019-09 on, however the schema is decoded.
+func TestIgnoreRefSiblings(t *testing.T) {
+	// There is no draft-07 package, so draft-07 is
+	// draft 2020-12 with the rule of draft-07.
+	const draft07 = "http://json-schema.org/draft-07/schema"
+	vocab := *draft202012.Vocabulary
+	vocab.Name = "draft-07"
+	vocab.Schema = draft07
+	vocab.IgnoreRefSiblings = true
+	env := schema.NewEnvironment()
+	env.RegisterVocabulary(&vocab, false)
+
+	decoders := []struct {
+		name   string
+		decode func(src []byte) (*schema.Schema, error)
+	}{
+		{"UnmarshalJSON", func(src []byte) (*schema.Schema, error) {
+			var s schema.Schema
+			err := json.Unmarshal(src, &s)
+			return &s, err
+		}},
+		{"UnmarshalWithSource", func(src []byte) (*schema.Schema, error) {
+			return schema.UnmarshalWithSource(src, nil)
+		}},
+	}
+
+	tests := []struct {
+		schemaID string
+		// ignored is whether the siblings of $ref are ignored.
+		ignored bool
+	}{
+		{draft04.SchemaID, true},
+		{draft06.SchemaID, true},
+		{draft07, true},
+		{draft202012.SchemaID, false},
+	}
+	for _, test := range tests {
+		src := []byte(`{
+			"$schema": "` + test.schemaID + `#",
+			"definitions": {"int": {"type": "integer"}},
+			"$defs": {"int": {"type": "integer"}},
+			"properties": {
+				"p": {"$ref": "#/definitions/int", "maximum": 10}
+			}
+		}`)
+		instance := map[string]any{"p": 20.0}
+		check := func(name string, s *schema.Schema, err error) {
+			if err != nil {
+				t.Errorf("%s %s: %v", test.schemaID, name, err)
+				return
+			}
+			if err := s.Validate(instance); (err == nil) != test.ignored {
+				t.Errorf("%s %s: got error %v, want siblings ignored %t", test.schemaID, name, err, test.ignored)
+			}
+		}
+		if test.schemaID == draft07 {
+			s, err := env.Decode(context.Background(), src, nil)
+			check("Environment.Decode", s, err)
+			continue
+		}
+		for _, d := range decoders {
+			s, err := d.decode(src)
+			check(d.name, s, err)
+		}
+	}
+}
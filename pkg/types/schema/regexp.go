@@ -0,0 +1,56 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import "regexp"
+
+// Regexp is a compiled regular expression,
+// as returned by [CompileRegexp].
+// A [*regexp.Regexp] is a Regexp.
+type Regexp interface {
+	// MatchString reports whether the regular
+	// expression matches anywhere in s.
+	MatchString(s string) bool
+}
+
+// SetRegexpCompiler sets a function to compile the regular expressions
+// of the pattern and patternProperties keywords, and of the regex
+// format. This is a global property, as regular expressions are
+// compiled when schemas are decoded. It should be set before any
+// schema is decoded.
+// Callers should use appropriate locking.
+//
+// JSON schema regular expressions are those of ECMA-262, but by
+// default they are compiled by the regexp package, which doesn't
+// support such features as lookahead and backreferences.
+// A program may use another engine, such as regexp2 with its
+// ECMAScript option, by setting a function that wraps it:
+// the MatchString method of a regexp2 Regexp also returns an error,
+// so it is not itself a [Regexp].
+//
+// This returns the old function. The default function is nil,
+// which means that [regexp.Compile] is used.
+func SetRegexpCompiler(fn func(pattern string) (Regexp, error)) func(string) (Regexp, error) {
+	ret := regexpCompiler
+	regexpCompiler = fn
+	return ret
+}
+
+// regexpCompiler is the function set by SetRegexpCompiler.
+var regexpCompiler func(pattern string) (Regexp, error)
+
+// CompileRegexp compiles the regular expression pattern of a schema,
+// with the function set by [SetRegexpCompiler], if any,
+// or else with [regexp.Compile].
+func CompileRegexp(pattern string) (Regexp, error) {
+	if regexpCompiler != nil {
+		return regexpCompiler(pattern)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
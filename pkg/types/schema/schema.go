@@ -2,17 +2,24 @@ package schema
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"iter"
 	"maps"
 	"math"
+	"math/big"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
 	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
 	"github.com/altshiftab/jsonschema/pkg/notes"
 	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
@@ -40,26 +47,21 @@ func (s *Schema) Clone() *Schema {
 }
 
 // String returns a somewhat readable representation of a Schema.
-// The format differs from JSON output, and also includes internal
-// information not stored in JSON.
+// The format differs from JSON output. Generated keywords, which
+// hold internal information not stored in JSON, are left out.
 func (s *Schema) String() string {
 	var sb strings.Builder
 	sb.WriteString("Schema{")
-	for i, part := range s.Parts {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		val := part.Value
+	first := true
+	for _, part := range s.Parts {
 		if part.Keyword.Generated {
-			// Don't try to print schemas of generated keywords.
-			// They can cause infinite recursion.
-			switch part.Keyword.ArgType {
-			case arg_type.ArgTypeBool, arg_type.ArgTypeString, arg_type.ArgTypeStrings, arg_type.ArgTypeInt, arg_type.ArgTypeFloat:
-			default:
-				val = PartString("<not printed>")
-			}
+			continue
 		}
-		fmt.Fprintf(&sb, "{%s %v}", part.Keyword.Name, val)
+		if !first {
+			sb.WriteString(", ")
+		}
+		first = false
+		fmt.Fprintf(&sb, "{%s %v}", part.Keyword.Name, part.Value)
 	}
 	sb.WriteByte('}')
 	return sb.String()
@@ -99,24 +101,85 @@ func (s *Schema) Resolve(opts *ResolveOpts) error {
 			if part.Keyword == &SchemaKeyword {
 				v = LookupVocabulary(string(part.Value.(PartString)))
 				if v == nil {
-					return fmt.Errorf("no registered vocabularyPkg for schema %q when resolving", part.Value.(PartString))
+					return &SchemaError{Err: fmt.Errorf("no registered vocabularyPkg for schema %q when resolving", part.Value.(PartString))}
 				}
 				break
 			}
 		}
 		if v == nil {
-			return errors.New("unknown schema vocabularyPkg when resolving")
+			return &SchemaError{Err: errors.New("unknown schema vocabularyPkg when resolving")}
 		}
 	}
 
 	if opts == nil {
 		opts = &ResolveOpts{
 			Vocabulary: v,
-			Loader:     loader,
+			Loader:     globalLoader(),
+		}
+	}
+
+	if err := v.Resolve(s, opts); err != nil {
+		return errors2.AsSchemaError(err)
+	}
+
+	s.specialize()
+
+	return nil
+}
+
+// specialize marks the leaf schemas in s and its subschemas,
+// so that ValidateSubSchema can validate them directly.
+// Other schemas are marked with whether they have a keyword
+// that uses notes, so that validation can skip recording
+// notes that nothing will read.
+// This must be called after the schema has been resolved,
+// as resolving may add keywords that are not leaf keywords.
+func (s *Schema) specialize() {
+	// A schema with an exact number is not a leaf, so that
+	// its keywords can find the number through state.Schema.
+	isLeaf := !slices.ContainsFunc(s.Parts, func(p Part) bool {
+		return !p.Keyword.Leaf
+	}) && !s.hasExactNumbers()
+	mark := Part{
+		Keyword: &leafKeyword,
+		Value:   PartBool(true),
+	}
+	if !isLeaf {
+		mark = Part{
+			Keyword: &usesNotesKeyword,
+			Value:   PartBool(s.scanUsesNotes()),
 		}
 	}
+	if m := s.specializeMark(); m == nil || *m != mark {
+		// Replace the mark of an earlier call,
+		// as keywords may have been added since.
+		s.Parts = slices.DeleteFunc(s.Parts, func(p Part) bool {
+			return p.Keyword == &leafKeyword || p.Keyword == &usesNotesKeyword
+		})
+		s.Parts = append(s.Parts, mark)
+	}
+
+	for _, sub := range s.Children() {
+		sub.specialize()
+	}
+}
 
-	return v.Resolve(s, opts)
+// isLeaf reports whether specialize marked s as a leaf schema.
+func (s *Schema) isLeaf() bool {
+	m := s.specializeMark()
+	return m != nil && m.Keyword == &leafKeyword
+}
+
+// specializeMark returns the part that specialize added to s,
+// or nil if there is none. The mark is normally the last part,
+// so the parts are searched from the end.
+func (s *Schema) specializeMark() *Part {
+	for i := len(s.Parts) - 1; i >= 0; i-- {
+		if k := s.Parts[i].Keyword; k == &leafKeyword || k == &usesNotesKeyword {
+			return &s.Parts[i]
+		}
+	}
+	return nil
 }
 
 // Children returns an iterator over the immediate subschemas.
@@ -158,7 +221,7 @@ func (s *Schema) Children() iter.Seq2[string, *Schema] {
 					return strings.Compare(a.key, b.key)
 				})
 				for _, kv := range keyVals {
-					name := part.Keyword.Name + "/" + kv.key
+					name := part.Keyword.Name + "/" + pointertoken.Escape(kv.key)
 					if !yield(name, kv.val) {
 						return
 					}
@@ -196,11 +259,23 @@ func (s *Schema) Children() iter.Seq2[string, *Schema] {
 					return strings.Compare(a.key, b.key)
 				})
 				for _, kv := range keyVals {
-					name := part.Keyword.Name + "/" + kv.key
+					name := part.Keyword.Name + "/" + pointertoken.Escape(kv.key)
 					if !yield(name, kv.val) {
 						return
 					}
 				}
+
+			case arg_type.ArgTypeMapMapSchema:
+				// Sort for determinism.
+				m := part.Value.(PartMapMapSchema)
+				for _, k1 := range slices.Sorted(maps.Keys(m)) {
+					for _, k2 := range slices.Sorted(maps.Keys(m[k1])) {
+						name := part.Keyword.Name + "/" + pointertoken.Escape(k1) + "/" + pointertoken.Escape(k2)
+						if !yield(name, m[k1][k2]) {
+							return
+						}
+					}
+				}
 			}
 		}
 	}
@@ -209,16 +284,25 @@ func (s *Schema) Children() iter.Seq2[string, *Schema] {
 // MarshalJSON marshals a [Schema] into JSON format.
 // This implements [encoding/json.Marshaler].
 func (s *Schema) MarshalJSON() ([]byte, error) {
-	var buf bytes.Buffer
-	if err := s.marshalSchema(&buf); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	return s.MarshalWithOpts(nil)
 }
 
 // marshalSchema marshals a [Schema] into JSON format,
-// storing the results in buf.
-func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
+// storing the results in buf. ms is nil unless
+// the caller asked for shared subschemas to be hoisted.
+func (s *Schema) marshalSchema(buf *bytes.Buffer, ms *marshalState) error {
+	if ref, ok := ms.hoistedRef(s); ok {
+		fmt.Fprintf(buf, `{"$ref":%s}`, encodeString(ref))
+		return nil
+	}
+	if target, ok := ms.inlined(s); ok {
+		return target.marshalSchema(buf, ms)
+	}
+	return s.marshalParts(buf, ms)
+}
+
+// marshalParts marshals the keywords of a [Schema] into buf.
+func (s *Schema) marshalParts(buf *bytes.Buffer, ms *marshalState) error {
 	if isBoolSchema, isTrueSchema := s.isBoolSchema(); isBoolSchema {
 		if isTrueSchema {
 			buf.WriteString("true")
@@ -227,12 +311,13 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 		}
 		return nil
 	}
+	defer ms.enter(s)()
 
 	buf.WriteByte('{')
 
 	first := true
 	for _, part := range s.Parts {
-		if part.Keyword.Generated {
+		if part.Keyword.Generated || ms.skipPart(s, part) {
 			continue
 		}
 
@@ -244,6 +329,14 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 
 		fmt.Fprintf(buf, "%s:", encodeString(part.Keyword.Name))
 
+		if part.Keyword.Name == "$defs" && ms.isRoot(s) {
+			defs, _ := part.Value.(PartMapSchema)
+			if err := ms.marshalDefs(buf, defs); err != nil {
+				return err
+			}
+			continue
+		}
+
 		switch v := part.Value.(type) {
 		case PartBool:
 			fmt.Fprintf(buf, "%t", v)
@@ -274,15 +367,11 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 		case PartInt:
 			fmt.Fprintf(buf, "%d", v)
 		case PartFloat:
-			if PartFloat(int64(v)) == v {
-				fmt.Fprintf(buf, "%d", int64(v))
-			} else if PartFloat(uint64(v)) == v {
-				fmt.Fprintf(buf, "%d", uint64(v))
-			} else {
-				fmt.Fprintf(buf, "%g", v)
+			if err := s.marshalFloat(buf, ms, part.Keyword.Name, float64(v)); err != nil {
+				return err
 			}
 		case PartSchema:
-			if err := v.S.marshalSchema(buf); err != nil {
+			if err := v.S.marshalSchema(buf, ms); err != nil {
 				return err
 			}
 		case PartSchemas:
@@ -291,7 +380,7 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 				if i > 0 {
 					buf.WriteByte(',')
 				}
-				if err := schema.marshalSchema(buf); err != nil {
+				if err := schema.marshalSchema(buf, ms); err != nil {
 					return err
 				}
 			}
@@ -306,20 +395,23 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 					buf.WriteByte(',')
 				}
 				fmt.Fprintf(buf, "%s:", encodeString(name))
-				if err := v[name].marshalSchema(buf); err != nil {
+				if err := v[name].marshalSchema(buf, ms); err != nil {
 					return err
 				}
 			}
 			buf.WriteByte('}')
 		case PartSchemaOrSchemas:
 			if v.Schema != nil {
-				if err := v.Schema.marshalSchema(buf); err != nil {
+				if err := v.Schema.marshalSchema(buf, ms); err != nil {
 					return err
 				}
 			} else {
 				buf.WriteByte('[')
-				for _, schema := range v.Schemas {
-					if err := schema.marshalSchema(buf); err != nil {
+				for i, schema := range v.Schemas {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					if err := schema.marshalSchema(buf, ms); err != nil {
 						return err
 					}
 				}
@@ -337,7 +429,7 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 				fmt.Fprintf(buf, "%s:", encodeString(name))
 				as := v[name]
 				if as.Schema != nil {
-					if err := as.Schema.marshalSchema(buf); err != nil {
+					if err := as.Schema.marshalSchema(buf, ms); err != nil {
 						return err
 					}
 				} else {
@@ -352,6 +444,30 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 				}
 			}
 			buf.WriteByte('}')
+		case PartMapMapSchema:
+			buf.WriteByte('{')
+			// Sort the names for predictable results.
+			names := slices.Collect(maps.Keys(v))
+			slices.Sort(names)
+			for i, name := range names {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				fmt.Fprintf(buf, "%s:{", encodeString(name))
+				values := slices.Collect(maps.Keys(v[name]))
+				slices.Sort(values)
+				for j, value := range values {
+					if j > 0 {
+						buf.WriteByte(',')
+					}
+					fmt.Fprintf(buf, "%s:", encodeString(value))
+					if err := v[name][value].marshalSchema(buf, ms); err != nil {
+						return err
+					}
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteByte('}')
 		case PartAny:
 			if err := json.NewEncoder(buf).Encode(v.V); err != nil {
 				return err
@@ -361,6 +477,16 @@ func (s *Schema) marshalSchema(buf *bytes.Buffer) error {
 		}
 	}
 
+	if ms.isRoot(s) && !ms.wroteDefs && len(ms.names) > 0 {
+		if !first {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"$defs":`)
+		if err := ms.marshalDefs(buf, nil); err != nil {
+			return err
+		}
+	}
+
 	buf.WriteByte('}')
 
 	return nil
@@ -393,37 +519,44 @@ func encodeString(s string) []byte {
 }
 
 // UnmarshalJSON decodes the JSON representation of a [Schema].
-// This is fairly inefficient; we can probably do better with
-// encoding/json/v2.
+// The schema is built as the JSON is read, rather than from
+// a decoded empty interface value, which for a large document
+// saves most of the memory that the intermediate value would use.
 func (s *Schema) UnmarshalJSON(data []byte) error {
 	s.Parts = s.Parts[:0:0]
 
-	var v any
-	if err := json.Unmarshal(data, &v); err != nil {
+	if !json.Valid(data) {
+		// Report the syntax error as the decoder does.
+		_, err := decodeSchemaJSON(data)
 		return err
 	}
 
-	vocabulary, err := s.buildTopFromJSON("", nil, v)
+	vocabulary, err := s.buildTopFromStream(&decodeConfig{reg: &reg}, data)
 	if err != nil {
-		return err
+		return errors2.AsSchemaError(locateParseError(err, data, nil))
 	}
 
 	ropts := &ResolveOpts{
 		Vocabulary: vocabulary,
-		Loader:     loader,
+		Loader:     globalLoader(),
 	}
-	return s.Resolve(ropts)
+	if err := s.Resolve(ropts); err != nil {
+		return err
+	}
+	reportWarnings(s, vocabulary)
+	return nil
 }
 
 // buildTopFromJSON builds a [Schema] from JSON parsed into the
 // empty interface value v. This assumes that this is the root schema.
-func (s *Schema) buildTopFromJSON(schemaID string, uri *url.URL, v any) (*Vocabulary, error) {
+// The vocabulary is found as described by dc.
+func (s *Schema) buildTopFromJSON(dc *decodeConfig, schemaID string, uri *url.URL, v any) (*Vocabulary, error) {
 	var version string
 	if m, ok := v.(map[string]any); ok {
 		if schemaVal, ok := m["$schema"]; ok {
 			version, ok = schemaVal.(string)
 			if !ok {
-				return nil, errors.New("$schema does not have a string value")
+				return nil, wrapParseError(errors.New("$schema does not have a string value"), "$schema")
 			}
 			s.Parts = append(s.Parts,
 				Part{
@@ -442,7 +575,7 @@ func (s *Schema) buildTopFromJSON(schemaID string, uri *url.URL, v any) (*Vocabu
 
 	var vocabulary *Vocabulary
 	if version == "" {
-		vocabulary = DefaultVocabulary()
+		vocabulary = dc.reg.def()
 		if vocabulary == nil {
 			return nil, errors.New("JSON schema version not specified and there is no default")
 		}
@@ -454,9 +587,10 @@ func (s *Schema) buildTopFromJSON(schemaID string, uri *url.URL, v any) (*Vocabu
 			},
 		)
 	} else {
-		vocabulary = LookupVocabulary(version)
-		if vocabulary == nil {
-			return nil, fmt.Errorf("JSON schema version %q not recognized", version)
+		var err error
+		vocabulary, err = dc.vocabulary(version)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -474,18 +608,50 @@ func (s *Schema) buildTopFromJSON(schemaID string, uri *url.URL, v any) (*Vocabu
 // This can be useful in cases where it's not clear whether the
 // JSON encoding contains a schema or not.
 //
+// Numbers in v may be float64 values, as json.Unmarshal decodes
+// them, or [json.Number] values, as a [json.Decoder] decodes them
+// after a call to UseNumber. Use json.Number to keep the precision
+// of integer arguments that a float64 can't represent exactly.
+//
 // The optional schemaID argument is something like [draft202012.SchemaID].
 // The optional uri is where the schema was loaded from.
 //
 // It is normally necessary to call Resolve on the result.
 func SchemaFromJSON(schemaID string, uri *url.URL, v any) (*Schema, error) {
+	return schemaFromJSON(&decodeConfig{reg: &reg}, schemaID, uri, v)
+}
+
+// schemaFromJSON is like [SchemaFromJSON],
+// but finds the vocabulary as described by dc.
+func schemaFromJSON(dc *decodeConfig, schemaID string, uri *url.URL, v any) (*Schema, error) {
 	var s Schema
-	if _, err := s.buildTopFromJSON(schemaID, uri, v); err != nil {
-		return nil, err
+	if _, err := s.buildTopFromJSON(dc, schemaID, uri, v); err != nil {
+		return nil, errors2.AsSchemaError(err)
 	}
 	return &s, nil
 }
 
+// unknownPart returns a part for a keyword that is ignored,
+// either because it is unrecognized or because it is next to
+// a $ref in a vocabulary that ignores such keywords.
+// The part does not affect the validation result,
+// but is kept so that the schema marshals as it was.
+func unknownPart(keyword string, val any) (Part, error) {
+	val, err := plainNumbers(val)
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{
+		Keyword: &Keyword{
+			Name:     keyword,
+			ArgType:  arg_type.ArgTypeAny,
+			Validate: validateTrue,
+			Leaf:     true,
+		},
+		Value: PartAny{val},
+	}, nil
+}
+
 // buildFromJSON builds a [Schema] from JSON parsed into the
 // empty interface value v.
 func (s *Schema) buildFromJSON(v any, vocabulary *Vocabulary) error {
@@ -497,11 +663,23 @@ func (s *Schema) buildFromJSON(v any, vocabulary *Vocabulary) error {
 		})
 
 	case map[string]any:
+		_, hasRef := v["$ref"]
 		for keyword, val := range v {
+			if hasRef && vocabulary.IgnoreRefSiblings && keyword != "$ref" {
+				part, err := unknownPart(keyword, val)
+				if err != nil {
+					return wrapParseError(err, pointertoken.Escape(keyword))
+				}
+				s.Parts = append(s.Parts, part)
+				continue
+			}
 			if err := s.addKeywordFromJSON(keyword, val, vocabulary); err != nil {
-				return err
+				return wrapParseError(err, pointertoken.Escape(keyword))
 			}
 		}
+		if err := s.CheckArguments(); err != nil {
+			return err
+		}
 		s.Finalize(vocabulary)
 
 	default:
@@ -518,16 +696,14 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 
 	sk, ok := vocabulary.Keywords[keyword]
 	if !ok {
-		// Unrecognized keywords are ignored.
-		// They do not affect the validation result.
-		s.Parts = append(s.Parts, Part{
-			Keyword: &Keyword{
-				Name:     keyword,
-				ArgType:  arg_type.ArgTypeAny,
-				Validate: validateTrue,
-			},
-			Value: PartAny{val},
-		})
+		sk, _, ok = LookupExtension(keyword)
+	}
+	if !ok {
+		part, err := unknownPart(keyword, val)
+		if err != nil {
+			return err
+		}
+		s.Parts = append(s.Parts, part)
 		return nil
 	}
 
@@ -579,20 +755,20 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 			spv = PartStringOrStrings{Strings: strs}
 		}
 	case arg_type.ArgTypeInt:
-		f, ok := val.(float64)
-		if !ok {
-			return fmt.Errorf("%q argument is type %T, want integer", keyword, val)
-		}
-		if f != math.Trunc(f) {
-			return fmt.Errorf("%q argument is non-integer, want integer", keyword)
+		i, err := intFromJSON(keyword, val)
+		if err != nil {
+			return err
 		}
-		spv = PartInt(f)
+		spv = PartInt(i)
 	case arg_type.ArgTypeFloat:
-		f, ok := val.(float64)
-		if !ok {
-			return fmt.Errorf("%q argument is type %T, want number", keyword, val)
+		f, err := floatFromJSON(keyword, val)
+		if err != nil {
+			return err
 		}
 		spv = PartFloat(f)
+		if lit, ok := val.(json.Number); ok {
+			s.recordNumberLiteral(keyword, f, lit)
+		}
 	case arg_type.ArgTypeSchema:
 		var s Schema
 		if err := s.buildFromJSON(val, vocabulary); err != nil {
@@ -605,10 +781,10 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 			return fmt.Errorf("%q argument is type %T, want array", keyword, val)
 		}
 		schemas := make([]*Schema, 0, len(as))
-		for _, a := range as {
+		for i, a := range as {
 			var s Schema
 			if err := s.buildFromJSON(a, vocabulary); err != nil {
-				return err
+				return wrapParseError(err, strconv.Itoa(i))
 			}
 			schemas = append(schemas, &s)
 		}
@@ -622,7 +798,7 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 		for k, v := range jm {
 			var s Schema
 			if err := s.buildFromJSON(v, vocabulary); err != nil {
-				return err
+				return wrapParseError(err, pointertoken.Escape(k))
 			}
 			nm[k] = &s
 		}
@@ -635,10 +811,10 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 		as, ok := val.([]any)
 		if ok {
 			schemas = make([]*Schema, 0, len(as))
-			for _, a := range as {
+			for i, a := range as {
 				var s Schema
 				if err := s.buildFromJSON(a, vocabulary); err != nil {
-					return err
+					return wrapParseError(err, strconv.Itoa(i))
 				}
 				schemas = append(schemas, &s)
 			}
@@ -665,7 +841,7 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 			case bool, map[string]any:
 				var s Schema
 				if err := s.buildFromJSON(v, vocabulary); err != nil {
-					return err
+					return wrapParseError(err, pointertoken.Escape(k))
 				}
 				as.Schema = &s
 			case []any:
@@ -684,8 +860,34 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 			nm[k] = as
 		}
 		spv = PartMapArrayOrSchema(nm)
+	case arg_type.ArgTypeMapMapSchema:
+		jm, ok := val.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%q argument is type %T, want object", keyword, val)
+		}
+		nm := make(map[string]map[string]*Schema, len(jm))
+		for k, v := range jm {
+			jm2, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%q argument item %s is %T, want object", keyword, k, v)
+			}
+			nm2 := make(map[string]*Schema, len(jm2))
+			for k2, v2 := range jm2 {
+				var s Schema
+				if err := s.buildFromJSON(v2, vocabulary); err != nil {
+					return wrapParseError(err, pointertoken.Escape(k)+"/"+pointertoken.Escape(k2))
+				}
+				nm2[k2] = &s
+			}
+			nm[k] = nm2
+		}
+		spv = PartMapMapSchema(nm)
 	case arg_type.ArgTypeAny:
-		spv = PartAny{val}
+		v, err := plainNumbers(val)
+		if err != nil {
+			return err
+		}
+		spv = PartAny{v}
 	default:
 		panic("can't happen")
 	}
@@ -697,6 +899,161 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 	return nil
 }
 
+// decodeSchemaJSON decodes the JSON encoding of a schema into an
+// empty interface value, as [json.Unmarshal] does, except that
+// numbers are decoded as [json.Number]. This lets the keyword
+// arguments that are numbers be parsed without going through
+// float64, which can't represent every integer exactly.
+func decodeSchemaJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+// intFromJSON returns the integer argument val of keyword,
+// which is a float64 or a [json.Number].
+// JSON schema permits an integer to be written as a number
+// with a zero fractional part, such as 1.0.
+func intFromJSON(keyword string, val any) (int64, error) {
+	var f float64
+	switch v := val.(type) {
+	case float64:
+		f = v
+	case json.Number:
+		if isIntegerLiteral(string(v)) {
+			i, err := strconv.ParseInt(string(v), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%q argument %s is out of range", keyword, v)
+			}
+			return i, nil
+		}
+		var err error
+		f, err = strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q argument %s is out of range", keyword, v)
+		}
+	default:
+		return 0, fmt.Errorf("%q argument is type %T, want integer", keyword, val)
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("%q argument is non-integer, want integer", keyword)
+	}
+	// The limits are exact powers of two, so the comparisons
+	// are exact; float64(math.MaxInt64) is 1<<63.
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("%q argument %v is out of range", keyword, val)
+	}
+	return int64(f), nil
+}
+
+// floatFromJSON returns the number argument val of keyword,
+// which is a float64 or a [json.Number].
+// An integer that a float64 can't represent exactly, such as
+// 18446744073709551615, is rounded to the nearest float64, and
+// its literal, which the caller records, is what validation
+// compares with; see [Schema.ExactNumber].
+// Other numbers, such as 0.1, are rounded as usual.
+func floatFromJSON(keyword string, val any) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		if isIntegerLiteral(string(v)) {
+			bi, ok := new(big.Int).SetString(string(v), 10)
+			if !ok {
+				return 0, fmt.Errorf("%q argument %s is not a number", keyword, v)
+			}
+			f, _ := new(big.Float).SetInt(bi).Float64()
+			if math.IsInf(f, 0) {
+				return 0, fmt.Errorf("%q argument %s is out of range", keyword, v)
+			}
+			return f, nil
+		}
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q argument %s is out of range", keyword, v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%q argument is type %T, want number", keyword, val)
+	}
+}
+
+// isIntegerLiteral reports whether the JSON number s
+// is written without a fraction or an exponent.
+func isIntegerLiteral(s string) bool {
+	return !strings.ContainsAny(s, ".eE")
+}
+
+// plainNumbers returns v with any [json.Number] values,
+// at any depth, replaced by float64 values, as json.Unmarshal
+// decodes them, so that keyword arguments of any type, such as
+// those of const and enum, hold the usual Go values.
+// A number that a float64 would change, such as an integer
+// beyond 2^53, is kept as a json.Number, which is compared
+// with instances exactly.
+func plainNumbers(v any) (any, error) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s is out of range", v)
+		}
+		if !sameNumber(v, f) {
+			return v, nil
+		}
+		return f, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			pe, err := plainNumbers(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = pe
+		}
+		return m, nil
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			pe, err := plainNumbers(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = pe
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+// sameNumber reports whether n is the number f, in the shortest
+// decimal form that is how f is written, as when n is 0.1 or 1.0.
+// A number with a very large exponent is taken to be f, which
+// is then zero, rather than computing its exact value.
+func sameNumber(n json.Number, f float64) bool {
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if text == string(n) {
+		return true
+	}
+	if i := strings.IndexAny(string(n), "eE"); i >= 0 {
+		if exp, err := strconv.Atoi(string(n[i+1:])); err != nil || exp < -1000 {
+			return true
+		}
+	}
+	a, ok1 := new(big.Rat).SetString(text)
+	b, ok2 := new(big.Rat).SetString(string(n))
+	return ok1 && ok2 && a.Cmp(b) == 0
+}
+
 // Validate reports whether instance satisfies schema.
 // If it does, this will return nil.
 // If it does not, this will return an error with type either
@@ -708,6 +1065,9 @@ func (s *Schema) addKeywordFromJSON(keyword string, val any, vocabulary *Vocabul
 // with a Go type like map[string]any or []any.
 // An instance may also be a Go struct or a pointer to a Go struct;
 // in this case json tags on fields are used when matching field names.
+// A float that is NaN or infinite, which JSON can't represent,
+// is not a number for the type keyword, and fails the keywords
+// that compare numbers, such as maximum and multipleOf.
 func (s *Schema) Validate(instance any) error {
 	return s.ValidateWithOpts(instance, &ValidateOpts{ValidateFormat: true})
 }
@@ -745,48 +1105,230 @@ type ValidateOpts struct {
 	// jsonschema/format must be blank imported;
 	// by default the format keyword always matches.
 	ValidateFormat bool
+
+	// Whether to validate the contentEncoding, contentMediaType
+	// and contentSchema keywords. If this is true, a string must
+	// decode by its contentEncoding, if it is base64, base64url,
+	// base32 or base16; if its contentMediaType is JSON, the
+	// decoded content must be JSON; and that JSON must match the
+	// contentSchema. By default these keywords are only annotations,
+	// as draft 2020-12 says.
+	ValidateContent bool
+
+	// Whether the contains keyword also applies to objects,
+	// as proposed for drafts after 2020-12. If this is true,
+	// contains matches an object if the value of any property
+	// matches the subschema, and minContains and maxContains
+	// count the matching property values. The properties whose
+	// values match count as evaluated for unevaluatedProperties.
+	// By default contains ignores objects, as draft 2020-12 says.
+	ObjectContains bool
+
+	// Whether a oneOf keyword that matches reports the index of
+	// the matching branch as its annotation, for the output of
+	// [Schema.ValidateDetailed] and [Schema.Annotations].
+	// This tells which variant of a payload was found,
+	// without checking its discriminating fields again.
+	// The location of the branch is the keyword location
+	// of the annotation followed by "/" and the index.
+	OneOfAnnotations bool
+
+	// If not nil, the format validators registered in Environment
+	// are used for the format keyword, in preference to those
+	// registered globally. See [Environment].
+	Environment *Environment
+
+	// Whether the OpenAPI 3.1 discriminator keyword selects the
+	// branch of the oneOf or anyOf keyword next to it. If this is
+	// true, and the instance has the property that the discriminator
+	// names, with a string value, the instance is validated only
+	// against the branch whose $ref the value selects, and the
+	// keyword fails if there is none. This reports the errors of that
+	// branch, rather than a failure to match any branch.
+	// By default discriminator is only an annotation.
+	Discriminator bool
+
+	// The output format used by [Schema.ValidateDetailed].
+	// The default is [OutputBasic].
+	OutputFormat OutputFormat
+
+	// Whether the required, dependentRequired and minProperties
+	// keywords always match, so that an incomplete document, such
+	// as a form that is saved before it is done, is only checked
+	// for values that are wrong. [Schema.ValidatePartial] sets this
+	// to separate the properties that are missing from the rest.
+	DeferRequired bool
+
+	// Whether [Schema.ValidateDetailed] suggests, for some failures,
+	// a JSON patch that fixes the instance, in the Patch field of the
+	// output unit of the keyword. The failures are a missing required
+	// property that has a default, a value of the wrong type that
+	// converts to the right one, and a number beyond a limit.
+	SuggestPatches bool
+
+	// If not nil, Trace is called for each keyword evaluated
+	// against each location of the instance, once validation
+	// is complete. See [TraceEntry] for the format, which is
+	// meant to be compared with that of ajv. Tracing is slow,
+	// as every subschema is evaluated; it is for debugging.
+	// Trace is not called by [Schema.ValidateDetailed]
+	// or [Schema.Annotations].
+	Trace func(*TraceEntry)
+
+	// If not nil, Collector is told of each validation: how long
+	// it took and which keywords failed, for monitoring.
+	// It is not told of validations by [Schema.ValidateDetailed]
+	// or [Schema.Annotations].
+	Collector Collector
+
+	// Whether to remember the result of evaluating a subschema
+	// against a value of the instance, so that evaluating it again,
+	// as when several branches of an anyOf share a base schema
+	// through $ref, returns the result without doing the work.
+	// This trades memory for time on large documents; the memo
+	// lasts for one validation. It is not used for structs,
+	// when applying defaults, by [Schema.ValidateDetailed], or
+	// where unevaluatedProperties or unevaluatedItems need the
+	// details of the evaluation.
+	Memoize bool
+
+	// Whether validation stops at the first error found, rather
+	// than going on to report every error in the instance. This is
+	// faster when only the validity of the instance and one reason
+	// are needed. Which error is found first is not specified.
+	// Branches of anyOf and oneOf are still all tried until one
+	// matches. FailFast is not used by [Schema.ValidateDetailed].
+	FailFast bool
+
+	// Whether a value of the instance that contains itself, through
+	// a pointer, map or slice, is accepted. Such an instance can't
+	// be encoded as JSON, so by default validation fails with a
+	// "circular instance reference" error where the value is found
+	// again. If this is true, the value found again is treated as
+	// valid, on the grounds that it is being validated already.
+	AllowInstanceCycles bool
+
+	// The limit on the nesting of subschemas being evaluated,
+	// counting both the schemas applied to the same value, as
+	// through $ref, and those applied to the values within it.
+	// Validation that goes deeper fails with an error that
+	// matches [ErrMaxDepth]. If zero, DefaultMaxDepth is used.
+	MaxDepth int
+
+	// Options for extension keywords, keyed by keyword name.
+	// The meaning of each value is up to the extension.
+	Extensions map[string]any
 }
 
 // ValidateWithOpts is like Validate but supports options.
 func (s *Schema) ValidateWithOpts(instance any, opts *ValidateOpts) error {
+	return s.ValidateContextWithOpts(context.Background(), instance, opts)
+}
+
+// ValidateContext is like [Schema.Validate], but stops early if
+// ctx is canceled or its deadline passes. In that case it returns
+// an error for which errors.Is reports ctx.Err().
+// This bounds the time spent validating a large or deeply
+// nested instance that comes from an untrusted source.
+func (s *Schema) ValidateContext(ctx context.Context, instance any) error {
+	return s.ValidateContextWithOpts(ctx, instance, &ValidateOpts{ValidateFormat: true})
+}
+
+// ValidateContextWithOpts is like [Schema.ValidateWithOpts],
+// but stops early if ctx is done, as for [Schema.ValidateContext].
+func (s *Schema) ValidateContextWithOpts(ctx context.Context, instance any, opts *ValidateOpts) error {
+	if opts != nil && opts.Collector != nil {
+		start := time.Now()
+		err := s.validateContextWithOpts(ctx, instance, opts)
+		collect(opts.Collector, s, instance, opts, start, err)
+		return err
+	}
+	return s.validateContextWithOpts(ctx, instance, opts)
+}
+
+// validateContextWithOpts implements [Schema.ValidateContextWithOpts].
+func (s *Schema) validateContextWithOpts(ctx context.Context, instance any, opts *ValidateOpts) error {
+	if opts != nil && opts.Trace != nil {
+		root, err := s.validateUnits(ctx, instance, opts)
+		if cerr := ctx.Err(); cerr != nil {
+			return cerr
+		}
+		root.trace(opts.Trace)
+		return err
+	}
+
 	var versionData any
 	state := &ValidationState{
-		Root:        s,
-		VersionData: &versionData,
-		Opts:        opts,
+		Root:         s,
+		RootInstance: instance,
+		VersionData:  &versionData,
+		Opts:         opts,
+		ctx:          ctx,
 	}
 	state.RootState = state
-	return s.ValidateSubSchema(instance, state)
+	if opts != nil && opts.Memoize && !opts.ApplyDefaults {
+		state.memo = make(map[memoKey]memoResult)
+	}
+	err := s.ValidateSubSchema(instance, state)
+	if cerr := ctx.Err(); cerr != nil {
+		// The result may be incomplete.
+		return cerr
+	}
+	if err != nil && !IsValidationError(err) {
+		// Anything other than a validation error
+		// is a problem with the schema.
+		err = errors2.AsSchemaError(err)
+	}
+	return err
 }
 
 // ValidateInPlaceSchema reports whether instance satisfies schema,
 // where schema is a subschema that is evaluated in the same context
 // as the parent schema.
 func (s *Schema) ValidateInPlaceSchema(instance any, state *ValidationState) error {
+	if state.out != nil {
+		return s.validateOutput(instance, state, true)
+	}
+
+	notesNeeded := state.notesNeeded || s.usesNotes()
+	key, memoOK := state.memoKey(s, instance, true, notesNeeded)
+	if memoOK {
+		if err, ok := state.memoized(key, s); ok {
+			return err
+		}
+	}
+
 	subState, err := state.Child()
 	if err != nil {
 		return err
 	}
 	subState.Schema = s
+	subState.notesNeeded = notesNeeded
 
 	var topErr error
+	failed := false
 	for i, p := range s.Parts {
-		if p.Keyword.Validate == nil {
+		if p.Keyword.Validate == nil || (failed && !p.Keyword.restoresState()) {
 			continue
 		}
 		subState.Index = i
 		if err := p.Keyword.Validate(p.Value, instance, subState); err != nil {
-			// Prefix with the current keyword name only if the error lacks any location.
-			if hasAnyLocation(err) {
-				errors2.AddError(&topErr, err, "")
-			} else {
-				errors2.AddError(&topErr, err, p.Keyword.Name)
-			}
+			state.locateErrors(err, s, p.Keyword.Name)
+			addKeywordError(&topErr, err, p.Keyword)
+			failed = state.FailFast()
 		}
 	}
 
 	state.Notes.AddNotes(subState.Notes)
 
+	if topErr != nil {
+		if src, ok := s.Source(); ok {
+			addErrorSource(topErr, src)
+		}
+	}
+	if memoOK {
+		state.memoize(key, s, topErr)
+	}
 	return topErr
 }
 
@@ -794,30 +1336,112 @@ func (s *Schema) ValidateInPlaceSchema(instance any, state *ValidationState) err
 // where schema is a sub-schema of some larger validation request.
 // This is like Validate but also accepts the current validation state.
 func (s *Schema) ValidateSubSchema(instance any, state *ValidationState) error {
+	entered, done, err := state.enterInstance(instance)
+	if done {
+		return err
+	}
+	if entered {
+		defer state.leaveInstance()
+	}
+	if state.out != nil {
+		return s.validateOutput(instance, state, false)
+	}
+	if s.isLeaf() {
+		return s.validateLeaf(instance, state)
+	}
+	key, memoOK := state.memoKey(s, instance, false, false)
+	if memoOK {
+		if err, ok := state.memoized(key, s); ok {
+			return err
+		}
+	}
+
 	subState, err := state.Child()
 	if err != nil {
 		return err
 	}
 	subState.Schema = s
+	subState.cache = new(instanceCache)
+	subState.notesNeeded = s.usesNotes()
 
 	var topErr error
+	failed := false
 	for i, p := range s.Parts {
-		if p.Keyword.Validate == nil {
+		if p.Keyword.Validate == nil || (failed && !p.Keyword.restoresState()) {
 			continue
 		}
 		subState.Index = i
 		if err := p.Keyword.Validate(p.Value, instance, subState); err != nil {
-			// Prefix with the current keyword name only if the error lacks any location.
-			if hasAnyLocation(err) {
-				errors2.AddError(&topErr, err, "")
-			} else {
-				errors2.AddError(&topErr, err, p.Keyword.Name)
-			}
+			state.locateErrors(err, s, p.Keyword.Name)
+			addKeywordError(&topErr, err, p.Keyword)
+			failed = state.FailFast()
+		}
+	}
+	if topErr != nil {
+		if src, ok := s.Source(); ok {
+			addErrorSource(topErr, src)
+		}
+	}
+	if memoOK {
+		state.memoize(key, s, topErr)
+	}
+	return topErr
+}
+
+// usesNotes reports whether s has a keyword that reads
+// the notes of its in-place subschemas.
+// This uses the mark added by specialize, if there is one.
+func (s *Schema) usesNotes() bool {
+	if m := s.specializeMark(); m != nil {
+		return m.Keyword == &usesNotesKeyword && bool(m.Value.(PartBool))
+	}
+	return s.scanUsesNotes()
+}
+
+// scanUsesNotes reports whether s has a keyword with UsesNotes set.
+func (s *Schema) scanUsesNotes() bool {
+	return slices.ContainsFunc(s.Parts, func(p Part) bool {
+		return p.Keyword.UsesNotes
+	})
+}
+
+// validateLeaf validates a schema marked by specialize.
+// Leaf keywords don't use the validation state,
+// so we can pass state through rather than creating a child.
+func (s *Schema) validateLeaf(instance any, state *ValidationState) error {
+	if err := state.contextErr(); err != nil {
+		return err
+	}
+	var topErr error
+	failed := false
+	for _, p := range s.Parts {
+		if p.Keyword.Validate == nil || (failed && !p.Keyword.restoresState()) {
+			continue
+		}
+		if err := p.Keyword.Validate(p.Value, instance, state); err != nil {
+			state.locateErrors(err, s, p.Keyword.Name)
+			addKeywordError(&topErr, err, p.Keyword)
+			failed = state.FailFast()
+		}
+	}
+	if topErr != nil {
+		if src, ok := s.Source(); ok {
+			addErrorSource(topErr, src)
 		}
 	}
 	return topErr
 }
 
+// addKeywordError adds an error reported by keyword to *topErr.
+func addKeywordError(topErr *error, err error, keyword *Keyword) {
+	// Prefix with the current keyword name only if the error lacks any location.
+	if hasAnyLocation(err) {
+		errors2.AddError(topErr, err, "")
+	} else {
+		errors2.AddError(topErr, err, keyword.Name)
+	}
+}
+
 // hasAnyLocation reports whether err already has a populated keyword or instance location.
 func hasAnyLocation(err error) bool {
 	switch e := err.(type) {
@@ -847,6 +1471,30 @@ func IsValidationError(err error) bool {
 	return errors2.IsValidationError(err)
 }
 
+// SchemaError is an error caused by a problem with a schema.
+type SchemaError = errors2.SchemaError
+
+// ErrInvalidInstance matches, using [errors.Is], the errors
+// returned when an instance fails validation.
+var ErrInvalidInstance = errors2.ErrInvalidInstance
+
+// ErrSchema matches, using [errors.Is], the errors caused by
+// a problem with a schema rather than with an instance.
+var ErrSchema = errors2.ErrSchema
+
+// MaxDepthError is the error returned when validating or
+// resolving a schema goes deeper than its depth limit.
+type MaxDepthError = errors2.MaxDepthError
+
+// ErrMaxDepth matches, using [errors.Is], the errors returned when
+// validating or resolving a schema goes deeper than its depth limit.
+// See [ValidateOpts.MaxDepth] and [ResolveOpts.MaxDepth].
+var ErrMaxDepth = errors2.ErrMaxDepth
+
+// DefaultMaxDepth is the depth limit used if
+// [ValidateOpts.MaxDepth] or [ResolveOpts.MaxDepth] is zero.
+const DefaultMaxDepth = 1000
+
 // Keyword is a schema keyword.
 type Keyword struct {
 	// Name is the keyword, such as allOf, anyOf, and so forth.
@@ -871,6 +1519,33 @@ type Keyword struct {
 	// If this is true the keyword should be ignored by anything
 	// that wants to treat the Schema as a JSON object.
 	Generated bool
+
+	// Leaf is true if Validate only looks at the argument and the
+	// instance, and does not use the [ValidationState] other than
+	// to report errors or to call [ValidationState.RuneCount].
+	// A schema whose keywords are all leaf keywords
+	// is validated without creating a child ValidationState.
+	Leaf bool
+
+	// UsesNotes is true if Validate reads the notes left by
+	// the keywords of in-place subschemas, as unevaluatedItems
+	// and unevaluatedProperties do. Keywords that apply several
+	// subschemas, such as anyOf, may skip subschemas whose
+	// notes can't be used; see [ValidationState.NotesNeeded].
+	UsesNotes bool
+
+	// Annotation is true if the value of the keyword is an
+	// annotation, such as title or default, that is reported
+	// for the instance locations that the schema applies to.
+	// See [Schema.Annotations].
+	Annotation bool
+
+	// CheckArg, if not nil, reports whether arg is a valid argument
+	// of the keyword, beyond having the type given by ArgType, as for
+	// an expression that must parse. It is called by
+	// [Schema.CheckArguments], so that a bad argument is reported
+	// when the schema is decoded rather than when it is used.
+	CheckArg func(arg PartValue) error
 }
 
 // Equal reports whether two keywords are equal.
@@ -910,6 +1585,7 @@ func MakePart(keyword *Keyword, value PartValue) Part {
 //   - [PartMapSchema]
 //   - [PartSchemaOrSchemas]
 //   - [PartMapArrayOrSchema]
+//   - [PartMapMapSchema]
 //   - [PartAny]
 type PartValue interface {
 	partValue() // restrict to types defined in this package
@@ -989,6 +1665,11 @@ type ArrayOrSchema struct {
 	Schema *Schema
 }
 
+// PartMapMapSchema is a map from strings to maps from strings to schemas.
+// This is used for the draft-next "propertyDependencies" keyword,
+// which maps a property name to a map from property values to schemas.
+type PartMapMapSchema map[string]map[string]*Schema
+
 // PartAny is a schema part value that is an arbitrary type.
 // For example, the schema keyword "$vocabulary" expects an
 // object where each property is a URI.
@@ -1010,6 +1691,7 @@ func (PartSchemas) partValue()          {}
 func (PartMapSchema) partValue()        {}
 func (PartSchemaOrSchemas) partValue()  {}
 func (PartMapArrayOrSchema) partValue() {}
+func (PartMapMapSchema) partValue()     {}
 func (PartAny) partValue()              {}
 
 // ResolveOpts is options to use when resolving the schema.
@@ -1021,27 +1703,32 @@ type ResolveOpts struct {
 	// URI of root of schema.
 	// This is overridden by a $id keyword, if present.
 	URI *url.URL
-	// Load a remote reference, specifying the default schema.
-	// This will be resolved by the resolver of the schema that
-	// references it; no need for Loader to call (*Schema).Resolve.
-	Loader func(schemaID string, uri *url.URL) (*Schema, error)
+	// Loader loads a schema referenced by a $ref to another
+	// document. If nil, such a reference is an error.
+	Loader Loader
+	// Context is passed to Loader.
+	// If nil, [context.Background] is used.
+	Context context.Context
+	// MaxDepth is the limit on the length of a chain of schemas
+	// loaded by Loader, each referred to by the one before.
+	// Resolving a longer chain fails with an error that matches
+	// [ErrMaxDepth]. If zero, DefaultMaxDepth is used.
+	MaxDepth int
 }
 
 // SetLoader sets a function to call when resolving a $ref
 // to an external schema. This is a global property,
 // as there is no way to pass the desired value into the JSON decoder.
 // Callers should use appropriate locking.
-//
-// Note that when unmarshaling user-written schemas,
-// the loader function can be called with arbitrary URIs.
-// It's probably unwise to simply call [net/http.Get] in all cases.
-//
-// To fully support JSON schema cross references, the loader should call
-// [SchemaFromJSON]. The caller will handle calling [Schema.Resolve].
+// The function is used as described for [Loader].
 //
 // This returns the old loader function.
 // The default loader function is nil, which will produce an
 // error for a $ref to an external schema.
+//
+// Deprecated: A global loader can't be shared safely by unrelated
+// packages in one program. Use a [Decoder] or an [Environment],
+// or set [ResolveOpts.Loader].
 func SetLoader(fn func(schemaID string, uri *url.URL) (*Schema, error)) func(string, *url.URL) (*Schema, error) {
 	ret := loader
 	loader = fn
@@ -1063,6 +1750,9 @@ type ValidationState struct {
 	RootState *ValidationState
 	// The Schema being validated.
 	Schema *Schema
+	// The instance passed to the validation,
+	// of which InstancePath locates the current instance.
+	RootInstance any
 	// The index in schema.Parts of the keyword currently being validated.
 	Index int
 	// Current URI, from $id keyword.
@@ -1074,24 +1764,145 @@ type ValidationState struct {
 	// Validation options. Nil for the defaults.
 	Opts *ValidateOpts
 	// For use by version-specific code.
+	// This is created for each validation and shared by its
+	// child states, so it can hold the mutable state of a
+	// validation without changing the schema.
 	VersionData *any
 
 	// InstancePath holds the JSON Pointer tokens to the current location
 	// within the instance being validated.
 	InstancePath []string
+
+	// cache holds information computed about the instance.
+	// See the InstanceFields and RuneCount methods.
+	cache *instanceCache
+
+	// notesNeeded is set if a keyword of a schema being
+	// evaluated at this instance location uses notes.
+	// See the NotesNeeded method.
+	notesNeeded bool
+
+	// out is where output units are added,
+	// if validating with [Schema.ValidateDetailed].
+	out *outputNode
+
+	// ctx is the context of the validation, if any.
+	// See the Context method.
+	ctx context.Context
+
+	// memo holds the results of evaluations,
+	// if validating with [ValidateOpts.Memoize].
+	memo map[memoKey]memoResult
+
+	// instances holds the maps, slices and pointers of the
+	// instance that are being validated, in the root state.
+	// See enterInstance.
+	instances []instanceFrame
+
+	// parent is the state that vs is a child of, if any.
+	// See evaluationPath.
+	parent *ValidationState
+
+	// locations holds the absolute locations of the schemas,
+	// in the root state, once there is an error to locate.
+	// See schemaLocation.
+	locations map[*Schema]string
+}
+
+// Context returns the context of the validation, as passed to
+// [Schema.ValidateContext]. It is never nil.
+// A keyword that may take a long time, other than by validating
+// subschemas, should stop when the context is done.
+func (vs *ValidationState) Context() context.Context {
+	if vs.ctx == nil {
+		return context.Background()
+	}
+	return vs.ctx
+}
+
+// contextErr returns the error of the validation context, if any.
+func (vs *ValidationState) contextErr() error {
+	if vs.ctx == nil {
+		return nil
+	}
+	return vs.ctx.Err()
+}
+
+// NotesNeeded reports whether a keyword that is in scope reads the
+// notes of in-place subschemas, as unevaluatedProperties does.
+// That is, whether any schema being evaluated at the current
+// instance location has a keyword with [Keyword.UsesNotes] set.
+// If not, a keyword that applies several subschemas can stop
+// once it knows the result, rather than validating the rest
+// of the subschemas to collect their notes.
+func (vs *ValidationState) NotesNeeded() bool {
+	return vs.notesNeeded
+}
+
+// FailFast reports whether validation stops at the first error,
+// as set by [ValidateOpts.FailFast]. A keyword that validates
+// several values or subschemas can return its first error.
+func (vs *ValidationState) FailFast() bool {
+	return vs.Opts != nil && vs.Opts.FailFast
+}
+
+// Annotate records v as the annotation of the keyword being
+// validated, when collecting output for [Schema.ValidateDetailed]
+// or [Schema.Annotations]. Otherwise it does nothing.
+// This is for a keyword whose annotation is computed during
+// validation, rather than being the value of the keyword.
+// The annotation is dropped if the keyword fails.
+func (vs *ValidationState) Annotate(v any) {
+	if vs.out != nil {
+		vs.out.unit.Annotation = v
+	}
+}
+
+// Exhaustive reports whether every subschema should be evaluated,
+// as when collecting output for [Schema.ValidateDetailed].
+// If so, a keyword should not skip subschemas that can't change
+// its result, as their results are reported.
+func (vs *ValidationState) Exhaustive() bool {
+	return vs.out != nil
+}
+
+// instanceCache is information computed about the instance
+// being validated, shared by the keywords of a schema.
+type instanceCache struct {
+	// Cached value for [ValidationState.InstanceFields].
+	fieldsSet bool
+	fields    any
+	fieldsOK  bool
+
+	// Cached value for [ValidationState.RuneCount]. Comparing
+	// runesOf with the same string is cheap, as the comparison
+	// stops when it sees the same data.
+	runesOf string
+	runes   int
 }
 
 // Child returns a new ValidationState that is a child of vs.
 // This can be used to validate a subschema without changing
 // the notes stored in vs.
+//
+// This returns an error if the recursion is deeper than
+// [ValidateOpts.MaxDepth], or if the context of the validation is done.
 func (vs *ValidationState) Child() (*ValidationState, error) {
-	if vs.Depth > 1000 {
-		return nil, errors.New("recursion while validating schema too deep")
+	limit := DefaultMaxDepth
+	if vs.Opts != nil && vs.Opts.MaxDepth > 0 {
+		limit = vs.Opts.MaxDepth
+	}
+	if vs.Depth > limit {
+		return nil, &MaxDepthError{Op: "validating", Limit: limit}
+	}
+	if err := vs.contextErr(); err != nil {
+		return nil, err
 	}
 
 	ret := &ValidationState{
 		Root:         vs.Root,
 		RootState:    vs.RootState,
+		RootInstance: vs.RootInstance,
 		Schema:       vs.Schema,
 		Index:        vs.Index,
 		URI:          vs.URI,
@@ -1099,10 +1910,64 @@ func (vs *ValidationState) Child() (*ValidationState, error) {
 		Opts:         vs.Opts,
 		VersionData:  vs.VersionData,
 		InstancePath: append([]string(nil), vs.InstancePath...),
+
+		cache:       vs.cache,
+		notesNeeded: vs.notesNeeded,
+		out:         vs.out,
+		ctx:         vs.ctx,
+		memo:        vs.memo,
+		parent:      vs,
 	}
 	return ret, nil
 }
 
+// InstanceFields returns the field names of the instance being
+// validated, as computed by compute. The result is computed at most
+// once for each evaluation of a schema against an instance, and is
+// shared by the keywords of that schema and its in-place subschemas.
+// This avoids scanning a wide object once for each keyword
+// that looks at every field.
+func (vs *ValidationState) InstanceFields(compute func() (any, bool)) (any, bool) {
+	c := vs.cache
+	if c == nil {
+		return compute()
+	}
+	if !c.fieldsSet {
+		c.fields, c.fieldsOK = compute()
+		c.fieldsSet = true
+	}
+	return c.fields, c.fieldsOK
+}
+
+// InvalidateInstanceFields discards the value cached by
+// InstanceFields. This must be called after adding a field
+// to the instance, as when applying a default.
+func (vs *ValidationState) InvalidateInstanceFields() {
+	if c := vs.cache; c != nil {
+		c.fieldsSet = false
+		c.fields = nil
+		c.fieldsOK = false
+	}
+}
+
+// RuneCount returns the number of runes in s, which is
+// normally the instance being validated. The result is cached,
+// so that keywords like minLength and maxLength that both need
+// the length of a long string only count it once.
+func (vs *ValidationState) RuneCount(s string) int {
+	// Counting a short string is faster than using the cache.
+	const minCached = 64
+	c := vs.cache
+	if c == nil || len(s) < minCached {
+		return utf8.RuneCountInString(s)
+	}
+	if c.runesOf != s {
+		c.runesOf = s
+		c.runes = utf8.RuneCountInString(s)
+	}
+	return c.runes
+}
+
 // PushInstanceToken appends a token to the instance path.
 func (vs *ValidationState) PushInstanceToken(tok string) {
 	vs.InstancePath = append(vs.InstancePath, tok)
@@ -1118,13 +1983,19 @@ func (vs *ValidationState) PopInstanceToken() {
 // InstancePointer returns the current instance location as a JSON Pointer
 // string starting with '#'.
 func (vs *ValidationState) InstancePointer() string {
-	if len(vs.InstancePath) == 0 {
+	return instancePointer(vs.InstancePath)
+}
+
+// instancePointer returns the JSON Pointer string,
+// starting with '#', of the instance location path.
+func instancePointer(path []string) string {
+	if len(path) == 0 {
 		return "#"
 	}
 	// Escape per RFC 6901
-	b := make([]byte, 0, 2*len(vs.InstancePath))
+	b := make([]byte, 0, 2*len(path))
 	b = append(b, '#', '/')
-	for i, t := range vs.InstancePath {
+	for i, t := range path {
 		if i > 0 {
 			b = append(b, '/')
 		}
@@ -1168,6 +2039,7 @@ var SchemaKeyword = Keyword{
 	Name:     "$schema",
 	ArgType:  arg_type.ArgTypeString,
 	Validate: validateTrue,
+	Leaf:     true,
 }
 
 // BoolKeyword is not a real keyword, but is used to represent the
@@ -1176,6 +2048,26 @@ var BoolKeyword = Keyword{
 	Name:     "$bool",
 	ArgType:  arg_type.ArgTypeBool,
 	Validate: validateBool,
+	Leaf:     true,
+}
+
+// leafKeyword is a generated keyword that marks a leaf schema:
+// a schema all of whose keywords are leaf keywords.
+// It is added by specialize, normally as the last keyword of the schema.
+var leafKeyword = Keyword{
+	Name:      LeafKeywordName,
+	ArgType:   arg_type.ArgTypeBool,
+	Generated: true,
+	Leaf:      true,
+}
+
+// usesNotesKeyword is a generated keyword that records whether
+// a schema that is not a leaf has a keyword with UsesNotes set.
+// It is added by specialize, normally as the last keyword of the schema.
+var usesNotesKeyword = Keyword{
+	Name:      UsesNotesKeywordName,
+	ArgType:   arg_type.ArgTypeBool,
+	Generated: true,
 }
 
 // validateTrue is a validator function that always succeeds.
@@ -1212,6 +2104,23 @@ type Vocabulary struct {
 	// The sorting function of this schema.
 	// Used to sort the keywords of an instance of the schema.
 	Cmp func(string, string) int
+	// Documentation for the keywords, keyed by keyword name.
+	// This is for help text in error messages and editors.
+	// It need not have an entry for every keyword.
+	Docs map[string]KeywordDoc
+	// Whether keywords next to a $ref keyword are ignored,
+	// as in draft-07 and earlier. From draft 2019-09 on,
+	// they apply along with the referenced schema.
+	IgnoreRefSiblings bool
+}
+
+// KeywordDoc is documentation for a keyword.
+type KeywordDoc struct {
+	// A short description of the keyword.
+	Description string
+	// A link to the section of the specification
+	// that defines the keyword.
+	Link string
 }
 
 // A registry is a mapping from schema name to Vocabulary.
@@ -1219,6 +2128,9 @@ type registry struct {
 	mu      sync.Mutex
 	mapping map[string]*Vocabulary
 	defval  *Vocabulary // default vocabulary
+	// dialects holds the vocabularies built from the
+	// $vocabulary keywords of meta-schemas; see [decodeConfig].
+	dialects map[string]*Vocabulary
 }
 
 // Adds adds an item to the registry.
@@ -1245,7 +2157,26 @@ func (r *registry) add(s string, v *Vocabulary, def bool) {
 func (r *registry) lookup(s string) *Vocabulary {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.mapping[s]
+	if v, ok := r.mapping[s]; ok {
+		return v
+	}
+	return r.dialects[s]
+}
+
+// addDialect adds a vocabulary built from a meta-schema,
+// unless there already is one for s. It returns the
+// vocabulary that is in the registry.
+func (r *registry) addDialect(s string, v *Vocabulary) *Vocabulary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.dialects[s]; ok {
+		return old
+	}
+	if r.dialects == nil {
+		r.dialects = make(map[string]*Vocabulary)
+	}
+	r.dialects[s] = v
+	return v
 }
 
 // def returns the default vocabulary,
@@ -1285,6 +2216,7 @@ func (r *registry) clear() {
 	defer r.mu.Unlock()
 	r.mapping = nil
 	r.defval = nil
+	r.dialects = nil
 }
 
 // reg is the global registry.
@@ -1318,13 +2250,15 @@ func DefaultVocabulary() *Vocabulary {
 // The argument should be something like "draft7" or "draft2020-12".
 // This is a global property, as there is no way to pass the desired
 // value into the JSON decoder. Callers should use appropriate locking.
-// This is mainly for tests.
+// This is mainly for tests; a library should set
+// [DecoderOpts.DefaultDraft] instead.
 func SetDefaultSchema(s string) error {
 	return reg.setDef(s)
 }
 
 // ClearVocabularies discards the vocabulary registry.
-// This is for tests.
+// This is for tests. A program that needs a different set of
+// vocabularies should use an [Environment] instead.
 func ClearVocabularies() {
 	reg.clear()
 }
@@ -0,0 +1,396 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+// Source is the location of a schema in the text it was decoded from.
+type Source struct {
+	// URI is where the text was loaded from. It may be empty.
+	URI string
+	// Line and Column are 1-based. Column counts bytes.
+	Line, Column int
+	// Offset is the 0-based byte offset in the text.
+	Offset int64
+}
+
+// String returns the location in the usual uri:line:column format.
+func (src Source) String() string {
+	if src.URI == "" {
+		return fmt.Sprintf("%d:%d", src.Line, src.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", src.URI, src.Line, src.Column)
+}
+
+// sourceKeyword is a generated keyword that records the [Source]
+// of a schema decoded by [SchemaFromJSONWithSource].
+var sourceKeyword = Keyword{
+	Name:      SourceKeywordName,
+	ArgType:   arg_type.ArgTypeAny,
+	Generated: true,
+	Leaf:      true,
+}
+
+// Source returns where s was decoded from, if known.
+// This is only recorded by [SchemaFromJSONWithSource]
+// and [UnmarshalWithSource].
+func (s *Schema) Source() (Source, bool) {
+	for _, part := range s.Parts {
+		if part.Keyword == &sourceKeyword {
+			return part.Value.(PartAny).V.(Source), true
+		}
+	}
+	return Source{}, false
+}
+
+// UnmarshalWithSource is like [json.Unmarshal] of data into a [Schema],
+// but records the location of each subschema in data.
+// When validation fails in a subschema, the error reports the location.
+// The uri is where data was loaded from; it is used in the recorded
+// locations and as the base URI of the schema. It may be nil.
+func UnmarshalWithSource(data []byte, uri *url.URL) (*Schema, error) {
+	loader := globalLoader()
+	s, err := schemaFromJSONWithSource(&decodeConfig{reg: &reg}, "", uri, data)
+	if err != nil {
+		return nil, err
+	}
+
+	ropts := &ResolveOpts{
+		URI:    uri,
+		Loader: loader,
+	}
+	if err := s.Resolve(ropts); err != nil {
+		return nil, err
+	}
+	reportWarnings(s, nil)
+	return s, nil
+}
+
+// SchemaFromJSONWithSource is like [SchemaFromJSON], but takes the
+// JSON encoding, and records the location of each subschema in data.
+// This is intended for use by loader functions, so that errors in
+// remote schemas report where they come from.
+//
+// It is normally necessary to call Resolve on the result.
+func SchemaFromJSONWithSource(schemaID string, uri *url.URL, data []byte) (*Schema, error) {
+	return schemaFromJSONWithSource(&decodeConfig{reg: &reg}, schemaID, uri, data)
+}
+
+// schemaFromJSONWithSource is like [SchemaFromJSONWithSource],
+// but finds the vocabulary as described by dc.
+func schemaFromJSONWithSource(dc *decodeConfig, schemaID string, uri *url.URL, data []byte) (*Schema, error) {
+	v, offsets, err := decodeWithOffsets(data, true)
+	if err != nil {
+		return nil, &SchemaError{Err: err}
+	}
+	s, err := schemaFromJSON(dc, schemaID, uri, v)
+	if err != nil {
+		return nil, locateParseError(err, data, uri)
+	}
+
+	lines := lineStarts(data)
+	var walk func(s *Schema, ptr string)
+	walk = func(s *Schema, ptr string) {
+		if _, ok := s.Source(); ok {
+			// Shared with another location.
+			return
+		}
+		if off, ok := offsets[ptr]; ok {
+			src := sourceAt(data, lines, off, uri)
+			s.Parts = append(s.Parts, Part{&sourceKeyword, PartAny{src}})
+		}
+		for name, sub := range s.Children() {
+			walk(sub, ptr+"/"+name)
+		}
+	}
+	walk(s, "")
+
+	return s, nil
+}
+
+// sourceAt returns the [Source] of offset off in data.
+// lines is the result of lineStarts(data).
+func sourceAt(data []byte, lines []int64, off int64, uri *url.URL) Source {
+	line, _ := slices.BinarySearch(lines, off+1)
+	src := Source{
+		Line:   line,
+		Column: int(off-lines[line-1]) + 1,
+		Offset: off,
+	}
+	if uri != nil {
+		src.URI = uri.String()
+	}
+	return src
+}
+
+// lineStarts returns the offsets at which the lines of data start.
+func lineStarts(data []byte) []int64 {
+	ret := []int64{0}
+	for i, b := range data {
+		if b == '\n' {
+			ret = append(ret, int64(i+1))
+		}
+	}
+	return ret
+}
+
+// decodeWithOffsets decodes JSON data into an empty interface value,
+// as [json.Unmarshal] does, and also returns the byte offset of every
+// value in data, keyed by JSON pointer.
+// If useNumber is set, numbers are decoded as [json.Number],
+// as for a schema; see [decodeSchemaJSON].
+func decodeWithOffsets(data []byte, useNumber bool) (any, map[string]int64, error) {
+	d := &offsetDecoder{
+		data:    data,
+		dec:     json.NewDecoder(bytes.NewReader(data)),
+		offsets: make(map[string]int64),
+	}
+	if useNumber {
+		d.dec.UseNumber()
+	}
+	v, err := d.value("")
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, nil, errors.New("invalid character after top-level value")
+	}
+	return v, d.offsets, nil
+}
+
+// offsetDecoder holds the state used by decodeWithOffsets.
+type offsetDecoder struct {
+	data    []byte
+	dec     *json.Decoder
+	offsets map[string]int64
+}
+
+// value decodes the value at the JSON pointer ptr.
+func (d *offsetDecoder) value(ptr string) (any, error) {
+	d.offsets[ptr] = d.start()
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := make(map[string]any)
+		for d.dec.More() {
+			tok, err := d.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			v, err := d.value(ptr + "/" + pointertoken.Escape(key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+		return m, nil
+
+	case json.Delim('['):
+		a := []any{}
+		for i := 0; d.dec.More(); i++ {
+			v, err := d.value(ptr + "/" + strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+		return a, nil
+
+	default:
+		return tok, nil
+	}
+}
+
+// start returns the offset of the next value. The decoder's
+// offset is after the previous token, which may be followed
+// by white space and a separator.
+func (d *offsetDecoder) start() int64 {
+	off := d.dec.InputOffset()
+	for off < int64(len(d.data)) {
+		switch d.data[off] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			off++
+		default:
+			return off
+		}
+	}
+	return off
+}
+
+// addErrorSource records src in the validation errors in err
+// that don't already have a source.
+func addErrorSource(err error, src Source) {
+	switch e := err.(type) {
+	case *ValidationError:
+		if e.Source == "" {
+			e.Source = src.String()
+		}
+	case *ValidationErrors:
+		for _, ve := range e.Errs {
+			if ve.Source == "" {
+				ve.Source = src.String()
+			}
+		}
+	}
+}
+
+// ParseError is an error in the JSON encoding of a schema,
+// such as a keyword argument of the wrong type.
+type ParseError struct {
+	// Pointer is the JSON pointer to the value with the error.
+	Pointer string
+	// Source is the location of the value in the text,
+	// if the schema was decoded from text.
+	Source Source
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (pe *ParseError) Error() string {
+	if pe.Source.Line == 0 {
+		return fmt.Sprintf("#%s: %v", pe.Pointer, pe.Err)
+	}
+	return fmt.Sprintf("%s: #%s: %v", pe.Source, pe.Pointer, pe.Err)
+}
+
+// Unwrap returns the underlying error.
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// Is reports whether target is [ErrSchema].
+func (pe *ParseError) Is(target error) bool {
+	return target == ErrSchema
+}
+
+// wrapParseError returns err as a [*ParseError] whose pointer
+// is prefixed with the JSON pointer tokens in seg.
+func wrapParseError(err error, seg string) error {
+	if pe, ok := err.(*ParseError); ok {
+		pe.Pointer = "/" + seg + pe.Pointer
+		return pe
+	}
+	return &ParseError{Pointer: "/" + seg, Err: err}
+}
+
+// locateParseError sets the source location of a [*ParseError],
+// given the text that was decoded.
+// Other errors are returned unchanged.
+func locateParseError(err error, data []byte, uri *url.URL) error {
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		return err
+	}
+	_, offsets, derr := decodeWithOffsets(data, false)
+	if derr != nil {
+		return err
+	}
+	if off, ok := offsets[pe.Pointer]; ok {
+		pe.Source = sourceAt(data, lineStarts(data), off, uri)
+	}
+	return err
+}
+
+// ValidateJSONWithSource is like Validate, but takes the JSON
+// encoding of the instance. Validation errors record the location
+// of the failing value in data, which is useful when validating
+// a text file such as a schema against its meta-schema.
+// The uri is where data was loaded from; it may be nil.
+func (s *Schema) ValidateJSONWithSource(data []byte, uri *url.URL) error {
+	return s.ValidateJSON(data, uri, nil)
+}
+
+// ValidateJSON is like ValidateWithOpts, but takes the JSON encoding
+// of the instance. The InstanceSource and InstancePosition fields
+// of each validation error record the location of the failing value
+// in data, so that tools can point at it.
+// The uri is where data was loaded from; it may be nil.
+func (s *Schema) ValidateJSON(data []byte, uri *url.URL, opts *ValidateOpts) error {
+	instance, offsets, err := decodeWithOffsets(data, false)
+	if err != nil {
+		return err
+	}
+	verr := s.ValidateWithOpts(instance, opts)
+	if verr == nil {
+		return nil
+	}
+
+	lines := lineStarts(data)
+	locate := func(ve *ValidationError) {
+		ptr := strings.TrimPrefix(ve.InstanceLocation, "#")
+		if off, ok := offsets[ptr]; ok {
+			src := sourceAt(data, lines, off, uri)
+			ve.InstanceSource = src.String()
+			ve.InstancePosition = &errors2.Position{
+				Offset: src.Offset,
+				Line:   src.Line,
+				Column: src.Column,
+			}
+		}
+	}
+	switch e := verr.(type) {
+	case *ValidationError:
+		locate(e)
+	case *ValidationErrors:
+		for _, ve := range e.Errs {
+			locate(ve)
+		}
+	}
+	return verr
+}
+
+// ValidateBytes is like Validate, but takes the JSON encoding
+// of the instance. Numbers are decoded as [json.Number] values,
+// so that they are compared exactly: an integer too large
+// for a float64 is not rounded. An error decoding data
+// is returned as is; it is not a validation error.
+func (s *Schema) ValidateBytes(data []byte) error {
+	return s.ValidateReader(bytes.NewReader(data))
+}
+
+// ValidateReader is like [Schema.ValidateBytes],
+// but reads the JSON encoding of the instance from r.
+// Nothing but white space may follow the instance.
+func (s *Schema) ValidateReader(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var instance any
+	if err := dec.Decode(&instance); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid character after top-level value")
+		}
+		return err
+	}
+	return s.Validate(instance)
+}
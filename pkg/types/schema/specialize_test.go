@@ -0,0 +1,77 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// TestSpecializeMarks checks that a resolved schema validates
+// correctly when the generated keywords that mark leaf schemas,
+// and schemas that use notes, are not its last keywords,
+// and that String leaves the generated keywords out.
+func TestSpecializeMarks(t *testing.T) {
+	tests := []struct {
+		schema         string
+		valid, invalid string
+		str            string
+	}{
+		{
+			`{"type": "string", "minLength": 2}`,
+			`"ab"`, `"a"`,
+			`Schema{{$schema https://json-schema.org/draft/2020-12/schema}, {minLength 2}, {type {string []}}}`,
+		},
+		{
+			`{"properties": {"a": true}, "unevaluatedProperties": false}`,
+			`{"a": 1}`, `{"b": 1}`,
+			`Schema{{$schema https://json-schema.org/draft/2020-12/schema}, {properties map[a:Schema{{$bool true}}]}, {unevaluatedProperties {Schema{{$bool false}}}}}`,
+		},
+	}
+	for _, test := range tests {
+		var s schema.Schema
+		src := `{"$schema": "https://json-schema.org/draft/2020-12/schema", ` + strings.TrimPrefix(test.schema, "{")
+		if err := json.Unmarshal([]byte(src), &s); err != nil {
+			t.Fatalf("%s: %v", test.schema, err)
+		}
+		if got := s.String(); got != test.str {
+			t.Errorf("%s: String() = %s, want %s", test.schema, got, test.str)
+		}
+
+		// Move the generated keywords to the front.
+		var parts []schema.Part
+		for _, p := range s.Parts {
+			if p.Keyword.Generated {
+				parts = append(parts, p)
+			}
+		}
+		for _, p := range s.Parts {
+			if !p.Keyword.Generated {
+				parts = append(parts, p)
+			}
+		}
+		s.Parts = parts
+
+		for _, inst := range []struct {
+			src   string
+			valid bool
+		}{{test.valid, true}, {test.invalid, false}} {
+			var v any
+			if err := json.Unmarshal([]byte(inst.src), &v); err != nil {
+				t.Fatal(err)
+			}
+			err := s.Validate(v)
+			if inst.valid && err != nil {
+				t.Errorf("%s with %s: unexpected error %v", test.schema, inst.src, err)
+			} else if !inst.valid && err == nil {
+				t.Errorf("%s with %s: no error", test.schema, inst.src)
+			}
+		}
+	}
+}
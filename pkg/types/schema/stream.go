@@ -0,0 +1,451 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/altshiftab/jsonschema/internal/pointertoken"
+	"github.com/altshiftab/jsonschema/pkg/types/arg_type"
+)
+
+// streamDecoder builds a schema from the tokens of its JSON encoding,
+// without first decoding the whole document into an empty interface
+// value, as [Schema.UnmarshalJSON] used to. Subschemas are built as
+// they are read. Other keyword arguments, which are small,
+// are decoded into empty interface values and converted as by
+// addKeywordFromJSON.
+//
+// A vocabulary that ignores the keywords next to $ref can't tell
+// how to treat a keyword until it has seen the whole object, so for
+// such a vocabulary each argument is decoded into an empty interface
+// value, and the object is built as buildFromJSON does.
+type streamDecoder struct {
+	data  []byte
+	dec   *json.Decoder
+	vocab *Vocabulary
+}
+
+// buildTopFromStream is like buildTopFromJSON,
+// for the JSON encoding data of a schema, which must be valid JSON.
+func (s *Schema) buildTopFromStream(dc *decodeConfig, data []byte) (*Vocabulary, error) {
+	version, found, err := topLevelSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	var vocabulary *Vocabulary
+	if found {
+		vocabulary, err = dc.vocabulary(version)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		vocabulary = dc.reg.def()
+		if vocabulary == nil {
+			return nil, errors.New("JSON schema version not specified and there is no default")
+		}
+		version = vocabulary.Schema
+	}
+	s.Parts = append(s.Parts, Part{&SchemaKeyword, PartString(version)})
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	sd := &streamDecoder{data: data, dec: dec, vocab: vocabulary}
+	if err := sd.schema(s, true); err != nil {
+		return nil, err
+	}
+	return vocabulary, nil
+}
+
+// topLevelSchema returns the value of the $schema keyword
+// of the valid JSON encoding data of a schema, if it has one.
+// It stops reading at the keyword, which is normally first.
+func topLevelSchema(data []byte) (string, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return "", false, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", false, err
+		}
+		if tok != "$schema" {
+			if err := skipValue(dec); err != nil {
+				return "", false, err
+			}
+			continue
+		}
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return "", false, err
+		}
+		version, ok := v.(string)
+		if !ok {
+			return "", false, wrapParseError(errors.New("$schema does not have a string value"), "$schema")
+		}
+		return version, true, nil
+	}
+	return "", false, nil
+}
+
+// skipValue reads the next value from dec and discards it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// schema reads a schema into s. top reports whether it is
+// the root schema, whose $schema keyword is already in s.
+func (sd *streamDecoder) schema(s *Schema, top bool) error {
+	tok, err := sd.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case true, false:
+		s.Parts = append(s.Parts, Part{&BoolKeyword, PartBool(tok.(bool))})
+		return nil
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("unexpected type %s while JSON decoding schema", tokenType(tok))
+	}
+
+	if sd.vocab.IgnoreRefSiblings {
+		return sd.objectValues(s, top)
+	}
+
+	// index maps keywords to their parts in s,
+	// so that a repeated keyword replaces the earlier one,
+	// as for json.Unmarshal.
+	index := make(map[string]int)
+	for sd.dec.More() {
+		tok, err := sd.dec.Token()
+		if err != nil {
+			return err
+		}
+		keyword := tok.(string)
+		if top && keyword == "$schema" {
+			if err := skipValue(sd.dec); err != nil {
+				return err
+			}
+			continue
+		}
+		n := len(s.Parts)
+		if err := sd.keyword(s, keyword); err != nil {
+			return wrapParseError(err, pointertoken.Escape(keyword))
+		}
+		// The keyword may also have added a generated part,
+		// such as the one for number literals, so find its
+		// own part by name.
+		j := n + slices.IndexFunc(s.Parts[n:], func(p Part) bool {
+			return p.Keyword.Name == keyword
+		})
+		if j < n {
+			continue
+		}
+		if i, ok := index[keyword]; ok {
+			s.Parts[i] = s.Parts[j]
+			s.Parts = slices.Delete(s.Parts, j, j+1)
+		} else {
+			index[keyword] = j
+		}
+	}
+	if _, err := sd.dec.Token(); err != nil {
+		return err
+	}
+	if err := s.CheckArguments(); err != nil {
+		return err
+	}
+	s.Finalize(sd.vocab)
+	return nil
+}
+
+// objectValues reads the rest of a schema object into s, for a
+// vocabulary that ignores the keywords next to $ref: it decodes
+// the object into an empty interface value and builds s from that.
+func (sd *streamDecoder) objectValues(s *Schema, top bool) error {
+	m := make(map[string]any)
+	for sd.dec.More() {
+		tok, err := sd.dec.Token()
+		if err != nil {
+			return err
+		}
+		var v any
+		if err := sd.dec.Decode(&v); err != nil {
+			return err
+		}
+		m[tok.(string)] = v
+	}
+	if _, err := sd.dec.Token(); err != nil {
+		return err
+	}
+	if top {
+		delete(m, "$schema")
+	}
+	return s.buildFromJSON(m, sd.vocab)
+}
+
+// keyword reads the argument of keyword and adds it to s.
+func (sd *streamDecoder) keyword(s *Schema, keyword string) error {
+	sk, ok := sd.vocab.Keywords[keyword]
+	if !ok {
+		sk, _, ok = LookupExtension(keyword)
+	}
+	if !ok || !sk.ArgType.IsSchemaBearing() {
+		var val any
+		if err := sd.dec.Decode(&val); err != nil {
+			return err
+		}
+		return s.addKeywordFromJSON(keyword, val, sd.vocab)
+	}
+
+	var spv PartValue
+	switch sk.ArgType {
+	case arg_type.ArgTypeSchema:
+		sub, err := sd.subschema()
+		if err != nil {
+			return err
+		}
+		spv = PartSchema{sub}
+	case arg_type.ArgTypeSchemas:
+		if err := sd.expect('[', keyword, "array"); err != nil {
+			return err
+		}
+		schemas, err := sd.schemaList()
+		if err != nil {
+			return err
+		}
+		spv = PartSchemas(schemas)
+	case arg_type.ArgTypeMapSchema:
+		if err := sd.expect('{', keyword, "object"); err != nil {
+			return err
+		}
+		m, err := sd.schemaMap()
+		if err != nil {
+			return err
+		}
+		spv = PartMapSchema(m)
+	case arg_type.ArgTypeSchemaOrSchemas:
+		if sd.peek() == '[' {
+			if _, err := sd.dec.Token(); err != nil {
+				return err
+			}
+			schemas, err := sd.schemaList()
+			if err != nil {
+				return err
+			}
+			spv = PartSchemaOrSchemas{Schemas: schemas}
+		} else {
+			sub, err := sd.subschema()
+			if err != nil {
+				return err
+			}
+			spv = PartSchemaOrSchemas{Schema: sub}
+		}
+	case arg_type.ArgTypeMapArrayOrSchema:
+		if err := sd.expect('{', keyword, "object"); err != nil {
+			return err
+		}
+		m := make(map[string]ArrayOrSchema)
+		for sd.dec.More() {
+			k, err := sd.key()
+			if err != nil {
+				return err
+			}
+			var as ArrayOrSchema
+			switch sd.peek() {
+			case '[':
+				var vals []any
+				if err := sd.dec.Decode(&vals); err != nil {
+					return err
+				}
+				strs := make([]string, 0, len(vals))
+				for i, v := range vals {
+					str, ok := v.(string)
+					if !ok {
+						return fmt.Errorf("jsongschema: %q argument item %s:%d is %T, want string", keyword, k, i, v)
+					}
+					strs = append(strs, str)
+				}
+				as.Array = strs
+			case '{', 't', 'f':
+				sub, err := sd.subschema()
+				if err != nil {
+					return wrapParseError(err, pointertoken.Escape(k))
+				}
+				as.Schema = sub
+			default:
+				var v any
+				if err := sd.dec.Decode(&v); err != nil {
+					return err
+				}
+				return fmt.Errorf("%q argument item %s is %T, want schema or array of strings", keyword, k, v)
+			}
+			m[k] = as
+		}
+		if _, err := sd.dec.Token(); err != nil {
+			return err
+		}
+		spv = PartMapArrayOrSchema(m)
+	case arg_type.ArgTypeMapMapSchema:
+		if err := sd.expect('{', keyword, "object"); err != nil {
+			return err
+		}
+		m := make(map[string]map[string]*Schema)
+		for sd.dec.More() {
+			k, err := sd.key()
+			if err != nil {
+				return err
+			}
+			if sd.peek() != '{' {
+				var v any
+				if err := sd.dec.Decode(&v); err != nil {
+					return err
+				}
+				return fmt.Errorf("%q argument item %s is %T, want object", keyword, k, v)
+			}
+			if _, err := sd.dec.Token(); err != nil {
+				return err
+			}
+			m2, err := sd.schemaMap()
+			if err != nil {
+				return wrapParseError(err, pointertoken.Escape(k))
+			}
+			m[k] = m2
+		}
+		if _, err := sd.dec.Token(); err != nil {
+			return err
+		}
+		spv = PartMapMapSchema(m)
+	}
+
+	s.Parts = append(s.Parts, Part{Keyword: sk, Value: spv})
+	return nil
+}
+
+// subschema reads a subschema.
+func (sd *streamDecoder) subschema() (*Schema, error) {
+	var s Schema
+	if err := sd.schema(&s, false); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// schemaList reads the schemas of an array whose
+// opening bracket has been read, and the closing bracket.
+func (sd *streamDecoder) schemaList() ([]*Schema, error) {
+	var schemas []*Schema
+	for i := 0; sd.dec.More(); i++ {
+		sub, err := sd.subschema()
+		if err != nil {
+			return nil, wrapParseError(err, strconv.Itoa(i))
+		}
+		schemas = append(schemas, sub)
+	}
+	if _, err := sd.dec.Token(); err != nil {
+		return nil, err
+	}
+	if schemas == nil {
+		schemas = []*Schema{}
+	}
+	return schemas, nil
+}
+
+// schemaMap reads the schemas of an object whose
+// opening brace has been read, and the closing brace.
+func (sd *streamDecoder) schemaMap() (map[string]*Schema, error) {
+	m := make(map[string]*Schema)
+	for sd.dec.More() {
+		k, err := sd.key()
+		if err != nil {
+			return nil, err
+		}
+		sub, err := sd.subschema()
+		if err != nil {
+			return nil, wrapParseError(err, pointertoken.Escape(k))
+		}
+		m[k] = sub
+	}
+	if _, err := sd.dec.Token(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// key reads the key of an object.
+func (sd *streamDecoder) key() (string, error) {
+	tok, err := sd.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	return tok.(string), nil
+}
+
+// expect reads the delimiter delim that starts the argument of
+// keyword, which is described by want, or returns an error.
+func (sd *streamDecoder) expect(delim json.Delim, keyword, want string) error {
+	if sd.peek() != byte(delim) {
+		var val any
+		if err := sd.dec.Decode(&val); err != nil {
+			return err
+		}
+		return fmt.Errorf("%q argument is type %T, want %s", keyword, val, want)
+	}
+	_, err := sd.dec.Token()
+	return err
+}
+
+// peek returns the first byte of the next value.
+func (sd *streamDecoder) peek() byte {
+	for _, c := range sd.data[sd.dec.InputOffset():] {
+		switch c {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			continue
+		}
+		return c
+	}
+	return 0
+}
+
+// tokenType describes the type of a JSON token for an error message,
+// as the type of the value that json.Unmarshal decodes it to.
+func tokenType(tok json.Token) string {
+	switch tok.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return "string"
+	case json.Number:
+		return "json.Number"
+	case json.Delim:
+		if tok == json.Delim('[') {
+			return "[]interface {}"
+		}
+	}
+	return fmt.Sprintf("%T", tok)
+}
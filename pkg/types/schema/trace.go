@@ -0,0 +1,70 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+// TraceEntry is the result of evaluating one keyword against
+// one location in the instance, reported to the Trace function
+// of [ValidateOpts].
+//
+// The JSON encoding of a TraceEntry uses the field names of the
+// error objects that the JavaScript validator ajv reports with its
+// verbose option, so that traces from both validators can be
+// compared. Unlike ajv, a TraceEntry is also reported for a keyword
+// that passed, and there are some differences in the values:
+//   - SchemaPath follows references from the root schema,
+//     as "#/properties/a/$ref/type", where ajv reports the
+//     location of the keyword in the referenced schema.
+//   - Message is the message of this package, not that of ajv,
+//     and there is no params field.
+type TraceEntry struct {
+	// Keyword is the name of the keyword, such as "type".
+	Keyword string `json:"keyword"`
+	// InstancePath is the location in the instance, as a JSON
+	// pointer: the root of the instance is the empty string.
+	InstancePath string `json:"instancePath"`
+	// SchemaPath is the location of the keyword,
+	// as a URI fragment such as "#/properties/a/type".
+	SchemaPath string `json:"schemaPath"`
+	// Valid reports whether the instance location
+	// matched the keyword.
+	Valid bool `json:"valid"`
+	// Message is the error message, if the keyword failed.
+	Message string `json:"message,omitempty"`
+	// Schema is the value of the keyword, as JSON decodes it.
+	Schema any `json:"schema"`
+	// ParentSchema is the schema that holds the keyword,
+	// as JSON decodes it.
+	ParentSchema any `json:"parentSchema"`
+	// Data is the value at InstancePath.
+	Data any `json:"data"`
+}
+
+// trace calls fn with an entry for each keyword unit under u.
+// The entries of the keywords of a subschema come before
+// the entry of the keyword that applied the subschema,
+// as ajv reports errors.
+func (u *OutputUnit) trace(fn func(*TraceEntry)) {
+	for _, c := range u.children {
+		c.trace(fn)
+	}
+	if u.part == nil {
+		return
+	}
+
+	// The values only fail to convert for a keyword added
+	// by a Builder with an unexpected type; leave them out.
+	arg, _ := partToValue(u.part.Value)
+	parent, _ := u.parent.ToValue()
+	fn(&TraceEntry{
+		Keyword:      u.keyword,
+		InstancePath: u.InstanceLocation,
+		SchemaPath:   "#" + u.KeywordLocation,
+		Valid:        u.Valid,
+		Message:      u.Error,
+		Schema:       arg,
+		ParentSchema: parent,
+		Data:         u.instance,
+	})
+}
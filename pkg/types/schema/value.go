@@ -0,0 +1,208 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"strconv"
+
+	errors2 "github.com/altshiftab/jsonschema/pkg/errors"
+)
+
+// FromValue builds a resolved schema from v, a tree of Go values
+// of the kind that json.Unmarshal produces: map[string]any, []any,
+// string, float64, bool and nil. Numbers may also be [json.Number].
+// This is like unmarshaling the JSON encoding of v,
+// without encoding v as JSON first.
+//
+// The vocabulary is found from the $schema keyword of v.
+// If there is none, vocab is used, or, if vocab is nil,
+// the default vocabulary. FromValue does not modify v.
+func FromValue(v any, vocab *Vocabulary) (*Schema, error) {
+	if m, ok := v.(map[string]any); ok {
+		// buildTopFromJSON removes $schema.
+		v = maps.Clone(m)
+	}
+
+	schemaID := ""
+	if vocab != nil {
+		schemaID = vocab.Schema
+	}
+	var s Schema
+	loader := globalLoader()
+	vocab, err := s.buildTopFromJSON(&decodeConfig{reg: &reg}, schemaID, nil, v)
+	if err != nil {
+		return nil, errors2.AsSchemaError(err)
+	}
+
+	ropts := &ResolveOpts{
+		Vocabulary: vocab,
+		Loader:     loader,
+	}
+	if err := s.Resolve(ropts); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ToValue returns s as a tree of Go values, as json.Unmarshal
+// produces from the JSON encoding of s: a schema is a bool or a
+// map[string]any, and an array is a []any. Numbers are float64,
+// except for an integer that a float64 can't represent exactly,
+// which is a [json.Number]; this includes a number argument whose
+// exact value is known, as reported by [Schema.ExactNumber].
+// [FromValue] builds the schema again.
+//
+// The result does not share memory with s, except for values
+// of keywords of any type that are not made of maps, slices
+// and basic types, as a Builder's AddAny method may add.
+func (s *Schema) ToValue() (any, error) {
+	if isBool, isTrue := s.isBoolSchema(); isBool {
+		return isTrue, nil
+	}
+
+	m := make(map[string]any)
+	for _, part := range s.Parts {
+		if part.Keyword.Generated {
+			continue
+		}
+		if f, ok := part.Value.(PartFloat); ok {
+			if i, ok := s.ExactNumber(part.Keyword.Name, float64(f)); ok {
+				m[part.Keyword.Name] = json.Number(i.String())
+				continue
+			}
+		}
+		v, err := partToValue(part.Value)
+		if err != nil {
+			return nil, err
+		}
+		m[part.Keyword.Name] = v
+	}
+	return m, nil
+}
+
+// partToValue returns the argument pv as a tree of Go values,
+// for [Schema.ToValue].
+func partToValue(pv PartValue) (any, error) {
+	switch v := pv.(type) {
+	case PartBool:
+		return bool(v), nil
+	case PartString:
+		return string(v), nil
+	case PartStrings:
+		return stringsToValue(v), nil
+	case PartStringOrStrings:
+		if v.Strings == nil {
+			return v.String, nil
+		}
+		return stringsToValue(v.Strings), nil
+	case PartInt:
+		if f := float64(v); f < 1<<63 && PartInt(f) == v {
+			return f, nil
+		}
+		return json.Number(strconv.FormatInt(int64(v), 10)), nil
+	case PartFloat:
+		if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+			return nil, fmt.Errorf("argument %v is not a finite number", float64(v))
+		}
+		return float64(v), nil
+	case PartSchema:
+		return v.S.ToValue()
+	case PartSchemas:
+		return schemasToValue(v)
+	case PartSchemaOrSchemas:
+		if v.Schema != nil {
+			return v.Schema.ToValue()
+		}
+		return schemasToValue(v.Schemas)
+	case PartMapSchema:
+		m := make(map[string]any, len(v))
+		for name, sub := range v {
+			sv, err := sub.ToValue()
+			if err != nil {
+				return nil, err
+			}
+			m[name] = sv
+		}
+		return m, nil
+	case PartMapArrayOrSchema:
+		m := make(map[string]any, len(v))
+		for name, as := range v {
+			if as.Schema == nil {
+				m[name] = stringsToValue(as.Array)
+				continue
+			}
+			sv, err := as.Schema.ToValue()
+			if err != nil {
+				return nil, err
+			}
+			m[name] = sv
+		}
+		return m, nil
+	case PartMapMapSchema:
+		m := make(map[string]any, len(v))
+		for name, values := range v {
+			m2 := make(map[string]any, len(values))
+			for value, sub := range values {
+				sv, err := sub.ToValue()
+				if err != nil {
+					return nil, err
+				}
+				m2[value] = sv
+			}
+			m[name] = m2
+		}
+		return m, nil
+	case PartAny:
+		return copyValue(v.V), nil
+	default:
+		return nil, fmt.Errorf("schema.ToValue: unexpected type %T", pv)
+	}
+}
+
+// stringsToValue returns strs as a []any.
+func stringsToValue(strs []string) []any {
+	ret := make([]any, len(strs))
+	for i, s := range strs {
+		ret[i] = s
+	}
+	return ret
+}
+
+// schemasToValue returns schemas as a []any.
+func schemasToValue(schemas []*Schema) ([]any, error) {
+	ret := make([]any, len(schemas))
+	for i, sub := range schemas {
+		sv, err := sub.ToValue()
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = sv
+	}
+	return ret, nil
+}
+
+// copyValue returns a copy of the maps and slices in v.
+func copyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = copyValue(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
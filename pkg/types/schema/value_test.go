@@ -0,0 +1,184 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// decodeValue decodes src as FromValue expects,
+// with numbers as json.Number.
+func decodeValue(t *testing.T, src string) any {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// want is the JSON encoding of the result of ToValue,
+		// without $schema.
+		want string
+	}{
+		{
+			"bool",
+			`false`,
+			`false`,
+		},
+		{
+			"number literals",
+			`{"minimum": 1.0, "multipleOf": 0.1, "exclusiveMaximum": 1e3}`,
+			`{"exclusiveMaximum":1000,"minimum":1,"multipleOf":0.1}`,
+		},
+		{
+			"large integers",
+			`{"maxLength": 9007199254740993, "maximum": 18446744073709551615, "minimum": -18446744073709551617}`,
+			`{"maxLength":9007199254740993,"maximum":18446744073709551615,"minimum":-18446744073709551617}`,
+		},
+		{
+			"any values",
+			`{"const": {"n": 12345678901234567890, "a": [1.5, null, true]}, "default": "x"}`,
+			`{"const":{"a":[1.5,null,true],"n":12345678901234567890},"default":"x"}`,
+		},
+		{
+			"nested schemas",
+			`{
+				"properties": {"a/b": {"items": {"type": ["integer", "null"]}}},
+				"prefixItems": [true, {"anyOf": [{"minimum": 0}, {"$ref": "#/$defs/s"}]}],
+				"dependentSchemas": {"x": false},
+				"dependentRequired": {"y": ["z"]},
+				"$defs": {"s": {"type": "string", "enum": ["p", "q"]}}
+			}`,
+			`{"$defs":{"s":{"enum":["p","q"],"type":"string"}},"dependentRequired":{"y":["z"]},"dependentSchemas":{"x":false},"prefixItems":[true,{"anyOf":[{"minimum":0},{"$ref":"#/$defs/s"}]}],"properties":{"a/b":{"items":{"type":["integer","null"]}}}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := decodeValue(t, test.src)
+			s, err := schema.FromValue(v, nil)
+			if err != nil {
+				t.Fatalf("FromValue failed: %v", err)
+			}
+			got, err := s.ToValue()
+			if err != nil {
+				t.Fatalf("ToValue failed: %v", err)
+			}
+			if m, ok := got.(map[string]any); ok {
+				if m["$schema"] != draft202012.SchemaID {
+					t.Errorf("got $schema %v, want %s", m["$schema"], draft202012.SchemaID)
+				}
+				delete(m, "$schema")
+			}
+			b, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != test.want {
+				t.Errorf("ToValue got %s, want %s", b, test.want)
+			}
+
+			// The value builds the same schema again. The text of
+			// numbers such as 1.0 is not kept, so compare values.
+			s2, err := schema.FromValue(got, nil)
+			if err != nil {
+				t.Fatalf("FromValue of ToValue result failed: %v", err)
+			}
+			got2, err := s2.ToValue()
+			if err != nil {
+				t.Fatalf("ToValue after round trip failed: %v", err)
+			}
+			if m, ok := got2.(map[string]any); ok {
+				delete(m, "$schema")
+			}
+			b2, err := json.Marshal(got2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b2) != test.want {
+				t.Errorf("ToValue after round trip got %s, want %s", b2, test.want)
+			}
+		})
+	}
+}
+
+func TestValueExactLimit(t *testing.T) {
+	s, err := schema.FromValue(decodeValue(t, `{"maximum": 18446744073709551615}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.ToValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = schema.FromValue(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 18446744073709551616 is 2^64, which is also the float64
+	// value nearest to the limit.
+	if err := s.Validate(json.Number("18446744073709551616")); err == nil {
+		t.Error("2^64 is valid after a round trip, want invalid")
+	}
+	if err := s.Validate(json.Number("18446744073709551615")); err != nil {
+		t.Errorf("limit is invalid after a round trip: %v", err)
+	}
+}
+
+func TestFromValueNoChange(t *testing.T) {
+	v := decodeValue(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "string"}`)
+	if _, err := schema.FromValue(v, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(map[string]any)["$schema"]; !ok {
+		t.Error("FromValue removed $schema from its argument")
+	}
+}
+
+func TestFromValueErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{`[]`, "unexpected type"},
+		{`"string"`, "unexpected type"},
+		{`{"minLength": "x"}`, `"minLength" argument is type string, want integer`},
+		{`{"minLength": -1}`, `"minLength" argument -1 is negative`},
+		{`{"minLength": 1.5}`, `"minLength" argument is non-integer`},
+		{`{"maximum": 1e400}`, `"maximum" argument 1e400 is out of range`},
+		{`{"properties": {"a": 1}}`, "#/properties/a"},
+		{`{"items": {"minimum": "0"}}`, "#/items/minimum"},
+		{`{"$schema": "https://example.com/unknown"}`, "not recognized"},
+	}
+	for _, test := range tests {
+		_, err := schema.FromValue(decodeValue(t, test.src), nil)
+		if err == nil {
+			t.Errorf("FromValue(%s) succeeded, want error", test.src)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("FromValue(%s) got error %q, want %q", test.src, err, test.wantErr)
+		}
+	}
+}
+
+func TestToValueError(t *testing.T) {
+	s := draft202012.NewBuilder().AddMaximum(math.Inf(1)).Build()
+	if v, err := s.ToValue(); err == nil {
+		t.Errorf("ToValue of infinite maximum got %v, want error", v)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Warning is a possible mistake in a schema that does not
+// prevent it from being used, such as a keyword that is ignored.
+type Warning struct {
+	// Pointer is the JSON pointer to the schema
+	// that contains the keyword.
+	Pointer string
+	// Keyword is the name of the keyword.
+	Keyword string
+	// Message describes the problem.
+	Message string
+	// Source is the location of the schema,
+	// if it was decoded by [SchemaFromJSONWithSource].
+	Source Source
+}
+
+// String returns the warning as a message for a user.
+func (w Warning) String() string {
+	msg := fmt.Sprintf("#%s: %s", w.Pointer, w.Message)
+	if w.Source.Line != 0 {
+		msg = fmt.Sprintf("%s: %s", w.Source, msg)
+	}
+	return msg
+}
+
+// otherKeyword describes a keyword of a draft that is not
+// implemented by this module, for warnings.
+type otherKeyword struct {
+	drafts string // the drafts that define the keyword
+	hint   string // what to use instead, if anything
+}
+
+// otherKeywords are the keywords of other drafts.
+// Keywords of registered vocabularies are found in the registry.
+var otherKeywords = map[string]otherKeyword{
+	"additionalItems":  {"draft-04 to draft 2019-09", "use items with prefixItems"},
+	"dependencies":     {"draft-04 to draft-07", "use dependentRequired or dependentSchemas"},
+	"definitions":      {"draft-04 to draft-07", "use $defs"},
+	"id":               {"draft-04", "use $id"},
+	"$recursiveRef":    {"draft 2019-09", "use $dynamicRef"},
+	"$recursiveAnchor": {"draft 2019-09", "use $dynamicAnchor"},
+	"divisibleBy":      {"draft-03", "use multipleOf"},
+	"disallow":         {"draft-03", "use not"},
+	"extends":          {"draft-03", "use allOf"},
+}
+
+// Warnings returns possible mistakes in s: the keywords that are
+// ignored because they are not in the vocabulary of the schema,
+// but are defined by another JSON schema draft. These are usually
+// left over from converting a schema from an older draft.
+// The vocabulary v is the vocabulary of s. If it is nil, it is found
+// from the $schema keyword, or is the default vocabulary.
+// Subschemas with their own $schema keyword use that vocabulary.
+func (s *Schema) Warnings(v *Vocabulary) []Warning {
+	if v == nil {
+		v = schemaVocabulary(s)
+		if v == nil {
+			v = DefaultVocabulary()
+		}
+	}
+	if v == nil {
+		return nil
+	}
+
+	var ret []Warning
+	seen := make(map[*Schema]bool)
+	var walk func(s *Schema, ptr string, v *Vocabulary)
+	walk = func(s *Schema, ptr string, v *Vocabulary) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		if sv := schemaVocabulary(s); sv != nil {
+			v = sv
+		}
+		src, _ := s.Source()
+		for _, part := range s.Parts {
+			name := part.Keyword.Name
+			if part.Keyword.Generated || v.Keywords[name] != nil {
+				continue
+			}
+			if _, _, ok := LookupExtension(name); ok {
+				continue
+			}
+			if msg := otherDraftMessage(name, v); msg != "" {
+				ret = append(ret, Warning{
+					Pointer: ptr,
+					Keyword: name,
+					Message: msg,
+					Source:  src,
+				})
+			}
+		}
+		for name, sub := range s.Children() {
+			walk(sub, ptr+"/"+name, v)
+		}
+	}
+	walk(s, "", v)
+	return ret
+}
+
+// otherDraftMessage returns a warning message if name is a keyword
+// of a draft other than v, or "" if it is not.
+func otherDraftMessage(name string, v *Vocabulary) string {
+	if other, ok := otherKeywords[name]; ok {
+		return fmt.Sprintf("%q is a keyword of %s, and is ignored by %s; %s", name, other.drafts, v.Name, other.hint)
+	}
+
+	reg.mu.Lock()
+	var drafts []string
+	for _, rv := range reg.mapping {
+		if rv != v && rv.Keywords[name] != nil && !rv.Keywords[name].Generated {
+			drafts = append(drafts, rv.Name)
+		}
+	}
+	reg.mu.Unlock()
+	if len(drafts) == 0 {
+		return ""
+	}
+	slices.Sort(drafts)
+	drafts = slices.Compact(drafts)
+	return fmt.Sprintf("%q is a keyword of %s, and is ignored by %s", name, strings.Join(drafts, ", "), v.Name)
+}
+
+// schemaVocabulary returns the vocabulary named by
+// the $schema keyword of s, or nil if there is none.
+func schemaVocabulary(s *Schema) *Vocabulary {
+	for _, part := range s.Parts {
+		if part.Keyword == &SchemaKeyword {
+			return LookupVocabulary(string(part.Value.(PartString)))
+		}
+	}
+	return nil
+}
+
+// SetWarningHandler sets a function to call with the warnings for
+// each schema decoded by the JSON unmarshaler, as reported by
+// [Schema.Warnings]. This is a global property, as there is no way
+// to pass the desired value into the JSON decoder.
+// Callers should use appropriate locking.
+//
+// This returns the old handler. The default handler is nil,
+// which means that warnings are not reported.
+func SetWarningHandler(fn func(Warning)) func(Warning) {
+	ret := warningHandler
+	warningHandler = fn
+	return ret
+}
+
+// warningHandler is the function set by SetWarningHandler.
+var warningHandler func(Warning)
+
+// reportWarnings passes the warnings for s, whose vocabulary is v,
+// to the handler set by SetWarningHandler, if any.
+func reportWarnings(s *Schema, v *Vocabulary) {
+	if warningHandler == nil {
+		return
+	}
+	for _, w := range s.Warnings(v) {
+		warningHandler(w)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package watch reloads a schema when its source changes, so that
+// a long-running service picks up a new version of a schema
+// without being restarted.
+//
+// A [Watcher] checks its [Source] periodically. When the JSON
+// encoding of the schema changes, the Watcher decodes and resolves
+// it, and, if that succeeds, atomically replaces the schema that
+// [Watcher.Schema] returns and notifies its subscribers. A schema
+// that fails to decode is reported and the previous one is kept.
+//
+//	w, err := watch.New(ctx, watch.File("schemas/order.json"), nil)
+//	if err != nil { ... }
+//	go w.Run(ctx)
+//	...
+//	err = w.Schema().Validate(order)
+//
+// Files are polled, rather than watched with operating system
+// notifications, which keeps this package portable and
+// works the same for files and URLs.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/altshiftab/jsonschema/pkg/types/schema"
+)
+
+// DefaultInterval is the interval between checks
+// if [Options.Interval] is zero.
+const DefaultInterval = 5 * time.Second
+
+// A Source provides the JSON encoding of a schema.
+type Source interface {
+	// Read returns the current JSON encoding of the schema,
+	// and the URI that it comes from, which may be nil.
+	Read(ctx context.Context) (data []byte, uri *url.URL, err error)
+}
+
+// SourceFunc is an adapter that lets an ordinary function be used
+// as a [Source], such as one that fetches the latest version of
+// a subject from a schema registry.
+type SourceFunc func(ctx context.Context) ([]byte, *url.URL, error)
+
+// Read calls f(ctx).
+func (f SourceFunc) Read(ctx context.Context) ([]byte, *url.URL, error) {
+	return f(ctx)
+}
+
+// File returns a [Source] that reads the schema from the file name.
+// The URI of the schema is the file: URL of the file.
+func File(name string) Source {
+	return SourceFunc(func(ctx context.Context) ([]byte, *url.URL, error) {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		var uri *url.URL
+		if abs, err := filepath.Abs(name); err == nil {
+			p := filepath.ToSlash(abs)
+			if p[0] != '/' {
+				// A Windows path, such as C:/schemas/order.json.
+				p = "/" + p
+			}
+			uri = &url.URL{Scheme: "file", Path: p}
+		}
+		return data, uri, nil
+	})
+}
+
+// URL returns a [Source] that fetches the schema from uri
+// with client, or with [http.DefaultClient] if client is nil.
+func URL(uri string, client *http.Client) Source {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return SourceFunc(func(ctx context.Context) ([]byte, *url.URL, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("fetching %s: %s", uri, resp.Status)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, u, nil
+	})
+}
+
+// Options describes the configuration of a [Watcher].
+type Options struct {
+	// Interval is the time between checks of the source.
+	// If zero, DefaultInterval is used.
+	Interval time.Duration
+	// Decoder decodes the schema. If nil, a [schema.Decoder]
+	// with no loader is used, so the schema may not refer
+	// to other documents.
+	Decoder *schema.Decoder
+	// OnError, if not nil, is called when the source can't be read
+	// or its schema can't be decoded, after the first time.
+	// The Watcher keeps the schema that it has.
+	OnError func(error)
+}
+
+// A Watcher holds the current version of a schema.
+// A Watcher may be used concurrently.
+type Watcher struct {
+	src  Source
+	opts Options
+
+	cur atomic.Pointer[schema.Schema]
+
+	mu sync.Mutex
+	// hash is the hash of the JSON encoding of the current schema.
+	hash [sha256.Size]byte
+	// subs are the channels of the subscribers.
+	subs map[chan *schema.Schema]bool
+}
+
+// New returns a [Watcher] for the schema of src, which it reads
+// and decodes. It is an error if that fails. The options may be nil.
+// The Watcher does not check src again until [Watcher.Run] or
+// [Watcher.Check] is called.
+func New(ctx context.Context, src Source, opts *Options) (*Watcher, error) {
+	w := &Watcher{
+		src:  src,
+		subs: make(map[chan *schema.Schema]bool),
+	}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = DefaultInterval
+	}
+	if w.opts.Decoder == nil {
+		w.opts.Decoder = &schema.Decoder{}
+	}
+	if _, err := w.Check(ctx); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Schema returns the current schema.
+func (w *Watcher) Schema() *schema.Schema {
+	return w.cur.Load()
+}
+
+// Subscribe returns a channel on which the Watcher sends each new
+// schema, and a function that ends the subscription. The channel
+// holds one schema: if the subscriber has not received a schema
+// when the next one arrives, it only receives the newer one.
+func (w *Watcher) Subscribe() (<-chan *schema.Schema, func()) {
+	ch := make(chan *schema.Schema, 1)
+	w.mu.Lock()
+	w.subs[ch] = true
+	w.mu.Unlock()
+	return ch, func() {
+		w.mu.Lock()
+		delete(w.subs, ch)
+		w.mu.Unlock()
+	}
+}
+
+// Run checks the source every [Options.Interval] until ctx is done,
+// and then returns ctx.Err(). Errors are passed to [Options.OnError].
+func (w *Watcher) Run(ctx context.Context) error {
+	t := time.NewTicker(w.opts.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if _, err := w.Check(ctx); err != nil && w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// Check reads the source once, and if the schema has changed,
+// decodes it and makes it the current schema. It reports whether
+// the schema changed. If there is an error, the current schema
+// is kept.
+func (w *Watcher) Check(ctx context.Context) (bool, error) {
+	data, uri, err := w.src.Read(ctx)
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256(data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cur.Load() != nil && bytes.Equal(hash[:], w.hash[:]) {
+		return false, nil
+	}
+	s, err := w.opts.Decoder.Decode(ctx, data, uri)
+	if err != nil {
+		return false, err
+	}
+	w.cur.Store(s)
+	w.hash = hash
+	for ch := range w.subs {
+		// Replace a schema that was not received.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- s
+	}
+	return true, nil
+}
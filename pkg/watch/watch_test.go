@@ -0,0 +1,210 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/altshiftab/jsonschema/pkg/draft202012"
+	"github.com/altshiftab/jsonschema/pkg/watch"
+)
+
+// A source is a watch.Source whose schema the test sets.
+type source struct {
+	mu   sync.Mutex
+	data string
+	err  error
+}
+
+func (s *source) set(data string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data, s.err = data, err
+}
+
+func (s *source) Read(ctx context.Context) ([]byte, *url.URL, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return []byte(s.data), nil, s.err
+}
+
+// schemaOf returns a schema of the given type.
+func schemaOf(typ string) string {
+	return `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "` + typ + `"}`
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	src := &source{data: schemaOf("string")}
+	w, err := watch.New(ctx, src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Schema().Validate("x"); err != nil {
+		t.Errorf("initial schema: %v", err)
+	}
+	ch, cancel := w.Subscribe()
+	defer cancel()
+
+	if changed, err := w.Check(ctx); changed || err != nil {
+		t.Errorf("unchanged source: got %t, %v", changed, err)
+	}
+
+	src.set(schemaOf("integer"), nil)
+	if changed, err := w.Check(ctx); !changed || err != nil {
+		t.Errorf("changed source: got %t, %v", changed, err)
+	}
+	if err := w.Schema().Validate(1.0); err != nil {
+		t.Errorf("new schema: %v", err)
+	}
+	select {
+	case s := <-ch:
+		if s != w.Schema() {
+			t.Error("subscriber received a different schema")
+		}
+	default:
+		t.Error("subscriber received no schema")
+	}
+
+	// Errors keep the current schema.
+	cur := w.Schema()
+	src.set(`{"type": 1`, nil)
+	if changed, err := w.Check(ctx); changed || err == nil {
+		t.Errorf("invalid schema: got %t, %v", changed, err)
+	}
+	src.set("", errors.New("unavailable"))
+	if changed, err := w.Check(ctx); changed || err == nil {
+		t.Errorf("read error: got %t, %v", changed, err)
+	}
+	if w.Schema() != cur {
+		t.Error("schema replaced after errors")
+	}
+
+	// A subscriber that doesn't keep up receives the latest schema.
+	src.set(schemaOf("boolean"), nil)
+	w.Check(ctx)
+	src.set(schemaOf("null"), nil)
+	w.Check(ctx)
+	if s := <-ch; s != w.Schema() {
+		t.Error("subscriber did not receive the latest schema")
+	}
+	select {
+	case <-ch:
+		t.Error("subscriber received a stale schema")
+	default:
+	}
+
+	// After cancel, nothing is sent.
+	cancel()
+	src.set(schemaOf("object"), nil)
+	w.Check(ctx)
+	select {
+	case <-ch:
+		t.Error("canceled subscriber received a schema")
+	default:
+	}
+}
+
+func TestNewError(t *testing.T) {
+	ctx := context.Background()
+	if _, err := watch.New(ctx, &source{data: `{"type": 1}`}, nil); err == nil {
+		t.Error("invalid schema: no error")
+	}
+	if _, err := watch.New(ctx, &source{err: errors.New("unavailable")}, nil); err == nil {
+		t.Error("read error: no error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	src := &source{data: schemaOf("string")}
+	errc := make(chan error, 1)
+	w, err := watch.New(ctx, src, &watch.Options{
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errc <- err:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, unsubscribe := w.Subscribe()
+	defer unsubscribe()
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	src.set(schemaOf("integer"), nil)
+	if err := (<-ch).Validate(1.0); err != nil {
+		t.Errorf("new schema: %v", err)
+	}
+	src.set(`{`, nil)
+	if err := <-errc; err == nil {
+		t.Error("OnError called with nil")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(name, []byte(schemaOf("string")), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	data, uri, err := watch.File(name).Read(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != schemaOf("string") {
+		t.Errorf("got %s", data)
+	}
+	if uri.Scheme != "file" || !strings.HasSuffix(uri.Path, "/schema.json") {
+		t.Errorf("got URI %s", uri)
+	}
+	if _, _, err := watch.File(name + ".missing").Read(context.Background()); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+func TestURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schema.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(schemaOf("string")))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	data, uri, err := watch.URL(srv.URL+"/schema.json", srv.Client()).Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != schemaOf("string") {
+		t.Errorf("got %s", data)
+	}
+	if got, want := uri.String(), srv.URL+"/schema.json"; got != want {
+		t.Errorf("got URI %s, want %s", got, want)
+	}
+	if _, _, err := watch.URL(srv.URL+"/missing.json", nil).Read(ctx); err == nil {
+		t.Error("missing schema: no error")
+	}
+}